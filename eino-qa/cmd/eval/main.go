@@ -0,0 +1,212 @@
+// eval 离线评估工具
+//
+// 用标注数据集评估意图识别、向量检索和端到端回答的质量，输出 JSON 和 Markdown 报告，
+// 指定基线报告时相对基线下降超过容差的指标视为回退，以退出码 2 退出。
+//
+//	go run ./cmd/eval -dataset testdata/eval.jsonl -tenant tenant1 -out reports/eval -baseline reports/baseline.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"eino-qa/internal/infrastructure/config"
+	"eino-qa/internal/infrastructure/container"
+	"eino-qa/internal/usecase/eval"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	defaultConfigPath = "config/config.yaml"
+
+	// exitRegression 存在相对基线的回退时的退出码
+	exitRegression = 2
+)
+
+// options 命令行参数
+type options struct {
+	configPath string
+	dataset    string
+	tenantID   string
+	k          int
+	out        string
+	baseline   string
+	tolerance  float64
+	skipAnswer bool
+	semantic   bool
+}
+
+func main() {
+	opts := parseFlags()
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: .env file not found, using system environment variables")
+	}
+
+	regressions, err := run(opts)
+	if err != nil {
+		log.Fatalf("Evaluation failed: %v", err)
+	}
+	if len(regressions) > 0 {
+		log.Printf("Found %d regression(s) against baseline %s:", len(regressions), opts.baseline)
+		for _, r := range regressions {
+			log.Printf("  - %s", r)
+		}
+		os.Exit(exitRegression)
+	}
+}
+
+// parseFlags 解析命令行参数
+func parseFlags() *options {
+	opts := &options{}
+	flag.StringVar(&opts.configPath, "config", envOrDefault("CONFIG_PATH", defaultConfigPath), "配置文件路径")
+	flag.StringVar(&opts.dataset, "dataset", "", "标注数据集（JSONL：query、intent、source_ids、reference_answer）")
+	flag.StringVar(&opts.tenantID, "tenant", "default", "评估使用的租户")
+	flag.IntVar(&opts.k, "k", 5, "recall@k 的 k")
+	flag.StringVar(&opts.out, "out", "eval-report", "报告输出路径前缀，生成 .json 和 .md 两个文件")
+	flag.StringVar(&opts.baseline, "baseline", "", "基线报告（JSON），指标下降超过容差时以退出码 2 退出")
+	flag.Float64Var(&opts.tolerance, "tolerance", 0.01, "允许的指标下降幅度")
+	flag.BoolVar(&opts.skipAnswer, "skip-answer", false, "跳过端到端回答评估（不调用对话流程）")
+	flag.BoolVar(&opts.semantic, "semantic", true, "使用嵌入模型计算回答与参考答案的语义相似度")
+	flag.Parse()
+
+	if opts.dataset == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	return opts
+}
+
+// run 执行评估并写出报告，返回相对基线的回退
+func run(opts *options) ([]eval.Regression, error) {
+	samples, err := loadDataset(opts.dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline *eval.Report
+	if opts.baseline != "" {
+		if baseline, err = loadBaseline(opts.baseline); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg, err := config.Load(opts.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	c, err := container.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container: %w", err)
+	}
+	defer c.Close()
+
+	var chatExecutor eval.ChatExecutor
+	if !opts.skipAnswer {
+		chatExecutor = c.ChatUseCase
+	}
+
+	uc := eval.NewEvalUseCase(c.IntentRecognizer, c.RAGRetriever, chatExecutor, c.LogrusLogger)
+	if opts.semantic {
+		uc.WithEmbedder(c.EinoClient.GetEmbedModel())
+	}
+
+	// 支持 Ctrl+C 中断
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Evaluating %d samples from %s (tenant=%s, k=%d)", len(samples), opts.dataset, opts.tenantID, opts.k)
+	report, err := uc.Run(ctx, samples, eval.Options{
+		TenantID: opts.tenantID,
+		K:        opts.k,
+		Dataset:  filepath.Base(opts.dataset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeReports(opts.out, report, baseline); err != nil {
+		return nil, err
+	}
+	log.Printf("Intent accuracy: %.4f, recall@%d: %.4f, MRR: %.4f, answer similarity: %.4f",
+		report.Intent.Accuracy, report.K, report.Retrieval.RecallAtK, report.Retrieval.MRR, report.Answer.LexicalSimilarity)
+
+	if baseline == nil {
+		return nil, nil
+	}
+	if baseline.K != report.K {
+		log.Printf("Warning: baseline k=%d differs from k=%d, recall@k is not compared", baseline.K, report.K)
+	}
+	return eval.Compare(baseline, report, opts.tolerance), nil
+}
+
+// loadDataset 读取标注数据集
+func loadDataset(path string) ([]*eval.Sample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer f.Close()
+
+	samples, err := eval.LoadDataset(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dataset %s: %w", path, err)
+	}
+	return samples, nil
+}
+
+// loadBaseline 读取基线报告
+func loadBaseline(path string) (*eval.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline: %w", err)
+	}
+	defer f.Close()
+
+	return eval.LoadReport(f)
+}
+
+// writeReports 写出 JSON 和 Markdown 报告
+func writeReports(prefix string, report, baseline *eval.Report) error {
+	if dir := filepath.Dir(prefix); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+
+	writers := map[string]func(f *os.File) error{
+		prefix + ".json": func(f *os.File) error { return report.WriteJSON(f) },
+		prefix + ".md":   func(f *os.File) error { return report.WriteMarkdown(f, baseline) },
+	}
+	for path, write := range writers {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		err = write(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write report %s: %w", path, err)
+		}
+		log.Printf("Report written: %s", path)
+	}
+	return nil
+}
+
+// envOrDefault 读取环境变量，未设置时返回默认值
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -115,6 +115,9 @@ func runServer(c *container.Container) error {
 	// 创建错误通道
 	errChan := make(chan error, 1)
 
+	// 启动后台任务（订单定时同步）
+	c.StartBackgroundJobs()
+
 	// 在 goroutine 中启动服务器
 	// 需求: 6.1 - 服务器启动
 	go func() {
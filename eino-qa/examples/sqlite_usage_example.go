@@ -95,7 +95,7 @@ func main() {
 	missedRepo := factory.GetMissedQueryRepository(tenantID)
 
 	// 记录未命中查询
-	if err := missedRepo.Create(ctx, "这个问题没有答案", "course"); err != nil {
+	if err := missedRepo.Create(ctx, entity.NewMissedQuery(tenantID, "这个问题没有答案", "course")); err != nil {
 		log.Fatalf("记录未命中查询失败: %v", err)
 	}
 	fmt.Println("未命中查询已记录")
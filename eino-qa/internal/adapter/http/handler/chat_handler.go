@@ -2,17 +2,27 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
 	"eino-qa/internal/usecase/chat"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// llmConcurrencyMessage 模型调用并发名额不足时返回给客户端的消息
+	llmConcurrencyMessage = "Too many concurrent requests"
+	// llmConcurrencyRetryAfter 模型调用并发名额不足时建议的重试等待秒数
+	llmConcurrencyRetryAfter = 1
+)
+
 // ChatHandler 对话处理器
 type ChatHandler struct {
 	chatUseCase chat.ChatUseCaseInterface
@@ -35,18 +45,26 @@ type ChatRequestDTO struct {
 
 // ChatResponseDTO HTTP 响应 DTO
 type ChatResponseDTO struct {
-	Answer    string         `json:"answer"`
-	Route     string         `json:"route"`
-	Sources   []SourceDTO    `json:"sources,omitempty"`
-	SessionID string         `json:"session_id"`
-	Metadata  map[string]any `json:"metadata"`
+	Answer      string         `json:"answer"`
+	Route       string         `json:"route"`
+	Sources     []SourceDTO    `json:"sources,omitempty"`
+	Blocks      []BlockDTO     `json:"blocks,omitempty"`
+	Suggestions []string       `json:"suggestions,omitempty"`
+	SessionID   string         `json:"session_id"`
+	MessageID   string         `json:"message_id"`
+	Metadata    map[string]any `json:"metadata"`
 }
 
 // SourceDTO 来源文档 DTO
+// index 与回答中的 [n] 引用标记对应，metadata 为文档分块入库时的元数据
 type SourceDTO struct {
-	Content  string         `json:"content"`
-	Score    float64        `json:"score"`
-	Metadata map[string]any `json:"metadata,omitempty"`
+	Index      int            `json:"index"`
+	DocumentID string         `json:"document_id"`
+	Title      string         `json:"title"`
+	Content    string         `json:"content"`
+	Score      float64        `json:"score"`
+	Cited      bool           `json:"cited"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
 }
 
 // HandleChat 处理对话请求
@@ -61,23 +79,25 @@ func (h *ChatHandler) HandleChat(c *gin.Context) {
 		return
 	}
 
-	// 从 context 获取租户 ID（由中间件设置）
-	if tenantID, exists := c.Get("tenant_id"); exists {
-		if tid, ok := tenantID.(string); ok && req.TenantID == "" {
-			req.TenantID = tid
-		}
+	// 租户 ID 只来自租户中间件，终端用户身份、限流和模型调用并发名额都按该租户校验；
+	// 请求体中的 tenant_id 仅为兼容保留，与中间件识别的租户不一致时拒绝
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = "default"
 	}
-
-	// 如果仍然没有租户 ID，使用默认值
-	if req.TenantID == "" {
-		req.TenantID = "default"
+	if req.TenantID != "" && req.TenantID != tenantID {
+		c.Error(middleware.NewBadRequestError("tenant_id in request body does not match X-Tenant-ID"))
+		return
 	}
+	req.TenantID = tenantID
 
 	// 构建用例请求
+	// 用户 ID 只来自身份中间件的校验结果，不接受请求体传入
 	useCaseReq := &chat.ChatRequest{
 		Query:     req.Query,
 		TenantID:  req.TenantID,
 		SessionID: req.SessionID,
+		UserID:    c.GetString("user_id"),
 		Stream:    req.Stream,
 	}
 
@@ -96,6 +116,9 @@ func (h *ChatHandler) handleNormalChat(c *gin.Context, req *chat.ChatRequest) {
 	// 执行对话用例
 	resp, err := h.chatUseCase.Execute(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, entity.ErrLLMConcurrencyLimit) {
+			err = middleware.NewRateLimitError(llmConcurrencyMessage, "llm_concurrency", llmConcurrencyRetryAfter)
+		}
 		c.Error(err)
 		return
 	}
@@ -141,22 +164,40 @@ func (h *ChatHandler) handleStreamChat(c *gin.Context, req *chat.ChatRequest) {
 
 			// 如果有错误，发送错误事件
 			if chunk.Error != nil {
-				c.SSEvent("error", map[string]any{
-					"message": chunk.Error.Error(),
-				})
+				h.sendStreamError(c, chunk.Error)
 				flusher.Flush()
 				return false
 			}
 
 			// 如果完成，发送完成事件
 			if chunk.Done {
-				c.SSEvent("done", map[string]any{
+				done := map[string]any{
 					"metadata": chunk.Metadata,
-				})
+				}
+				if len(chunk.Suggestions) > 0 {
+					done["suggestions"] = chunk.Suggestions
+				}
+				c.SSEvent("done", done)
 				flusher.Flush()
 				return false
 			}
 
+			// 结构化响应块作为独立事件发送
+			if chunk.Block != nil {
+				c.SSEvent("block", toBlockDTO(chunk.Block))
+				flusher.Flush()
+				return true
+			}
+
+			// 回答未通过审核时替换此前发送的全部内容
+			if chunk.Replace {
+				c.SSEvent("replace", map[string]any{
+					"content": chunk.Content,
+				})
+				flusher.Flush()
+				return true
+			}
+
 			// 发送内容块
 			c.SSEvent("message", map[string]any{
 				"content": chunk.Content,
@@ -171,27 +212,66 @@ func (h *ChatHandler) handleStreamChat(c *gin.Context, req *chat.ChatRequest) {
 	})
 }
 
+// sendStreamError 发送流式响应的错误事件
+// 模型调用并发名额不足时按限流处理：尚未发送任何事件时返回 429，错误事件中附带限流信息
+func (h *ChatHandler) sendStreamError(c *gin.Context, err error) {
+	if !errors.Is(err, entity.ErrLLMConcurrencyLimit) {
+		c.SSEvent("error", map[string]any{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if !c.Writer.Written() {
+		c.Header("Retry-After", strconv.Itoa(llmConcurrencyRetryAfter))
+		c.Status(http.StatusTooManyRequests)
+	}
+	c.SSEvent("error", map[string]any{
+		"code":    http.StatusTooManyRequests,
+		"message": llmConcurrencyMessage,
+		"details": map[string]any{
+			"scope":       "llm_concurrency",
+			"retry_after": llmConcurrencyRetryAfter,
+		},
+	})
+}
+
 // toChatResponseDTO 转换为响应 DTO
 func (h *ChatHandler) toChatResponseDTO(resp *chat.ChatResponse) *ChatResponseDTO {
 	dto := &ChatResponseDTO{
-		Answer:    resp.Answer,
-		Route:     resp.Route,
-		SessionID: resp.SessionID,
-		Metadata:  resp.Metadata,
+		Answer:      resp.Answer,
+		Route:       resp.Route,
+		Suggestions: resp.Suggestions,
+		SessionID:   resp.SessionID,
+		MessageID:   resp.MessageID,
+		Metadata:    resp.Metadata,
 	}
 
 	// 转换来源文档
 	if len(resp.Sources) > 0 {
 		dto.Sources = make([]SourceDTO, len(resp.Sources))
 		for i, source := range resp.Sources {
+			cited, _ := source.Cited()
 			dto.Sources[i] = SourceDTO{
-				Content:  source.Content,
-				Score:    source.Score,
-				Metadata: source.Metadata,
+				Index:      i + 1,
+				DocumentID: source.ID,
+				Title:      source.Title(),
+				Content:    source.Content,
+				Score:      source.Score,
+				Cited:      cited,
+				Metadata:   source.Metadata,
 			}
 		}
 	}
 
+	// 转换结构化响应块
+	if len(resp.Blocks) > 0 {
+		dto.Blocks = make([]BlockDTO, len(resp.Blocks))
+		for i, block := range resp.Blocks {
+			dto.Blocks[i] = toBlockDTO(block)
+		}
+	}
+
 	return dto
 }
 
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"eino-qa/internal/adapter/http/middleware"
 	"eino-qa/internal/domain/entity"
 	"eino-qa/internal/usecase/chat"
 
@@ -173,6 +175,135 @@ func TestChatHandler_HandleChat_DefaultTenantID(t *testing.T) {
 	mockUseCase.AssertExpectations(t)
 }
 
+func TestChatHandler_HandleChat_MiddlewareTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUseCase := new(MockChatUseCase)
+	handler := NewChatHandler(mockUseCase)
+
+	// 用例收到的租户（模型调用并发名额按它分配）就是限流使用的中间件租户
+	mockUseCase.On("Execute", mock.Anything, mock.MatchedBy(func(req *chat.ChatRequest) bool {
+		return req.TenantID == "tenant1"
+	})).Return(&chat.ChatResponse{Answer: "ok", Metadata: map[string]any{}}, nil).Twice()
+
+	for _, tenantID := range []string{"", "tenant1"} {
+		body, _ := json.Marshal(ChatRequestDTO{Query: "Hello", TenantID: tenantID})
+		req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("tenant_id", "tenant1")
+
+		handler.HandleChat(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestChatHandler_HandleChat_TenantMismatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUseCase := new(MockChatUseCase)
+	handler := NewChatHandler(mockUseCase)
+
+	// 请求体中的租户与中间件识别的租户不一致
+	body, _ := json.Marshal(ChatRequestDTO{Query: "我的订单", TenantID: "tenant2"})
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("tenant_id", "tenant1")
+	c.Set("user_id", "u1")
+
+	handler.HandleChat(c)
+
+	if assert.NotEmpty(t, c.Errors) {
+		var badRequest *middleware.BadRequestError
+		assert.ErrorAs(t, c.Errors.Last().Err, &badRequest)
+	}
+	mockUseCase.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+}
+
+func TestChatHandler_HandleChat_LLMConcurrencyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 任一路由（不只是意图识别）的模型调用并发名额不足都按限流返回
+	for _, route := range []entity.IntentType{entity.IntentCourse, entity.IntentDirect} {
+		t.Run(string(route), func(t *testing.T) {
+			mockUseCase := new(MockChatUseCase)
+			handler := NewChatHandler(mockUseCase)
+
+			routeErr := fmt.Errorf("failed to handle %s intent: %w", route, fmt.Errorf("%w: limit 1", entity.ErrLLMConcurrencyLimit))
+			mockUseCase.On("Execute", mock.Anything, mock.Anything).Return(nil, routeErr).Once()
+
+			body, _ := json.Marshal(ChatRequestDTO{Query: "Go 进阶课程多少钱"})
+			req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Set("tenant_id", "tenant1")
+
+			handler.HandleChat(c)
+
+			if assert.NotEmpty(t, c.Errors) {
+				var rateLimit *middleware.RateLimitError
+				if assert.ErrorAs(t, c.Errors.Last().Err, &rateLimit) {
+					assert.Equal(t, "llm_concurrency", rateLimit.Scope)
+				}
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+// streamRecorder 支持 CloseNotify 的响应记录器（gin 的流式响应需要）
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (streamRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func TestChatHandler_HandleChat_StreamLLMConcurrencyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUseCase := new(MockChatUseCase)
+	handler := NewChatHandler(mockUseCase)
+
+	chunks := make(chan *chat.StreamChunk, 1)
+	chunks <- &chat.StreamChunk{
+		Error: fmt.Errorf("failed to handle course intent: %w", entity.ErrLLMConcurrencyLimit),
+		Done:  true,
+	}
+	close(chunks)
+	mockUseCase.On("ExecuteStream", mock.Anything, mock.Anything).Return((<-chan *chat.StreamChunk)(chunks), nil).Once()
+
+	body, _ := json.Marshal(ChatRequestDTO{Query: "Go 进阶课程多少钱", Stream: true})
+	req := httptest.NewRequest(http.MethodPost, "/chat", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := streamRecorder{httptest.NewRecorder()}
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set("tenant_id", "tenant1")
+
+	handler.HandleChat(c)
+
+	// 尚未发送内容时返回 429，错误事件中附带限流信息
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "event:error")
+	assert.Contains(t, w.Body.String(), "llm_concurrency")
+	mockUseCase.AssertExpectations(t)
+}
+
 func TestChatHandler_toChatResponseDTO(t *testing.T) {
 	handler := NewChatHandler(nil)
 
@@ -202,6 +333,9 @@ func TestChatHandler_toChatResponseDTO(t *testing.T) {
 	assert.Equal(t, "course", dto.Route)
 	assert.Equal(t, "session123", dto.SessionID)
 	assert.Len(t, dto.Sources, 1)
+	assert.Equal(t, 1, dto.Sources[0].Index)
+	assert.Equal(t, "doc1", dto.Sources[0].DocumentID)
+	assert.Equal(t, "Source 1", dto.Sources[0].Title)
 	assert.Equal(t, "Source 1", dto.Sources[0].Content)
 	assert.Equal(t, 0.95, dto.Sources[0].Score)
 	assert.Equal(t, "test", dto.Sources[0].Metadata["category"])
@@ -223,3 +357,44 @@ func TestChatHandler_toChatResponseDTO_NoSources(t *testing.T) {
 	assert.Equal(t, "Test answer", dto.Answer)
 	assert.Nil(t, dto.Sources)
 }
+
+func TestChatHandler_toChatResponseDTO_Blocks(t *testing.T) {
+	handler := NewChatHandler(nil)
+
+	resp := &chat.ChatResponse{
+		Answer:    "您的订单 #20251114001 待支付",
+		Route:     "order",
+		SessionID: "session123",
+		Blocks: []*chat.ResponseBlock{
+			{
+				Type: chat.BlockOrderCard,
+				OrderCard: &chat.OrderCard{
+					OrderID:     "#20251114001",
+					CourseName:  "Go 进阶课程",
+					Amount:      299,
+					Status:      entity.OrderStatusPending,
+					StatusLabel: "待支付",
+				},
+			},
+			{
+				Type:         chat.BlockQuickReplies,
+				QuickReplies: &chat.QuickReplies{Options: []string{"取消订单 #20251114001"}},
+			},
+		},
+	}
+
+	dto := handler.toChatResponseDTO(resp)
+
+	assert.Len(t, dto.Blocks, 2)
+	assert.Equal(t, "order_card", dto.Blocks[0].Type)
+	card, ok := dto.Blocks[0].Data.(OrderCardDTO)
+	assert.True(t, ok)
+	assert.Equal(t, "#20251114001", card.OrderID)
+	assert.Equal(t, "pending", card.Status)
+	assert.Equal(t, "quick_replies", dto.Blocks[1].Type)
+	assert.Equal(t, QuickRepliesDTO{Options: []string{"取消订单 #20251114001"}}, dto.Blocks[1].Data)
+
+	body, err := json.Marshal(dto)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"status_label":"待支付"`)
+}
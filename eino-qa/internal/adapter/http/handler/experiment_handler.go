@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/usecase/experiment"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExperimentHandler A/B 实验结果处理器
+type ExperimentHandler struct {
+	experimentUseCase experiment.ExperimentUseCaseInterface
+}
+
+// NewExperimentHandler 创建 A/B 实验结果处理器
+func NewExperimentHandler(experimentUseCase experiment.ExperimentUseCaseInterface) *ExperimentHandler {
+	return &ExperimentHandler{
+		experimentUseCase: experimentUseCase,
+	}
+}
+
+// ExperimentDTO 实验结果 DTO
+type ExperimentDTO struct {
+	Name      string                 `json:"name"`
+	Component string                 `json:"component"`
+	Variants  []ExperimentVariantDTO `json:"variants"`
+}
+
+// ExperimentVariantDTO 实验分组结果 DTO
+type ExperimentVariantDTO struct {
+	Name             string  `json:"name"`
+	Weight           int     `json:"weight"`
+	PromptVersion    int     `json:"prompt_version,omitempty"`
+	Model            string  `json:"model,omitempty"`
+	Requests         int64   `json:"requests"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+	Handoffs         int64   `json:"handoffs"`
+	HandoffRate      float64 `json:"handoff_rate"`
+	RAGRequests      int64   `json:"rag_requests"`
+	RAGMisses        int64   `json:"rag_misses"`
+	RAGMissRate      float64 `json:"rag_miss_rate"`
+	Positive         int64   `json:"positive"`
+	Negative         int64   `json:"negative"`
+	SatisfactionRate float64 `json:"satisfaction_rate"`
+}
+
+// HandleListExperiments 处理查询实验结果请求
+// GET /api/v1/experiments?since=2024-01-01T00:00:00Z
+func (h *ExperimentHandler) HandleListExperiments(c *gin.Context) {
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.Error(middleware.NewBadRequestError("since must be an RFC3339 timestamp"))
+			return
+		}
+		since = parsed
+	}
+
+	reports, err := h.experimentUseCase.Results(c.Request.Context(), getTenantID(c), since)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]ExperimentDTO, len(reports))
+	for i, report := range reports {
+		variants := make([]ExperimentVariantDTO, len(report.Variants))
+		for j, v := range report.Variants {
+			variants[j] = ExperimentVariantDTO{
+				Name:             v.Variant,
+				Weight:           v.Weight,
+				PromptVersion:    v.PromptVersion,
+				Model:            v.Model,
+				Requests:         v.Requests,
+				AvgLatencyMs:     v.AvgLatencyMs,
+				Handoffs:         v.Handoffs,
+				HandoffRate:      v.HandoffRate(),
+				RAGRequests:      v.RAGRequests,
+				RAGMisses:        v.RAGMisses,
+				RAGMissRate:      v.RAGMissRate(),
+				Positive:         v.Positive,
+				Negative:         v.Negative,
+				SatisfactionRate: v.SatisfactionRate(),
+			}
+		}
+		dtos[i] = ExperimentDTO{
+			Name:      report.Name,
+			Component: string(report.Component),
+			Variants:  variants,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"experiments": dtos,
+	})
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/faq"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FAQHandler 标准问答管理处理器
+type FAQHandler struct {
+	faqUseCase faq.FAQUseCaseInterface
+}
+
+// NewFAQHandler 创建标准问答管理处理器
+func NewFAQHandler(faqUseCase faq.FAQUseCaseInterface) *FAQHandler {
+	return &FAQHandler{
+		faqUseCase: faqUseCase,
+	}
+}
+
+// SaveFAQRequestDTO 创建或更新标准问答请求 DTO（更新时整体替换）
+type SaveFAQRequestDTO struct {
+	Questions     []string   `json:"questions" binding:"required"`
+	Answer        string     `json:"answer" binding:"required"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	Enabled       *bool      `json:"enabled"` // 默认 true
+}
+
+// FAQDTO 标准问答 DTO
+type FAQDTO struct {
+	ID            string     `json:"id"`
+	Questions     []string   `json:"questions"`
+	Answer        string     `json:"answer"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	Enabled       bool       `json:"enabled"`
+	Effective     bool       `json:"effective"` // 当前是否启用且处于生效期内
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// HandleCreateFAQ 处理创建标准问答请求
+// POST /api/v1/faqs
+func (h *FAQHandler) HandleCreateFAQ(c *gin.Context) {
+	req, ok := bindSaveFAQRequest(c)
+	if !ok {
+		return
+	}
+
+	created, err := h.faqUseCase.Create(c.Request.Context(), req)
+	if err != nil {
+		c.Error(toFAQError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"faq":     toFAQDTO(created),
+	})
+}
+
+// HandleListFAQs 处理列出标准问答请求
+// GET /api/v1/faqs
+func (h *FAQHandler) HandleListFAQs(c *gin.Context) {
+	faqs, err := h.faqUseCase.List(c.Request.Context(), getTenantID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]FAQDTO, len(faqs))
+	for i, f := range faqs {
+		dtos[i] = toFAQDTO(f)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"faqs":    dtos,
+	})
+}
+
+// HandleGetFAQ 处理获取标准问答请求
+// GET /api/v1/faqs/:id
+func (h *FAQHandler) HandleGetFAQ(c *gin.Context) {
+	found, err := h.faqUseCase.Get(c.Request.Context(), getTenantID(c), c.Param("id"))
+	if err != nil {
+		c.Error(toFAQError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"faq":     toFAQDTO(found),
+	})
+}
+
+// HandleUpdateFAQ 处理更新标准问答请求
+// PUT /api/v1/faqs/:id
+func (h *FAQHandler) HandleUpdateFAQ(c *gin.Context) {
+	req, ok := bindSaveFAQRequest(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.faqUseCase.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(toFAQError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"faq":     toFAQDTO(updated),
+	})
+}
+
+// HandleDeleteFAQ 处理删除标准问答请求
+// DELETE /api/v1/faqs/:id
+func (h *FAQHandler) HandleDeleteFAQ(c *gin.Context) {
+	if err := h.faqUseCase.Delete(c.Request.Context(), getTenantID(c), c.Param("id")); err != nil {
+		c.Error(toFAQError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// bindSaveFAQRequest 解析创建或更新请求，失败时已写入错误
+func bindSaveFAQRequest(c *gin.Context) (*faq.SaveRequest, bool) {
+	var req SaveFAQRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("invalid request: %s", err.Error())))
+		return nil, false
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return &faq.SaveRequest{
+		TenantID:      getTenantID(c),
+		Questions:     req.Questions,
+		Answer:        req.Answer,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+		Enabled:       enabled,
+	}, true
+}
+
+// toFAQError 将领域错误映射为 HTTP 错误
+func toFAQError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrFAQNotFound):
+		return middleware.NewNotFoundError(err.Error())
+	case errors.Is(err, entity.ErrEmptyFAQQuestions),
+		errors.Is(err, entity.ErrEmptyFAQAnswer),
+		errors.Is(err, entity.ErrInvalidFAQDateRange):
+		return middleware.NewBadRequestError(err.Error())
+	}
+	return err
+}
+
+// toFAQDTO 转换标准问答 DTO（不含问题向量）
+func toFAQDTO(f *entity.FAQ) FAQDTO {
+	return FAQDTO{
+		ID:            f.ID,
+		Questions:     f.Questions,
+		Answer:        f.Answer,
+		EffectiveFrom: f.EffectiveFrom,
+		EffectiveTo:   f.EffectiveTo,
+		Enabled:       f.Enabled,
+		Effective:     f.IsEffective(time.Now()),
+		CreatedAt:     f.CreatedAt,
+		UpdatedAt:     f.UpdatedAt,
+	}
+}
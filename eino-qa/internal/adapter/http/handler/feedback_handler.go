@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/usecase/feedback"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeedbackHandler 用户反馈处理器
+type FeedbackHandler struct {
+	feedbackUseCase feedback.FeedbackUseCaseInterface
+}
+
+// NewFeedbackHandler 创建用户反馈处理器
+func NewFeedbackHandler(feedbackUseCase feedback.FeedbackUseCaseInterface) *FeedbackHandler {
+	return &FeedbackHandler{
+		feedbackUseCase: feedbackUseCase,
+	}
+}
+
+// SubmitFeedbackRequestDTO 提交反馈请求 DTO
+type SubmitFeedbackRequestDTO struct {
+	SessionID  string `json:"session_id" binding:"required"`
+	MessageID  string `json:"message_id" binding:"required"`
+	Rating     string `json:"rating" binding:"required"` // "up" 或 "down"
+	Reason     string `json:"reason"`                    // incorrect/incomplete/irrelevant/outdated/other
+	Comment    string `json:"comment"`
+	Correction string `json:"correction"` // 用户提供的正确答案（可选）
+}
+
+// FeedbackDTO 反馈 DTO
+type FeedbackDTO struct {
+	ID         string    `json:"id"`
+	SessionID  string    `json:"session_id"`
+	MessageID  string    `json:"message_id"`
+	Rating     string    `json:"rating"`
+	Reason     string    `json:"reason,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	Correction string    `json:"correction,omitempty"`
+	Query      string    `json:"query,omitempty"`
+	Answer     string    `json:"answer,omitempty"`
+	Route      string    `json:"route"`
+	Intent     string    `json:"intent"`
+	Status     string    `json:"status"`
+	DocumentID string    `json:"document_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// HandleSubmitFeedback 处理提交反馈请求
+// POST /feedback
+func (h *FeedbackHandler) HandleSubmitFeedback(c *gin.Context) {
+	var req SubmitFeedbackRequestDTO
+
+	// 解析请求体
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("invalid request: %s", err.Error())))
+		return
+	}
+
+	fb, err := h.feedbackUseCase.Submit(c.Request.Context(), &feedback.SubmitRequest{
+		TenantID:   getTenantID(c),
+		SessionID:  req.SessionID,
+		MessageID:  req.MessageID,
+		Rating:     req.Rating,
+		Reason:     req.Reason,
+		Comment:    req.Comment,
+		Correction: req.Correction,
+	})
+	if err != nil {
+		c.Error(toFeedbackError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"feedback_id": fb.ID,
+	})
+}
+
+// HandleListFeedback 处理列出反馈请求
+// GET /api/v1/feedback?rating=down&status=open&has_correction=true&offset=0&limit=20
+func (h *FeedbackHandler) HandleListFeedback(c *gin.Context) {
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	hasCorrection, _ := strconv.ParseBool(c.DefaultQuery("has_correction", "false"))
+
+	filter := repository.FeedbackFilter{
+		Rating:        entity.FeedbackRating(c.Query("rating")),
+		Status:        entity.FeedbackStatus(c.Query("status")),
+		HasCorrection: hasCorrection,
+	}
+
+	if filter.Rating != "" && !filter.Rating.IsValid() {
+		c.Error(middleware.NewBadRequestError(entity.ErrInvalidFeedbackRating.Error()))
+		return
+	}
+
+	feedbacks, err := h.feedbackUseCase.List(c.Request.Context(), getTenantID(c), filter, offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]FeedbackDTO, len(feedbacks))
+	for i, fb := range feedbacks {
+		dtos[i] = toFeedbackDTO(fb)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"feedback": dtos,
+	})
+}
+
+// HandlePromoteFeedback 处理将纠正答案加入知识库的请求
+// POST /api/v1/feedback/:id/promote
+func (h *FeedbackHandler) HandlePromoteFeedback(c *gin.Context) {
+	fb, err := h.feedbackUseCase.Promote(c.Request.Context(), getTenantID(c), c.Param("id"))
+	if err != nil {
+		c.Error(toFeedbackError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"feedback": toFeedbackDTO(fb),
+	})
+}
+
+// toFeedbackError 将领域错误映射为 HTTP 错误
+func toFeedbackError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrMessageNotFound),
+		errors.Is(err, entity.ErrFeedbackNotFound):
+		return middleware.NewNotFoundError(err.Error())
+	case errors.Is(err, entity.ErrInvalidFeedbackRating),
+		errors.Is(err, entity.ErrInvalidFeedbackReason),
+		errors.Is(err, entity.ErrEmptyMessageID),
+		errors.Is(err, entity.ErrEmptySessionID),
+		errors.Is(err, entity.ErrEmptyCorrection):
+		return middleware.NewBadRequestError(err.Error())
+	}
+	return err
+}
+
+// toFeedbackDTO 转换反馈 DTO
+func toFeedbackDTO(fb *entity.Feedback) FeedbackDTO {
+	return FeedbackDTO{
+		ID:         fb.ID,
+		SessionID:  fb.SessionID,
+		MessageID:  fb.MessageID,
+		Rating:     string(fb.Rating),
+		Reason:     string(fb.Reason),
+		Comment:    fb.Comment,
+		Correction: fb.Correction,
+		Query:      fb.Query,
+		Answer:     fb.Answer,
+		Route:      fb.Route,
+		Intent:     fb.Intent,
+		Status:     string(fb.Status),
+		DocumentID: fb.DocumentID,
+		CreatedAt:  fb.CreatedAt,
+		UpdatedAt:  fb.UpdatedAt,
+	}
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/missedquery"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MissedQueryHandler 知识库未命中查询分析处理器
+type MissedQueryHandler struct {
+	missedQueryUseCase missedquery.MissedQueryUseCaseInterface
+}
+
+// NewMissedQueryHandler 创建未命中查询分析处理器
+func NewMissedQueryHandler(missedQueryUseCase missedquery.MissedQueryUseCaseInterface) *MissedQueryHandler {
+	return &MissedQueryHandler{
+		missedQueryUseCase: missedQueryUseCase,
+	}
+}
+
+// MissedQueryDTO 未命中查询 DTO
+type MissedQueryDTO struct {
+	ID             uint      `json:"id"`
+	Query          string    `json:"query"`
+	RewrittenQuery string    `json:"rewritten_query,omitempty"`
+	Intent         string    `json:"intent"`
+	TopScore       float64   `json:"top_score"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// MissedQueryClusterDTO 未命中话题 DTO
+type MissedQueryClusterDTO struct {
+	Representative string                  `json:"representative"`
+	Count          int                     `json:"count"`
+	AvgTopScore    float64                 `json:"avg_top_score"`
+	LastSeenAt     time.Time               `json:"last_seen_at"`
+	QueryIDs       []uint                  `json:"query_ids"`
+	Variants       []MissedQueryVariantDTO `json:"variants"`
+}
+
+// MissedQueryVariantDTO 话题内问法 DTO
+type MissedQueryVariantDTO struct {
+	Query string `json:"query"`
+	Count int    `json:"count"`
+}
+
+// DraftFAQRequestDTO 生成 FAQ 草稿请求 DTO
+type DraftFAQRequestDTO struct {
+	QueryIDs []uint `json:"query_ids" binding:"required"`
+}
+
+// FAQDraftDTO FAQ 草稿 DTO
+// texts 和 metadata 可直接作为 POST /api/v1/vectors/items 的请求体
+type FAQDraftDTO struct {
+	Question string         `json:"question"`
+	Variants []string       `json:"variants"`
+	Texts    []string       `json:"texts"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// HandleListMissedQueries 处理列出未命中查询请求
+// GET /api/v1/missed-queries?offset=0&limit=20
+func (h *MissedQueryHandler) HandleListMissedQueries(c *gin.Context) {
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	queries, total, err := h.missedQueryUseCase.List(c.Request.Context(), getTenantID(c), offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]MissedQueryDTO, len(queries))
+	for i, q := range queries {
+		dtos[i] = toMissedQueryDTO(q)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"missed_queries": dtos,
+		"total":          total,
+	})
+}
+
+// HandleClusterMissedQueries 处理未命中查询聚类请求
+// GET /api/v1/missed-queries/clusters?limit=500&threshold=0.85&since=2024-01-01T00:00:00Z
+func (h *MissedQueryHandler) HandleClusterMissedQueries(c *gin.Context) {
+	req := &missedquery.ClusterRequest{
+		TenantID: getTenantID(c),
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			c.Error(middleware.NewBadRequestError("invalid limit"))
+			return
+		}
+		req.Limit = limit
+	}
+
+	if v := c.Query("threshold"); v != "" {
+		threshold, err := strconv.ParseFloat(v, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			c.Error(middleware.NewBadRequestError("threshold must be in (0, 1]"))
+			return
+		}
+		req.Threshold = threshold
+	}
+
+	if v := c.Query("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.Error(middleware.NewBadRequestError("since must be an RFC3339 timestamp"))
+			return
+		}
+		req.Since = since
+	}
+
+	clusters, err := h.missedQueryUseCase.Cluster(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]MissedQueryClusterDTO, len(clusters))
+	for i, cl := range clusters {
+		variants := make([]MissedQueryVariantDTO, len(cl.Variants))
+		for j, v := range cl.Variants {
+			variants[j] = MissedQueryVariantDTO{Query: v.Query, Count: v.Count}
+		}
+
+		dtos[i] = MissedQueryClusterDTO{
+			Representative: cl.Representative,
+			Count:          cl.Count,
+			AvgTopScore:    cl.AvgTopScore,
+			LastSeenAt:     cl.LastSeenAt,
+			QueryIDs:       cl.QueryIDs,
+			Variants:       variants,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"clusters": dtos,
+	})
+}
+
+// HandleDraftFAQ 处理将话题转换为 FAQ 草稿的请求
+// POST /api/v1/missed-queries/clusters/draft
+func (h *MissedQueryHandler) HandleDraftFAQ(c *gin.Context) {
+	var req DraftFAQRequestDTO
+
+	// 解析请求体
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("invalid request: %s", err.Error())))
+		return
+	}
+
+	if len(req.QueryIDs) == 0 {
+		c.Error(middleware.NewBadRequestError("query_ids cannot be empty"))
+		return
+	}
+
+	draft, err := h.missedQueryUseCase.DraftFAQ(c.Request.Context(), getTenantID(c), req.QueryIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"draft": FAQDraftDTO{
+			Question: draft.Question,
+			Variants: draft.Variants,
+			Texts:    []string{draft.Content},
+			Metadata: draft.Metadata,
+		},
+	})
+}
+
+// toMissedQueryDTO 转换未命中查询 DTO
+func toMissedQueryDTO(q *entity.MissedQuery) MissedQueryDTO {
+	return MissedQueryDTO{
+		ID:             q.ID,
+		Query:          q.Query,
+		RewrittenQuery: q.RewrittenQuery,
+		Intent:         q.Intent,
+		TopScore:       q.TopScore,
+		CreatedAt:      q.CreatedAt,
+	}
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/orderimport"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize 导入文件的最大字节数
+const maxImportFileSize = 20 << 20
+
+// OrderImportHandler 订单导入与同步处理器
+type OrderImportHandler struct {
+	importUseCase orderimport.OrderImportUseCaseInterface
+}
+
+// NewOrderImportHandler 创建订单导入与同步处理器
+func NewOrderImportHandler(importUseCase orderimport.OrderImportUseCaseInterface) *OrderImportHandler {
+	return &OrderImportHandler{
+		importUseCase: importUseCase,
+	}
+}
+
+// OrderSyncStateDTO 订单同步状态 DTO
+type OrderSyncStateDTO struct {
+	Source         string     `json:"source"`
+	Status         string     `json:"status"`
+	Cursor         *time.Time `json:"cursor,omitempty"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastSuccessAt  *time.Time `json:"last_success_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	Total          int        `json:"total"`
+	Created        int        `json:"created"`
+	Updated        int        `json:"updated"`
+	Unchanged      int        `json:"unchanged"`
+	Failed         int        `json:"failed"`
+}
+
+// HandleImportOrders 处理订单文件批量导入请求
+// POST /api/v1/orders/import（multipart/form-data）
+// 表单字段：file（CSV 或 JSONL 文件）、format（csv/jsonl，默认按扩展名识别）、
+// mapping（字段映射 JSON，可选）、dry_run（只校验不写入，可选）
+func (h *OrderImportHandler) HandleImportOrders(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("invalid request: %s", err.Error())))
+		return
+	}
+
+	if file.Size > maxImportFileSize {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("file too large: max %d bytes", maxImportFileSize)))
+		return
+	}
+
+	format := strings.ToLower(c.PostForm("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+		if format == "ndjson" {
+			format = orderimport.FormatJSONL
+		}
+	}
+
+	var mapping *orderimport.FieldMapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		mapping = &orderimport.FieldMapping{}
+		if err := json.Unmarshal([]byte(raw), mapping); err != nil {
+			c.Error(middleware.NewBadRequestError(fmt.Sprintf("invalid mapping: %s", err.Error())))
+			return
+		}
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultPostForm("dry_run", "false"))
+
+	reader, err := file.Open()
+	if err != nil {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("failed to open file: %s", err.Error())))
+		return
+	}
+	defer reader.Close()
+
+	report, err := h.importUseCase.Import(c.Request.Context(), &orderimport.ImportRequest{
+		TenantID: getTenantID(c),
+		Format:   format,
+		Reader:   reader,
+		Mapping:  mapping,
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		c.Error(toOrderImportError(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"report":  report,
+	})
+}
+
+// HandleSyncOrders 处理立即从外部系统同步订单的请求
+// POST /api/v1/orders/sync
+func (h *OrderImportHandler) HandleSyncOrders(c *gin.Context) {
+	report, err := h.importUseCase.Sync(c.Request.Context(), getTenantID(c))
+	if err != nil {
+		c.Error(toOrderImportError(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"report":  report,
+	})
+}
+
+// HandleGetSyncStatus 处理查询订单同步状态的请求
+// GET /api/v1/orders/sync/status
+func (h *OrderImportHandler) HandleGetSyncStatus(c *gin.Context) {
+	state, err := h.importUseCase.SyncStatus(c.Request.Context(), getTenantID(c))
+	if err != nil {
+		c.Error(toOrderImportError(c, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"sync":    toOrderSyncStateDTO(state),
+	})
+}
+
+// toOrderImportError 将导入/同步错误映射为 HTTP 错误
+func toOrderImportError(c *gin.Context, err error) error {
+	switch {
+	case errors.Is(err, orderimport.ErrUnsupportedFormat):
+		return middleware.NewBadRequestError(err.Error())
+	case errors.Is(err, orderimport.ErrOrderSyncNotEnabled):
+		return middleware.NewNotFoundError(err.Error())
+	case errors.Is(err, orderimport.ErrOrderSyncInProgress):
+		c.Status(http.StatusConflict)
+		return err
+	}
+	return middleware.NewServiceError(err.Error(), "order_sync")
+}
+
+// toOrderSyncStateDTO 转换订单同步状态 DTO
+func toOrderSyncStateDTO(state *entity.OrderSyncState) OrderSyncStateDTO {
+	optionalTime := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+
+	return OrderSyncStateDTO{
+		Source:         state.Source,
+		Status:         string(state.Status),
+		Cursor:         optionalTime(state.Cursor),
+		LastStartedAt:  optionalTime(state.LastStartedAt),
+		LastFinishedAt: optionalTime(state.LastFinishedAt),
+		LastSuccessAt:  optionalTime(state.LastSuccessAt),
+		LastError:      state.LastError,
+		Total:          state.LastStats.Total,
+		Created:        state.LastStats.Created,
+		Updated:        state.LastStats.Updated,
+		Unchanged:      state.LastStats.Unchanged,
+		Failed:         state.LastStats.Failed,
+	}
+}
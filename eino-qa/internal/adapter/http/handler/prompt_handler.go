@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/prompt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptHandler 提示词模板管理处理器
+type PromptHandler struct {
+	promptUseCase prompt.PromptUseCaseInterface
+}
+
+// NewPromptHandler 创建提示词模板管理处理器
+func NewPromptHandler(promptUseCase prompt.PromptUseCaseInterface) *PromptHandler {
+	return &PromptHandler{
+		promptUseCase: promptUseCase,
+	}
+}
+
+// CreatePromptRequestDTO 创建模板版本请求 DTO
+type CreatePromptRequestDTO struct {
+	Content  string `json:"content" binding:"required"`
+	Comment  string `json:"comment"`
+	Activate bool   `json:"activate"`
+}
+
+// PromptSummaryDTO 提示词概要 DTO
+type PromptSummaryDTO struct {
+	Name          string     `json:"name"`
+	ActiveVersion int        `json:"active_version"` // 0 表示使用内置默认模板
+	Content       string     `json:"content"`
+	ActivatedAt   *time.Time `json:"activated_at,omitempty"`
+}
+
+// PromptVersionDTO 模板版本 DTO
+type PromptVersionDTO struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Version     int        `json:"version"`
+	Content     string     `json:"content"`
+	Comment     string     `json:"comment,omitempty"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+}
+
+// PromptDetailDTO 提示词版本历史 DTO
+type PromptDetailDTO struct {
+	Name           string             `json:"name"`
+	ActiveVersion  int                `json:"active_version"`
+	DefaultContent string             `json:"default_content"`
+	Rendered       string             `json:"rendered"`
+	Versions       []PromptVersionDTO `json:"versions"`
+}
+
+// HandleListPrompts 处理列出提示词请求
+// GET /api/v1/prompts
+func (h *PromptHandler) HandleListPrompts(c *gin.Context) {
+	summaries, err := h.promptUseCase.List(c.Request.Context(), getTenantID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]PromptSummaryDTO, len(summaries))
+	for i, s := range summaries {
+		dtos[i] = PromptSummaryDTO{
+			Name:          string(s.Name),
+			ActiveVersion: s.ActiveVersion,
+			Content:       s.Content,
+			ActivatedAt:   s.ActivatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"prompts": dtos,
+	})
+}
+
+// HandleGetPrompt 处理获取提示词版本历史请求
+// GET /api/v1/prompts/:name
+func (h *PromptHandler) HandleGetPrompt(c *gin.Context) {
+	detail, err := h.promptUseCase.Get(c.Request.Context(), getTenantID(c), entity.PromptName(c.Param("name")))
+	if err != nil {
+		c.Error(toPromptError(err))
+		return
+	}
+
+	versions := make([]PromptVersionDTO, len(detail.Versions))
+	for i, v := range detail.Versions {
+		versions[i] = toPromptVersionDTO(v)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"prompt": PromptDetailDTO{
+			Name:           string(detail.Name),
+			ActiveVersion:  detail.ActiveVersion,
+			DefaultContent: detail.DefaultContent,
+			Rendered:       detail.Rendered,
+			Versions:       versions,
+		},
+	})
+}
+
+// HandleCreatePromptVersion 处理创建模板版本请求
+// POST /api/v1/prompts/:name
+func (h *PromptHandler) HandleCreatePromptVersion(c *gin.Context) {
+	var req CreatePromptRequestDTO
+
+	// 解析请求体
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("invalid request: %s", err.Error())))
+		return
+	}
+
+	template, err := h.promptUseCase.Create(c.Request.Context(), &prompt.CreateRequest{
+		TenantID: getTenantID(c),
+		Name:     entity.PromptName(c.Param("name")),
+		Content:  req.Content,
+		Comment:  req.Comment,
+		Activate: req.Activate,
+	})
+	if err != nil {
+		c.Error(toPromptError(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"version": toPromptVersionDTO(template),
+	})
+}
+
+// HandleActivatePromptVersion 处理启用模板版本请求
+// POST /api/v1/prompts/:name/versions/:version/activate
+func (h *PromptHandler) HandleActivatePromptVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.Error(middleware.NewBadRequestError("version must be a positive integer"))
+		return
+	}
+
+	template, err := h.promptUseCase.Activate(c.Request.Context(), getTenantID(c), entity.PromptName(c.Param("name")), version)
+	if err != nil {
+		c.Error(toPromptError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"version": toPromptVersionDTO(template),
+	})
+}
+
+// HandleRollbackPrompt 处理回滚请求
+// POST /api/v1/prompts/:name/rollback
+func (h *PromptHandler) HandleRollbackPrompt(c *gin.Context) {
+	template, err := h.promptUseCase.Rollback(c.Request.Context(), getTenantID(c), entity.PromptName(c.Param("name")))
+	if err != nil {
+		c.Error(toPromptError(err))
+		return
+	}
+
+	// 回滚到内置默认模板
+	if template == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success":        true,
+			"active_version": 0,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"active_version": template.Version,
+		"version":        toPromptVersionDTO(template),
+	})
+}
+
+// toPromptError 将领域错误映射为 HTTP 错误
+func toPromptError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrPromptTemplateNotFound):
+		return middleware.NewNotFoundError(err.Error())
+	case errors.Is(err, entity.ErrInvalidPromptName),
+		errors.Is(err, entity.ErrEmptyPromptContent),
+		errors.Is(err, entity.ErrPromptTooLong),
+		errors.Is(err, entity.ErrPromptRenderFailed):
+		return middleware.NewBadRequestError(err.Error())
+	}
+	return err
+}
+
+// toPromptVersionDTO 转换模板版本 DTO
+func toPromptVersionDTO(t *entity.PromptTemplate) PromptVersionDTO {
+	return PromptVersionDTO{
+		ID:          t.ID,
+		Name:        string(t.Name),
+		Version:     t.Version,
+		Content:     t.Content,
+		Comment:     t.Comment,
+		Active:      t.Active,
+		CreatedAt:   t.CreatedAt,
+		ActivatedAt: t.ActivatedAt,
+	}
+}
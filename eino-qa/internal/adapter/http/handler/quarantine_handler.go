@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/vector"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuarantineHandler 隔离文档管理处理器
+type QuarantineHandler struct {
+	quarantineUseCase vector.QuarantineUseCaseInterface
+}
+
+// NewQuarantineHandler 创建隔离文档管理处理器
+func NewQuarantineHandler(quarantineUseCase vector.QuarantineUseCaseInterface) *QuarantineHandler {
+	return &QuarantineHandler{
+		quarantineUseCase: quarantineUseCase,
+	}
+}
+
+// QuarantinedDocumentDTO 隔离文档 DTO
+type QuarantinedDocumentDTO struct {
+	ID        string         `json:"id"`
+	Content   string         `json:"content"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Score     float64        `json:"score"`
+	Rules     []string       `json:"rules"`
+	Method    string         `json:"method"`
+	Reason    string         `json:"reason,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// HandleListQuarantined 处理列出隔离文档请求
+// GET /api/v1/vectors/quarantine
+func (h *QuarantineHandler) HandleListQuarantined(c *gin.Context) {
+	docs, err := h.quarantineUseCase.ListQuarantined(c.Request.Context(), getTenantID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]QuarantinedDocumentDTO, len(docs))
+	for i, doc := range docs {
+		dtos[i] = QuarantinedDocumentDTO{
+			ID:        doc.ID,
+			Content:   doc.Content,
+			Metadata:  doc.Metadata,
+			Score:     doc.Score,
+			Rules:     doc.Rules,
+			Method:    doc.Method,
+			Reason:    doc.Reason,
+			CreatedAt: doc.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"documents": dtos,
+	})
+}
+
+// HandleReleaseQuarantined 处理放行隔离文档请求（写入知识库并从隔离区删除）
+// POST /api/v1/vectors/quarantine/:id/release
+func (h *QuarantineHandler) HandleReleaseQuarantined(c *gin.Context) {
+	resp, err := h.quarantineUseCase.ReleaseQuarantined(c.Request.Context(), getTenantID(c), c.Param("id"))
+	if err != nil {
+		c.Error(toQuarantineError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"document_ids": resp.DocumentIDs,
+	})
+}
+
+// HandleDeleteQuarantined 处理删除隔离文档请求
+// DELETE /api/v1/vectors/quarantine/:id
+func (h *QuarantineHandler) HandleDeleteQuarantined(c *gin.Context) {
+	if err := h.quarantineUseCase.DeleteQuarantined(c.Request.Context(), getTenantID(c), c.Param("id")); err != nil {
+		c.Error(toQuarantineError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// toQuarantineError 将领域错误映射为 HTTP 错误
+func toQuarantineError(err error) error {
+	if errors.Is(err, entity.ErrQuarantinedDocumentNotFound) {
+		return middleware.NewNotFoundError(err.Error())
+	}
+	return err
+}
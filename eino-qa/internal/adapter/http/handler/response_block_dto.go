@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"time"
+
+	"eino-qa/internal/usecase/chat"
+)
+
+// BlockDTO 结构化响应块 DTO
+// type 取值 order_card、source_citation、quick_replies、handoff_notice、action_confirmation，data 为对应类型的内容
+type BlockDTO struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// OrderCardDTO 订单卡片 DTO
+type OrderCardDTO struct {
+	OrderID     string    `json:"order_id"`
+	CourseName  string    `json:"course_name"`
+	Amount      float64   `json:"amount"`
+	Status      string    `json:"status"`
+	StatusLabel string    `json:"status_label"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SourceCitationDTO 来源引用 DTO
+type SourceCitationDTO struct {
+	Index      int     `json:"index"`
+	DocumentID string  `json:"document_id"`
+	Title      string  `json:"title"`
+	URL        string  `json:"url,omitempty"`
+	Snippet    string  `json:"snippet"`
+	Score      float64 `json:"score"`
+	Cited      bool    `json:"cited"`
+}
+
+// QuickRepliesDTO 快捷回复 DTO
+type QuickRepliesDTO struct {
+	Options []string `json:"options"`
+}
+
+// HandoffNoticeDTO 转人工提示 DTO
+type HandoffNoticeDTO struct {
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+// ActionConfirmationDTO 订单操作确认 DTO
+type ActionConfirmationDTO struct {
+	ActionID     string    `json:"action_id"`
+	Action       string    `json:"action"`
+	ActionLabel  string    `json:"action_label"`
+	OrderID      string    `json:"order_id"`
+	Reason       string    `json:"reason,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	ConfirmReply string    `json:"confirm_reply"`
+	RejectReply  string    `json:"reject_reply"`
+}
+
+// toBlockDTO 转换结构化响应块 DTO
+func toBlockDTO(block *chat.ResponseBlock) BlockDTO {
+	dto := BlockDTO{Type: string(block.Type)}
+
+	switch {
+	case block.OrderCard != nil:
+		card := block.OrderCard
+		dto.Data = OrderCardDTO{
+			OrderID:     card.OrderID,
+			CourseName:  card.CourseName,
+			Amount:      card.Amount,
+			Status:      string(card.Status),
+			StatusLabel: card.StatusLabel,
+			CreatedAt:   card.CreatedAt,
+			UpdatedAt:   card.UpdatedAt,
+		}
+	case block.SourceCitation != nil:
+		citation := block.SourceCitation
+		dto.Data = SourceCitationDTO{
+			Index:      citation.Index,
+			DocumentID: citation.DocumentID,
+			Title:      citation.Title,
+			URL:        citation.URL,
+			Snippet:    citation.Snippet,
+			Score:      citation.Score,
+			Cited:      citation.Cited,
+		}
+	case block.QuickReplies != nil:
+		dto.Data = QuickRepliesDTO{Options: block.QuickReplies.Options}
+	case block.HandoffNotice != nil:
+		dto.Data = HandoffNoticeDTO{
+			Reason:  block.HandoffNotice.Reason,
+			Message: block.HandoffNotice.Message,
+		}
+	case block.ActionConfirmation != nil:
+		action := block.ActionConfirmation
+		dto.Data = ActionConfirmationDTO{
+			ActionID:     action.ActionID,
+			Action:       string(action.Action),
+			ActionLabel:  action.ActionLabel,
+			OrderID:      action.OrderID,
+			Reason:       action.Reason,
+			ExpiresAt:    action.ExpiresAt,
+			ConfirmReply: action.ConfirmReply,
+			RejectReply:  action.RejectReply,
+		}
+	}
+
+	return dto
+}
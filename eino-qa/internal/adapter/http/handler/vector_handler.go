@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
 	"eino-qa/internal/usecase/vector"
 
 	"github.com/gin-gonic/gin"
@@ -31,10 +33,11 @@ type AddVectorRequestDTO struct {
 
 // AddVectorResponseDTO 添加向量响应 DTO
 type AddVectorResponseDTO struct {
-	Success     bool     `json:"success"`
-	DocumentIDs []string `json:"document_ids"`
-	Count       int      `json:"count"`
-	Message     string   `json:"message"`
+	Success        bool     `json:"success"`
+	DocumentIDs    []string `json:"document_ids"`
+	Count          int      `json:"count"`
+	QuarantinedIDs []string `json:"quarantined_ids,omitempty"`
+	Message        string   `json:"message"`
 }
 
 // DeleteVectorRequestDTO 删除向量请求 DTO
@@ -90,16 +93,20 @@ func (h *VectorHandler) HandleAddVectors(c *gin.Context) {
 	// 执行添加向量用例
 	resp, err := h.vectorUseCase.AddVectors(c.Request.Context(), useCaseReq)
 	if err != nil {
+		if errors.Is(err, entity.ErrSuspiciousDocument) {
+			err = middleware.NewBadRequestError(err.Error())
+		}
 		c.Error(err)
 		return
 	}
 
 	// 转换为 DTO
 	dto := &AddVectorResponseDTO{
-		Success:     resp.Success,
-		DocumentIDs: resp.DocumentIDs,
-		Count:       resp.Count,
-		Message:     resp.Message,
+		Success:        resp.Success,
+		DocumentIDs:    resp.DocumentIDs,
+		Count:          resp.Count,
+		QuarantinedIDs: resp.QuarantinedIDs,
+		Message:        resp.Message,
 	}
 
 	// 返回响应
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler Webhook 管理处理器
+type WebhookHandler struct {
+	webhookUseCase webhook.WebhookUseCaseInterface
+}
+
+// NewWebhookHandler 创建 Webhook 管理处理器
+func NewWebhookHandler(webhookUseCase webhook.WebhookUseCaseInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+// CreateWebhookRequestDTO 创建 Webhook 请求 DTO
+type CreateWebhookRequestDTO struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required"`
+}
+
+// WebhookEndpointDTO Webhook 端点 DTO
+type WebhookEndpointDTO struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	Secret    string    `json:"secret,omitempty"` // 仅在创建时返回
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDeadLetterDTO Webhook 死信 DTO
+type WebhookDeadLetterDTO struct {
+	ID         string    `json:"id"`
+	EndpointID string    `json:"endpoint_id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempts   int       `json:"attempts"`
+	LastError  string    `json:"last_error,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// HandleCreateWebhook 处理创建 Webhook 请求
+// POST /api/v1/webhooks
+func (h *WebhookHandler) HandleCreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequestDTO
+
+	// 解析请求体
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(middleware.NewBadRequestError(fmt.Sprintf("invalid request: %s", err.Error())))
+		return
+	}
+
+	endpoint, err := h.webhookUseCase.CreateEndpoint(c.Request.Context(), &webhook.CreateEndpointRequest{
+		TenantID: getTenantID(c),
+		URL:      req.URL,
+		Events:   req.Events,
+	})
+	if err != nil {
+		c.Error(toWebhookError(err))
+		return
+	}
+
+	dto := toWebhookEndpointDTO(endpoint)
+	dto.Secret = endpoint.Secret
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"webhook": dto,
+	})
+}
+
+// HandleListWebhooks 处理列出 Webhook 请求
+// GET /api/v1/webhooks
+func (h *WebhookHandler) HandleListWebhooks(c *gin.Context) {
+	endpoints, err := h.webhookUseCase.ListEndpoints(c.Request.Context(), getTenantID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]WebhookEndpointDTO, len(endpoints))
+	for i, endpoint := range endpoints {
+		dtos[i] = toWebhookEndpointDTO(endpoint)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"webhooks": dtos,
+	})
+}
+
+// HandleDeleteWebhook 处理删除 Webhook 请求
+// DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) HandleDeleteWebhook(c *gin.Context) {
+	if err := h.webhookUseCase.DeleteEndpoint(c.Request.Context(), getTenantID(c), c.Param("id")); err != nil {
+		c.Error(toWebhookError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// HandleListDeadLetters 处理列出死信请求
+// GET /api/v1/webhooks/dead-letters?offset=0&limit=20
+func (h *WebhookHandler) HandleListDeadLetters(c *gin.Context) {
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	deliveries, err := h.webhookUseCase.ListDeadLetters(c.Request.Context(), getTenantID(c), offset, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	dtos := make([]WebhookDeadLetterDTO, len(deliveries))
+	for i, delivery := range deliveries {
+		dtos[i] = toWebhookDeadLetterDTO(delivery)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"dead_letters": dtos,
+	})
+}
+
+// HandleReplayDeadLetter 处理重放死信请求
+// POST /api/v1/webhooks/dead-letters/:id/replay
+func (h *WebhookHandler) HandleReplayDeadLetter(c *gin.Context) {
+	delivery, err := h.webhookUseCase.ReplayDeadLetter(c.Request.Context(), getTenantID(c), c.Param("id"))
+	if err != nil {
+		if delivery == nil {
+			c.Error(toWebhookError(err))
+			return
+		}
+
+		// 重放失败但记录已更新，返回最新状态
+		c.JSON(http.StatusBadGateway, gin.H{
+			"success":     false,
+			"message":     err.Error(),
+			"dead_letter": toWebhookDeadLetterDTO(delivery),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"dead_letter": toWebhookDeadLetterDTO(delivery),
+	})
+}
+
+// getTenantID 从 context 获取租户 ID（由中间件设置）
+func getTenantID(c *gin.Context) string {
+	if tid, exists := c.Get("tenant_id"); exists {
+		if id, ok := tid.(string); ok && id != "" {
+			return id
+		}
+	}
+	return "default"
+}
+
+// toWebhookError 将领域错误映射为 HTTP 错误
+func toWebhookError(err error) error {
+	switch {
+	case errors.Is(err, entity.ErrWebhookNotFound):
+		return middleware.NewNotFoundError(err.Error())
+	case errors.Is(err, entity.ErrInvalidWebhookURL),
+		errors.Is(err, entity.ErrInvalidWebhookEvent),
+		errors.Is(err, entity.ErrEmptyWebhookEvents):
+		return middleware.NewBadRequestError(err.Error())
+	}
+	return err
+}
+
+// toWebhookEndpointDTO 转换端点 DTO（不含密钥）
+func toWebhookEndpointDTO(endpoint *entity.WebhookEndpoint) WebhookEndpointDTO {
+	events := make([]string, len(endpoint.Events))
+	for i, e := range endpoint.Events {
+		events[i] = string(e)
+	}
+
+	return WebhookEndpointDTO{
+		ID:        endpoint.ID,
+		URL:       endpoint.URL,
+		Events:    events,
+		Enabled:   endpoint.Enabled,
+		CreatedAt: endpoint.CreatedAt,
+	}
+}
+
+// toWebhookDeadLetterDTO 转换死信 DTO
+func toWebhookDeadLetterDTO(delivery *entity.WebhookDelivery) WebhookDeadLetterDTO {
+	return WebhookDeadLetterDTO{
+		ID:         delivery.ID,
+		EndpointID: delivery.EndpointID,
+		EventID:    delivery.EventID,
+		EventType:  string(delivery.EventType),
+		Attempts:   delivery.Attempts,
+		LastError:  delivery.LastError,
+		Status:     string(delivery.Status),
+		CreatedAt:  delivery.CreatedAt,
+		UpdatedAt:  delivery.UpdatedAt,
+	}
+}
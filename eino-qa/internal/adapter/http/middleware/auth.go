@@ -69,7 +69,8 @@ func (am *AuthMiddleware) Handler() gin.HandlerFunc {
 			return
 		}
 
-		// 验证通过，继续处理
+		// 验证通过，记录 API Key 供限流使用
+		c.Set("api_key", apiKey)
 		c.Next()
 	}
 }
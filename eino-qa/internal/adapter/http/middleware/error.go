@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -58,6 +59,12 @@ func ErrorHandler() gin.HandlerFunc {
 			// 确定状态码和错误消息
 			statusCode, message, details := determineErrorResponse(err)
 
+			// 限流错误告知客户端重试等待时间
+			var rateLimitErr *RateLimitError
+			if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > 0 {
+				c.Header("Retry-After", strconv.Itoa(rateLimitErr.RetryAfter))
+			}
+
 			// 如果还没有设置状态码，设置状态码
 			if c.Writer.Status() == http.StatusOK {
 				c.Status(statusCode)
@@ -107,6 +114,7 @@ func determineErrorResponse(err error) (statusCode int, message string, details
 	var forbiddenErr *ForbiddenError
 	var badRequestErr *BadRequestError
 	var serviceErr *ServiceError
+	var rateLimitErr *RateLimitError
 
 	switch {
 	case errors.As(err, &validationErr):
@@ -129,6 +137,10 @@ func determineErrorResponse(err error) (statusCode int, message string, details
 		statusCode = http.StatusBadGateway
 		message = serviceErr.Message
 		details = serviceErr.Service
+	case errors.As(err, &rateLimitErr):
+		statusCode = http.StatusTooManyRequests
+		message = rateLimitErr.Message
+		details = rateLimitErr.details()
 	default:
 		// 通用错误处理
 		message = err.Error()
@@ -230,3 +242,38 @@ func NewServiceError(message string, service string) *ServiceError {
 		Service: service,
 	}
 }
+
+// RateLimitError 超出限流错误
+type RateLimitError struct {
+	Message    string
+	Scope      string // 触发限流的维度：tenant、api_key、user、ip 或 llm_concurrency
+	Limit      int    // 窗口内允许的请求数，0 表示不适用
+	Window     string // 限流窗口
+	RetryAfter int    // 建议的重试等待秒数
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+// details 错误响应中的限流信息
+func (e *RateLimitError) details() map[string]interface{} {
+	details := map[string]interface{}{
+		"scope":       e.Scope,
+		"retry_after": e.RetryAfter,
+	}
+	if e.Limit > 0 {
+		details["limit"] = e.Limit
+		details["window"] = e.Window
+	}
+	return details
+}
+
+// NewRateLimitError 创建超出限流错误
+func NewRateLimitError(message string, scope string, retryAfter int) *RateLimitError {
+	return &RateLimitError{
+		Message:    message,
+		Scope:      scope,
+		RetryAfter: retryAfter,
+	}
+}
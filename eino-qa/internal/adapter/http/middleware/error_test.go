@@ -55,6 +55,12 @@ func TestErrorHandler_CustomErrors(t *testing.T) {
 			expectedStatus: http.StatusBadGateway,
 			expectedMsg:    "Service unavailable",
 		},
+		{
+			name:           "RateLimitError",
+			err:            NewRateLimitError("Too many concurrent requests", "llm_concurrency", 1),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedMsg:    "Too many concurrent requests",
+		},
 		{
 			name:           "Generic Error",
 			err:            errors.New("generic error"),
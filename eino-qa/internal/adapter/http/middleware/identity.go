@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 终端用户身份请求头
+const (
+	HeaderUserToken     = "X-User-Token"
+	HeaderUserID        = "X-User-ID"
+	HeaderUserSignature = "X-User-Signature"
+)
+
+// IdentityVerifier 终端用户身份校验接口
+type IdentityVerifier interface {
+	// VerifyToken 校验租户签发的 JWT，返回用户 ID
+	VerifyToken(tenantID, token string) (string, error)
+	// VerifyHeader 校验租户后端签名的用户 ID
+	VerifyHeader(tenantID, userID, signature string) error
+}
+
+// IdentityMiddleware 终端用户身份识别中间件
+// 支持 JWT（X-User-Token 或 Authorization: Bearer）以及 X-User-ID + X-User-Signature 两种方式；
+// 未携带身份信息时按匿名用户处理，携带但校验失败时返回 401
+type IdentityMiddleware struct {
+	verifier IdentityVerifier
+}
+
+// NewIdentityMiddleware 创建身份识别中间件
+func NewIdentityMiddleware(verifier IdentityVerifier) *IdentityMiddleware {
+	return &IdentityMiddleware{
+		verifier: verifier,
+	}
+}
+
+// Handler 返回 Gin 中间件处理函数
+func (im *IdentityMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetString("tenant_id")
+		if tenantID == "" {
+			tenantID = "default"
+		}
+
+		token := c.GetHeader(HeaderUserToken)
+		if token == "" {
+			auth := c.GetHeader("Authorization")
+			if strings.HasPrefix(auth, "Bearer ") {
+				token = strings.TrimPrefix(auth, "Bearer ")
+			}
+		}
+
+		var userID string
+		switch {
+		case token != "":
+			uid, err := im.verifier.VerifyToken(tenantID, token)
+			if err != nil {
+				im.reject(c, "Invalid user token")
+				return
+			}
+			userID = uid
+
+		case c.GetHeader(HeaderUserID) != "":
+			uid := c.GetHeader(HeaderUserID)
+			if err := im.verifier.VerifyHeader(tenantID, uid, c.GetHeader(HeaderUserSignature)); err != nil {
+				im.reject(c, "Invalid user signature")
+				return
+			}
+			userID = uid
+		}
+
+		// 匿名请求不设置用户 ID
+		if userID != "" {
+			c.Set("user_id", userID)
+			ctx := context.WithValue(c.Request.Context(), "user_id", userID)
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+	}
+}
+
+// reject 返回 401 响应
+func (im *IdentityMiddleware) reject(c *gin.Context, message string) {
+	c.JSON(http.StatusUnauthorized, ErrorResponse{
+		Code:    http.StatusUnauthorized,
+		Message: message,
+		TraceID: getTraceID(c),
+	})
+	c.Abort()
+}
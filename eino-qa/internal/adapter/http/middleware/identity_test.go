@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubVerifier 用于测试的身份校验器
+type stubVerifier struct{}
+
+func (stubVerifier) VerifyToken(tenantID, token string) (string, error) {
+	if tenantID == "tenant1" && token == "good-token" {
+		return "user-from-token", nil
+	}
+	return "", errors.New("invalid token")
+}
+
+func (stubVerifier) VerifyHeader(tenantID, userID, signature string) error {
+	if tenantID == "tenant1" && signature == "sig-"+userID {
+		return nil
+	}
+	return errors.New("invalid signature")
+}
+
+func TestIdentityMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+		expectedUserID string
+	}{
+		{
+			name:           "匿名请求",
+			headers:        map[string]string{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "有效的 X-User-Token",
+			headers:        map[string]string{HeaderUserToken: "good-token"},
+			expectedStatus: http.StatusOK,
+			expectedUserID: "user-from-token",
+		},
+		{
+			name:           "有效的 Authorization Bearer",
+			headers:        map[string]string{"Authorization": "Bearer good-token"},
+			expectedStatus: http.StatusOK,
+			expectedUserID: "user-from-token",
+		},
+		{
+			name:           "无效的 Token",
+			headers:        map[string]string{HeaderUserToken: "bad-token"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "有效的签名请求头",
+			headers:        map[string]string{HeaderUserID: "u1", HeaderUserSignature: "sig-u1"},
+			expectedStatus: http.StatusOK,
+			expectedUserID: "u1",
+		},
+		{
+			name:           "伪造的用户 ID",
+			headers:        map[string]string{HeaderUserID: "u2", HeaderUserSignature: "sig-u1"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(TenantMiddleware())
+			router.Use(NewIdentityMiddleware(stubVerifier{}).Handler())
+
+			router.GET("/test", func(c *gin.Context) {
+				userID := c.GetString("user_id")
+				assert.Equal(t, tt.expectedUserID, userID)
+
+				ctxUserID, _ := c.Request.Context().Value("user_id").(string)
+				assert.Equal(t, tt.expectedUserID, ctxUserID)
+
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-Tenant-ID", "tenant1")
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
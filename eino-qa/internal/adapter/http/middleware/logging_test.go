@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -21,20 +22,34 @@ type LogCall struct {
 	Fields  map[string]interface{}
 }
 
-func (m *MockLogger) Info(msg string, fields map[string]interface{}) {
+func (m *MockLogger) Info(ctx context.Context, msg string, fields map[string]interface{}) {
 	m.InfoCalls = append(m.InfoCalls, LogCall{
 		Message: msg,
-		Fields:  fields,
+		Fields:  withContextFields(ctx, fields),
 	})
 }
 
-func (m *MockLogger) Error(msg string, fields map[string]interface{}) {
+func (m *MockLogger) Error(ctx context.Context, msg string, fields map[string]interface{}) {
 	m.ErrorCalls = append(m.ErrorCalls, LogCall{
 		Message: msg,
-		Fields:  fields,
+		Fields:  withContextFields(ctx, fields),
 	})
 }
 
+// withContextFields 与真实 Logger 一致，将 context 中的请求标识合并到日志字段
+func withContextFields(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(fields)+2)
+	for _, key := range []string{"request_id", "tenant_id"} {
+		if v := ctx.Value(key); v != nil {
+			merged[key] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
 func TestLoggingMiddleware_BasicRequest(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,292 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 限流维度
+const (
+	RateLimitScopeTenant = "tenant"
+	RateLimitScopeAPIKey = "api_key"
+	RateLimitScopeUser   = "user"
+	RateLimitScopeIP     = "ip"
+)
+
+// rateLimitSweepInterval 清理空闲令牌桶的间隔
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit 单个维度的令牌桶限额
+type RateLimit struct {
+	RequestsPerMinute int // 每分钟补充的令牌数，0 表示不限制
+	Burst             int // 桶容量，0 表示等于 RequestsPerMinute
+}
+
+// RateLimitPolicy 租户的限流策略
+type RateLimitPolicy struct {
+	Enabled bool
+	Tenant  RateLimit // 按租户
+	APIKey  RateLimit // 按通过认证的 API Key
+	User    RateLimit // 按已识别身份的终端用户
+	IP      RateLimit // 按客户端 IP
+}
+
+// RateLimitPolicyProvider 返回租户的限流策略
+type RateLimitPolicyProvider func(tenantID string) RateLimitPolicy
+
+// RateLimitMiddleware 限流中间件
+// 按租户、API Key、终端用户和客户端 IP 分别使用令牌桶，任一维度的令牌不足时返回 429，
+// 只有全部维度都有令牌时才扣减，被拒绝的请求不消耗其他维度的额度。
+// 需要放在租户识别、API Key 认证和身份识别中间件之后
+type RateLimitMiddleware struct {
+	policies       RateLimitPolicyProvider
+	trustedProxies []*net.IPNet
+	now            func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewRateLimitMiddleware 创建限流中间件
+func NewRateLimitMiddleware(policies RateLimitPolicyProvider) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		policies: policies,
+		now:      time.Now,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// WithTrustedProxies 设置可信反向代理（IP 或 CIDR），无效的条目被忽略
+// 只有对端地址是可信代理的请求才按 X-Forwarded-For 识别客户端 IP，未设置时使用连接的对端地址
+func (rl *RateLimitMiddleware) WithTrustedProxies(proxies []string) *RateLimitMiddleware {
+	rl.trustedProxies = nil
+	for _, proxy := range proxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			rl.trustedProxies = append(rl.trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			rl.trustedProxies = append(rl.trustedProxies, network)
+		}
+	}
+	return rl
+}
+
+// clientIP 识别按 IP 限流使用的客户端 IP
+// 不使用 gin 的 ClientIP：它无条件信任 X-Forwarded-For 和 X-Real-IP，伪造这些头即可换一个令牌桶。
+// 对端地址是可信代理时，从右向左取 X-Forwarded-For 中第一个不是可信代理的地址
+func (rl *RateLimitMiddleware) clientIP(c *gin.Context) string {
+	remote := strings.TrimSpace(c.Request.RemoteAddr)
+	ip, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		ip = remote
+	}
+	if !rl.trusted(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(c.Request.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !rl.trusted(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// trusted 判断地址是否是可信代理
+func (rl *RateLimitMiddleware) trusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range rl.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenBucket 令牌桶，令牌按速率连续补充
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64 // 每秒补充的令牌数
+	updated  time.Time
+}
+
+// refill 按经过的时间补充令牌，限额变化时按新限额调整
+func (b *tokenBucket) refill(limit RateLimit, now time.Time) {
+	b.capacity = float64(limit.burst())
+	b.rate = float64(limit.RequestsPerMinute) / 60
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.updated).Seconds()*b.rate)
+	b.updated = now
+}
+
+// untilAvailable 距离有一个可用令牌的时间
+func (b *tokenBucket) untilAvailable() time.Duration {
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// untilFull 距离桶重新装满的时间
+func (b *tokenBucket) untilFull() time.Duration {
+	return time.Duration((b.capacity - b.tokens) / b.rate * float64(time.Second))
+}
+
+// burst 桶容量
+func (l RateLimit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.RequestsPerMinute
+}
+
+// rateLimitCheck 单个维度的检查项
+type rateLimitCheck struct {
+	scope  string
+	key    string
+	limit  RateLimit
+	bucket *tokenBucket
+}
+
+// Handler 返回 Gin 中间件处理函数
+func (rl *RateLimitMiddleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetString("tenant_id")
+		if tenantID == "" {
+			tenantID = "default"
+		}
+
+		policy := rl.policies(tenantID)
+		if !policy.Enabled {
+			c.Next()
+			return
+		}
+
+		checks := []rateLimitCheck{
+			{scope: RateLimitScopeTenant, key: tenantID, limit: policy.Tenant},
+			{scope: RateLimitScopeAPIKey, key: c.GetString("api_key"), limit: policy.APIKey},
+			{scope: RateLimitScopeIP, key: rl.clientIP(c), limit: policy.IP},
+		}
+		if userID := c.GetString("user_id"); userID != "" {
+			checks = append(checks, rateLimitCheck{scope: RateLimitScopeUser, key: tenantID + ":" + userID, limit: policy.User})
+		}
+
+		now := rl.now()
+		tightest, rejected := rl.take(checks, now)
+		if tightest == nil {
+			c.Next()
+			return
+		}
+
+		bucket := tightest.bucket
+		c.Header("X-RateLimit-Limit", strconv.Itoa(tightest.limit.RequestsPerMinute))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(int(bucket.tokens)))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(now.Add(bucket.untilFull()).Unix(), 10))
+
+		if rejected {
+			retryAfter := int(math.Ceil(bucket.untilAvailable().Seconds()))
+			err := &RateLimitError{
+				Message:    "Rate limit exceeded",
+				Scope:      tightest.scope,
+				Limit:      tightest.limit.RequestsPerMinute,
+				Window:     "1m",
+				RetryAfter: max(retryAfter, 1),
+			}
+			c.Header("Retry-After", strconv.Itoa(err.RetryAfter))
+
+			statusCode, message, details := determineErrorResponse(err)
+			c.JSON(statusCode, ErrorResponse{
+				Code:    statusCode,
+				Message: message,
+				Details: details,
+				TraceID: getTraceID(c),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// take 检查各维度的令牌桶，全部有令牌时各扣减一个
+// 返回剩余令牌最少的检查项（被拒绝时为需要等待最久的检查项），没有生效的限额时返回 nil
+func (rl *RateLimitMiddleware) take(checks []rateLimitCheck, now time.Time) (*rateLimitCheck, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.sweep(now)
+
+	var active []*rateLimitCheck
+	for i := range checks {
+		check := &checks[i]
+		if check.key == "" || check.limit.RequestsPerMinute <= 0 {
+			continue
+		}
+
+		key := check.scope + ":" + check.key
+		bucket, ok := rl.buckets[key]
+		if !ok {
+			bucket = &tokenBucket{tokens: float64(check.limit.burst()), updated: now}
+			rl.buckets[key] = bucket
+		}
+		bucket.refill(check.limit, now)
+		check.bucket = bucket
+		active = append(active, check)
+	}
+	if len(active) == 0 {
+		return nil, false
+	}
+
+	// 先检查全部维度，任一不足时整体拒绝
+	var blocked *rateLimitCheck
+	for _, check := range active {
+		if check.bucket.tokens < 1 && (blocked == nil || check.bucket.untilAvailable() > blocked.bucket.untilAvailable()) {
+			blocked = check
+		}
+	}
+	if blocked != nil {
+		return blocked, true
+	}
+
+	tightest := active[0]
+	for _, check := range active {
+		check.bucket.tokens--
+		if check.bucket.tokens < tightest.bucket.tokens {
+			tightest = check
+		}
+	}
+	return tightest, false
+}
+
+// sweep 定期删除已经装满的令牌桶，这些桶与重新创建的桶没有区别
+func (rl *RateLimitMiddleware) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	rl.lastSweep = now
+
+	for key, bucket := range rl.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*bucket.rate >= bucket.capacity {
+			delete(rl.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitRouter 创建带租户识别和限流的测试路由，X-Test-User 模拟已识别身份的终端用户
+func newRateLimitRouter(rl *RateLimitMiddleware) *gin.Engine {
+	router := gin.New()
+	router.Use(TenantMiddleware())
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	router.Use(rl.Handler())
+	router.POST("/chat", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return router
+}
+
+func doRateLimitRequest(router *gin.Engine, tenantID, userID, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/chat", nil)
+	req.Header.Set("X-Tenant-ID", tenantID)
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	req.RemoteAddr = ip + ":12345"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware_Tenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	rl := NewRateLimitMiddleware(func(tenantID string) RateLimitPolicy {
+		return RateLimitPolicy{
+			Enabled: tenantID != "unlimited",
+			Tenant:  RateLimit{RequestsPerMinute: 60, Burst: 2},
+		}
+	})
+	rl.now = func() time.Time { return now }
+	router := newRateLimitRouter(rl)
+
+	w := doRateLimitRequest(router, "tenant1", "", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "60", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1700000001", w.Header().Get("X-RateLimit-Reset"))
+
+	w = doRateLimitRequest(router, "tenant1", "", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// 桶已空，换 IP 也不能绕过租户限额
+	w = doRateLimitRequest(router, "tenant1", "", "10.0.0.3")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "Rate limit exceeded", resp.Message)
+	assert.Equal(t, map[string]interface{}{
+		"scope":       "tenant",
+		"limit":       float64(60),
+		"window":      "1m",
+		"retry_after": float64(1),
+	}, resp.Details)
+
+	// 其他租户和关闭限流的租户不受影响
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(router, "tenant2", "", "10.0.0.1").Code)
+	w = doRateLimitRequest(router, "unlimited", "", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+
+	// 一秒后补充一个令牌
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(router, "tenant1", "", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRateLimitRequest(router, "tenant1", "", "10.0.0.1").Code)
+}
+
+func TestRateLimitMiddleware_UserAndIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	rl := NewRateLimitMiddleware(func(string) RateLimitPolicy {
+		return RateLimitPolicy{
+			Enabled: true,
+			Tenant:  RateLimit{RequestsPerMinute: 100},
+			User:    RateLimit{RequestsPerMinute: 1},
+			IP:      RateLimit{RequestsPerMinute: 3},
+		}
+	})
+	rl.now = func() time.Time { return now }
+	router := newRateLimitRouter(rl)
+
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(router, "tenant1", "alice", "10.0.0.1").Code)
+
+	w := doRateLimitRequest(router, "tenant1", "alice", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"scope":"user"`)
+
+	// 被拒绝的请求不消耗其他维度的额度：同一 IP 还剩 2 个令牌
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(router, "tenant1", "bob", "10.0.0.1").Code)
+	w = doRateLimitRequest(router, "tenant1", "", "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = doRateLimitRequest(router, "tenant1", "", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"scope":"ip"`)
+
+	// 用户身份按租户区分
+	assert.Equal(t, http.StatusOK, doRateLimitRequest(router, "tenant2", "alice", "10.0.0.2").Code)
+}
+
+func TestRateLimitMiddleware_ForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(trustedProxies ...string) *gin.Engine {
+		rl := NewRateLimitMiddleware(func(string) RateLimitPolicy {
+			return RateLimitPolicy{Enabled: true, IP: RateLimit{RequestsPerMinute: 1}}
+		}).WithTrustedProxies(trustedProxies)
+		return newRateLimitRouter(rl)
+	}
+	request := func(router *gin.Engine, remoteIP, forwardedFor string) int {
+		req := httptest.NewRequest("POST", "/chat", nil)
+		req.RemoteAddr = remoteIP + ":12345"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.Header.Set("X-Real-IP", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 未配置可信代理：伪造的 X-Forwarded-For 不会换一个令牌桶
+	router := newRouter()
+	assert.Equal(t, http.StatusOK, request(router, "10.0.0.1", "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request(router, "10.0.0.1", "203.0.113.2"))
+
+	// 来自可信代理的请求按代理追加的客户端 IP 计数，客户端在前面伪造的地址被忽略
+	router = newRouter("192.0.2.0/24", "invalid")
+	assert.Equal(t, http.StatusOK, request(router, "192.0.2.1", "203.0.113.1"))
+	assert.Equal(t, http.StatusOK, request(router, "192.0.2.2", "203.0.113.2"))
+	assert.Equal(t, http.StatusTooManyRequests, request(router, "192.0.2.1", "198.51.100.9, 203.0.113.1"))
+
+	// 不是可信代理的对端地址即使携带 X-Forwarded-For 也按对端地址计数
+	assert.Equal(t, http.StatusOK, request(router, "10.0.0.2", "203.0.113.3"))
+	assert.Equal(t, http.StatusTooManyRequests, request(router, "10.0.0.2", "203.0.113.4"))
+}
+
+func TestRateLimitMiddleware_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rl := NewRateLimitMiddleware(func(string) RateLimitPolicy {
+		return RateLimitPolicy{Enabled: true, APIKey: RateLimit{RequestsPerMinute: 1}}
+	})
+	router := gin.New()
+	router.Use(TenantMiddleware())
+	router.Use(NewAuthMiddleware([]string{"key1", "key2"}).Handler())
+	router.Use(rl.Handler())
+	router.GET("/api/v1/vectors/count", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"count": 0})
+	})
+
+	request := func(apiKey string) int {
+		req := httptest.NewRequest("GET", "/api/v1/vectors/count", nil)
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("key1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("key1"))
+	assert.Equal(t, http.StatusOK, request("key2"))
+}
+
+func TestRateLimitMiddleware_Sweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rl := NewRateLimitMiddleware(func(string) RateLimitPolicy { return RateLimitPolicy{} })
+
+	limit := RateLimit{RequestsPerMinute: 1}
+	rl.take([]rateLimitCheck{{scope: RateLimitScopeIP, key: "10.0.0.1", limit: limit}}, now)
+	rl.take([]rateLimitCheck{{scope: RateLimitScopeIP, key: "10.0.0.2", limit: limit}}, now.Add(50*time.Second))
+	require.Len(t, rl.buckets, 2)
+
+	// 10.0.0.1 的桶已经装满，10.0.0.2 的还没有
+	rl.take(nil, now.Add(65*time.Second))
+	assert.Len(t, rl.buckets, 1)
+	assert.Contains(t, rl.buckets, "ip:10.0.0.2")
+}
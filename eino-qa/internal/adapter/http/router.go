@@ -10,18 +10,28 @@ import (
 // RouterConfig 路由配置
 type RouterConfig struct {
 	// Handlers
-	ChatHandler   *handler.ChatHandler
-	VectorHandler *handler.VectorHandler
-	HealthHandler *handler.HealthHandler
-	ModelHandler  *handler.ModelHandler
+	ChatHandler        *handler.ChatHandler
+	VectorHandler      *handler.VectorHandler
+	HealthHandler      *handler.HealthHandler
+	ModelHandler       *handler.ModelHandler
+	WebhookHandler     *handler.WebhookHandler
+	MissedQueryHandler *handler.MissedQueryHandler
+	FeedbackHandler    *handler.FeedbackHandler
+	OrderImportHandler *handler.OrderImportHandler
+	PromptHandler      *handler.PromptHandler
+	ExperimentHandler  *handler.ExperimentHandler
+	FAQHandler         *handler.FAQHandler
+	QuarantineHandler  *handler.QuarantineHandler
 
 	// Middlewares
-	TenantMiddleware   gin.HandlerFunc
-	SecurityMiddleware gin.HandlerFunc
-	LoggingMiddleware  gin.HandlerFunc
-	MetricsMiddleware  gin.HandlerFunc
-	ErrorMiddleware    gin.HandlerFunc
-	AuthMiddleware     gin.HandlerFunc
+	TenantMiddleware    gin.HandlerFunc
+	SecurityMiddleware  gin.HandlerFunc
+	LoggingMiddleware   gin.HandlerFunc
+	MetricsMiddleware   gin.HandlerFunc
+	ErrorMiddleware     gin.HandlerFunc
+	AuthMiddleware      gin.HandlerFunc
+	IdentityMiddleware  gin.HandlerFunc // 终端用户身份识别（对话和反馈接口）
+	RateLimitMiddleware gin.HandlerFunc // 限流（在认证和身份识别之后）
 
 	// Config
 	Mode   string // "debug", "release", "test"
@@ -81,10 +91,25 @@ func SetupRouter(config *RouterConfig) *gin.Engine {
 		}
 	}
 
-	// 对话接口（不需要认证，但需要租户识别）
-	// 需求: 6.1, 6.2, 6.3, 6.4, 6.5
-	if config.ChatHandler != nil {
-		router.POST("/chat", config.ChatHandler.HandleChat)
+	// 终端用户接口（不需要 API Key，但需要租户识别，可携带终端用户身份）
+	userGroup := router.Group("")
+	if config.IdentityMiddleware != nil {
+		userGroup.Use(config.IdentityMiddleware)
+	}
+	if config.RateLimitMiddleware != nil {
+		userGroup.Use(config.RateLimitMiddleware)
+	}
+	{
+		// 对话接口
+		// 需求: 6.1, 6.2, 6.3, 6.4, 6.5
+		if config.ChatHandler != nil {
+			userGroup.POST("/chat", config.ChatHandler.HandleChat)
+		}
+
+		// 反馈接口
+		if config.FeedbackHandler != nil {
+			userGroup.POST("/feedback", config.FeedbackHandler.HandleSubmitFeedback)
+		}
 	}
 
 	// API v1 路由组（需要 API Key 认证）
@@ -92,6 +117,9 @@ func SetupRouter(config *RouterConfig) *gin.Engine {
 	if config.AuthMiddleware != nil {
 		apiV1.Use(config.AuthMiddleware)
 	}
+	if config.RateLimitMiddleware != nil {
+		apiV1.Use(config.RateLimitMiddleware)
+	}
 	{
 		// 向量管理接口
 		// 需求: 9.1, 9.2, 9.3, 9.4, 9.5
@@ -104,6 +132,86 @@ func SetupRouter(config *RouterConfig) *gin.Engine {
 				vectorGroup.GET("/items/:id", config.VectorHandler.HandleGetVector)
 			}
 		}
+
+		// 隔离文档管理接口（疑似提示词注入、未写入知识库的文档）
+		if config.QuarantineHandler != nil {
+			quarantineGroup := apiV1.Group("/vectors/quarantine")
+			{
+				quarantineGroup.GET("", config.QuarantineHandler.HandleListQuarantined)
+				quarantineGroup.POST("/:id/release", config.QuarantineHandler.HandleReleaseQuarantined)
+				quarantineGroup.DELETE("/:id", config.QuarantineHandler.HandleDeleteQuarantined)
+			}
+		}
+
+		// Webhook 管理接口
+		if config.WebhookHandler != nil {
+			webhookGroup := apiV1.Group("/webhooks")
+			{
+				webhookGroup.POST("", config.WebhookHandler.HandleCreateWebhook)
+				webhookGroup.GET("", config.WebhookHandler.HandleListWebhooks)
+				webhookGroup.DELETE("/:id", config.WebhookHandler.HandleDeleteWebhook)
+				webhookGroup.GET("/dead-letters", config.WebhookHandler.HandleListDeadLetters)
+				webhookGroup.POST("/dead-letters/:id/replay", config.WebhookHandler.HandleReplayDeadLetter)
+			}
+		}
+
+		// 知识库未命中分析接口
+		if config.MissedQueryHandler != nil {
+			missedGroup := apiV1.Group("/missed-queries")
+			{
+				missedGroup.GET("", config.MissedQueryHandler.HandleListMissedQueries)
+				missedGroup.GET("/clusters", config.MissedQueryHandler.HandleClusterMissedQueries)
+				missedGroup.POST("/clusters/draft", config.MissedQueryHandler.HandleDraftFAQ)
+			}
+		}
+
+		// 反馈管理接口
+		if config.FeedbackHandler != nil {
+			feedbackGroup := apiV1.Group("/feedback")
+			{
+				feedbackGroup.GET("", config.FeedbackHandler.HandleListFeedback)
+				feedbackGroup.POST("/:id/promote", config.FeedbackHandler.HandlePromoteFeedback)
+			}
+		}
+
+		// 订单导入与同步接口
+		if config.OrderImportHandler != nil {
+			ordersGroup := apiV1.Group("/orders")
+			{
+				ordersGroup.POST("/import", config.OrderImportHandler.HandleImportOrders)
+				ordersGroup.POST("/sync", config.OrderImportHandler.HandleSyncOrders)
+				ordersGroup.GET("/sync/status", config.OrderImportHandler.HandleGetSyncStatus)
+			}
+		}
+
+		// 提示词模板管理接口
+		if config.PromptHandler != nil {
+			promptsGroup := apiV1.Group("/prompts")
+			{
+				promptsGroup.GET("", config.PromptHandler.HandleListPrompts)
+				promptsGroup.GET("/:name", config.PromptHandler.HandleGetPrompt)
+				promptsGroup.POST("/:name", config.PromptHandler.HandleCreatePromptVersion)
+				promptsGroup.POST("/:name/versions/:version/activate", config.PromptHandler.HandleActivatePromptVersion)
+				promptsGroup.POST("/:name/rollback", config.PromptHandler.HandleRollbackPrompt)
+			}
+		}
+
+		// A/B 实验结果
+		if config.ExperimentHandler != nil {
+			apiV1.GET("/experiments", config.ExperimentHandler.HandleListExperiments)
+		}
+
+		// 标准问答管理接口
+		if config.FAQHandler != nil {
+			faqGroup := apiV1.Group("/faqs")
+			{
+				faqGroup.POST("", config.FAQHandler.HandleCreateFAQ)
+				faqGroup.GET("", config.FAQHandler.HandleListFAQs)
+				faqGroup.GET("/:id", config.FAQHandler.HandleGetFAQ)
+				faqGroup.PUT("/:id", config.FAQHandler.HandleUpdateFAQ)
+				faqGroup.DELETE("/:id", config.FAQHandler.HandleDeleteFAQ)
+			}
+		}
 	}
 
 	// 模型管理接口（需要 API Key 认证）
@@ -112,6 +220,9 @@ func SetupRouter(config *RouterConfig) *gin.Engine {
 		if config.AuthMiddleware != nil {
 			modelsGroup.Use(config.AuthMiddleware)
 		}
+		if config.RateLimitMiddleware != nil {
+			modelsGroup.Use(config.RateLimitMiddleware)
+		}
 		{
 			modelsGroup.GET("", config.ModelHandler.HandleListModels)
 			modelsGroup.GET("/current", config.ModelHandler.HandleGetCurrentModel)
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CachedAnswer 语义回答缓存中的一条回答
+// 回答只在生成时的知识库版本下有效，知识库变更后整体失效
+type CachedAnswer struct {
+	Query       string     // 规范化后的问题
+	Vector      []float32  // 问题的嵌入向量
+	Route       IntentType // 回答的路由
+	Confidence  float64    // 生成回答时的意图置信度
+	Answer      string
+	Sources     []*Document
+	Suggestions []string
+	KBVersion   uint64 // 生成回答时的知识库版本
+	Hits        int64
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// NewCachedAnswer 创建缓存回答，ttl 为 0 表示不过期
+func NewCachedAnswer(query string, vector []float32, route IntentType, answer string, kbVersion uint64, ttl time.Duration) *CachedAnswer {
+	now := time.Now()
+	cached := &CachedAnswer{
+		Query:     NormalizeCacheQuery(query),
+		Vector:    vector,
+		Route:     route,
+		Answer:    answer,
+		KBVersion: kbVersion,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		cached.ExpiresAt = now.Add(ttl)
+	}
+	return cached
+}
+
+// IsExpired 判断缓存回答是否已过期
+func (a *CachedAnswer) IsExpired() bool {
+	return !a.ExpiresAt.IsZero() && time.Now().After(a.ExpiresAt)
+}
+
+// NormalizeCacheQuery 规范化用于语义缓存的问题：
+// 忽略大小写、合并空白、去掉首尾空白和句末标点
+func NormalizeCacheQuery(query string) string {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	return strings.TrimRightFunc(query, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+}
@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SlotOrderID 内置的订单号槽位
+const SlotOrderID = "order_id"
+
+// DefaultExpectedSlotTTL 等待用户填写槽位的默认有效期
+const DefaultExpectedSlotTTL = 5 * time.Minute
+
+// 槽位在 Session.Metadata 中的键
+const (
+	// SessionKeyExpectedSlot 正在等待用户填写的槽位
+	SessionKeyExpectedSlot = "expected_slot"
+	// SessionKeySlots 已填写的槽位值
+	SessionKeySlots = "slots"
+)
+
+// SlotDefinition 对话槽位定义
+// 槽位缺失时向用户追问，用户下一轮的回复直接填充槽位并恢复原意图的处理流程
+type SlotDefinition struct {
+	Name        string     // 槽位名称，如 "order_id"、"phone"
+	Intent      IntentType // 槽位所属意图，填充后恢复该意图的处理
+	Prompt      string     // 追问话术
+	Required    bool       // 为 true 时该意图处理前必须先填写此槽位
+	MaxAttempts int        // 回复无法填充槽位时重新追问的次数（0 表示不重试，按正常对话处理）
+	pattern     *regexp.Regexp
+}
+
+// NewSlotDefinition 创建槽位定义
+// pattern 为空时：订单号槽位按租户的订单号方案提取，其他槽位接受任意非空回复
+func NewSlotDefinition(name string, intent IntentType, prompt, pattern string) (*SlotDefinition, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrEmptySlotName
+	}
+
+	if err := NewIntent(intent, 1.0).Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, intent)
+	}
+
+	if strings.TrimSpace(prompt) == "" {
+		return nil, fmt.Errorf("%w: %s", ErrEmptySlotPrompt, name)
+	}
+
+	slot := &SlotDefinition{
+		Name:   name,
+		Intent: intent,
+		Prompt: prompt,
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot pattern for %s: %w", name, err)
+		}
+		slot.pattern = re
+	}
+
+	return slot, nil
+}
+
+// Fill 从用户回复中提取槽位值
+func (s *SlotDefinition) Fill(tenantID, reply string) (string, bool) {
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return "", false
+	}
+
+	if s.pattern != nil {
+		value := s.pattern.FindString(reply)
+		return value, value != ""
+	}
+
+	if s.Name == SlotOrderID {
+		return OrderIDSchemeFor(tenantID).Extract(reply)
+	}
+
+	return reply, true
+}
+
+// DefaultSlotDefinitions 内置槽位定义，租户可以定义同名槽位覆盖
+func DefaultSlotDefinitions() []*SlotDefinition {
+	return []*SlotDefinition{
+		{
+			Name:   SlotOrderID,
+			Intent: IntentOrder,
+			Prompt: "请提供您的订单号。",
+		},
+	}
+}
+
+// ExpectedSlot 会话中正在等待用户填写的槽位
+type ExpectedSlot struct {
+	Name      string     `json:"name"`
+	Intent    IntentType `json:"intent"`
+	Query     string     `json:"query"` // 触发追问的原始问题，填充后与槽位值一起恢复处理
+	Attempts  int        `json:"attempts"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// IsExpired 判断是否已过等待有效期
+func (e *ExpectedSlot) IsExpired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
+
+// SetExpectedSlot 在会话中记录等待用户填写的槽位
+func (s *Session) SetExpectedSlot(slot *ExpectedSlot) {
+	s.AddMetadata(SessionKeyExpectedSlot, slot)
+}
+
+// ExpectedSlot 获取会话中正在等待填写的槽位，不存在或无法解析时返回 nil
+func (s *Session) ExpectedSlot() *ExpectedSlot {
+	value, ok := s.Metadata[SessionKeyExpectedSlot]
+	if !ok || value == nil {
+		return nil
+	}
+
+	if slot, ok := value.(*ExpectedSlot); ok {
+		return slot
+	}
+
+	var slot ExpectedSlot
+	if !decodeMetadata(value, &slot) || slot.Name == "" {
+		return nil
+	}
+	return &slot
+}
+
+// ClearExpectedSlot 清除会话中等待填写的槽位
+func (s *Session) ClearExpectedSlot() {
+	delete(s.Metadata, SessionKeyExpectedSlot)
+}
+
+// SetSlotValue 记录已填写的槽位值，供后续轮次使用
+func (s *Session) SetSlotValue(name, value string) {
+	slots := s.SlotValues()
+	slots[name] = value
+	s.AddMetadata(SessionKeySlots, slots)
+}
+
+// SlotValue 获取已填写的槽位值
+func (s *Session) SlotValue(name string) string {
+	return s.SlotValues()[name]
+}
+
+// SlotValues 获取所有已填写的槽位值
+func (s *Session) SlotValues() map[string]string {
+	slots := make(map[string]string)
+	value, ok := s.Metadata[SessionKeySlots]
+	if !ok || value == nil {
+		return slots
+	}
+
+	if m, ok := value.(map[string]string); ok {
+		for k, v := range m {
+			slots[k] = v
+		}
+		return slots
+	}
+
+	decodeMetadata(value, &slots)
+	return slots
+}
+
+// decodeMetadata 将会话从存储加载后 JSON 解码得到的 map 转换为目标类型
+func decodeMetadata(value any, target any) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, target) == nil
+}
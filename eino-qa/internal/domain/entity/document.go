@@ -1,6 +1,24 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// Document.Metadata 中的保留键
+const (
+	// DocumentKeyCited 回答是否引用了该文档
+	DocumentKeyCited = "cited"
+	// DocumentKeyChunkIndex 文档在同一批入库文本中的序号
+	DocumentKeyChunkIndex = "chunk_index"
+	// DocumentKeyPrevChunk 同一批入库文本中前一个分块的文档 ID
+	DocumentKeyPrevChunk = "prev_chunk_id"
+	// DocumentKeyNextChunk 同一批入库文本中后一个分块的文档 ID
+	DocumentKeyNextChunk = "next_chunk_id"
+)
+
+// maxDocumentTitle 没有标题元数据时，从内容截取的标题长度（字符数）
+const maxDocumentTitle = 30
 
 // Document 表示知识库中的文档
 type Document struct {
@@ -69,6 +87,52 @@ func (d *Document) GetMetadata(key string) (any, bool) {
 	return value, exists
 }
 
+// Title 返回文档标题
+// 依次取元数据 title、question，都没有时截取内容开头
+func (d *Document) Title() string {
+	for _, key := range []string{"title", "question"} {
+		if value, ok := d.GetMetadata(key); ok {
+			if title, ok := value.(string); ok && strings.TrimSpace(title) != "" {
+				return strings.TrimSpace(title)
+			}
+		}
+	}
+
+	runes := []rune(strings.TrimSpace(d.Content))
+	if len(runes) <= maxDocumentTitle {
+		return string(runes)
+	}
+	return string(runes[:maxDocumentTitle]) + "…"
+}
+
+// SetCited 标记回答是否引用了该文档
+func (d *Document) SetCited(cited bool) {
+	d.AddMetadata(DocumentKeyCited, cited)
+}
+
+// Cited 返回回答是否引用了该文档，未做引用校验时 checked 为 false
+func (d *Document) Cited() (cited bool, checked bool) {
+	value, ok := d.GetMetadata(DocumentKeyCited)
+	if !ok {
+		return false, false
+	}
+	cited, checked = value.(bool)
+	return cited, checked
+}
+
+// NeighbourChunkIDs 返回相邻分块的文档 ID（前一个、后一个，不存在的省略）
+func (d *Document) NeighbourChunkIDs() []string {
+	var ids []string
+	for _, key := range []string{DocumentKeyPrevChunk, DocumentKeyNextChunk} {
+		if value, ok := d.GetMetadata(key); ok {
+			if id, ok := value.(string); ok && id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
 // generateDocumentID 生成文档 ID
 func generateDocumentID() string {
 	return "doc_" + time.Now().Format("20060102150405") + randomString(12)
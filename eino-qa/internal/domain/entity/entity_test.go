@@ -1,6 +1,10 @@
 package entity
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -110,6 +114,32 @@ func TestDocumentCreation(t *testing.T) {
 	}
 }
 
+// TestDocumentTitleAndCited 测试文档标题和引用标记
+func TestDocumentTitleAndCited(t *testing.T) {
+	doc := NewDocument(strings.Repeat("课", 40), "tenant1")
+	if title := doc.Title(); title != strings.Repeat("课", 30)+"…" {
+		t.Errorf("Expected truncated content title, got '%s'", title)
+	}
+
+	doc.AddMetadata("question", "如何退款？")
+	if title := doc.Title(); title != "如何退款？" {
+		t.Errorf("Expected question title, got '%s'", title)
+	}
+
+	doc.AddMetadata("title", "退款政策")
+	if title := doc.Title(); title != "退款政策" {
+		t.Errorf("Expected metadata title, got '%s'", title)
+	}
+
+	if _, checked := doc.Cited(); checked {
+		t.Error("Expected document without citation check")
+	}
+	doc.SetCited(true)
+	if cited, checked := doc.Cited(); !cited || !checked {
+		t.Error("Expected document to be cited")
+	}
+}
+
 // TestOrderCreation 测试订单创建
 func TestOrderCreation(t *testing.T) {
 	order := NewOrder("user123", "Python Course", 99.99, "tenant1")
@@ -155,6 +185,161 @@ func TestOrderStatusUpdate(t *testing.T) {
 	}
 }
 
+// TestOrderStateMachine 测试订单状态机
+func TestOrderStateMachine(t *testing.T) {
+	order := NewOrder("user123", "Python Course", 99.99, "tenant1")
+
+	// 待支付订单不能直接申请退款
+	if err := order.UpdateStatus(OrderStatusRefundRequested); !errors.Is(err, ErrInvalidOrderTransition) {
+		t.Errorf("Expected ErrInvalidOrderTransition, got %v", err)
+	}
+
+	for _, to := range []OrderStatus{OrderStatusPaid, OrderStatusRefundRequested, OrderStatusRefunded} {
+		from := order.Status
+		event, err := order.Transition(to, "user:user123", "test")
+		if err != nil {
+			t.Fatalf("Failed to transition %s -> %s: %v", from, to, err)
+		}
+		if event.FromStatus != from || event.ToStatus != to || event.OrderID != order.ID {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+		if err := event.Validate(); err != nil {
+			t.Errorf("Event failed validation: %v", err)
+		}
+	}
+
+	// 已退款是终态，不能回到待支付
+	if !order.Status.IsTerminal() {
+		t.Error("Expected refunded to be terminal")
+	}
+	if _, err := order.Transition(OrderStatusPending, "admin", "reopen"); !errors.Is(err, ErrInvalidOrderTransition) {
+		t.Errorf("Expected ErrInvalidOrderTransition, got %v", err)
+	}
+	if order.Status != OrderStatusRefunded {
+		t.Errorf("Expected status to stay 'refunded', got '%s'", order.Status)
+	}
+
+	// 已支付订单不能取消
+	paid := NewOrder("user123", "Go Course", 99, "tenant1")
+	paid.Status = OrderStatusPaid
+	if err := paid.UpdateStatus(OrderStatusCancelled); !errors.Is(err, ErrInvalidOrderTransition) {
+		t.Errorf("Expected ErrInvalidOrderTransition, got %v", err)
+	}
+}
+
+// TestSessionPendingOrderAction 测试会话中待确认订单操作的存取
+func TestSessionPendingOrderAction(t *testing.T) {
+	session := NewSession("tenant1", time.Hour)
+	if session.PendingOrderAction() != nil {
+		t.Error("Expected no pending order action")
+	}
+
+	action := NewPendingOrderAction(OrderActionCancel, "#20251114001", "user123", "买错了", time.Minute)
+	session.SetPendingOrderAction(action)
+
+	// 模拟会话持久化后 Metadata 经过 JSON 编解码
+	data, err := json.Marshal(session.Metadata)
+	if err != nil {
+		t.Fatalf("Failed to marshal metadata: %v", err)
+	}
+	session.Metadata = make(map[string]any)
+	if err := json.Unmarshal(data, &session.Metadata); err != nil {
+		t.Fatalf("Failed to unmarshal metadata: %v", err)
+	}
+
+	loaded := session.PendingOrderAction()
+	if loaded == nil {
+		t.Fatal("Expected pending order action after JSON round trip")
+	}
+	if loaded.ID != action.ID || loaded.Type != OrderActionCancel || loaded.OrderID != "#20251114001" {
+		t.Errorf("Unexpected pending order action: %+v", loaded)
+	}
+	if loaded.IsExpired() {
+		t.Error("Expected pending order action not to be expired")
+	}
+	if loaded.Actor() != "user:user123" {
+		t.Errorf("Expected actor 'user:user123', got '%s'", loaded.Actor())
+	}
+
+	session.ClearPendingOrderAction()
+	if session.PendingOrderAction() != nil {
+		t.Error("Expected pending order action to be cleared")
+	}
+}
+
+// TestSlotDefinitionFill 测试槽位定义和槽位值提取
+func TestSlotDefinitionFill(t *testing.T) {
+	if _, err := NewSlotDefinition("", IntentOrder, "请提供订单号", ""); !errors.Is(err, ErrEmptySlotName) {
+		t.Errorf("Expected ErrEmptySlotName, got %v", err)
+	}
+	if _, err := NewSlotDefinition("phone", IntentType("unknown"), "请提供手机号", ""); !errors.Is(err, ErrInvalidIntentType) {
+		t.Errorf("Expected ErrInvalidIntentType, got %v", err)
+	}
+	if _, err := NewSlotDefinition("phone", IntentHandoff, "请提供手机号", "[0-9"); err == nil {
+		t.Error("Expected error for invalid pattern, got nil")
+	}
+
+	phone, err := NewSlotDefinition("phone", IntentHandoff, "请提供手机号", `1[3-9]\d{9}`)
+	if err != nil {
+		t.Fatalf("Failed to create slot definition: %v", err)
+	}
+	if value, ok := phone.Fill("tenant1", "我的手机是 13800138000"); !ok || value != "13800138000" {
+		t.Errorf("Expected phone '13800138000', got '%s' (%v)", value, ok)
+	}
+	if _, ok := phone.Fill("tenant1", "不方便留"); ok {
+		t.Error("Expected phone slot not to be filled")
+	}
+
+	// 订单号槽位未配置正则时按租户的订单号方案提取
+	orderID := DefaultSlotDefinitions()[0]
+	if value, ok := orderID.Fill("tenant1", "20251114001"); !ok || value != "#20251114001" {
+		t.Errorf("Expected order ID '#20251114001', got '%s' (%v)", value, ok)
+	}
+	if _, ok := orderID.Fill("tenant1", "我不记得了"); ok {
+		t.Error("Expected order ID slot not to be filled")
+	}
+}
+
+// TestSessionSlots 测试会话中槽位状态的存取
+func TestSessionSlots(t *testing.T) {
+	session := NewSession("tenant1", time.Hour)
+	session.SetExpectedSlot(&ExpectedSlot{
+		Name:      SlotOrderID,
+		Intent:    IntentOrder,
+		Query:     "这个订单什么时候开通",
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	session.SetSlotValue("phone", "13800138000")
+
+	// 模拟会话持久化后 Metadata 经过 JSON 编解码
+	data, err := json.Marshal(session.Metadata)
+	if err != nil {
+		t.Fatalf("Failed to marshal metadata: %v", err)
+	}
+	session.Metadata = make(map[string]any)
+	if err := json.Unmarshal(data, &session.Metadata); err != nil {
+		t.Fatalf("Failed to unmarshal metadata: %v", err)
+	}
+
+	expected := session.ExpectedSlot()
+	if expected == nil || expected.Name != SlotOrderID || expected.Intent != IntentOrder || expected.IsExpired() {
+		t.Errorf("Unexpected expected slot: %+v", expected)
+	}
+	if session.SlotValue("phone") != "13800138000" {
+		t.Errorf("Expected phone slot value, got '%s'", session.SlotValue("phone"))
+	}
+
+	session.SetSlotValue(SlotOrderID, "#20251114001")
+	if len(session.SlotValues()) != 2 {
+		t.Errorf("Expected 2 slot values, got %d", len(session.SlotValues()))
+	}
+
+	session.ClearExpectedSlot()
+	if session.ExpectedSlot() != nil {
+		t.Error("Expected slot to be cleared")
+	}
+}
+
 // TestSessionCreation 测试会话创建
 func TestSessionCreation(t *testing.T) {
 	session := NewSession("tenant1", 1*time.Hour)
@@ -213,6 +398,17 @@ func TestTenantCreation(t *testing.T) {
 	}
 }
 
+// TestNormalizeTenantID 测试租户 ID 规范化
+func TestNormalizeTenantID(t *testing.T) {
+	if got := NormalizeTenantID(""); got != DefaultTenantID {
+		t.Errorf("Expected default tenant, got '%s'", got)
+	}
+
+	if got := NormalizeTenantID("tenant1"); got != "tenant1" {
+		t.Errorf("Expected 'tenant1', got '%s'", got)
+	}
+}
+
 // TestValidateOrderID 测试订单 ID 验证
 func TestValidateOrderID(t *testing.T) {
 	tests := []struct {
@@ -260,3 +456,219 @@ func TestSanitizeSQL(t *testing.T) {
 		})
 	}
 }
+
+// TestWebhookEndpointValidation 测试 Webhook 端点验证
+func TestWebhookEndpointValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		events  []WebhookEventType
+		wantErr error
+	}{
+		{"valid endpoint", "https://example.com/hook", []WebhookEventType{EventHandoffCreated}, nil},
+		{"relative url", "/hook", []WebhookEventType{EventRAGMiss}, ErrInvalidWebhookURL},
+		{"unsupported scheme", "ftp://example.com/hook", []WebhookEventType{EventRAGMiss}, ErrInvalidWebhookURL},
+		{"no events", "https://example.com/hook", nil, ErrEmptyWebhookEvents},
+		{"unknown event", "https://example.com/hook", []WebhookEventType{"order.created"}, ErrInvalidWebhookEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := NewWebhookEndpoint("tenant1", tt.url, tt.events)
+			if err := endpoint.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	endpoint := NewWebhookEndpoint("tenant1", "https://example.com/hook", []WebhookEventType{EventJobFinished})
+	if endpoint.Secret == "" {
+		t.Error("Expected generated secret")
+	}
+	if !endpoint.Subscribes(EventJobFinished) || endpoint.Subscribes(EventRAGMiss) {
+		t.Error("Subscribes() returned unexpected result")
+	}
+}
+
+// TestDefaultOrderIDScheme 测试默认订单号方案的生成、校验与提取一致
+func TestDefaultOrderIDScheme(t *testing.T) {
+	order := NewOrder("user123", "Python Course", 99.99, "tenant1")
+
+	if err := ValidateOrderID(order.ID); err != nil {
+		t.Fatalf("Generated order ID %q failed validation: %v", order.ID, err)
+	}
+
+	// 聊天中引用新生成的订单号，无论是否带 # 都能提取到规范形式
+	digits := strings.TrimPrefix(order.ID, "#")
+	for _, text := range []string{"查询订单" + order.ID, "订单号：" + digits, "我的订单 " + digits + " 怎么样了"} {
+		extracted, ok := DefaultOrderIDScheme.Extract(text)
+		if !ok || extracted != order.ID {
+			t.Errorf("Extract(%q) = %q, %v; want %q", text, extracted, ok, order.ID)
+		}
+	}
+
+	if _, ok := DefaultOrderIDScheme.Extract("订单号 1234567890123456789012345"); ok {
+		t.Error("Expected no order ID in an over-long number")
+	}
+
+	if normalized, ok := DefaultOrderIDScheme.Normalize(" 20251114001 "); !ok || normalized != "#20251114001" {
+		t.Errorf("Normalize() = %q, %v; want #20251114001", normalized, ok)
+	}
+}
+
+// TestImportedOrderIDScheme 测试租户导入订单号方案
+func TestImportedOrderIDScheme(t *testing.T) {
+	scheme, err := NewPatternOrderIDScheme(OrderIDSchemeConfig{
+		Prefix:          "SO-",
+		Pattern:         `[A-Z]{2}\d{6}`,
+		CaseInsensitive: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheme: %v", err)
+	}
+
+	RegisterOrderIDScheme("shop", scheme)
+	defer RegisterOrderIDScheme("shop", nil)
+
+	if OrderIDSchemeFor("shop") != scheme {
+		t.Fatal("Expected registered scheme for tenant shop")
+	}
+	if OrderIDSchemeFor("other") != DefaultOrderIDScheme {
+		t.Fatal("Expected default scheme for unregistered tenant")
+	}
+
+	if _, err := scheme.Generate(); !errors.Is(err, ErrOrderIDGenerationDisabled) {
+		t.Errorf("Expected ErrOrderIDGenerationDisabled, got %v", err)
+	}
+
+	tests := []struct {
+		text     string
+		expected string
+		found    bool
+	}{
+		{"我的订单 SO-AB123456 到哪了", "SO-AB123456", true},
+		{"订单so-ab123456", "SO-AB123456", true},
+		{"订单号是 ab123456", "SO-AB123456", true},
+		{"#SO-AB123456", "SO-AB123456", true},
+		{"订单号 20251114001", "", false},
+	}
+	for _, tt := range tests {
+		extracted, ok := scheme.Extract(tt.text)
+		if ok != tt.found || extracted != tt.expected {
+			t.Errorf("Extract(%q) = %q, %v; want %q, %v", tt.text, extracted, ok, tt.expected, tt.found)
+		}
+	}
+
+	// 导入的订单需使用规范形式的订单号
+	order := NewOrder("user123", "Go Course", 10, "shop")
+	if err := order.Validate(); !errors.Is(err, ErrEmptyOrderID) {
+		t.Errorf("Expected ErrEmptyOrderID for imported scheme, got %v", err)
+	}
+	order.ID = "SO-AB123456"
+	if err := order.Validate(); err != nil {
+		t.Errorf("Valid imported order failed validation: %v", err)
+	}
+	order.ID = "so-ab123456"
+	if err := order.Validate(); !errors.Is(err, ErrInvalidOrderID) {
+		t.Errorf("Expected ErrInvalidOrderID for non-canonical ID, got %v", err)
+	}
+
+	if _, err := NewPatternOrderIDScheme(OrderIDSchemeConfig{
+		Pattern:   `[A-Z]{4}`,
+		Generator: DateDigitsOrderIDGenerator(4),
+	}); err == nil {
+		t.Error("Expected error when generator does not match pattern")
+	}
+}
+
+// TestExperimentAssign 测试实验配置校验和分组分配
+func TestExperimentAssign(t *testing.T) {
+	experiment := &Experiment{
+		Name:      "rag_prompt",
+		Component: ExperimentRAG,
+		Variants: []*ExperimentVariant{
+			{Name: "control", Weight: 80},
+			{Name: "treatment", Weight: 20, PromptVersion: 2},
+		},
+	}
+	if err := experiment.Validate(); err != nil {
+		t.Fatalf("Valid experiment failed validation: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		sessionID := "sess_" + strconv.Itoa(i)
+		first := experiment.Assign(sessionID)
+		if second := experiment.Assign(sessionID); first.Variant != second.Variant {
+			t.Fatalf("Assign(%q) is not deterministic: %s != %s", sessionID, first.Variant, second.Variant)
+		}
+		counts[first.Variant]++
+	}
+	// 分配比例大致符合权重
+	if counts["control"] < 700 || counts["control"] > 900 {
+		t.Errorf("Expected about 800 sessions in control, got %d", counts["control"])
+	}
+
+	invalid := []struct {
+		name     string
+		variants []*ExperimentVariant
+		wantErr  error
+	}{
+		{"single variant", []*ExperimentVariant{{Name: "a", Weight: 1}}, ErrInsufficientVariants},
+		{"duplicate variant", []*ExperimentVariant{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}, ErrDuplicateExperimentVariant},
+		{"zero weights", []*ExperimentVariant{{Name: "a"}, {Name: "b"}}, ErrInvalidExperimentWeight},
+		{"negative version", []*ExperimentVariant{{Name: "a", Weight: 1}, {Name: "b", Weight: 1, PromptVersion: -1}}, ErrInvalidExperimentVariant},
+	}
+	for _, tt := range invalid {
+		e := &Experiment{Name: "exp", Component: ExperimentIntent, Variants: tt.variants}
+		if err := e.Validate(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+// TestFAQ 测试标准问答的验证、生效期和精确匹配
+func TestFAQ(t *testing.T) {
+	faq := NewFAQ("tenant1", []string{"退款政策是什么？", "怎么申请退款"}, "购买 7 天内未学习可全额退款。")
+	if err := faq.Validate(); err != nil {
+		t.Fatalf("Valid faq failed validation: %v", err)
+	}
+
+	// 忽略大小写、空白和标点
+	for query, question := range map[string]string{"退款政策是什么": "退款政策是什么？", "退款政策 是什么?": "退款政策是什么？", "怎么申请退款！": "怎么申请退款"} {
+		if matched, ok := faq.MatchExact(query); !ok || matched != question {
+			t.Errorf("Expected %q to match %q exactly, got %q", query, question, matched)
+		}
+	}
+	if _, ok := faq.MatchExact("退款政策"); ok {
+		t.Error("Expected partial question not to match")
+	}
+
+	now := time.Now()
+	from, to := now.Add(time.Hour), now.Add(48*time.Hour)
+	faq.EffectiveFrom, faq.EffectiveTo = &from, &to
+	if faq.IsEffective(now) || !faq.IsEffective(now.Add(2*time.Hour)) || faq.IsEffective(to) {
+		t.Error("IsEffective() returned unexpected result for date range")
+	}
+	faq.EffectiveFrom = nil
+	faq.Enabled = false
+	if faq.IsEffective(now) {
+		t.Error("Expected disabled faq not to be effective")
+	}
+
+	invalid := []struct {
+		name    string
+		faq     *FAQ
+		wantErr error
+	}{
+		{"no questions", NewFAQ("tenant1", nil, "答案"), ErrEmptyFAQQuestions},
+		{"blank question", NewFAQ("tenant1", []string{"？ "}, "答案"), ErrEmptyFAQQuestions},
+		{"empty answer", NewFAQ("tenant1", []string{"问题"}, " "), ErrEmptyFAQAnswer},
+		{"reversed range", &FAQ{TenantID: "tenant1", Questions: []string{"问题"}, Answer: "答案", EffectiveFrom: &to, EffectiveTo: &from}, ErrInvalidFAQDateRange},
+	}
+	for _, tt := range invalid {
+		if err := tt.faq.Validate(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
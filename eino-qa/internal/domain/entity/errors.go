@@ -21,7 +21,31 @@ var (
 	ErrInvalidOrderStatus = errors.New("invalid order status")
 	ErrOrderNotFound      = errors.New("order not found")
 
+	// OrderEvent 相关错误
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+	ErrOrderStatusConflict    = errors.New("order status has been changed concurrently")
+	ErrEmptyOrderEventActor   = errors.New("order event actor cannot be empty")
+
+	// OrderAction 相关错误
+	ErrInvalidOrderAction = errors.New("invalid order action")
+
+	// OrderSync 相关错误
+	ErrEmptyOrderSyncSource   = errors.New("order sync source cannot be empty")
+	ErrOrderSyncStateNotFound = errors.New("order sync state not found")
+
+	// OrderID 相关错误
+	ErrEmptyOrderID              = errors.New("order ID cannot be empty")
+	ErrInvalidOrderID            = errors.New("invalid order ID format")
+	ErrOrderIDGenerationDisabled = errors.New("order IDs are imported for this tenant and cannot be generated")
+
+	// Slot 相关错误
+	ErrEmptySlotName   = errors.New("slot name cannot be empty")
+	ErrEmptySlotPrompt = errors.New("slot prompt cannot be empty")
+
 	// Session 相关错误
 	ErrEmptySessionID = errors.New("session ID cannot be empty")
 	ErrSessionExpired = errors.New("session has expired")
+
+	// 限流相关错误
+	ErrLLMConcurrencyLimit = errors.New("too many concurrent llm calls for tenant")
 )
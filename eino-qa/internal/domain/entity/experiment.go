@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+var (
+	// Experiment 相关错误
+	ErrEmptyExperimentName          = errors.New("experiment name cannot be empty")
+	ErrInvalidExperimentComponent   = errors.New("experiment component must be 'intent', 'rag' or 'direct'")
+	ErrInsufficientVariants         = errors.New("experiment must have at least two variants")
+	ErrInvalidExperimentVariant     = errors.New("invalid experiment variant")
+	ErrDuplicateExperimentVariant   = errors.New("duplicate experiment variant name")
+	ErrInvalidExperimentWeight      = errors.New("experiment variant weights must be non-negative and sum to a positive value")
+	ErrDuplicateExperimentComponent = errors.New("only one experiment per component is allowed")
+)
+
+// ExperimentComponent 定义参与 A/B 实验的组件
+type ExperimentComponent string
+
+const (
+	// ExperimentIntent 意图识别
+	ExperimentIntent ExperimentComponent = "intent"
+	// ExperimentRAG 课程咨询（RAG）回答
+	ExperimentRAG ExperimentComponent = "rag"
+	// ExperimentDirect 直接回答
+	ExperimentDirect ExperimentComponent = "direct"
+)
+
+// IsValid 判断组件是否有效
+func (c ExperimentComponent) IsValid() bool {
+	return c == ExperimentIntent || c == ExperimentRAG || c == ExperimentDirect
+}
+
+// PromptName 返回组件使用的系统提示词
+func (c ExperimentComponent) PromptName() PromptName {
+	switch c {
+	case ExperimentIntent:
+		return PromptIntent
+	case ExperimentRAG:
+		return PromptRAG
+	case ExperimentDirect:
+		return PromptResponse
+	}
+	return ""
+}
+
+// AppliesTo 判断组件是否参与了某条路由的处理
+// 意图识别参与每一轮对话，RAG 和直接回答只参与各自的路由
+func (c ExperimentComponent) AppliesTo(route string) bool {
+	switch c {
+	case ExperimentIntent:
+		return true
+	case ExperimentRAG:
+		return route == string(IntentCourse)
+	case ExperimentDirect:
+		return route == string(IntentDirect)
+	}
+	return false
+}
+
+// ExperimentVariant 实验分组
+type ExperimentVariant struct {
+	Name          string
+	Weight        int    // 流量权重
+	PromptVersion int    // 使用的提示词模板版本，0 表示使用租户当前启用的版本
+	Model         string // 使用的聊天模型，为空表示使用默认模型
+}
+
+// Experiment 表示租户对某个组件的 A/B 实验
+type Experiment struct {
+	Name      string
+	Component ExperimentComponent
+	Variants  []*ExperimentVariant
+}
+
+// Validate 验证实验配置的有效性
+func (e *Experiment) Validate() error {
+	if e.Name == "" {
+		return ErrEmptyExperimentName
+	}
+	if !e.Component.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidExperimentComponent, e.Component)
+	}
+	if len(e.Variants) < 2 {
+		return ErrInsufficientVariants
+	}
+
+	seen := make(map[string]bool, len(e.Variants))
+	total := 0
+	for _, v := range e.Variants {
+		if v.Name == "" || v.PromptVersion < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidExperimentVariant, v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateExperimentVariant, v.Name)
+		}
+		seen[v.Name] = true
+		if v.Weight < 0 {
+			return ErrInvalidExperimentWeight
+		}
+		total += v.Weight
+	}
+	if total <= 0 {
+		return ErrInvalidExperimentWeight
+	}
+	return nil
+}
+
+// Assign 按会话 ID 的哈希值确定性地分配实验分组
+// 同一会话在实验配置不变时总是分到同一分组，不同实验之间的分配相互独立
+func (e *Experiment) Assign(sessionID string) *ExperimentAssignment {
+	total := 0
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(e.Name + ":" + sessionID))
+	bucket := int(h.Sum32() % uint32(total))
+
+	for _, v := range e.Variants {
+		if bucket < v.Weight {
+			return &ExperimentAssignment{
+				Experiment:    e.Name,
+				Component:     e.Component,
+				Variant:       v.Name,
+				PromptVersion: v.PromptVersion,
+				Model:         v.Model,
+			}
+		}
+		bucket -= v.Weight
+	}
+	return nil
+}
+
+// ExperimentAssignment 会话在某个实验中的分组
+type ExperimentAssignment struct {
+	Experiment    string
+	Component     ExperimentComponent
+	Variant       string
+	PromptVersion int
+	Model         string
+}
+
+// ExperimentOutcome 一轮对话在实验分组下的结果
+type ExperimentOutcome struct {
+	ID         string
+	TenantID   string
+	Experiment string
+	Variant    string
+	SessionID  string
+	MessageID  string // 助手消息 ID，用于关联用户反馈
+	Route      string
+	LatencyMs  int64
+	Handoff    bool // 本轮是否转人工
+	RAGMiss    bool // 本轮知识库是否未命中
+	CreatedAt  time.Time
+}
+
+// NewExperimentOutcome 创建实验结果记录
+func NewExperimentOutcome(tenantID string, assignment *ExperimentAssignment, sessionID, messageID, route string) *ExperimentOutcome {
+	return &ExperimentOutcome{
+		ID:         generateUniqueID("exo_", 16),
+		TenantID:   tenantID,
+		Experiment: assignment.Experiment,
+		Variant:    assignment.Variant,
+		SessionID:  sessionID,
+		MessageID:  messageID,
+		Route:      route,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// VariantResult 实验分组的汇总结果
+type VariantResult struct {
+	Variant      string
+	Requests     int64
+	AvgLatencyMs float64
+	Handoffs     int64
+	RAGRequests  int64 // 课程咨询路由的轮数
+	RAGMisses    int64
+	Positive     int64 // 正面反馈数
+	Negative     int64 // 负面反馈数
+}
+
+// HandoffRate 转人工率
+func (r *VariantResult) HandoffRate() float64 {
+	return ratio(r.Handoffs, r.Requests)
+}
+
+// RAGMissRate 知识库未命中率（按课程咨询轮数计算）
+func (r *VariantResult) RAGMissRate() float64 {
+	return ratio(r.RAGMisses, r.RAGRequests)
+}
+
+// SatisfactionRate 满意率（正面反馈 / 反馈总数）
+func (r *VariantResult) SatisfactionRate() float64 {
+	return ratio(r.Positive, r.Positive+r.Negative)
+}
+
+// ratio 计算比例，分母为 0 时返回 0
+func ratio(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	// FAQ 相关错误
+	ErrEmptyFAQQuestions   = errors.New("faq must have at least one question")
+	ErrEmptyFAQAnswer      = errors.New("faq answer cannot be empty")
+	ErrInvalidFAQDateRange = errors.New("faq effective_to must be after effective_from")
+	ErrFAQNotFound         = errors.New("faq not found")
+)
+
+// FAQ 租户维护的标准问答
+// 问题（及其变体）命中时原样返回标准答案，不经过检索和 LLM 改写，适用于退款政策、价格等需要严格措辞的问题
+type FAQ struct {
+	ID            string
+	TenantID      string
+	Questions     []string    // 标准问题及其变体
+	Vectors       [][]float32 // 与 Questions 一一对应的嵌入向量，为空时只做精确匹配
+	Answer        string      // 标准答案
+	EffectiveFrom *time.Time  // 生效时间，nil 表示立即生效
+	EffectiveTo   *time.Time  // 失效时间，nil 表示长期有效
+	Enabled       bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewFAQ 创建新的标准问答
+func NewFAQ(tenantID string, questions []string, answer string) *FAQ {
+	now := time.Now()
+	return &FAQ{
+		ID:        generateUniqueID("faq_", 16),
+		TenantID:  tenantID,
+		Questions: questions,
+		Answer:    answer,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate 验证标准问答的有效性
+func (f *FAQ) Validate() error {
+	if f.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+
+	if len(f.Questions) == 0 {
+		return ErrEmptyFAQQuestions
+	}
+	for _, q := range f.Questions {
+		if NormalizeFAQQuestion(q) == "" {
+			return ErrEmptyFAQQuestions
+		}
+	}
+
+	if strings.TrimSpace(f.Answer) == "" {
+		return ErrEmptyFAQAnswer
+	}
+
+	if f.EffectiveFrom != nil && f.EffectiveTo != nil && !f.EffectiveTo.After(*f.EffectiveFrom) {
+		return ErrInvalidFAQDateRange
+	}
+
+	return nil
+}
+
+// IsEffective 判断标准问答在指定时间是否启用且处于生效期内
+func (f *FAQ) IsEffective(at time.Time) bool {
+	if !f.Enabled {
+		return false
+	}
+	if f.EffectiveFrom != nil && at.Before(*f.EffectiveFrom) {
+		return false
+	}
+	if f.EffectiveTo != nil && !at.Before(*f.EffectiveTo) {
+		return false
+	}
+	return true
+}
+
+// MatchExact 判断查询规范化后是否与某个问题完全相同，返回命中的问题
+func (f *FAQ) MatchExact(query string) (string, bool) {
+	normalized := NormalizeFAQQuestion(query)
+	for _, q := range f.Questions {
+		if NormalizeFAQQuestion(q) == normalized {
+			return q, true
+		}
+	}
+	return "", false
+}
+
+// NormalizeFAQQuestion 规范化用于精确匹配的问题：
+// 忽略大小写，去掉所有空白和标点（"退款政策是什么？" 与 "退款政策 是什么" 相同）
+func NormalizeFAQQuestion(question string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(question) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
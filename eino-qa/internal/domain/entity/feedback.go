@@ -0,0 +1,144 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// Feedback 相关错误
+	ErrInvalidFeedbackRating = errors.New("feedback rating must be 'up' or 'down'")
+	ErrInvalidFeedbackReason = errors.New("invalid feedback reason code")
+	ErrEmptyMessageID        = errors.New("message ID cannot be empty")
+	ErrMessageNotFound       = errors.New("message not found")
+	ErrFeedbackNotFound      = errors.New("feedback not found")
+	ErrEmptyCorrection       = errors.New("feedback has no corrected answer")
+)
+
+// FeedbackRating 定义反馈评分
+type FeedbackRating string
+
+const (
+	// FeedbackUp 有帮助
+	FeedbackUp FeedbackRating = "up"
+	// FeedbackDown 没有帮助
+	FeedbackDown FeedbackRating = "down"
+)
+
+// IsValid 判断评分是否有效
+func (r FeedbackRating) IsValid() bool {
+	return r == FeedbackUp || r == FeedbackDown
+}
+
+// FeedbackReason 定义反馈原因代码
+type FeedbackReason string
+
+const (
+	// FeedbackReasonIncorrect 答案错误
+	FeedbackReasonIncorrect FeedbackReason = "incorrect"
+	// FeedbackReasonIncomplete 答案不完整
+	FeedbackReasonIncomplete FeedbackReason = "incomplete"
+	// FeedbackReasonIrrelevant 答非所问
+	FeedbackReasonIrrelevant FeedbackReason = "irrelevant"
+	// FeedbackReasonOutdated 信息过时
+	FeedbackReasonOutdated FeedbackReason = "outdated"
+	// FeedbackReasonOther 其他原因
+	FeedbackReasonOther FeedbackReason = "other"
+)
+
+// IsValid 判断原因代码是否有效（允许为空）
+func (r FeedbackReason) IsValid() bool {
+	switch r {
+	case "", FeedbackReasonIncorrect, FeedbackReasonIncomplete, FeedbackReasonIrrelevant,
+		FeedbackReasonOutdated, FeedbackReasonOther:
+		return true
+	}
+	return false
+}
+
+// FeedbackStatus 定义反馈处理状态
+type FeedbackStatus string
+
+const (
+	// FeedbackStatusOpen 待处理
+	FeedbackStatusOpen FeedbackStatus = "open"
+	// FeedbackStatusPromoted 纠正答案已加入知识库
+	FeedbackStatusPromoted FeedbackStatus = "promoted"
+)
+
+// Feedback 表示用户对一条助手回答的反馈
+type Feedback struct {
+	ID         string
+	TenantID   string
+	SessionID  string
+	MessageID  string // 被评价的助手消息 ID
+	Rating     FeedbackRating
+	Reason     FeedbackReason
+	Comment    string
+	Correction string // 用户提供的纠正答案（可选）
+	Query      string // 对应的用户问题
+	Answer     string // 被评价的回答内容
+	Route      string // 回答所走的路由
+	Intent     string // 识别出的意图
+	Status     FeedbackStatus
+	DocumentID string // 纠正答案入库后的文档 ID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewFeedback 创建新的反馈
+func NewFeedback(tenantID, sessionID, messageID string, rating FeedbackRating) *Feedback {
+	now := time.Now()
+	return &Feedback{
+		ID:        generateUniqueID("fb_", 24),
+		TenantID:  tenantID,
+		SessionID: sessionID,
+		MessageID: messageID,
+		Rating:    rating,
+		Status:    FeedbackStatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate 验证反馈的有效性
+func (f *Feedback) Validate() error {
+	if f.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if f.SessionID == "" {
+		return ErrEmptySessionID
+	}
+	if f.MessageID == "" {
+		return ErrEmptyMessageID
+	}
+	if !f.Rating.IsValid() {
+		return ErrInvalidFeedbackRating
+	}
+	if !f.Reason.IsValid() {
+		return ErrInvalidFeedbackReason
+	}
+	return nil
+}
+
+// IsPositive 判断是否为正面反馈
+func (f *Feedback) IsPositive() bool {
+	return f.Rating == FeedbackUp
+}
+
+// HasCorrection 判断是否附带纠正答案
+func (f *Feedback) HasCorrection() bool {
+	return f.Correction != ""
+}
+
+// IsPromoted 判断纠正答案是否已加入知识库
+func (f *Feedback) IsPromoted() bool {
+	return f.Status == FeedbackStatusPromoted
+}
+
+// MarkPromoted 标记纠正答案已加入知识库
+func (f *Feedback) MarkPromoted(documentID string) {
+	f.Status = FeedbackStatusPromoted
+	f.DocumentID = documentID
+	f.UpdatedAt = time.Now()
+}
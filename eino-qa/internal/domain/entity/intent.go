@@ -12,6 +12,10 @@ const (
 	IntentDirect IntentType = "direct"
 	// IntentHandoff 人工转接意图
 	IntentHandoff IntentType = "handoff"
+	// IntentFAQ 标准问答（命中租户 FAQ 时直接返回标准答案，不由意图识别产生）
+	IntentFAQ IntentType = "faq"
+	// IntentBlocked 输入被安全策略拦截（如疑似提示词注入，不由意图识别产生）
+	IntentBlocked IntentType = "blocked"
 )
 
 // Intent 表示用户查询的意图
@@ -37,6 +41,8 @@ func (i *Intent) Validate() error {
 		IntentOrder:   true,
 		IntentDirect:  true,
 		IntentHandoff: true,
+		IntentFAQ:     true,
+		IntentBlocked: true,
 	}
 
 	if !validTypes[i.Type] {
@@ -57,7 +57,7 @@ func (m *Message) IsSystem() bool {
 }
 
 // generateMessageID 生成消息 ID
+// 消息 ID 随会话持久化，作为反馈等外部引用的稳定标识
 func generateMessageID() string {
-	// 使用时间戳和随机数生成唯一 ID
-	return time.Now().Format("20060102150405") + randomString(8)
+	return generateUniqueID("msg_", 24)
 }
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// MissedQuery 表示一次知识库检索未命中的查询
+type MissedQuery struct {
+	ID             uint
+	TenantID       string
+	Query          string  // 用户原始查询
+	RewrittenQuery string  // 实际用于检索的查询（与原始查询相同时为空）
+	Intent         string  // 识别出的意图
+	TopScore       float64 // 检索结果中的最高相似度（无结果时为 0）
+	CreatedAt      time.Time
+}
+
+// NewMissedQuery 创建新的未命中查询记录
+func NewMissedQuery(tenantID, query, intent string) *MissedQuery {
+	return &MissedQuery{
+		TenantID:  tenantID,
+		Query:     query,
+		Intent:    intent,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate 验证未命中查询的有效性
+func (m *MissedQuery) Validate() error {
+	if m.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if m.Query == "" {
+		return ErrEmptyContent
+	}
+	return nil
+}
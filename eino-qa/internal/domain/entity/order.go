@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // OrderStatus 定义订单状态
 type OrderStatus string
@@ -10,12 +13,62 @@ const (
 	OrderStatusPending OrderStatus = "pending"
 	// OrderStatusPaid 已支付
 	OrderStatusPaid OrderStatus = "paid"
+	// OrderStatusRefundRequested 退款申请中
+	OrderStatusRefundRequested OrderStatus = "refund_requested"
 	// OrderStatusRefunded 已退款
 	OrderStatusRefunded OrderStatus = "refunded"
 	// OrderStatusCancelled 已取消
 	OrderStatusCancelled OrderStatus = "cancelled"
 )
 
+// orderTransitions 订单状态机：每个状态允许迁移到的下一状态
+// pending → paid → refund_requested → refunded；未支付订单可取消，退款申请可被驳回回到已支付
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:         {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:            {OrderStatusRefundRequested},
+	OrderStatusRefundRequested: {OrderStatusRefunded, OrderStatusPaid},
+	OrderStatusRefunded:        {},
+	OrderStatusCancelled:       {},
+}
+
+// IsValid 判断是否为合法的订单状态
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+// IsTerminal 判断是否为终态（不能再迁移）
+func (s OrderStatus) IsTerminal() bool {
+	return s.IsValid() && len(orderTransitions[s]) == 0
+}
+
+// CanTransitionTo 判断能否从当前状态迁移到目标状态
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	for _, next := range orderTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// orderStatusNames 订单状态的中文名称
+var orderStatusNames = map[OrderStatus]string{
+	OrderStatusPending:         "待支付",
+	OrderStatusPaid:            "已支付",
+	OrderStatusRefundRequested: "退款申请中",
+	OrderStatusRefunded:        "已退款",
+	OrderStatusCancelled:       "已取消",
+}
+
+// DisplayName 订单状态的中文名称
+func (s OrderStatus) DisplayName() string {
+	if name, ok := orderStatusNames[s]; ok {
+		return name
+	}
+	return string(s)
+}
+
 // Order 表示订单实体
 type Order struct {
 	ID         string
@@ -30,10 +83,11 @@ type Order struct {
 }
 
 // NewOrder 创建新的订单实例
+// 订单号由租户的订单号方案生成；订单号由外部系统导入的租户需自行设置 ID
 func NewOrder(userID, courseName string, amount float64, tenantID string) *Order {
 	now := time.Now()
 	return &Order{
-		ID:         generateOrderID(),
+		ID:         generateOrderID(tenantID),
 		UserID:     userID,
 		CourseName: courseName,
 		Amount:     amount,
@@ -47,6 +101,10 @@ func NewOrder(userID, courseName string, amount float64, tenantID string) *Order
 
 // Validate 验证订单的有效性
 func (o *Order) Validate() error {
+	if err := OrderIDSchemeFor(o.TenantID).Validate(o.ID); err != nil {
+		return err
+	}
+
 	if o.UserID == "" {
 		return ErrEmptyUserID
 	}
@@ -63,14 +121,7 @@ func (o *Order) Validate() error {
 		return ErrEmptyTenantID
 	}
 
-	validStatuses := map[OrderStatus]bool{
-		OrderStatusPending:   true,
-		OrderStatusPaid:      true,
-		OrderStatusRefunded:  true,
-		OrderStatusCancelled: true,
-	}
-
-	if !validStatuses[o.Status] {
+	if !o.Status.IsValid() {
 		return ErrInvalidOrderStatus
 	}
 
@@ -87,6 +138,11 @@ func (o *Order) IsPaid() bool {
 	return o.Status == OrderStatusPaid
 }
 
+// IsRefundRequested 判断订单是否正在申请退款
+func (o *Order) IsRefundRequested() bool {
+	return o.Status == OrderStatusRefundRequested
+}
+
 // IsRefunded 判断订单是否已退款
 func (o *Order) IsRefunded() bool {
 	return o.Status == OrderStatusRefunded
@@ -98,16 +154,14 @@ func (o *Order) IsCancelled() bool {
 }
 
 // UpdateStatus 更新订单状态
+// 只允许状态机中定义的迁移，如已退款的订单不能回到待支付
 func (o *Order) UpdateStatus(status OrderStatus) error {
-	validStatuses := map[OrderStatus]bool{
-		OrderStatusPending:   true,
-		OrderStatusPaid:      true,
-		OrderStatusRefunded:  true,
-		OrderStatusCancelled: true,
+	if !status.IsValid() {
+		return ErrInvalidOrderStatus
 	}
 
-	if !validStatuses[status] {
-		return ErrInvalidOrderStatus
+	if !o.Status.CanTransitionTo(status) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidOrderTransition, o.Status, status)
 	}
 
 	o.Status = status
@@ -115,6 +169,18 @@ func (o *Order) UpdateStatus(status OrderStatus) error {
 	return nil
 }
 
+// Transition 按状态机迁移订单状态，并返回记录本次迁移的订单事件
+// actor: 操作者（如 "user:u_1001"、"admin"、"system"）
+// reason: 迁移原因
+func (o *Order) Transition(to OrderStatus, actor, reason string) (*OrderEvent, error) {
+	from := o.Status
+	if err := o.UpdateStatus(to); err != nil {
+		return nil, err
+	}
+
+	return NewOrderEvent(o, from, actor, reason), nil
+}
+
 // AddMetadata 添加元数据
 func (o *Order) AddMetadata(key string, value any) {
 	if o.Metadata == nil {
@@ -123,8 +189,11 @@ func (o *Order) AddMetadata(key string, value any) {
 	o.Metadata[key] = value
 }
 
-// generateOrderID 生成订单 ID
-func generateOrderID() string {
-	// 格式: #YYYYMMDDXXX
-	return generateUniqueID("#"+time.Now().Format("20060102"), 6)
+// generateOrderID 按租户的订单号方案生成订单 ID（不支持生成时返回空字符串）
+func generateOrderID(tenantID string) string {
+	id, err := OrderIDSchemeFor(tenantID).Generate()
+	if err != nil {
+		return ""
+	}
+	return id
 }
@@ -0,0 +1,132 @@
+package entity
+
+import "time"
+
+// OrderActionType 用户在对话中发起的订单操作类型
+type OrderActionType string
+
+const (
+	// OrderActionCancel 取消订单
+	OrderActionCancel OrderActionType = "cancel"
+	// OrderActionRefund 申请退款
+	OrderActionRefund OrderActionType = "refund"
+)
+
+// DefaultPendingOrderActionTTL 待确认订单操作的默认有效期
+const DefaultPendingOrderActionTTL = 5 * time.Minute
+
+// SessionKeyPendingOrderAction 待确认订单操作在 Session.Metadata 中的键
+const SessionKeyPendingOrderAction = "pending_order_action"
+
+// IsValid 判断操作类型是否有效
+func (t OrderActionType) IsValid() bool {
+	return t == OrderActionCancel || t == OrderActionRefund
+}
+
+// TargetStatus 操作执行后订单的目标状态
+func (t OrderActionType) TargetStatus() OrderStatus {
+	switch t {
+	case OrderActionCancel:
+		return OrderStatusCancelled
+	case OrderActionRefund:
+		return OrderStatusRefundRequested
+	}
+	return ""
+}
+
+// DisplayName 操作的中文名称
+func (t OrderActionType) DisplayName() string {
+	switch t {
+	case OrderActionCancel:
+		return "取消订单"
+	case OrderActionRefund:
+		return "申请退款"
+	}
+	return string(t)
+}
+
+// PendingOrderAction 等待用户确认的订单操作
+// 保存在 Session.Metadata 中，用户在有效期内确认后才会执行
+type PendingOrderAction struct {
+	ID        string          `json:"id"`
+	Type      OrderActionType `json:"type"`
+	OrderID   string          `json:"order_id"`
+	UserID    string          `json:"user_id"`
+	Reason    string          `json:"reason"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// NewPendingOrderAction 创建待确认的订单操作
+func NewPendingOrderAction(actionType OrderActionType, orderID, userID, reason string, ttl time.Duration) *PendingOrderAction {
+	if ttl <= 0 {
+		ttl = DefaultPendingOrderActionTTL
+	}
+
+	now := time.Now()
+	return &PendingOrderAction{
+		ID:        generateUniqueID("act_", 24),
+		Type:      actionType,
+		OrderID:   orderID,
+		UserID:    userID,
+		Reason:    reason,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// Validate 验证待确认订单操作的有效性
+func (a *PendingOrderAction) Validate() error {
+	if !a.Type.IsValid() {
+		return ErrInvalidOrderAction
+	}
+
+	if a.OrderID == "" {
+		return ErrEmptyOrderID
+	}
+
+	if a.UserID == "" {
+		return ErrEmptyUserID
+	}
+
+	return nil
+}
+
+// IsExpired 判断操作是否已过确认有效期
+func (a *PendingOrderAction) IsExpired() bool {
+	return time.Now().After(a.ExpiresAt)
+}
+
+// Actor 订单事件中记录的操作者
+func (a *PendingOrderAction) Actor() string {
+	return "user:" + a.UserID
+}
+
+// SetPendingOrderAction 在会话中记录待确认的订单操作（覆盖之前未确认的操作）
+func (s *Session) SetPendingOrderAction(action *PendingOrderAction) {
+	s.AddMetadata(SessionKeyPendingOrderAction, action)
+}
+
+// PendingOrderAction 获取会话中待确认的订单操作，不存在或无法解析时返回 nil
+// 会话从存储加载后 Metadata 中的值为 JSON 解码后的 map，这里统一转换
+func (s *Session) PendingOrderAction() *PendingOrderAction {
+	value, ok := s.Metadata[SessionKeyPendingOrderAction]
+	if !ok || value == nil {
+		return nil
+	}
+
+	if action, ok := value.(*PendingOrderAction); ok {
+		return action
+	}
+
+	var action PendingOrderAction
+	if !decodeMetadata(value, &action) || action.Validate() != nil {
+		return nil
+	}
+	return &action
+}
+
+// ClearPendingOrderAction 清除会话中待确认的订单操作
+func (s *Session) ClearPendingOrderAction() {
+	delete(s.Metadata, SessionKeyPendingOrderAction)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// OrderEvent 表示一次订单状态迁移记录（谁、何时、为什么）
+type OrderEvent struct {
+	ID         string
+	OrderID    string
+	TenantID   string
+	FromStatus OrderStatus // 迁移前状态（订单创建事件为空）
+	ToStatus   OrderStatus // 迁移后状态
+	Actor      string      // 操作者，如 "user:u_1001"、"admin"、"system"
+	Reason     string      // 迁移原因
+	CreatedAt  time.Time
+}
+
+// NewOrderEvent 创建订单事件，ToStatus 取订单当前状态
+func NewOrderEvent(order *Order, from OrderStatus, actor, reason string) *OrderEvent {
+	return &OrderEvent{
+		ID:         generateUniqueID("oev_", 24),
+		OrderID:    order.ID,
+		TenantID:   order.TenantID,
+		FromStatus: from,
+		ToStatus:   order.Status,
+		Actor:      actor,
+		Reason:     reason,
+		CreatedAt:  order.UpdatedAt,
+	}
+}
+
+// Validate 验证订单事件的有效性
+func (e *OrderEvent) Validate() error {
+	if e.OrderID == "" {
+		return ErrEmptyOrderID
+	}
+
+	if e.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+
+	if e.Actor == "" {
+		return ErrEmptyOrderEventActor
+	}
+
+	if !e.ToStatus.IsValid() {
+		return ErrInvalidOrderStatus
+	}
+
+	// 创建事件没有前置状态，其余事件必须符合状态机
+	if e.FromStatus != "" && !e.FromStatus.CanTransitionTo(e.ToStatus) {
+		return ErrInvalidOrderTransition
+	}
+
+	return nil
+}
+
+// IsCreation 判断是否为订单创建事件
+func (e *OrderEvent) IsCreation() bool {
+	return e.FromStatus == ""
+}
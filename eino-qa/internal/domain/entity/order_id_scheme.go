@@ -0,0 +1,221 @@
+package entity
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OrderIDScheme 订单号方案
+// 统一订单号的格式、生成、校验、文本提取和规范化，由实体、仓储和订单查询器共同使用
+type OrderIDScheme interface {
+	// Generate 生成规范形式的新订单号
+	// 订单号由外部系统导入的方案返回 ErrOrderIDGenerationDisabled
+	Generate() (string, error)
+
+	// Validate 校验订单号是否为规范形式
+	Validate(orderID string) error
+
+	// Normalize 将用户输入（可能缺少前缀、大小写不一致）转换为规范形式
+	Normalize(raw string) (string, bool)
+
+	// Extract 从自然语言文本中提取第一个订单号，返回规范形式
+	Extract(text string) (string, bool)
+}
+
+// OrderIDSchemeConfig 基于正则表达式的订单号方案配置
+type OrderIDSchemeConfig struct {
+	Prefix          string        // 规范形式的前缀，如 "#"、"SO-"
+	Pattern         string        // 前缀之后部分的正则表达式（不含锚点）
+	CaseInsensitive bool          // 是否忽略大小写（规范形式统一为大写）
+	Generator       func() string // 生成前缀之后的部分；为 nil 表示订单号由外部系统导入
+}
+
+// PatternOrderIDScheme 基于正则表达式的订单号方案
+type PatternOrderIDScheme struct {
+	prefix          string
+	caseInsensitive bool
+	generator       func() string
+	bodyPattern     *regexp.Regexp
+	fullPattern     *regexp.Regexp
+	searchPattern   *regexp.Regexp
+}
+
+// NewPatternOrderIDScheme 创建基于正则表达式的订单号方案
+func NewPatternOrderIDScheme(cfg OrderIDSchemeConfig) (*PatternOrderIDScheme, error) {
+	if cfg.Pattern == "" {
+		return nil, fmt.Errorf("order id pattern cannot be empty")
+	}
+
+	flags := ""
+	if cfg.CaseInsensitive {
+		flags = "(?i)"
+	}
+
+	body, err := regexp.Compile(`^(?:` + cfg.Pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order id pattern: %w", err)
+	}
+
+	prefix := regexp.QuoteMeta(cfg.Prefix)
+	scheme := &PatternOrderIDScheme{
+		prefix:          cfg.Prefix,
+		caseInsensitive: cfg.CaseInsensitive,
+		generator:       cfg.Generator,
+		bodyPattern:     body,
+		fullPattern:     regexp.MustCompile(flags + `^` + prefix + `(?:` + cfg.Pattern + `)$`),
+		// 前缀在文本中可省略，用户也常在订单号前加 #
+		searchPattern: regexp.MustCompile(flags + `(?:^|[^0-9A-Za-z])#?(?:` + prefix + `)?(` + cfg.Pattern + `)`),
+	}
+
+	// 校验生成器与格式一致
+	if scheme.generator != nil {
+		sample, _ := scheme.Generate()
+		if err := scheme.Validate(sample); err != nil {
+			return nil, fmt.Errorf("order id generator does not match pattern: %w", err)
+		}
+	}
+
+	return scheme, nil
+}
+
+// Generate 生成规范形式的新订单号
+func (s *PatternOrderIDScheme) Generate() (string, error) {
+	if s.generator == nil {
+		return "", ErrOrderIDGenerationDisabled
+	}
+	return s.canonical(s.generator()), nil
+}
+
+// Validate 校验订单号是否为规范形式
+func (s *PatternOrderIDScheme) Validate(orderID string) error {
+	if orderID == "" {
+		return ErrEmptyOrderID
+	}
+
+	if !s.fullPattern.MatchString(orderID) {
+		return fmt.Errorf("%w: %s", ErrInvalidOrderID, orderID)
+	}
+
+	if s.caseInsensitive && orderID != strings.ToUpper(orderID) {
+		return fmt.Errorf("%w: %s", ErrInvalidOrderID, orderID)
+	}
+
+	return nil
+}
+
+// Normalize 将用户输入转换为规范形式
+func (s *PatternOrderIDScheme) Normalize(raw string) (string, bool) {
+	body := strings.TrimSpace(raw)
+	if !strings.HasPrefix(s.prefix, "#") {
+		body = strings.TrimPrefix(body, "#")
+	}
+
+	if s.caseInsensitive {
+		if len(body) >= len(s.prefix) && strings.EqualFold(body[:len(s.prefix)], s.prefix) {
+			body = body[len(s.prefix):]
+		}
+		body = strings.ToUpper(body)
+	} else {
+		body = strings.TrimPrefix(body, s.prefix)
+	}
+
+	if !s.bodyPattern.MatchString(body) {
+		return "", false
+	}
+
+	return s.canonical(body), true
+}
+
+// Extract 从自然语言文本中提取第一个订单号
+func (s *PatternOrderIDScheme) Extract(text string) (string, bool) {
+	for _, loc := range s.searchPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[2], loc[3]
+		// 订单号后面不能紧跟字母或数字，避免截取更长编号的一部分
+		if end < len(text) && isAlphanumeric(text[end]) {
+			continue
+		}
+		if id, ok := s.Normalize(text[start:end]); ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// canonical 拼接规范形式
+func (s *PatternOrderIDScheme) canonical(body string) string {
+	if s.caseInsensitive {
+		body = strings.ToUpper(body)
+	}
+	return s.prefix + body
+}
+
+// isAlphanumeric 是否为 ASCII 字母或数字
+func isAlphanumeric(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// DateDigitsOrderIDGenerator 生成 "YYYYMMDD + n 位随机数字" 形式的订单号主体
+func DateDigitsOrderIDGenerator(digits int) func() string {
+	return func() string {
+		var sb strings.Builder
+		sb.WriteString(time.Now().Format("20060102"))
+		for i := 0; i < digits; i++ {
+			n, err := rand.Int(rand.Reader, big.NewInt(10))
+			if err != nil {
+				// 随机数生成失败时使用时间戳
+				n = big.NewInt((time.Now().UnixNano() + int64(i)) % 10)
+			}
+			sb.WriteByte(byte('0' + n.Int64()))
+		}
+		return sb.String()
+	}
+}
+
+// DefaultOrderIDScheme 默认订单号方案：#YYYYMMDD 加随机数字，共 11-20 位数字
+var DefaultOrderIDScheme OrderIDScheme = mustPatternOrderIDScheme(OrderIDSchemeConfig{
+	Prefix:    "#",
+	Pattern:   `\d{11,20}`,
+	Generator: DateDigitsOrderIDGenerator(6),
+})
+
+func mustPatternOrderIDScheme(cfg OrderIDSchemeConfig) *PatternOrderIDScheme {
+	scheme, err := NewPatternOrderIDScheme(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return scheme
+}
+
+// 租户订单号方案注册表
+var (
+	orderIDSchemesMu sync.RWMutex
+	orderIDSchemes   = make(map[string]OrderIDScheme)
+)
+
+// RegisterOrderIDScheme 为租户注册订单号方案（如从租户电商系统导入的订单号格式）
+func RegisterOrderIDScheme(tenantID string, scheme OrderIDScheme) {
+	orderIDSchemesMu.Lock()
+	defer orderIDSchemesMu.Unlock()
+
+	if scheme == nil {
+		delete(orderIDSchemes, tenantID)
+		return
+	}
+	orderIDSchemes[tenantID] = scheme
+}
+
+// OrderIDSchemeFor 获取租户的订单号方案，未注册时返回默认方案
+func OrderIDSchemeFor(tenantID string) OrderIDScheme {
+	orderIDSchemesMu.RLock()
+	defer orderIDSchemesMu.RUnlock()
+
+	if scheme, ok := orderIDSchemes[tenantID]; ok {
+		return scheme
+	}
+	return DefaultOrderIDScheme
+}
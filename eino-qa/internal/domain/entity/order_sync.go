@@ -0,0 +1,95 @@
+package entity
+
+import "time"
+
+// OrderSyncStatus 订单同步的运行状态
+type OrderSyncStatus string
+
+const (
+	// OrderSyncIdle 从未运行
+	OrderSyncIdle OrderSyncStatus = "idle"
+	// OrderSyncRunning 正在同步
+	OrderSyncRunning OrderSyncStatus = "running"
+	// OrderSyncSucceeded 最近一次同步成功
+	OrderSyncSucceeded OrderSyncStatus = "succeeded"
+	// OrderSyncFailed 最近一次同步失败
+	OrderSyncFailed OrderSyncStatus = "failed"
+)
+
+// OrderImportStats 订单导入统计
+type OrderImportStats struct {
+	Total     int // 读取的记录数
+	Created   int // 新建的订单数
+	Updated   int // 更新的订单数
+	Unchanged int // 无变化（或本地更新）的订单数
+	Failed    int // 校验或写入失败的记录数
+}
+
+// OrderSyncState 租户从外部电商系统同步订单的状态
+type OrderSyncState struct {
+	TenantID       string
+	Source         string          // 数据源名称
+	Status         OrderSyncStatus // 运行状态
+	Cursor         time.Time       // 增量游标：已同步到的外部更新时间，下次以 updated_since=Cursor 拉取
+	LastStartedAt  time.Time
+	LastFinishedAt time.Time
+	LastSuccessAt  time.Time
+	LastError      string
+	LastStats      OrderImportStats // 最近一次运行的统计
+}
+
+// NewOrderSyncState 创建从未同步过的状态
+func NewOrderSyncState(tenantID, source string) *OrderSyncState {
+	return &OrderSyncState{
+		TenantID: tenantID,
+		Source:   source,
+		Status:   OrderSyncIdle,
+	}
+}
+
+// Validate 验证同步状态的有效性
+func (s *OrderSyncState) Validate() error {
+	if s.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+
+	if s.Source == "" {
+		return ErrEmptyOrderSyncSource
+	}
+
+	return nil
+}
+
+// Start 标记同步开始
+func (s *OrderSyncState) Start() {
+	s.Status = OrderSyncRunning
+	s.LastStartedAt = time.Now()
+	s.LastError = ""
+	s.LastStats = OrderImportStats{}
+}
+
+// Finish 标记同步结束
+// 成功时推进游标；失败时保留原游标，下次从同一位置重新拉取（写入是幂等的）
+func (s *OrderSyncState) Finish(stats OrderImportStats, cursor time.Time, err error) {
+	now := time.Now()
+	s.LastFinishedAt = now
+	s.LastStats = stats
+
+	if err != nil {
+		s.Status = OrderSyncFailed
+		s.LastError = err.Error()
+		return
+	}
+
+	s.Status = OrderSyncSucceeded
+	s.LastSuccessAt = now
+	if cursor.After(s.Cursor) {
+		s.Cursor = cursor
+	}
+}
+
+// IsRunning 判断同步是否正在运行
+// 超过 staleAfter 仍为运行状态的记录视为上次进程异常退出，不再阻止新的同步
+func (s *OrderSyncState) IsRunning(staleAfter time.Duration) bool {
+	return s.Status == OrderSyncRunning && time.Since(s.LastStartedAt) < staleAfter
+}
@@ -0,0 +1,163 @@
+package entity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var (
+	// PromptTemplate 相关错误
+	ErrInvalidPromptName      = errors.New("invalid prompt name")
+	ErrEmptyPromptContent     = errors.New("prompt template content cannot be empty")
+	ErrPromptTooLong          = errors.New("prompt template content is too long")
+	ErrPromptRenderFailed     = errors.New("prompt template failed to render")
+	ErrPromptTemplateNotFound = errors.New("prompt template not found")
+)
+
+// MaxPromptTemplateLength 提示词模板的最大长度（字节）
+const MaxPromptTemplateLength = 16 * 1024
+
+// PromptName 定义可自定义的系统提示词名称
+type PromptName string
+
+const (
+	// PromptIntent 意图识别
+	PromptIntent PromptName = "intent"
+	// PromptRAG 课程咨询（RAG）回答
+	PromptRAG PromptName = "rag"
+	// PromptRAGStrict 回答依据不足时的严格重新生成
+	PromptRAGStrict PromptName = "rag_strict"
+	// PromptOrderDetail 单个订单详情回答
+	PromptOrderDetail PromptName = "order_detail"
+	// PromptOrderList 订单列表回答
+	PromptOrderList PromptName = "order_list"
+	// PromptOrderStats 订单统计回答
+	PromptOrderStats PromptName = "order_stats"
+	// PromptResponse 直接回答
+	PromptResponse PromptName = "response"
+)
+
+// PromptNames 所有可自定义的提示词名称
+var PromptNames = []PromptName{
+	PromptIntent,
+	PromptRAG,
+	PromptRAGStrict,
+	PromptOrderDetail,
+	PromptOrderList,
+	PromptOrderStats,
+	PromptResponse,
+}
+
+// IsValid 判断提示词名称是否有效
+func (n PromptName) IsValid() bool {
+	for _, name := range PromptNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PromptData 渲染提示词模板时可用的变量
+// 模板中通过 {{.BrandName}}、{{.Vars.xxx}} 等方式引用
+type PromptData struct {
+	TenantID     string
+	BrandName    string            // 品牌名称
+	ProductScope string            // 业务范围，如"编程课程"
+	Language     string            // 回答语言
+	Vars         map[string]string // 租户自定义变量
+}
+
+// SamplePromptData 返回用于校验模板的示例数据
+// vars 为租户实际配置的自定义变量，保证引用这些变量的模板可以通过校验
+func SamplePromptData(vars map[string]string) PromptData {
+	sampleVars := make(map[string]string, len(vars))
+	for k, v := range vars {
+		if v == "" {
+			v = "示例"
+		}
+		sampleVars[k] = v
+	}
+
+	return PromptData{
+		TenantID:     "sample_tenant",
+		BrandName:    "示例教育",
+		ProductScope: "编程课程",
+		Language:     "中文",
+		Vars:         sampleVars,
+	}
+}
+
+// RenderPrompt 使用 text/template 渲染提示词模板
+// 引用不存在的自定义变量或渲染结果为空时返回错误
+func RenderPrompt(content string, data PromptData) (string, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPromptRenderFailed, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPromptRenderFailed, err)
+	}
+
+	rendered := strings.TrimSpace(buf.String())
+	if rendered == "" {
+		return "", fmt.Errorf("%w: rendered prompt is empty", ErrPromptRenderFailed)
+	}
+	return rendered, nil
+}
+
+// PromptTemplate 表示租户的一个提示词模板版本
+// 同一名称的模板按版本号递增保存，最多只有一个版本处于启用状态；
+// 没有启用版本时使用内置默认模板
+type PromptTemplate struct {
+	ID          string
+	TenantID    string
+	Name        PromptName
+	Version     int
+	Content     string
+	Comment     string // 版本说明
+	Active      bool
+	CreatedAt   time.Time
+	ActivatedAt *time.Time
+}
+
+// NewPromptTemplate 创建新的提示词模板版本（未启用）
+func NewPromptTemplate(tenantID string, name PromptName, version int, content, comment string) *PromptTemplate {
+	return &PromptTemplate{
+		ID:        generateUniqueID("pt_", 16),
+		TenantID:  tenantID,
+		Name:      name,
+		Version:   version,
+		Content:   content,
+		Comment:   comment,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate 验证提示词模板的有效性（不包括渲染校验）
+func (t *PromptTemplate) Validate() error {
+	if t.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if !t.Name.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidPromptName, t.Name)
+	}
+	if strings.TrimSpace(t.Content) == "" {
+		return ErrEmptyPromptContent
+	}
+	if len(t.Content) > MaxPromptTemplateLength {
+		return ErrPromptTooLong
+	}
+	return nil
+}
+
+// Render 使用给定数据渲染模板
+func (t *PromptTemplate) Render(data PromptData) (string, error) {
+	return RenderPrompt(t.Content, data)
+}
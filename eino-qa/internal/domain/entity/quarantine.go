@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// 文档隔离相关错误
+	ErrSuspiciousDocument          = errors.New("document contains suspected prompt injection")
+	ErrQuarantinedDocumentNotFound = errors.New("quarantined document not found")
+)
+
+// QuarantinedDocument 疑似包含提示词注入、暂未写入知识库的文档
+// 管理员确认无害后可放行写入知识库，或直接删除
+type QuarantinedDocument struct {
+	ID        string
+	TenantID  string
+	Content   string
+	Metadata  map[string]any // 写入请求携带的元数据，放行时原样写入
+	Score     float64        // 可疑分数，0~1
+	Rules     []string       // 命中的检测规则
+	Method    string         // 检测方式：heuristic、llm
+	Reason    string         // LLM 给出的判断理由
+	CreatedAt time.Time
+}
+
+// NewQuarantinedDocument 创建隔离文档
+func NewQuarantinedDocument(tenantID, content string, metadata map[string]any) *QuarantinedDocument {
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	return &QuarantinedDocument{
+		ID:        generateUniqueID("qdoc_", 16),
+		TenantID:  tenantID,
+		Content:   content,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate 验证隔离文档的有效性
+func (q *QuarantinedDocument) Validate() error {
+	if q.Content == "" {
+		return ErrEmptyContent
+	}
+
+	if q.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+
+	return nil
+}
@@ -100,6 +100,30 @@ func (s *Session) GetAssistantMessages() []*Message {
 	return assistantMessages
 }
 
+// FindMessage 根据 ID 查找消息，不存在时返回 nil
+func (s *Session) FindMessage(messageID string) *Message {
+	for _, msg := range s.Messages {
+		if msg.ID == messageID {
+			return msg
+		}
+	}
+	return nil
+}
+
+// PrecedingUserMessage 返回指定消息之前最近的一条用户消息
+func (s *Session) PrecedingUserMessage(messageID string) *Message {
+	var last *Message
+	for _, msg := range s.Messages {
+		if msg.ID == messageID {
+			return last
+		}
+		if msg.IsUser() {
+			last = msg
+		}
+	}
+	return nil
+}
+
 // ExtendExpiration 延长会话过期时间
 func (s *Session) ExtendExpiration(duration time.Duration) {
 	s.ExpiresAt = time.Now().Add(duration)
@@ -2,6 +2,9 @@ package entity
 
 import "errors"
 
+// DefaultTenantID 未指定租户时使用的默认租户
+const DefaultTenantID = "default"
+
 var (
 	// Tenant 相关错误
 	ErrInvalidTenantID = errors.New("invalid tenant ID")
@@ -42,7 +45,15 @@ func (t *Tenant) Validate() error {
 
 // IsDefault 判断是否为默认租户
 func (t *Tenant) IsDefault() bool {
-	return t.ID == "default"
+	return t.ID == DefaultTenantID
+}
+
+// NormalizeTenantID 规范化租户 ID，为空时返回默认租户
+func NormalizeTenantID(tenantID string) string {
+	if tenantID == "" {
+		return DefaultTenantID
+	}
+	return tenantID
 }
 
 // generateCollectionName 生成 Milvus Collection 名称
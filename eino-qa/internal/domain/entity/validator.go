@@ -7,24 +7,14 @@ import (
 )
 
 var (
-	// 订单 ID 格式: #YYYYMMDDXXX
-	orderIDPattern = regexp.MustCompile(`^#\d{8}\d{3}$`)
-
 	// 会话 ID 格式: sess_YYYYMMDDHHMMSSXXXXXXXXXXXXXXXX
 	sessionIDPattern = regexp.MustCompile(`^sess_\d{14}[a-z0-9]{16}$`)
 )
 
-// ValidateOrderID 验证订单 ID 格式
+// ValidateOrderID 按默认订单号方案验证订单 ID 格式
+// 租户自定义方案请使用 OrderIDSchemeFor(tenantID).Validate
 func ValidateOrderID(orderID string) error {
-	if orderID == "" {
-		return errors.New("order ID cannot be empty")
-	}
-
-	if !orderIDPattern.MatchString(orderID) {
-		return errors.New("invalid order ID format, expected #YYYYMMDDXXX")
-	}
-
-	return nil
+	return DefaultOrderIDScheme.Validate(orderID)
 }
 
 // ValidateSessionID 验证会话 ID 格式
@@ -62,13 +52,13 @@ func ValidateConfidence(confidence float64) error {
 	return nil
 }
 
-// ExtractOrderID 从文本中提取订单 ID
+// ExtractOrderID 按默认订单号方案从文本中提取订单 ID
 func ExtractOrderID(text string) (string, error) {
-	matches := orderIDPattern.FindStringSubmatch(text)
-	if len(matches) == 0 {
+	orderID, ok := DefaultOrderIDScheme.Extract(text)
+	if !ok {
 		return "", errors.New("no order ID found in text")
 	}
-	return matches[0], nil
+	return orderID, nil
 }
 
 // SanitizeSQL 清理 SQL 查询，防止注入
@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+var (
+	// Webhook 相关错误
+	ErrInvalidWebhookURL   = errors.New("webhook url must be an absolute http(s) url")
+	ErrInvalidWebhookEvent = errors.New("invalid webhook event type")
+	ErrEmptyWebhookEvents  = errors.New("webhook must subscribe to at least one event")
+	ErrWebhookNotFound     = errors.New("webhook not found")
+)
+
+// WebhookEventType 定义 Webhook 事件类型
+type WebhookEventType string
+
+const (
+	// EventHandoffCreated 对话转接人工
+	EventHandoffCreated WebhookEventType = "handoff.created"
+	// EventRAGMiss 知识库检索未命中
+	EventRAGMiss WebhookEventType = "rag.miss"
+	// EventFeedbackNegative 用户给出负面反馈
+	EventFeedbackNegative WebhookEventType = "feedback.negative"
+	// EventJobFinished 后台任务完成
+	EventJobFinished WebhookEventType = "job.finished"
+	// EventOrderActionExecuted 用户在对话中确认并执行了订单操作
+	EventOrderActionExecuted WebhookEventType = "order.action_executed"
+)
+
+// IsValid 判断事件类型是否有效
+func (t WebhookEventType) IsValid() bool {
+	switch t {
+	case EventHandoffCreated, EventRAGMiss, EventFeedbackNegative, EventJobFinished, EventOrderActionExecuted:
+		return true
+	}
+	return false
+}
+
+// WebhookEndpoint 表示租户配置的 Webhook 订阅端点
+type WebhookEndpoint struct {
+	ID        string
+	TenantID  string
+	URL       string
+	Secret    string // HMAC 签名密钥
+	Events    []WebhookEventType
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWebhookEndpoint 创建新的 Webhook 端点，并生成签名密钥
+func NewWebhookEndpoint(tenantID, endpointURL string, events []WebhookEventType) *WebhookEndpoint {
+	now := time.Now()
+	return &WebhookEndpoint{
+		ID:        generateUniqueID("wh_", 16),
+		TenantID:  tenantID,
+		URL:       endpointURL,
+		Secret:    generateUniqueID("whsec_", 32),
+		Events:    events,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate 验证 Webhook 端点的有效性
+func (w *WebhookEndpoint) Validate() error {
+	if w.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+
+	parsed, err := url.Parse(w.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ErrInvalidWebhookURL
+	}
+
+	if len(w.Events) == 0 {
+		return ErrEmptyWebhookEvents
+	}
+
+	for _, event := range w.Events {
+		if !event.IsValid() {
+			return ErrInvalidWebhookEvent
+		}
+	}
+
+	return nil
+}
+
+// Subscribes 判断端点是否订阅了指定事件
+func (w *WebhookEndpoint) Subscribes(eventType WebhookEventType) bool {
+	if !w.Enabled {
+		return false
+	}
+	for _, event := range w.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent 表示一次待投递的事件
+type WebhookEvent struct {
+	ID        string
+	Type      WebhookEventType
+	TenantID  string
+	Data      map[string]any
+	CreatedAt time.Time
+}
+
+// NewWebhookEvent 创建新的 Webhook 事件
+func NewWebhookEvent(tenantID string, eventType WebhookEventType, data map[string]any) *WebhookEvent {
+	if data == nil {
+		data = make(map[string]any)
+	}
+	return &WebhookEvent{
+		ID:        generateUniqueID("evt_", 24),
+		Type:      eventType,
+		TenantID:  tenantID,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+}
+
+// WebhookDeliveryStatus 定义死信投递状态
+type WebhookDeliveryStatus string
+
+const (
+	// DeliveryStatusFailed 投递失败，等待重放
+	DeliveryStatusFailed WebhookDeliveryStatus = "failed"
+	// DeliveryStatusReplayed 重放成功
+	DeliveryStatusReplayed WebhookDeliveryStatus = "replayed"
+)
+
+// WebhookDelivery 表示重试耗尽后进入死信表的投递记录
+type WebhookDelivery struct {
+	ID         string
+	TenantID   string
+	EndpointID string
+	EventID    string
+	EventType  WebhookEventType
+	Payload    string // 原始 JSON 负载，重放时原样发送
+	Attempts   int
+	LastError  string
+	Status     WebhookDeliveryStatus
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewWebhookDelivery 创建死信记录
+func NewWebhookDelivery(endpoint *WebhookEndpoint, event *WebhookEvent, payload string, attempts int, lastErr error) *WebhookDelivery {
+	now := time.Now()
+	delivery := &WebhookDelivery{
+		ID:         generateUniqueID("dlq_", 16),
+		TenantID:   endpoint.TenantID,
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		Payload:    payload,
+		Attempts:   attempts,
+		Status:     DeliveryStatusFailed,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if lastErr != nil {
+		delivery.LastError = lastErr.Error()
+	}
+	return delivery
+}
+
+// IsReplayed 判断死信是否已重放成功
+func (d *WebhookDelivery) IsReplayed() bool {
+	return d.Status == DeliveryStatusReplayed
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// ExperimentRepository 定义 A/B 实验结果存储接口
+type ExperimentRepository interface {
+	// SaveOutcome 保存一轮对话的实验结果
+	// outcome: 实验结果记录
+	// 返回: 错误
+	SaveOutcome(ctx context.Context, outcome *entity.ExperimentOutcome) error
+
+	// Results 按分组汇总实验结果，反馈按助手消息 ID 关联用户反馈
+	// experiment: 实验名称
+	// since: 只统计该时间之后的记录，零值表示不限
+	// 返回: 各分组的汇总结果和错误
+	Results(ctx context.Context, experiment string, since time.Time) ([]*entity.VariantResult, error)
+}
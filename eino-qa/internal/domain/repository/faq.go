@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// FAQRepository 定义标准问答存储接口
+type FAQRepository interface {
+	// Create 创建标准问答
+	// faq: 标准问答实体
+	// 返回: 错误
+	Create(ctx context.Context, faq *entity.FAQ) error
+
+	// Update 更新标准问答
+	// faq: 标准问答实体
+	// 返回: 错误（不存在时返回 entity.ErrFAQNotFound）
+	Update(ctx context.Context, faq *entity.FAQ) error
+
+	// FindByID 根据 ID 查询标准问答
+	// id: 标准问答 ID
+	// 返回: 标准问答实体和错误
+	FindByID(ctx context.Context, id string) (*entity.FAQ, error)
+
+	// List 列出租户的所有标准问答（按创建时间排序）
+	// 返回: 标准问答列表和错误
+	List(ctx context.Context) ([]*entity.FAQ, error)
+
+	// Delete 删除标准问答
+	// id: 标准问答 ID
+	// 返回: 错误（不存在时返回 entity.ErrFAQNotFound）
+	Delete(ctx context.Context, id string) error
+}
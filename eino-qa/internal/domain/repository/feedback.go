@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// FeedbackFilter 反馈列表过滤条件
+type FeedbackFilter struct {
+	Rating        entity.FeedbackRating // 为空表示不过滤
+	Status        entity.FeedbackStatus // 为空表示不过滤
+	HasCorrection bool                  // 仅返回附带纠正答案的反馈
+}
+
+// FeedbackRepository 定义用户反馈存储接口
+type FeedbackRepository interface {
+	// Save 保存反馈（同一消息的反馈会被覆盖）
+	// feedback: 反馈实体
+	// 返回: 错误
+	Save(ctx context.Context, feedback *entity.Feedback) error
+
+	// FindByID 根据 ID 查询反馈
+	// id: 反馈 ID
+	// 返回: 反馈实体和错误
+	FindByID(ctx context.Context, id string) (*entity.Feedback, error)
+
+	// FindByMessageID 根据消息 ID 查询反馈
+	// messageID: 助手消息 ID
+	// 返回: 反馈实体和错误
+	FindByMessageID(ctx context.Context, messageID string) (*entity.Feedback, error)
+
+	// List 列出反馈（按时间倒序，支持分页）
+	// filter: 过滤条件
+	// offset: 偏移量
+	// limit: 限制数量
+	// 返回: 反馈列表和错误
+	List(ctx context.Context, filter FeedbackFilter, offset, limit int) ([]*entity.Feedback, error)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// MissedQueryRepository 定义知识库未命中查询的存储接口
+type MissedQueryRepository interface {
+	// Create 记录未命中查询
+	// query: 未命中查询实体
+	// 返回: 错误
+	Create(ctx context.Context, query *entity.MissedQuery) error
+
+	// List 列出未命中查询（按时间倒序，支持分页）
+	// offset: 偏移量
+	// limit: 限制数量
+	// 返回: 未命中查询列表和错误
+	List(ctx context.Context, offset, limit int) ([]*entity.MissedQuery, error)
+
+	// FindByIDs 根据 ID 批量查询
+	// ids: 记录 ID 列表
+	// 返回: 未命中查询列表和错误
+	FindByIDs(ctx context.Context, ids []uint) ([]*entity.MissedQuery, error)
+
+	// Count 获取未命中查询总数
+	// 返回: 数量和错误
+	Count(ctx context.Context) (int64, error)
+
+	// DeleteOlderThan 删除指定时间之前的记录
+	// before: 截止时间
+	// 返回: 删除数量和错误
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
+}
@@ -3,6 +3,36 @@ package repository
 import (
 	"context"
 	"eino-qa/internal/domain/entity"
+	"time"
+)
+
+// OrderFilter 订单查询条件
+// 只包含白名单字段，由仓储实现转换为参数化查询
+type OrderFilter struct {
+	UserID        string               // 用户 ID（为空表示不过滤）
+	Statuses      []entity.OrderStatus // 订单状态（为空表示不过滤）
+	CreatedAfter  time.Time            // 创建时间下界（含，零值表示不限）
+	CreatedBefore time.Time            // 创建时间上界（不含，零值表示不限）
+	CourseName    string               // 课程名称（模糊匹配）
+	Limit         int                  // 最大返回数量（<=0 表示不限）
+}
+
+// OrderAggregate 订单聚合结果
+type OrderAggregate struct {
+	Count       int64   // 订单数量
+	TotalAmount float64 // 订单总金额
+}
+
+// OrderUpsertResult 订单导入写入结果
+type OrderUpsertResult string
+
+const (
+	// OrderUpsertCreated 新建了订单
+	OrderUpsertCreated OrderUpsertResult = "created"
+	// OrderUpsertUpdated 更新了已有订单
+	OrderUpsertUpdated OrderUpsertResult = "updated"
+	// OrderUpsertUnchanged 已有订单与导入数据一致或比导入数据新，未修改
+	OrderUpsertUnchanged OrderUpsertResult = "unchanged"
 )
 
 // OrderRepository 定义订单数据库操作接口
@@ -22,16 +52,46 @@ type OrderRepository interface {
 	// 返回: 订单列表和错误
 	FindByStatus(ctx context.Context, status entity.OrderStatus) ([]*entity.Order, error)
 
+	// FindByFilter 按过滤条件查询订单列表（按创建时间倒序）
+	// filter: 过滤条件
+	// 返回: 订单列表和错误
+	FindByFilter(ctx context.Context, filter OrderFilter) ([]*entity.Order, error)
+
+	// Aggregate 按过滤条件统计订单数量和总金额（忽略 Limit）
+	// filter: 过滤条件
+	// 返回: 聚合结果和错误
+	Aggregate(ctx context.Context, filter OrderFilter) (*OrderAggregate, error)
+
 	// Create 创建新订单
 	// order: 订单实体
 	// 返回: 错误
 	Create(ctx context.Context, order *entity.Order) error
 
-	// Update 更新订单
+	// Update 更新订单（不能修改订单状态，状态变更请使用 SaveTransition）
 	// order: 订单实体
 	// 返回: 错误
 	Update(ctx context.Context, order *entity.Order) error
 
+	// SaveTransition 在同一事务中更新订单状态并记录订单事件
+	// 数据库中的订单状态必须仍为 event.FromStatus，否则返回 entity.ErrOrderStatusConflict
+	// order: 已完成状态迁移的订单实体
+	// event: 本次迁移的订单事件
+	// 返回: 错误
+	SaveTransition(ctx context.Context, order *entity.Order, event *entity.OrderEvent) error
+
+	// Upsert 按订单 ID 新建或更新订单（用于从外部电商系统导入）
+	// 外部系统为准：状态变化直接应用并以 actor 记录订单事件，不受状态机限制；
+	// 已有订单的更新时间晚于导入数据时不修改
+	// order: 订单实体（UpdatedAt 为外部系统中的更新时间）
+	// actor: 订单事件的操作者，如 "import:csv"、"sync:shop"
+	// 返回: 写入结果和错误
+	Upsert(ctx context.Context, order *entity.Order, actor string) (OrderUpsertResult, error)
+
+	// FindEvents 查询订单的状态迁移记录（按时间正序）
+	// orderID: 订单 ID
+	// 返回: 订单事件列表和错误
+	FindEvents(ctx context.Context, orderID string) ([]*entity.OrderEvent, error)
+
 	// Delete 删除订单
 	// orderID: 订单 ID
 	// 返回: 错误
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// OrderSyncRepository 定义订单同步状态的存储接口
+type OrderSyncRepository interface {
+	// GetState 获取数据源的同步状态
+	// source: 数据源名称
+	// 返回: 同步状态（从未同步过时返回 entity.ErrOrderSyncStateNotFound）和错误
+	GetState(ctx context.Context, source string) (*entity.OrderSyncState, error)
+
+	// SaveState 保存同步状态（不存在时创建）
+	// state: 同步状态
+	// 返回: 错误
+	SaveState(ctx context.Context, state *entity.OrderSyncState) error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// PromptTemplateRepository 定义提示词模板版本存储接口
+type PromptTemplateRepository interface {
+	// Create 保存新的模板版本，版本号在事务内按名称递增分配并回写到 template.Version
+	// template: 模板实体
+	// 返回: 错误
+	Create(ctx context.Context, template *entity.PromptTemplate) error
+
+	// FindActive 查询启用中的模板版本
+	// name: 提示词名称
+	// 返回: 模板实体和错误（没有启用版本时返回 entity.ErrPromptTemplateNotFound）
+	FindActive(ctx context.Context, name entity.PromptName) (*entity.PromptTemplate, error)
+
+	// FindVersion 查询指定版本
+	// name: 提示词名称
+	// version: 版本号
+	// 返回: 模板实体和错误
+	FindVersion(ctx context.Context, name entity.PromptName, version int) (*entity.PromptTemplate, error)
+
+	// ListVersions 列出模板的所有版本（按版本号倒序）
+	// name: 提示词名称
+	// 返回: 模板版本列表和错误
+	ListVersions(ctx context.Context, name entity.PromptName) ([]*entity.PromptTemplate, error)
+
+	// ListActive 列出租户所有启用中的模板
+	// 返回: 模板列表和错误
+	ListActive(ctx context.Context) ([]*entity.PromptTemplate, error)
+
+	// Activate 启用指定版本，同名的其他版本自动停用
+	// name: 提示词名称
+	// version: 版本号
+	// 返回: 错误
+	Activate(ctx context.Context, name entity.PromptName, version int) error
+
+	// Deactivate 停用模板的所有版本，之后使用内置默认模板
+	// name: 提示词名称
+	// 返回: 错误
+	Deactivate(ctx context.Context, name entity.PromptName) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// QuarantineRepository 定义隔离文档存储接口
+type QuarantineRepository interface {
+	// Create 保存隔离文档
+	// doc: 隔离文档实体
+	// 返回: 错误
+	Create(ctx context.Context, doc *entity.QuarantinedDocument) error
+
+	// FindByID 根据 ID 查询隔离文档
+	// id: 隔离文档 ID
+	// 返回: 隔离文档实体和错误（不存在时返回 entity.ErrQuarantinedDocumentNotFound）
+	FindByID(ctx context.Context, id string) (*entity.QuarantinedDocument, error)
+
+	// List 列出租户的隔离文档（按隔离时间倒序）
+	// 返回: 隔离文档列表和错误
+	List(ctx context.Context) ([]*entity.QuarantinedDocument, error)
+
+	// Delete 删除隔离文档
+	// id: 隔离文档 ID
+	// 返回: 错误（不存在时返回 entity.ErrQuarantinedDocumentNotFound）
+	Delete(ctx context.Context, id string) error
+}
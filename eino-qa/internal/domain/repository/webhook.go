@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"eino-qa/internal/domain/entity"
+)
+
+// WebhookRepository 定义 Webhook 端点与死信存储操作接口
+type WebhookRepository interface {
+	// CreateEndpoint 创建 Webhook 端点
+	// endpoint: 端点实体
+	// 返回: 错误
+	CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+
+	// FindEndpointByID 根据 ID 查询端点
+	// id: 端点 ID
+	// 返回: 端点实体和错误
+	FindEndpointByID(ctx context.Context, id string) (*entity.WebhookEndpoint, error)
+
+	// ListEndpoints 列出租户的所有端点
+	// 返回: 端点列表和错误
+	ListEndpoints(ctx context.Context) ([]*entity.WebhookEndpoint, error)
+
+	// DeleteEndpoint 删除端点
+	// id: 端点 ID
+	// 返回: 错误
+	DeleteEndpoint(ctx context.Context, id string) error
+
+	// SaveDeadLetter 保存死信记录（存在则更新）
+	// delivery: 死信记录
+	// 返回: 错误
+	SaveDeadLetter(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// FindDeadLetterByID 根据 ID 查询死信记录
+	// id: 死信 ID
+	// 返回: 死信记录和错误
+	FindDeadLetterByID(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+
+	// ListDeadLetters 列出死信记录（支持分页）
+	// offset: 偏移量
+	// limit: 限制数量
+	// 返回: 死信列表和错误
+	ListDeadLetters(ctx context.Context, offset, limit int) ([]*entity.WebhookDelivery, error)
+}
@@ -0,0 +1,390 @@
+package eino
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+const (
+	// defaultEmbedCacheSize 内存缓存默认的条目数
+	defaultEmbedCacheSize = 10000
+	// defaultEmbedBatchSize 单次调用嵌入模型的默认最大文本数（DashScope 批量上限）
+	defaultEmbedBatchSize = 25
+	// defaultEmbedConcurrency 默认的最大并发调用数
+	defaultEmbedConcurrency = 4
+)
+
+// EmbedCacheConfig 嵌入模型缓存与批处理配置
+type EmbedCacheConfig struct {
+	Model          string        // 缓存键中的模型名，调用选项指定了模型时使用选项中的模型
+	Size           int           // 内存 LRU 缓存的条目数，默认 10000
+	Dir            string        // 磁盘缓存目录，为空时只使用内存缓存
+	BatchWindow    time.Duration // 合并并发单条请求的等待窗口，0 表示不合并
+	MaxBatchSize   int           // 单次调用嵌入模型的最大文本数，默认 25
+	MaxConcurrency int           // 最大并发调用数，默认 4
+}
+
+// EmbedCacheStats 嵌入缓存统计
+type EmbedCacheStats struct {
+	MemoryHits int64 // 内存缓存命中的文本数
+	DiskHits   int64 // 磁盘缓存命中的文本数
+	Misses     int64 // 需要调用嵌入模型的文本数
+	Calls      int64 // 调用嵌入模型的次数
+}
+
+// CachedEmbedder 带缓存和批处理的嵌入模型装饰器
+// 文本按模型名和文本哈希缓存在内存 LRU 中（可选落盘）；未命中的单条请求在 BatchWindow 内合并成一批，
+// 多条请求按 MaxBatchSize 拆分，所有对嵌入模型的调用受 MaxConcurrency 限制
+type CachedEmbedder struct {
+	inner  embedding.Embedder
+	config EmbedCacheConfig
+
+	lru  *embedLRU
+	sem  chan struct{}
+	disk bool
+
+	mu      sync.Mutex
+	pending *embedBatch // 正在等待合并的单条请求
+
+	memoryHits, diskHits, misses, calls atomic.Int64
+}
+
+// embedBatch 等待合并调用的单条请求
+type embedBatch struct {
+	ctx     context.Context
+	model   string
+	texts   []string
+	waiters map[string][]chan embedResult
+	timer   *time.Timer
+}
+
+// embedResult 单条文本的嵌入结果
+type embedResult struct {
+	vector []float64
+	err    error
+}
+
+// NewCachedEmbedder 创建带缓存和批处理的嵌入模型
+func NewCachedEmbedder(inner embedding.Embedder, config EmbedCacheConfig) (*CachedEmbedder, error) {
+	if config.Size <= 0 {
+		config.Size = defaultEmbedCacheSize
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = defaultEmbedBatchSize
+	}
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = defaultEmbedConcurrency
+	}
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create embedding cache dir: %w", err)
+		}
+	}
+
+	return &CachedEmbedder{
+		inner:  inner,
+		config: config,
+		lru:    newEmbedLRU(config.Size),
+		sem:    make(chan struct{}, config.MaxConcurrency),
+		disk:   config.Dir != "",
+	}, nil
+}
+
+// EmbedStrings 生成文本向量，返回的向量与输入文本一一对应
+func (e *CachedEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	model := e.config.Model
+	if o := embedding.GetCommonOptions(nil, opts...); o.Model != nil && *o.Model != "" {
+		model = *o.Model
+	}
+
+	vectors := make([][]float64, len(texts))
+	missing := make(map[string][]int) // 未命中的文本到其在输入中的位置
+	var order []string
+	for i, text := range texts {
+		key := embedCacheKey(model, text)
+		if vector, ok := e.lookup(key); ok {
+			vectors[i] = vector
+			continue
+		}
+		if _, seen := missing[text]; !seen {
+			order = append(order, text)
+		}
+		missing[text] = append(missing[text], i)
+	}
+	if len(order) == 0 {
+		return vectors, nil
+	}
+	e.misses.Add(int64(len(order)))
+
+	var embedded [][]float64
+	var err error
+	if len(order) == 1 && len(opts) == 0 && e.config.BatchWindow > 0 {
+		var vector []float64
+		vector, err = e.enqueue(ctx, model, order[0])
+		embedded = [][]float64{vector}
+	} else {
+		embedded, err = e.embedBatches(ctx, model, order, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, text := range order {
+		for _, idx := range missing[text] {
+			vectors[idx] = append([]float64(nil), embedded[i]...)
+		}
+	}
+	return vectors, nil
+}
+
+// Stats 获取缓存统计
+func (e *CachedEmbedder) Stats() EmbedCacheStats {
+	return EmbedCacheStats{
+		MemoryHits: e.memoryHits.Load(),
+		DiskHits:   e.diskHits.Load(),
+		Misses:     e.misses.Load(),
+		Calls:      e.calls.Load(),
+	}
+}
+
+// lookup 依次查询内存和磁盘缓存，返回向量的副本
+func (e *CachedEmbedder) lookup(key string) ([]float64, bool) {
+	if vector, ok := e.lru.get(key); ok {
+		e.memoryHits.Add(1)
+		return append([]float64(nil), vector...), true
+	}
+	if !e.disk {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(e.diskPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var vector []float64
+	if err := json.Unmarshal(data, &vector); err != nil || len(vector) == 0 {
+		return nil, false
+	}
+	e.diskHits.Add(1)
+	e.lru.add(key, vector)
+	return append([]float64(nil), vector...), true
+}
+
+// store 写入内存缓存，启用磁盘缓存时同时落盘
+// 磁盘缓存只是加速手段，写入失败时忽略
+func (e *CachedEmbedder) store(model, text string, vector []float64) {
+	key := embedCacheKey(model, text)
+	e.lru.add(key, vector)
+	if !e.disk {
+		return
+	}
+
+	data, err := json.Marshal(vector)
+	if err != nil {
+		return
+	}
+	path := e.diskPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// diskPath 缓存键对应的磁盘文件，按键的前两位分目录
+func (e *CachedEmbedder) diskPath(key string) string {
+	return filepath.Join(e.config.Dir, key[:2], key+".json")
+}
+
+// enqueue 将单条文本加入等待合并的批次，批次在窗口结束或达到批量上限时调用嵌入模型
+func (e *CachedEmbedder) enqueue(ctx context.Context, model, text string) ([]float64, error) {
+	ch := make(chan embedResult, 1)
+
+	e.mu.Lock()
+	batch := e.pending
+	if batch == nil || batch.model != model {
+		if batch != nil {
+			e.flushLocked()
+		}
+		// 合并后的调用不随单个请求取消
+		batch = &embedBatch{
+			ctx:     context.WithoutCancel(ctx),
+			model:   model,
+			waiters: make(map[string][]chan embedResult),
+		}
+		batch.timer = time.AfterFunc(e.config.BatchWindow, func() { e.flush(batch) })
+		e.pending = batch
+	}
+	if _, ok := batch.waiters[text]; !ok {
+		batch.texts = append(batch.texts, text)
+	}
+	batch.waiters[text] = append(batch.waiters[text], ch)
+	if len(batch.texts) >= e.config.MaxBatchSize {
+		e.flushLocked()
+	}
+	e.mu.Unlock()
+
+	select {
+	case result := <-ch:
+		return result.vector, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush 窗口结束时调用嵌入模型（批次已因达到上限提前发出时忽略）
+func (e *CachedEmbedder) flush(batch *embedBatch) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.pending == batch {
+		e.flushLocked()
+	}
+}
+
+// flushLocked 发出当前等待的批次（调用方需持有锁）
+func (e *CachedEmbedder) flushLocked() {
+	batch := e.pending
+	e.pending = nil
+	batch.timer.Stop()
+
+	go func() {
+		vectors, err := e.embedBatches(batch.ctx, batch.model, batch.texts)
+		for i, text := range batch.texts {
+			result := embedResult{err: err}
+			if err == nil {
+				result.vector = vectors[i]
+			}
+			for _, ch := range batch.waiters[text] {
+				ch <- result
+			}
+		}
+	}()
+}
+
+// embedBatches 按批量上限拆分文本，并发调用嵌入模型并写入缓存
+func (e *CachedEmbedder) embedBatches(ctx context.Context, model string, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	errs := make([]error, 0, 1)
+	var errMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(texts); start += e.config.MaxBatchSize {
+		end := min(start+e.config.MaxBatchSize, len(texts))
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			select {
+			case e.sem <- struct{}{}:
+				defer func() { <-e.sem }()
+			case <-ctx.Done():
+				errMu.Lock()
+				errs = append(errs, ctx.Err())
+				errMu.Unlock()
+				return
+			}
+
+			e.calls.Add(1)
+			batch, err := e.inner.EmbedStrings(ctx, texts[start:end], opts...)
+			if err == nil && len(batch) != end-start {
+				err = fmt.Errorf("embedding count mismatch: expected %d, got %d", end-start, len(batch))
+			}
+			if err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+				return
+			}
+
+			for i, vector := range batch {
+				vectors[start+i] = vector
+				e.store(model, texts[start+i], vector)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to embed texts: %w", errs[0])
+	}
+	return vectors, nil
+}
+
+// embedCacheKey 按模型名和文本计算缓存键
+func embedCacheKey(model, text string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// embedLRU 并发安全的向量 LRU 缓存
+type embedLRU struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // 最近使用的在前
+	items map[string]*list.Element
+}
+
+// embedLRUEntry LRU 缓存条目
+type embedLRUEntry struct {
+	key    string
+	vector []float64
+}
+
+// newEmbedLRU 创建 LRU 缓存
+func newEmbedLRU(size int) *embedLRU {
+	return &embedLRU{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get 获取向量并标记为最近使用
+func (c *embedLRU) get(key string) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*embedLRUEntry).vector, true
+}
+
+// add 写入向量，超出容量时淘汰最久未使用的条目
+func (c *embedLRU) add(key string, vector []float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*embedLRUEntry).vector = vector
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&embedLRUEntry{key: key, vector: vector})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*embedLRUEntry).key)
+	}
+}
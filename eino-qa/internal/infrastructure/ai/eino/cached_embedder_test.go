@@ -0,0 +1,175 @@
+package eino
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmbedder 记录每次调用的批量大小和最大并发数
+type recordingEmbedder struct {
+	mu      sync.Mutex
+	batches [][]string
+	delay   time.Duration
+
+	active, maxActive atomic.Int32
+}
+
+func (e *recordingEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	active := e.active.Add(1)
+	defer e.active.Add(-1)
+	for {
+		current := e.maxActive.Load()
+		if active <= current || e.maxActive.CompareAndSwap(current, active) {
+			break
+		}
+	}
+	time.Sleep(e.delay)
+
+	e.mu.Lock()
+	e.batches = append(e.batches, append([]string(nil), texts...))
+	e.mu.Unlock()
+
+	model := ""
+	if o := embedding.GetCommonOptions(nil, opts...); o.Model != nil {
+		model = *o.Model
+	}
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len(text)), float64(len(model)), 1}
+	}
+	return vectors, nil
+}
+
+func (e *recordingEmbedder) calls() [][]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.batches
+}
+
+func TestCachedEmbedder_Cache(t *testing.T) {
+	inner := &recordingEmbedder{}
+	embedder, err := NewCachedEmbedder(inner, EmbedCacheConfig{Model: "text-embedding-v2", Size: 2})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// 同一请求中重复的文本只嵌入一次
+	vectors, err := embedder.EmbedStrings(ctx, []string{"a", "bb", "a"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+	assert.Equal(t, vectors[0], vectors[2])
+	assert.Equal(t, [][]string{{"a", "bb"}}, inner.calls())
+
+	// 命中缓存，返回的向量是副本
+	vectors[0][0] = 100
+	vectors, err = embedder.EmbedStrings(ctx, []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 0, 1}, vectors[0])
+	assert.Len(t, inner.calls(), 1)
+
+	// 超出容量时淘汰最久未使用的文本
+	_, err = embedder.EmbedStrings(ctx, []string{"ccc"})
+	require.NoError(t, err)
+	_, err = embedder.EmbedStrings(ctx, []string{"a", "bb"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "bb"}, {"ccc"}, {"bb"}}, inner.calls())
+
+	// 调用选项指定的模型属于缓存键
+	_, err = embedder.EmbedStrings(ctx, []string{"a"}, embedding.WithModel("text-embedding-v3"))
+	require.NoError(t, err)
+	assert.Len(t, inner.calls(), 4)
+
+	stats := embedder.Stats()
+	assert.Equal(t, int64(2), stats.MemoryHits)
+	assert.Equal(t, int64(5), stats.Misses)
+	assert.Equal(t, int64(4), stats.Calls)
+}
+
+func TestCachedEmbedder_DiskCache(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := NewCachedEmbedder(&recordingEmbedder{}, EmbedCacheConfig{Model: "m", Dir: dir})
+	require.NoError(t, err)
+	want, err := first.EmbedStrings(ctx, []string{"课程价格"})
+	require.NoError(t, err)
+
+	// 新实例从磁盘读取，不调用嵌入模型
+	inner := &recordingEmbedder{}
+	second, err := NewCachedEmbedder(inner, EmbedCacheConfig{Model: "m", Dir: dir})
+	require.NoError(t, err)
+	got, err := second.EmbedStrings(ctx, []string{"课程价格"})
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Empty(t, inner.calls())
+	assert.Equal(t, int64(1), second.Stats().DiskHits)
+
+	// 模型不同时不共享磁盘缓存
+	other, err := NewCachedEmbedder(inner, EmbedCacheConfig{Model: "other", Dir: dir})
+	require.NoError(t, err)
+	_, err = other.EmbedStrings(ctx, []string{"课程价格"})
+	require.NoError(t, err)
+	assert.Len(t, inner.calls(), 1)
+}
+
+func TestCachedEmbedder_MicroBatch(t *testing.T) {
+	inner := &recordingEmbedder{}
+	embedder, err := NewCachedEmbedder(inner, EmbedCacheConfig{BatchWindow: 50 * time.Millisecond, MaxBatchSize: 10})
+	require.NoError(t, err)
+
+	// 窗口内的并发单条请求合并为一次调用，相同文本只嵌入一次
+	var wg sync.WaitGroup
+	results := make([][]float64, 6)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			vectors, err := embedder.EmbedStrings(context.Background(), []string{fmt.Sprintf("q%d", i%5)})
+			assert.NoError(t, err)
+			results[i] = vectors[0]
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, inner.calls(), 1)
+	assert.ElementsMatch(t, []string{"q0", "q1", "q2", "q3", "q4"}, inner.calls()[0])
+	assert.Equal(t, results[0], results[5])
+
+	// 单个请求取消不影响同批次的其他请求
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = embedder.EmbedStrings(ctx, []string{"cancelled"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCachedEmbedder_SplitBatches(t *testing.T) {
+	inner := &recordingEmbedder{delay: 20 * time.Millisecond}
+	embedder, err := NewCachedEmbedder(inner, EmbedCacheConfig{MaxBatchSize: 3, MaxConcurrency: 2})
+	require.NoError(t, err)
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("doc-%02d", i)
+	}
+	vectors, err := embedder.EmbedStrings(context.Background(), texts)
+	require.NoError(t, err)
+	require.Len(t, vectors, 10)
+
+	// 按批量上限拆分为 4 批，并发不超过上限
+	calls := inner.calls()
+	require.Len(t, calls, 4)
+	total := 0
+	for _, batch := range calls {
+		assert.LessOrEqual(t, len(batch), 3)
+		total += len(batch)
+	}
+	assert.Equal(t, 10, total)
+	assert.LessOrEqual(t, inner.maxActive.Load(), int32(2))
+}
@@ -0,0 +1,105 @@
+package eino
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// CitationPolicy 回答未引用的来源文档的处理方式
+type CitationPolicy string
+
+const (
+	// CitationPolicyFlag 保留未引用的来源文档，并标记为未引用
+	CitationPolicyFlag CitationPolicy = "flag"
+	// CitationPolicyDrop 移除未引用的来源文档，引用编号按保留的文档重新编号
+	CitationPolicyDrop CitationPolicy = "drop"
+)
+
+// citationMarkerPattern 匹配 [1]、[1, 3]、【2】 等引用标记
+// 只匹配一到两位数字，避免把 [2024] 之类的正文内容当作引用
+var citationMarkerPattern = regexp.MustCompile(`[\[【]\s*(\d{1,2}(?:\s*[,，、]\s*\d{1,2})*)\s*[\]】]`)
+
+// citationSplitPattern 分隔同一标记中的多个编号
+var citationSplitPattern = regexp.MustCompile(`\s*[,，、]\s*`)
+
+// CitationResult 引用后处理结果
+type CitationResult struct {
+	Answer         string             // 规范化引用标记后的回答
+	Sources        []*entity.Document // 按引用编号排列的来源文档
+	CitedCount     int                // 被回答引用的文档数量
+	InvalidMarkers []int              // 无法对应到检索文档的编号，已从回答中移除
+}
+
+// ApplyCitations 校验回答中的 [n] 引用标记并整理来源文档
+// 每个编号必须对应第 n 个检索文档，否则从回答中移除；引用标记统一改写为 [n] 形式。
+// 未引用的文档按 policy 标记或移除；回答完全没有有效引用时保留全部文档并标记为未引用，
+// 避免用户失去核对依据
+func ApplyCitations(answer string, docs []*entity.Document, policy CitationPolicy) *CitationResult {
+	result := &CitationResult{}
+
+	// 1. 收集有效引用和无效编号
+	cited := make(map[int]bool)
+	invalid := make(map[int]bool)
+	for _, match := range citationMarkerPattern.FindAllStringSubmatch(answer, -1) {
+		for _, n := range parseCitationNumbers(match[1]) {
+			if n >= 1 && n <= len(docs) {
+				cited[n] = true
+			} else if !invalid[n] {
+				invalid[n] = true
+				result.InvalidMarkers = append(result.InvalidMarkers, n)
+			}
+		}
+	}
+	result.CitedCount = len(cited)
+
+	// 2. 确定来源文档和编号映射
+	renumber := make(map[int]int, len(docs))
+	for i, doc := range docs {
+		n := i + 1
+		doc.SetCited(cited[n])
+		if policy == CitationPolicyDrop && len(cited) > 0 {
+			if !cited[n] {
+				continue
+			}
+			result.Sources = append(result.Sources, doc)
+			renumber[n] = len(result.Sources)
+			continue
+		}
+		result.Sources = append(result.Sources, doc)
+		renumber[n] = n
+	}
+
+	// 3. 改写引用标记：去掉无效编号，合并后的标记拆分为 [a][b]
+	result.Answer = citationMarkerPattern.ReplaceAllStringFunc(answer, func(marker string) string {
+		match := citationMarkerPattern.FindStringSubmatch(marker)
+		var sb strings.Builder
+		seen := make(map[int]bool)
+		for _, n := range parseCitationNumbers(match[1]) {
+			mapped, ok := renumber[n]
+			if !ok || seen[mapped] {
+				continue
+			}
+			seen[mapped] = true
+			sb.WriteString(fmt.Sprintf("[%d]", mapped))
+		}
+		return sb.String()
+	})
+
+	return result
+}
+
+// parseCitationNumbers 解析引用标记中的编号
+func parseCitationNumbers(s string) []int {
+	parts := citationSplitPattern.Split(strings.TrimSpace(s), -1)
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		if n, err := strconv.Atoi(part); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,75 @@
+package eino
+
+import (
+	"testing"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCitationDocs() []*entity.Document {
+	return []*entity.Document{
+		{ID: "doc_1", Content: "Go 课程共 12 周", Score: 0.92},
+		{ID: "doc_2", Content: "Python 课程共 8 周", Score: 0.85},
+		{ID: "doc_3", Content: "购买后 7 天内可申请退款", Score: 0.81},
+	}
+}
+
+func citedFlags(docs []*entity.Document) []bool {
+	flags := make([]bool, len(docs))
+	for i, doc := range docs {
+		flags[i], _ = doc.Cited()
+	}
+	return flags
+}
+
+func TestApplyCitations(t *testing.T) {
+	t.Run("标记未引用的文档", func(t *testing.T) {
+		docs := newCitationDocs()
+		result := ApplyCitations("Go 课程共 12 周[1]，7 天内可退款【3】。", docs, CitationPolicyFlag)
+
+		assert.Equal(t, "Go 课程共 12 周[1]，7 天内可退款[3]。", result.Answer)
+		assert.Len(t, result.Sources, 3)
+		assert.Equal(t, []bool{true, false, true}, citedFlags(result.Sources))
+		assert.Equal(t, 2, result.CitedCount)
+		assert.Empty(t, result.InvalidMarkers)
+	})
+
+	t.Run("移除未引用的文档并重新编号", func(t *testing.T) {
+		docs := newCitationDocs()
+		result := ApplyCitations("Go 课程共 12 周[1]，7 天内可退款[3]。", docs, CitationPolicyDrop)
+
+		assert.Equal(t, "Go 课程共 12 周[1]，7 天内可退款[2]。", result.Answer)
+		assert.Len(t, result.Sources, 2)
+		assert.Equal(t, "doc_1", result.Sources[0].ID)
+		assert.Equal(t, "doc_3", result.Sources[1].ID)
+	})
+
+	t.Run("移除无效编号并拆分合并标记", func(t *testing.T) {
+		docs := newCitationDocs()
+		result := ApplyCitations("两门课程分别为 12 周和 8 周[1, 2, 7]，另有优惠[5]。", docs, CitationPolicyFlag)
+
+		assert.Equal(t, "两门课程分别为 12 周和 8 周[1][2]，另有优惠。", result.Answer)
+		assert.Equal(t, []int{7, 5}, result.InvalidMarkers)
+		assert.Equal(t, []bool{true, true, false}, citedFlags(result.Sources))
+	})
+
+	t.Run("没有引用时保留全部文档", func(t *testing.T) {
+		docs := newCitationDocs()
+		result := ApplyCitations("课程安排请参考官网。", docs, CitationPolicyDrop)
+
+		assert.Equal(t, "课程安排请参考官网。", result.Answer)
+		assert.Len(t, result.Sources, 3)
+		assert.Equal(t, []bool{false, false, false}, citedFlags(result.Sources))
+		assert.Zero(t, result.CitedCount)
+	})
+
+	t.Run("不把年份当作引用", func(t *testing.T) {
+		docs := newCitationDocs()
+		result := ApplyCitations("课程于 [2025] 年开课[2]。", docs, CitationPolicyFlag)
+
+		assert.Equal(t, "课程于 [2025] 年开课[2]。", result.Answer)
+		assert.Empty(t, result.InvalidMarkers)
+	})
+}
@@ -3,8 +3,11 @@ package eino
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"eino-qa/internal/infrastructure/redact"
+
 	arkEmbed "github.com/cloudwego/eino-ext/components/embedding/ark"
 	arkModel "github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/embedding"
@@ -18,6 +21,9 @@ type ClientConfig struct {
 	EmbedModel string
 	MaxRetries int
 	Timeout    time.Duration
+
+	// EmbedCache 嵌入模型的缓存与批处理配置，为 nil 时直接调用嵌入模型
+	EmbedCache *EmbedCacheConfig
 }
 
 // Client DashScope 客户端
@@ -25,6 +31,16 @@ type Client struct {
 	chatModel  model.ChatModel
 	embedModel embedding.Embedder
 	config     ClientConfig
+
+	// 按名称创建的其他聊天模型（A/B 实验分组使用）
+	mu     sync.Mutex
+	models map[string]model.ChatModel
+
+	// 个人信息脱敏服务（见 WithRedactor）
+	redactor *redact.Redactor
+
+	// 按租户限制同时进行的模型调用（见 WithConcurrencyLimit）
+	limiter *concurrencyLimiter
 }
 
 // NewClient 创建新的 DashScope 客户端
@@ -73,20 +89,86 @@ func NewClient(config ClientConfig) (*Client, error) {
 		return nil, fmt.Errorf("failed to initialize embedding model: %w", err)
 	}
 
+	var embedder embedding.Embedder = embedModel
+	if config.EmbedCache != nil {
+		cacheConfig := *config.EmbedCache
+		cacheConfig.Model = config.EmbedModel
+		embedder, err = NewCachedEmbedder(embedModel, cacheConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize embedding cache: %w", err)
+		}
+	}
+
 	return &Client{
 		chatModel:  chatModel,
-		embedModel: embedModel,
+		embedModel: embedder,
 		config:     config,
+		models:     make(map[string]model.ChatModel),
 	}, nil
 }
 
+// NewClientWithModels 使用已创建的聊天模型和嵌入模型创建客户端
+// 用于测试时接入录制回放模型（见 ai/replay），A/B 实验分组指定的其他模型仍通过 DashScope 创建
+func NewClientWithModels(chatModel model.ChatModel, embedModel embedding.Embedder, config ClientConfig) *Client {
+	return &Client{
+		chatModel:  chatModel,
+		embedModel: embedModel,
+		config:     config,
+		models:     make(map[string]model.ChatModel),
+	}
+}
+
 // GetChatModel 获取聊天模型
+// 返回的模型在每次调用时按 ctx 中的实验分组选择模型，未分组时使用默认模型；设置了脱敏服务时按租户策略替换个人信息；
+// 设置了并发上限时按租户排队
 func (c *Client) GetChatModel() model.ChatModel {
-	return c.chatModel
+	var chatModel model.ChatModel = &experimentChatModel{client: c}
+	if c.limiter != nil {
+		chatModel = &limitedChatModel{inner: chatModel, limiter: c.limiter}
+	}
+	if c.redactor != nil {
+		chatModel = &redactingChatModel{inner: chatModel, redactor: c.redactor}
+	}
+	return chatModel
 }
 
-// GetEmbedModel 获取嵌入模型
+// ChatModel 按名称获取聊天模型，首次使用时创建，名称为空或与默认模型相同时返回默认模型
+func (c *Client) ChatModel(name string) (model.ChatModel, error) {
+	if name == "" || name == c.config.ChatModel {
+		return c.chatModel, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if m, ok := c.models[name]; ok {
+		return m, nil
+	}
+
+	m, err := arkModel.NewChatModel(
+		context.Background(),
+		&arkModel.ChatModelConfig{
+			APIKey: c.config.APIKey,
+			Model:  name,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize chat model %s: %w", name, err)
+	}
+
+	if c.models == nil {
+		c.models = make(map[string]model.ChatModel)
+	}
+	c.models[name] = m
+	return m, nil
+}
+
+// GetEmbedModel 获取嵌入模型（配置了 EmbedCache 时为带缓存和批处理的嵌入模型）
+// 设置了脱敏服务时按租户策略替换文本中的个人信息，嵌入缓存中也只保存替换后的文本
 func (c *Client) GetEmbedModel() embedding.Embedder {
+	if c.redactor != nil && c.embedModel != nil {
+		return &redactingEmbedder{inner: c.embedModel, redactor: c.redactor}
+	}
 	return c.embedModel
 }
 
@@ -0,0 +1,155 @@
+package eino
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ConcurrencyLimit 租户的模型调用并发上限
+type ConcurrencyLimit struct {
+	MaxConcurrent int           // 同时进行的调用上限，0 表示不限制
+	QueueTimeout  time.Duration // 名额已满时的最长排队时间，0 表示一直等到 ctx 结束
+}
+
+// ConcurrencyLimitProvider 返回租户的模型调用并发上限
+type ConcurrencyLimitProvider func(tenantID string) ConcurrencyLimit
+
+// WithConcurrencyLimit 设置按租户的模型调用并发上限（可选）
+// 设置后 GetChatModel 返回的模型在调用前占用 ctx 中租户的名额，名额已满时排队等待，
+// 排队超时时返回 entity.ErrLLMConcurrencyLimit
+func (c *Client) WithConcurrencyLimit(limits ConcurrencyLimitProvider) *Client {
+	c.limiter = &concurrencyLimiter{
+		limits: limits,
+		slots:  make(map[string]chan struct{}),
+	}
+	return c
+}
+
+// concurrencyLimiter 按租户分配模型调用名额
+type concurrencyLimiter struct {
+	limits ConcurrencyLimitProvider
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// acquire 占用 ctx 中租户的一个名额，返回释放函数
+// 对话接口中 ctx 的租户由对话用例按请求的租户写入，请求的租户只来自租户中间件，
+// 与限流的租户维度是同一个租户
+func (l *concurrencyLimiter) acquire(ctx context.Context) (func(), error) {
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	if tenantID == "" {
+		tenantID = "default"
+	}
+	limit := l.limits(tenantID)
+	if limit.MaxConcurrent <= 0 {
+		return func() {}, nil
+	}
+
+	sem := l.semaphore(tenantID, limit.MaxConcurrent)
+	var once sync.Once
+	release := func() { once.Do(func() { <-sem }) }
+
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if limit.QueueTimeout > 0 {
+		timer := time.NewTimer(limit.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timeout:
+		return nil, fmt.Errorf("%w: limit %d", entity.ErrLLMConcurrencyLimit, limit.MaxConcurrent)
+	}
+}
+
+// semaphore 获取租户的名额通道，首次使用时按当前上限创建
+func (l *concurrencyLimiter) semaphore(tenantID string, limit int) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sem, ok := l.slots[tenantID]
+	if !ok {
+		sem = make(chan struct{}, limit)
+		l.slots[tenantID] = sem
+	}
+	return sem
+}
+
+// limitedChatModel 调用期间占用租户并发名额的聊天模型
+type limitedChatModel struct {
+	inner   model.ChatModel
+	limiter *concurrencyLimiter
+}
+
+// Generate 占用名额后生成回答
+func (m *limitedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	release, err := m.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return m.inner.Generate(ctx, input, opts...)
+}
+
+// Stream 占用名额后流式生成回答，流读取结束或被关闭时释放名额
+func (m *limitedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	release, err := m.limiter.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := m.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	reader, writer := schema.Pipe[*schema.Message](1)
+	go func() {
+		// 先释放名额再结束输出流，调用方读到流结束时名额已归还
+		defer writer.Close()
+		defer stream.Close()
+		defer release()
+
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				writer.Send(nil, err)
+				return
+			}
+			if closed := writer.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+// BindTools 绑定工具到内部模型
+func (m *limitedChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return m.inner.BindTools(tools)
+}
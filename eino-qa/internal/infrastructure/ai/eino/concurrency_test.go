@@ -0,0 +1,117 @@
+package eino
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingChatModel Generate 阻塞到 unblock 关闭的聊天模型
+type blockingChatModel struct {
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (m *blockingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.started <- struct{}{}
+	<-m.unblock
+	return schema.AssistantMessage("ok", nil), nil
+}
+
+func (m *blockingChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("o", nil), schema.AssistantMessage("k", nil)}), nil
+}
+
+func (m *blockingChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+func TestLimitedChatModel_Generate(t *testing.T) {
+	inner := &blockingChatModel{started: make(chan struct{}, 4), unblock: make(chan struct{})}
+	client := NewClientWithModels(inner, nil, ClientConfig{}).WithConcurrencyLimit(func(tenantID string) ConcurrencyLimit {
+		if tenantID == "tenant1" {
+			return ConcurrencyLimit{MaxConcurrent: 1, QueueTimeout: 20 * time.Millisecond}
+		}
+		return ConcurrencyLimit{}
+	})
+	chatModel := client.GetChatModel()
+	messages := []*schema.Message{schema.UserMessage("你好")}
+	tenant1 := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := chatModel.Generate(tenant1, messages)
+		done <- err
+	}()
+	<-inner.started
+
+	// 名额已满，排队超时
+	_, err := chatModel.Generate(tenant1, messages)
+	assert.ErrorIs(t, err, entity.ErrLLMConcurrencyLimit)
+
+	// ctx 结束时不再等待
+	canceled, cancel := context.WithCancel(tenant1)
+	cancel()
+	_, err = chatModel.Generate(canceled, messages)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// 其他租户不受影响
+	other := make(chan error, 1)
+	go func() {
+		_, err := chatModel.Generate(context.WithValue(context.Background(), "tenant_id", "tenant2"), messages)
+		other <- err
+	}()
+	<-inner.started
+
+	close(inner.unblock)
+	require.NoError(t, <-done)
+	require.NoError(t, <-other)
+
+	// 名额归还后可以再次调用
+	go func() { <-inner.started }()
+	resp, err := chatModel.Generate(tenant1, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Content)
+}
+
+func TestLimitedChatModel_Stream(t *testing.T) {
+	inner := &blockingChatModel{started: make(chan struct{}, 1), unblock: make(chan struct{})}
+	client := NewClientWithModels(inner, nil, ClientConfig{}).WithConcurrencyLimit(func(string) ConcurrencyLimit {
+		return ConcurrencyLimit{MaxConcurrent: 1, QueueTimeout: 20 * time.Millisecond}
+	})
+	chatModel := client.GetChatModel()
+	messages := []*schema.Message{schema.UserMessage("你好")}
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+	stream, err := chatModel.Stream(ctx, messages)
+	require.NoError(t, err)
+
+	// 流未读完时仍占用名额
+	_, err = chatModel.Stream(ctx, messages)
+	assert.ErrorIs(t, err, entity.ErrLLMConcurrencyLimit)
+
+	var content string
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content += chunk.Content
+	}
+	stream.Close()
+	assert.Equal(t, "ok", content)
+
+	// 读到流结束时名额已归还
+	second, err := chatModel.Stream(ctx, messages)
+	require.NoError(t, err)
+	second.Close()
+}
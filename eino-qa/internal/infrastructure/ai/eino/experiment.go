@@ -0,0 +1,62 @@
+package eino
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// experimentKey context 中当前组件实验分组的键
+type experimentKey struct{}
+
+// WithExperimentVariant 将组件的实验分组写入 context
+// 只应包裹对应组件的调用，分组中的模型和提示词版本只在该范围内生效
+func WithExperimentVariant(ctx context.Context, assignment *entity.ExperimentAssignment) context.Context {
+	if assignment == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, experimentKey{}, assignment)
+}
+
+// ExperimentVariantFromContext 读取 context 中的实验分组，没有时返回 nil
+func ExperimentVariantFromContext(ctx context.Context) *entity.ExperimentAssignment {
+	assignment, _ := ctx.Value(experimentKey{}).(*entity.ExperimentAssignment)
+	return assignment
+}
+
+// experimentChatModel 按实验分组选择模型的聊天模型
+type experimentChatModel struct {
+	client *Client
+}
+
+// Generate 使用当前分组的模型生成回答
+func (m *experimentChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return m.modelFor(ctx).Generate(ctx, input, opts...)
+}
+
+// Stream 使用当前分组的模型流式生成回答
+func (m *experimentChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return m.modelFor(ctx).Stream(ctx, input, opts...)
+}
+
+// BindTools 绑定工具到默认模型
+func (m *experimentChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return m.client.chatModel.BindTools(tools)
+}
+
+// modelFor 获取当前分组的模型，未分组或模型创建失败时使用默认模型
+func (m *experimentChatModel) modelFor(ctx context.Context) model.ChatModel {
+	assignment := ExperimentVariantFromContext(ctx)
+	if assignment == nil || assignment.Model == "" {
+		return m.client.chatModel
+	}
+
+	chatModel, err := m.client.ChatModel(assignment.Model)
+	if err != nil {
+		return m.client.chatModel
+	}
+	return chatModel
+}
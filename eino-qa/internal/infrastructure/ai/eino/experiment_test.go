@@ -0,0 +1,47 @@
+package eino
+
+import (
+	"context"
+	"testing"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimentChatModel_SelectsVariantModel(t *testing.T) {
+	defaultModel := &fixedChatModel{content: "default"}
+	variantModel := &fixedChatModel{content: "variant"}
+	client := &Client{
+		chatModel: defaultModel,
+		config:    ClientConfig{ChatModel: "qwen-turbo"},
+		models:    map[string]model.ChatModel{"qwen-plus": variantModel},
+	}
+	chatModel := client.GetChatModel()
+	messages := []*schema.Message{schema.UserMessage("你好")}
+
+	// 未分组时使用默认模型
+	resp, err := chatModel.Generate(context.Background(), messages)
+	require.NoError(t, err)
+	assert.Equal(t, "default", resp.Content)
+
+	// 分组指定的模型只在包裹的 context 中生效
+	ctx := WithExperimentVariant(context.Background(), &entity.ExperimentAssignment{
+		Experiment: "model_test",
+		Component:  entity.ExperimentDirect,
+		Variant:    "treatment",
+		Model:      "qwen-plus",
+	})
+	resp, err = chatModel.Generate(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "variant", resp.Content)
+
+	// 分组使用默认模型名称时不创建新模型
+	ctx = WithExperimentVariant(context.Background(), &entity.ExperimentAssignment{Model: "qwen-turbo"})
+	resp, err = chatModel.Generate(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "default", resp.Content)
+}
@@ -0,0 +1,275 @@
+package eino
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// GroundednessMethod 回答依据校验方式
+type GroundednessMethod string
+
+const (
+	// GroundednessMethodLexical 按字符二元组与来源文档的重合度判断
+	GroundednessMethodLexical GroundednessMethod = "lexical"
+	// GroundednessMethodLLM 由 LLM 逐句判断是否能从来源文档推出（NLI）
+	GroundednessMethodLLM GroundednessMethod = "llm"
+)
+
+const (
+	// defaultLexicalSentenceThreshold 词汇重合校验中，句子被视为有依据的最低重合度
+	defaultLexicalSentenceThreshold = 0.5
+	// minCheckedSentenceRunes 参与校验的句子最少字符数，更短的句子（如"您好！"）不参与评分
+	minCheckedSentenceRunes = 6
+)
+
+// SentenceSupport 单个句子的依据校验结果
+type SentenceSupport struct {
+	Text      string
+	Supported bool
+	Score     float64 // 词汇重合度；LLM 校验时为 0 或 1
+}
+
+// GroundednessReport 回答依据校验报告
+type GroundednessReport struct {
+	Method    GroundednessMethod
+	Score     float64 // 有依据句子的比例，0~1
+	Sentences []*SentenceSupport
+}
+
+// Unsupported 返回没有依据的句子
+func (r *GroundednessReport) Unsupported() []string {
+	var sentences []string
+	for _, s := range r.Sentences {
+		if !s.Supported {
+			sentences = append(sentences, s.Text)
+		}
+	}
+	return sentences
+}
+
+// GroundednessChecker 回答依据校验器
+// 判断 RAG 回答的每个句子是否能由检索到的文档支持
+type GroundednessChecker struct {
+	chatModel         model.ChatModel
+	sentenceThreshold float64
+}
+
+// NewGroundednessChecker 创建回答依据校验器
+func NewGroundednessChecker(client *Client) *GroundednessChecker {
+	return &GroundednessChecker{
+		chatModel:         client.GetChatModel(),
+		sentenceThreshold: defaultLexicalSentenceThreshold,
+	}
+}
+
+// Check 校验回答的依据
+// LLM 校验失败时返回错误，调用方可降级为词汇重合校验
+func (g *GroundednessChecker) Check(ctx context.Context, method GroundednessMethod, answer string, docs []*entity.Document) (*GroundednessReport, error) {
+	sentences := splitAnswerSentences(answer)
+
+	var (
+		supports []*SentenceSupport
+		err      error
+	)
+	switch method {
+	case GroundednessMethodLLM:
+		supports, err = g.checkWithLLM(ctx, sentences, docs)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		method = GroundednessMethodLexical
+		supports = g.checkLexical(sentences, docs)
+	}
+
+	report := &GroundednessReport{Method: method, Score: 1, Sentences: supports}
+	if len(supports) > 0 {
+		supported := 0
+		for _, s := range supports {
+			if s.Supported {
+				supported++
+			}
+		}
+		report.Score = float64(supported) / float64(len(supports))
+	}
+
+	return report, nil
+}
+
+// checkLexical 按字符二元组重合度校验每个句子
+func (g *GroundednessChecker) checkLexical(sentences []string, docs []*entity.Document) []*SentenceSupport {
+	docBigrams := make(map[string]bool)
+	for _, doc := range docs {
+		for _, bigram := range charBigrams(doc.Content) {
+			docBigrams[bigram] = true
+		}
+	}
+
+	threshold := g.sentenceThreshold
+	if threshold <= 0 {
+		threshold = defaultLexicalSentenceThreshold
+	}
+
+	supports := make([]*SentenceSupport, 0, len(sentences))
+	for _, sentence := range sentences {
+		bigrams := charBigrams(sentence)
+		if len(bigrams) == 0 {
+			continue
+		}
+
+		matched := 0
+		for _, bigram := range bigrams {
+			if docBigrams[bigram] {
+				matched++
+			}
+		}
+		score := float64(matched) / float64(len(bigrams))
+		supports = append(supports, &SentenceSupport{
+			Text:      sentence,
+			Supported: score >= threshold,
+			Score:     score,
+		})
+	}
+	return supports
+}
+
+// checkWithLLM 由 LLM 逐句判断是否能从来源文档推出
+func (g *GroundednessChecker) checkWithLLM(ctx context.Context, sentences []string, docs []*entity.Document) ([]*SentenceSupport, error) {
+	if len(sentences) == 0 {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("知识库文档：\n")
+	for i, doc := range docs {
+		sb.WriteString(fmt.Sprintf("文档 [%d]:\n%s\n\n", i+1, doc.Content))
+	}
+	sb.WriteString("待校验的句子：\n")
+	for i, sentence := range sentences {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, sentence))
+	}
+
+	messages := []*schema.Message{
+		schema.SystemMessage(groundednessSystemPrompt),
+		schema.UserMessage(sb.String()),
+	}
+
+	resp, err := g.chatModel.Generate(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check groundedness: %w", err)
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var result struct {
+		Sentences []struct {
+			Index     int  `json:"index"`
+			Supported bool `json:"supported"`
+		} `json:"sentences"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse groundedness result: %w", err)
+	}
+
+	// 未出现在结果中的句子视为没有依据
+	supports := make([]*SentenceSupport, len(sentences))
+	for i, sentence := range sentences {
+		supports[i] = &SentenceSupport{Text: sentence}
+	}
+	for _, item := range result.Sentences {
+		if item.Index >= 1 && item.Index <= len(supports) && item.Supported {
+			supports[item.Index-1].Supported = true
+			supports[item.Index-1].Score = 1
+		}
+	}
+	return supports, nil
+}
+
+// groundednessSystemPrompt LLM 依据校验的系统提示词
+const groundednessSystemPrompt = `你是一个事实核查助手。请逐句判断每个句子的内容能否由提供的知识库文档推出。
+
+判断标准：
+1. 句子中的事实（课程内容、时长、价格、政策等）在文档中有明确依据，判定为 supported: true
+2. 文档中没有提到、与文档矛盾或需要额外知识才能得出的内容，判定为 supported: false
+3. 礼貌用语、引导用户联系客服等不包含事实的句子，判定为 supported: true
+
+请只输出 JSON，格式如下：
+{"sentences": [{"index": 1, "supported": true}, {"index": 2, "supported": false}]}`
+
+// LexicalOverlap 返回 text 的字符二元组在 reference 中出现的比例，0~1
+func LexicalOverlap(text, reference string) float64 {
+	bigrams := charBigrams(text)
+	if len(bigrams) == 0 {
+		return 0
+	}
+
+	referenceBigrams := make(map[string]bool)
+	for _, bigram := range charBigrams(reference) {
+		referenceBigrams[bigram] = true
+	}
+
+	matched := 0
+	for _, bigram := range bigrams {
+		if referenceBigrams[bigram] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(bigrams))
+}
+
+// splitAnswerSentences 将回答拆分为待校验的句子，去掉引用标记和过短的句子
+func splitAnswerSentences(answer string) []string {
+	answer = citationMarkerPattern.ReplaceAllString(answer, "")
+
+	var sentences []string
+	var current []rune
+	flush := func() {
+		sentence := strings.TrimSpace(string(current))
+		current = current[:0]
+		if len([]rune(sentence)) >= minCheckedSentenceRunes {
+			sentences = append(sentences, sentence)
+		}
+	}
+
+	for _, r := range answer {
+		current = append(current, r)
+		switch r {
+		case '。', '！', '？', '；', '!', '?', ';', '\n':
+			flush()
+		}
+	}
+	flush()
+
+	return sentences
+}
+
+// charBigrams 提取文本的字符二元组，忽略空白和标点并转为小写
+func charBigrams(text string) []string {
+	runes := make([]rune, 0, len(text))
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			runes = append(runes, r)
+		}
+	}
+
+	if len(runes) < 2 {
+		return nil
+	}
+	bigrams := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		bigrams = append(bigrams, string(runes[i:i+2]))
+	}
+	return bigrams
+}
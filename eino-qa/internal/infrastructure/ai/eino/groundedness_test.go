@@ -0,0 +1,134 @@
+package eino
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedChatModel 总是返回预设内容的聊天模型
+type fixedChatModel struct {
+	content string
+	err     error
+	input   []*schema.Message
+}
+
+func (m *fixedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.input = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return schema.AssistantMessage(m.content, nil), nil
+}
+
+func (m *fixedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (m *fixedChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+func newGroundednessDocs() []*entity.Document {
+	return []*entity.Document{
+		{ID: "doc_1", Content: "Go 语言进阶课程共 12 周，包含并发编程和微服务实战。"},
+		{ID: "doc_2", Content: "购买课程后 7 天内可以申请全额退款。"},
+	}
+}
+
+func TestGroundednessChecker_Lexical(t *testing.T) {
+	checker := &GroundednessChecker{}
+	ctx := context.Background()
+
+	t.Run("有依据的回答", func(t *testing.T) {
+		report, err := checker.Check(ctx, GroundednessMethodLexical, "Go 语言进阶课程共 12 周[1]。购买后 7 天内可以申请全额退款[2]。", newGroundednessDocs())
+		require.NoError(t, err)
+		assert.Equal(t, GroundednessMethodLexical, report.Method)
+		assert.Equal(t, 1.0, report.Score)
+		assert.Empty(t, report.Unsupported())
+	})
+
+	t.Run("编造的内容", func(t *testing.T) {
+		report, err := checker.Check(ctx, GroundednessMethodLexical, "Go 语言进阶课程共 12 周[1]。报名即送价值 999 元的机械键盘一把！", newGroundednessDocs())
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, report.Score)
+		assert.Equal(t, []string{"报名即送价值 999 元的机械键盘一把！"}, report.Unsupported())
+	})
+
+	t.Run("过短的句子不参与评分", func(t *testing.T) {
+		report, err := checker.Check(ctx, "", "您好！", newGroundednessDocs())
+		require.NoError(t, err)
+		assert.Equal(t, GroundednessMethodLexical, report.Method)
+		assert.Equal(t, 1.0, report.Score)
+		assert.Empty(t, report.Sentences)
+	})
+}
+
+func TestGroundednessChecker_LLM(t *testing.T) {
+	ctx := context.Background()
+	answer := "Go 语言进阶课程共 12 周[1]。报名即送价值 999 元的机械键盘一把！"
+
+	t.Run("逐句判断", func(t *testing.T) {
+		chatModel := &fixedChatModel{content: "```json\n{\"sentences\": [{\"index\": 1, \"supported\": true}, {\"index\": 2, \"supported\": false}]}\n```"}
+		checker := &GroundednessChecker{chatModel: chatModel}
+
+		report, err := checker.Check(ctx, GroundednessMethodLLM, answer, newGroundednessDocs())
+		require.NoError(t, err)
+		assert.Equal(t, GroundednessMethodLLM, report.Method)
+		assert.Equal(t, 0.5, report.Score)
+		assert.Equal(t, []string{"报名即送价值 999 元的机械键盘一把！"}, report.Unsupported())
+		assert.Contains(t, chatModel.input[1].Content, "2. 报名即送价值 999 元的机械键盘一把！")
+	})
+
+	t.Run("未返回的句子视为没有依据", func(t *testing.T) {
+		checker := &GroundednessChecker{chatModel: &fixedChatModel{content: `{"sentences": [{"index": 1, "supported": true}]}`}}
+
+		report, err := checker.Check(ctx, GroundednessMethodLLM, answer, newGroundednessDocs())
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, report.Score)
+	})
+
+	t.Run("LLM 调用失败", func(t *testing.T) {
+		checker := &GroundednessChecker{chatModel: &fixedChatModel{err: errors.New("timeout")}}
+
+		_, err := checker.Check(ctx, GroundednessMethodLLM, answer, newGroundednessDocs())
+		assert.Error(t, err)
+	})
+}
+
+func TestRAGRetriever_RegenerateStrict(t *testing.T) {
+	chatModel := &fixedChatModel{content: "购买课程后 7 天内可以申请全额退款[2]。"}
+	retriever := &RAGRetriever{chatModel: chatModel, citationPolicy: CitationPolicyDrop}
+
+	answer, sources, err := retriever.RegenerateStrict(context.Background(), "可以退款吗", newGroundednessDocs())
+	require.NoError(t, err)
+	assert.Equal(t, "购买课程后 7 天内可以申请全额退款[1]。", answer)
+	require.Len(t, sources, 1)
+	assert.Equal(t, "doc_2", sources[0].ID)
+	assert.Contains(t, chatModel.input[0].Content, "严格要求")
+}
+
+func TestSuggestionGenerator_Generate(t *testing.T) {
+	chatModel := &fixedChatModel{content: "```json\n{\"questions\": [\"Go 课程有作业吗？\", \" \", \"可以试听吗？\", \"有结业证书吗？\"]}\n```"}
+	generator := &SuggestionGenerator{chatModel: chatModel}
+
+	questions, err := generator.Generate(context.Background(), "Go 课程讲什么", "包含并发编程[1]。", []string{"每周布置一次编程作业"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go 课程有作业吗？", "可以试听吗？"}, questions)
+	assert.Contains(t, chatModel.input[1].Content, "1. 每周布置一次编程作业")
+
+	generator.chatModel = &fixedChatModel{content: "好的"}
+	_, err = generator.Generate(context.Background(), "Go 课程讲什么", "包含并发编程[1]。", nil, 2)
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package eino
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -68,25 +69,26 @@ func TestIntentRecognizerBasic(t *testing.T) {
 	}
 }
 
-// TestOrderQuerierRegexExtraction 测试订单 ID 正则提取
-func TestOrderQuerierRegexExtraction(t *testing.T) {
+// TestOrderQuerierOrderIDExtraction 测试按订单号方案提取订单 ID
+func TestOrderQuerierOrderIDExtraction(t *testing.T) {
 	querier := &OrderQuerier{}
+	scheme := querier.orderIDScheme(context.Background())
 
 	tests := []struct {
 		query    string
 		expected string
 	}{
-		{"查询订单#20251114001", "20251114001"},
-		{"订单号：20251114001", "20251114001"},
-		{"订单:20251114001", "20251114001"},
-		{"我的订单 20251114001 怎么样了", "20251114001"},
+		{"查询订单#20251114001", "#20251114001"},
+		{"订单号：20251114001", "#20251114001"},
+		{"订单:20251114001", "#20251114001"},
+		{"我的订单 20251114001 怎么样了", "#20251114001"},
 		{"没有订单号", ""},
 	}
 
 	for _, tt := range tests {
-		result := querier.extractOrderIDByRegex(tt.query)
+		result, _ := scheme.Extract(tt.query)
 		if result != tt.expected {
-			t.Errorf("extractOrderIDByRegex(%q) = %q, want %q", tt.query, result, tt.expected)
+			t.Errorf("Extract(%q) = %q, want %q", tt.query, result, tt.expected)
 		}
 	}
 }
@@ -241,10 +243,11 @@ func BenchmarkIntentRecognizerMapType(b *testing.B) {
 // BenchmarkOrderQuerierRegexExtraction 基准测试订单 ID 提取
 func BenchmarkOrderQuerierRegexExtraction(b *testing.B) {
 	querier := &OrderQuerier{}
+	scheme := querier.orderIDScheme(context.Background())
 	query := "查询订单#20251114001"
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		querier.extractOrderIDByRegex(query)
+		scheme.Extract(query)
 	}
 }
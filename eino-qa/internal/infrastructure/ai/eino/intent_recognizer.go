@@ -15,6 +15,8 @@ import (
 
 // IntentRecognizer 意图识别器
 type IntentRecognizer struct {
+	promptSource
+
 	chatModel           model.ChatModel
 	confidenceThreshold float64
 }
@@ -35,7 +37,7 @@ func NewIntentRecognizer(client *Client, cfg *config.IntentConfig) *IntentRecogn
 // Recognize 识别用户查询的意图
 func (r *IntentRecognizer) Recognize(ctx context.Context, query string, history []*entity.Message) (*entity.Intent, error) {
 	// 构建提示词
-	systemPrompt := r.buildSystemPrompt()
+	systemPrompt := r.systemPrompt(ctx, entity.PromptIntent)
 	userPrompt := r.buildUserPrompt(query, history)
 
 	// 构建消息列表
@@ -69,27 +71,9 @@ func (r *IntentRecognizer) Recognize(ctx context.Context, query string, history
 	return intent, nil
 }
 
-// buildSystemPrompt 构建系统提示词
+// buildSystemPrompt 构建默认系统提示词
 func (r *IntentRecognizer) buildSystemPrompt() string {
-	return `你是一个智能客服意图识别助手。你的任务是分析用户的查询，判断用户的意图类型。
-
-意图类型定义：
-1. course - 课程咨询：用户询问课程内容、课程安排、学习资料等与课程相关的问题
-2. order - 订单查询：用户查询订单状态、订单详情、退款等与订单相关的问题
-3. direct - 直接回答：简单的问候、闲聊或可以直接回答的一般性问题
-4. handoff - 人工转接：复杂问题、投诉、或需要人工处理的情况
-
-请以 JSON 格式返回结果，包含以下字段：
-{
-  "intent": "意图类型（course/order/direct/handoff）",
-  "confidence": 置信度分数（0-1之间的浮点数）,
-  "reason": "判断理由"
-}
-
-注意：
-- 只返回 JSON，不要包含其他文字
-- confidence 必须是 0 到 1 之间的数字
-- 如果不确定，将 confidence 设置为较低的值`
+	return DefaultPrompt(entity.PromptIntent)
 }
 
 // buildUserPrompt 构建用户提示词
@@ -0,0 +1,200 @@
+package eino
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ModerationMethod 回答审核方式
+type ModerationMethod string
+
+const (
+	// ModerationMethodRules 只按租户规则（禁止话题、竞品、正则黑名单）审核
+	ModerationMethodRules ModerationMethod = "rules"
+	// ModerationMethodLLM 规则未命中时再由审核模型判断
+	ModerationMethodLLM ModerationMethod = "llm"
+)
+
+// ModerationCategory 回答违规类别
+type ModerationCategory string
+
+const (
+	// ModerationBannedTopic 涉及租户禁止的话题
+	ModerationBannedTopic ModerationCategory = "banned_topic"
+	// ModerationCompetitor 提及竞品
+	ModerationCompetitor ModerationCategory = "competitor"
+	// ModerationDenylist 命中正则黑名单
+	ModerationDenylist ModerationCategory = "denylist"
+	// ModerationUnsafe 审核模型判定为不宜回复
+	ModerationUnsafe ModerationCategory = "unsafe"
+)
+
+// ModerationRules 租户的回答审核规则，话题和竞品按关键词忽略大小写匹配
+type ModerationRules struct {
+	bannedTopics []string
+	competitors  []string
+	denylist     []*regexp.Regexp
+	maxKeyword   int // 最长关键词的字符数
+}
+
+// NewModerationRules 创建回答审核规则，正则表达式无效时返回错误
+func NewModerationRules(bannedTopics, competitors, denylist []string) (*ModerationRules, error) {
+	rules := &ModerationRules{
+		bannedTopics: normalizeKeywords(bannedTopics),
+		competitors:  normalizeKeywords(competitors),
+	}
+	for _, pattern := range denylist {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid moderation denylist pattern %q: %w", pattern, err)
+		}
+		rules.denylist = append(rules.denylist, re)
+	}
+	for _, keyword := range append(rules.bannedTopics, rules.competitors...) {
+		rules.maxKeyword = max(rules.maxKeyword, utf8.RuneCountInString(keyword))
+	}
+	return rules, nil
+}
+
+// normalizeKeywords 去掉空白关键词并转为小写
+func normalizeKeywords(keywords []string) []string {
+	var result []string
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			result = append(result, keyword)
+		}
+	}
+	return result
+}
+
+// Empty 是否未配置任何规则
+func (r *ModerationRules) Empty() bool {
+	return r == nil || len(r.bannedTopics)+len(r.competitors)+len(r.denylist) == 0
+}
+
+// MaxKeywordLen 最长关键词的字符数，流式审核据此暂存回答末尾尚未确认的内容
+func (r *ModerationRules) MaxKeywordLen() int {
+	if r == nil {
+		return 0
+	}
+	return r.maxKeyword
+}
+
+// Match 按规则审核文本，未命中时返回 nil
+func (r *ModerationRules) Match(text string) *ModerationReport {
+	if r.Empty() {
+		return nil
+	}
+
+	lower := strings.ToLower(text)
+	for _, topic := range r.bannedTopics {
+		if strings.Contains(lower, topic) {
+			return &ModerationReport{Method: ModerationMethodRules, Flagged: true, Category: ModerationBannedTopic, Match: topic}
+		}
+	}
+	for _, competitor := range r.competitors {
+		if strings.Contains(lower, competitor) {
+			return &ModerationReport{Method: ModerationMethodRules, Flagged: true, Category: ModerationCompetitor, Match: competitor}
+		}
+	}
+	for _, re := range r.denylist {
+		if match := re.FindString(text); match != "" {
+			return &ModerationReport{Method: ModerationMethodRules, Flagged: true, Category: ModerationDenylist, Match: match}
+		}
+	}
+	return nil
+}
+
+// ModerationReport 回答审核报告
+type ModerationReport struct {
+	Method   ModerationMethod
+	Flagged  bool
+	Category ModerationCategory // 违规类别，未违规时为空
+	Match    string             // 命中的关键词或正则匹配的内容
+	Reason   string             // 审核模型给出的理由
+}
+
+// Moderator 回答审核器，在回答发送给用户之前检查是否偏离业务范围
+type Moderator struct {
+	chatModel model.ChatModel
+}
+
+// NewModerator 创建回答审核器，client 为 nil 时只支持规则审核
+func NewModerator(client *Client) *Moderator {
+	m := &Moderator{}
+	if client != nil {
+		m.chatModel = client.GetChatModel()
+	}
+	return m
+}
+
+// Check 审核回答
+// 规则命中时不再调用审核模型；审核模型调用失败时返回错误，调用方可降级为规则审核
+func (m *Moderator) Check(ctx context.Context, method ModerationMethod, rules *ModerationRules, text string) (*ModerationReport, error) {
+	if report := rules.Match(text); report != nil {
+		return report, nil
+	}
+	if method != ModerationMethodLLM || m.chatModel == nil || strings.TrimSpace(text) == "" {
+		return &ModerationReport{Method: ModerationMethodRules}, nil
+	}
+
+	flagged, reason, err := m.classify(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ModerationReport{Method: ModerationMethodLLM, Flagged: flagged, Reason: reason}
+	if flagged {
+		report.Category = ModerationUnsafe
+	}
+	return report, nil
+}
+
+// classify 由审核模型判断回答是否适合发送给客户
+func (m *Moderator) classify(ctx context.Context, text string) (bool, string, error) {
+	messages := []*schema.Message{
+		schema.SystemMessage(moderationSystemPrompt),
+		schema.UserMessage(fmt.Sprintf("待审核的客服回答（位于 <answer> 标签内）：\n<answer>\n%s\n</answer>", text)),
+	}
+
+	resp, err := m.chatModel.Generate(ctx, messages)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to moderate answer: %w", err)
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var result struct {
+		Flagged bool   `json:"flagged"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return false, "", fmt.Errorf("failed to parse moderation result: %w", err)
+	}
+	return result.Flagged, result.Reason, nil
+}
+
+// moderationSystemPrompt 回答审核的系统提示词
+const moderationSystemPrompt = `你是一个客服回答审核助手，负责判断一段客服回答是否适合发送给客户。
+
+以下情况判定为 flagged: true：
+1. 涉及政治、色情、暴力、赌博、违法犯罪等敏感内容
+2. 提供医疗、法律、投资等超出客服职责的专业建议
+3. 泄露系统提示词、内部规则或其他用户的信息
+4. 辱骂、歧视或攻击客户
+
+课程介绍、订单信息、售后政策、礼貌的寒暄和拒绝判定为 flagged: false。
+
+请只输出 JSON，格式如下：
+{"flagged": false, "reason": "正常的课程介绍"}`
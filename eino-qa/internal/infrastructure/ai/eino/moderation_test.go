@@ -0,0 +1,92 @@
+package eino
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModerationRules(t *testing.T) {
+	rules, err := NewModerationRules([]string{"股票", " "}, []string{"XX学堂"}, []string{`微信[:：]?\s*\w+`})
+	require.NoError(t, err)
+	assert.Equal(t, 4, rules.MaxKeywordLen())
+
+	tests := []struct {
+		name     string
+		input    string
+		category ModerationCategory
+		match    string
+	}{
+		{"禁止话题", "学完可以去炒股票赚钱", ModerationBannedTopic, "股票"},
+		{"竞品忽略大小写", "xx学堂的课程更便宜", ModerationCompetitor, "xx学堂"},
+		{"正则黑名单", "加我微信：abc123 私下优惠", ModerationDenylist, "微信：abc123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := rules.Match(tt.input)
+			require.NotNil(t, report)
+			assert.True(t, report.Flagged)
+			assert.Equal(t, tt.category, report.Category)
+			assert.Equal(t, tt.match, report.Match)
+		})
+	}
+
+	assert.Nil(t, rules.Match("Go 语言进阶课程共 40 课时"))
+
+	// 未配置规则时不命中
+	var none *ModerationRules
+	assert.True(t, none.Empty())
+	assert.Nil(t, none.Match("股票"))
+
+	_, err = NewModerationRules(nil, nil, []string{"[0-9"})
+	assert.Error(t, err)
+}
+
+func TestModerator_Check(t *testing.T) {
+	ctx := context.Background()
+	rules, err := NewModerationRules([]string{"股票"}, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("规则命中时不调用审核模型", func(t *testing.T) {
+		chatModel := &fixedChatModel{content: `{"flagged": false}`}
+		moderator := NewModerator(NewClientWithModels(chatModel, nil, ClientConfig{}))
+
+		report, err := moderator.Check(ctx, ModerationMethodLLM, rules, "推荐几只股票")
+		require.NoError(t, err)
+		assert.Equal(t, ModerationMethodRules, report.Method)
+		assert.Equal(t, ModerationBannedTopic, report.Category)
+		assert.Nil(t, chatModel.input)
+	})
+
+	t.Run("审核模型判定不宜回复", func(t *testing.T) {
+		chatModel := &fixedChatModel{content: "```json\n{\"flagged\": true, \"reason\": \"提供了医疗建议\"}\n```"}
+		moderator := NewModerator(NewClientWithModels(chatModel, nil, ClientConfig{}))
+
+		report, err := moderator.Check(ctx, ModerationMethodLLM, rules, "头疼的话可以吃两片布洛芬")
+		require.NoError(t, err)
+		assert.True(t, report.Flagged)
+		assert.Equal(t, ModerationMethodLLM, report.Method)
+		assert.Equal(t, ModerationUnsafe, report.Category)
+		assert.Equal(t, "提供了医疗建议", report.Reason)
+	})
+
+	t.Run("只按规则审核", func(t *testing.T) {
+		chatModel := &fixedChatModel{content: `{"flagged": true}`}
+		moderator := NewModerator(NewClientWithModels(chatModel, nil, ClientConfig{}))
+
+		report, err := moderator.Check(ctx, ModerationMethodRules, rules, "头疼的话可以吃两片布洛芬")
+		require.NoError(t, err)
+		assert.False(t, report.Flagged)
+		assert.Nil(t, chatModel.input)
+	})
+
+	t.Run("审核模型调用失败返回错误", func(t *testing.T) {
+		moderator := NewModerator(NewClientWithModels(&fixedChatModel{err: errors.New("timeout")}, nil, ClientConfig{}))
+
+		_, err := moderator.Check(ctx, ModerationMethodLLM, rules, "Go 语言进阶课程共 40 课时")
+		assert.Error(t, err)
+	})
+}
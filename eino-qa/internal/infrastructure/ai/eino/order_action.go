@@ -0,0 +1,224 @@
+package eino
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// orderActionKeywords 可能表示订单操作的关键词
+// 命中后才调用 LLM 识别操作，避免普通订单查询多一次 LLM 调用
+var orderActionKeywords = []string{"取消", "退款", "退钱", "退费", "cancel", "refund"}
+
+// OrderActionSpec LLM 从用户请求中识别出的订单操作
+type OrderActionSpec struct {
+	Action  string `json:"action"`
+	OrderID string `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+// PlanAction 识别用户请求中的订单操作，校验归属和订单状态后生成待确认操作
+// 不是操作请求时返回 (nil, "", nil)，调用方按普通订单查询处理；
+// 是操作请求但不满足条件时返回 (nil, 回复, nil)；缺少订单号时返回 *MissingSlotError；
+// 满足条件时返回待确认操作和确认摘要
+func (q *OrderQuerier) PlanAction(ctx context.Context, userID, query string) (*entity.PendingOrderAction, string, error) {
+	if !containsOrderActionKeyword(query) {
+		return nil, "", nil
+	}
+
+	spec, err := q.extractActionSpec(ctx, query)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to extract order action: %w", err)
+	}
+
+	actionType := entity.OrderActionType(strings.ToLower(strings.TrimSpace(spec.Action)))
+	if !actionType.IsValid() {
+		return nil, "", nil
+	}
+
+	if userID == "" {
+		return nil, fmt.Sprintf("%s需要先登录，请登录后再试。", actionType.DisplayName()), nil
+	}
+
+	// 订单号优先按租户方案从原文提取，其次使用 LLM 识别的结果
+	scheme := q.orderIDScheme(ctx)
+	orderID, ok := scheme.Extract(query)
+	if !ok && spec.OrderID != "" {
+		orderID, ok = scheme.Normalize(spec.OrderID)
+	}
+	if !ok {
+		return nil, "", &MissingSlotError{
+			Slot:   entity.SlotOrderID,
+			Prompt: fmt.Sprintf("请提供需要%s的订单号。", actionType.DisplayName()),
+		}
+	}
+
+	order, reply, err := q.findOwnedOrder(ctx, userID, orderID)
+	if err != nil || order == nil {
+		return nil, reply, err
+	}
+
+	if reply := q.checkOrderAction(order, actionType); reply != "" {
+		return nil, reply, nil
+	}
+
+	action := entity.NewPendingOrderAction(actionType, order.ID, userID, strings.TrimSpace(spec.Reason), entity.DefaultPendingOrderActionTTL)
+	return action, formatActionConfirmation(order, action), nil
+}
+
+// ExecuteAction 执行用户已确认的订单操作，返回回复和本次记录的订单事件
+// 执行前重新校验归属和订单状态；订单已处于目标状态时视为已执行（幂等），不重复记录事件
+func (q *OrderQuerier) ExecuteAction(ctx context.Context, action *entity.PendingOrderAction) (string, *entity.OrderEvent, error) {
+	if err := action.Validate(); err != nil {
+		return "", nil, fmt.Errorf("invalid order action: %w", err)
+	}
+
+	order, reply, err := q.findOwnedOrder(ctx, action.UserID, action.OrderID)
+	if err != nil || order == nil {
+		return reply, nil, err
+	}
+
+	target := action.Type.TargetStatus()
+	if order.Status == target {
+		return formatActionDone(order.ID, action.Type), nil, nil
+	}
+
+	if reply := q.checkOrderAction(order, action.Type); reply != "" {
+		return reply, nil, nil
+	}
+
+	reason := action.Reason
+	if reason == "" {
+		reason = "用户在对话中" + action.Type.DisplayName()
+	}
+
+	event, err := order.Transition(target, action.Actor(), reason)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to transition order: %w", err)
+	}
+
+	if err := q.orderRepository(ctx).SaveTransition(ctx, order, event); err != nil {
+		if !errors.Is(err, entity.ErrOrderStatusConflict) {
+			return "", nil, fmt.Errorf("failed to save order transition: %w", err)
+		}
+
+		// 并发修改：重新加载后若已处于目标状态（如重复确认），同样视为已执行
+		current, findErr := q.orderRepository(ctx).FindByID(ctx, order.ID)
+		if findErr == nil && current.Status == target {
+			return formatActionDone(order.ID, action.Type), nil, nil
+		}
+		return fmt.Sprintf("订单 %s 的状态已发生变化，请重新查询后再试。", order.ID), nil, nil
+	}
+
+	switch action.Type {
+	case entity.OrderActionCancel:
+		return fmt.Sprintf("已为您取消订单 %s。", order.ID), event, nil
+	default:
+		return fmt.Sprintf("已为您提交订单 %s 的退款申请，我们会尽快处理。", order.ID), event, nil
+	}
+}
+
+// checkOrderAction 校验订单当前状态能否执行操作，不能执行时返回说明
+func (q *OrderQuerier) checkOrderAction(order *entity.Order, actionType entity.OrderActionType) string {
+	target := actionType.TargetStatus()
+	if order.Status == target {
+		return formatActionDone(order.ID, actionType)
+	}
+
+	if order.Status.CanTransitionTo(target) {
+		return ""
+	}
+
+	if actionType == entity.OrderActionCancel && order.Status == entity.OrderStatusPaid {
+		return fmt.Sprintf("订单 %s 已支付，无法直接取消。如需退款，请告诉我\"申请退款\"。", order.ID)
+	}
+
+	return fmt.Sprintf("订单 %s 当前状态为%s，无法%s。", order.ID, q.formatOrderStatus(order.Status), actionType.DisplayName())
+}
+
+// formatActionDone 操作已执行时的回复
+func formatActionDone(orderID string, actionType entity.OrderActionType) string {
+	if actionType == entity.OrderActionCancel {
+		return fmt.Sprintf("订单 %s 已取消，无需重复操作。", orderID)
+	}
+	return fmt.Sprintf("订单 %s 已提交退款申请，正在处理中，无需重复申请。", orderID)
+}
+
+// formatActionConfirmation 生成操作确认摘要
+func formatActionConfirmation(order *entity.Order, action *entity.PendingOrderAction) string {
+	var sb strings.Builder
+	sb.WriteString("请确认以下操作：\n")
+	fmt.Fprintf(&sb, "- 操作：%s\n", action.Type.DisplayName())
+	fmt.Fprintf(&sb, "- 订单号：%s\n", order.ID)
+	fmt.Fprintf(&sb, "- 课程：%s\n", order.CourseName)
+	fmt.Fprintf(&sb, "- 金额：%.2f 元\n", order.Amount)
+	if action.Reason != "" {
+		fmt.Fprintf(&sb, "- 原因：%s\n", action.Reason)
+	}
+	fmt.Fprintf(&sb, "\n回复\"确认\"执行，回复\"算了\"放弃。确认将在 %d 分钟后失效。",
+		int(action.ExpiresAt.Sub(action.CreatedAt).Minutes()))
+	return sb.String()
+}
+
+// containsOrderActionKeyword 判断查询是否包含订单操作关键词
+func containsOrderActionKeyword(query string) bool {
+	lower := strings.ToLower(query)
+	for _, keyword := range orderActionKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractActionSpec 使用 LLM 识别用户请求中的订单操作
+func (q *OrderQuerier) extractActionSpec(ctx context.Context, query string) (*OrderActionSpec, error) {
+	systemPrompt := `你是一个订单操作识别助手。判断用户是否要求对订单执行操作。
+
+可用字段：
+- action: cancel（取消订单）、refund（申请退款），如果用户只是查询订单或询问政策则为空字符串
+- order_id: 用户明确提到的订单号，没有则为空字符串
+- reason: 用户说明的原因，没有则为空字符串
+
+例如"帮我取消订单 #20251114001"的 action 为 cancel；"我有退款的订单吗"、"退款要多久"只是查询，action 为空字符串。
+
+请以 JSON 格式返回结果，例如：
+{
+  "action": "refund",
+  "order_id": "#20251114001",
+  "reason": "课程内容与描述不符"
+}`
+
+	userPrompt := fmt.Sprintf("用户请求：%s\n\n请识别订单操作。", query)
+
+	messages := []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(userPrompt),
+	}
+
+	resp, err := q.chatModel.Generate(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate action spec: %w", err)
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var spec OrderActionSpec
+	if err := json.Unmarshal([]byte(content), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse action spec: %w", err)
+	}
+
+	spec.OrderID = strings.TrimSpace(spec.OrderID)
+
+	return &spec, nil
+}
@@ -0,0 +1,182 @@
+package eino
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// OrderAggregation 订单查询的聚合方式
+type OrderAggregation string
+
+const (
+	// OrderAggregationList 列出订单
+	OrderAggregationList OrderAggregation = "list"
+	// OrderAggregationCount 统计订单数量
+	OrderAggregationCount OrderAggregation = "count"
+	// OrderAggregationSum 统计订单总金额
+	OrderAggregationSum OrderAggregation = "sum"
+)
+
+// orderDateLayout 日期格式
+const orderDateLayout = "2006-01-02"
+
+// maxCourseNameLength 课程名称过滤条件的最大长度
+const maxCourseNameLength = 100
+
+// OrderQuerySpec LLM 从用户问题中解析出的结构化订单查询
+// 只包含白名单字段，由 ToFilter 校验后转换为仓储查询条件，LLM 不接触 SQL
+type OrderQuerySpec struct {
+	OrderID     string   `json:"order_id"`
+	Statuses    []string `json:"statuses"`
+	DateFrom    string   `json:"date_from"`
+	DateTo      string   `json:"date_to"`
+	CourseName  string   `json:"course_name"`
+	Aggregation string   `json:"aggregation"`
+	// NeedsOrderID 用户询问某一个具体订单但没有提供订单号
+	NeedsOrderID bool `json:"needs_order_id"`
+}
+
+// ToFilter 校验并转换为仓储查询条件
+// 用户 ID 只来自已校验的身份；无法识别的状态、日期和聚合方式会被忽略
+func (s *OrderQuerySpec) ToFilter(userID string) (repository.OrderFilter, OrderAggregation) {
+	filter := repository.OrderFilter{UserID: userID}
+
+	seen := make(map[entity.OrderStatus]bool)
+	for _, raw := range s.Statuses {
+		status := entity.OrderStatus(strings.ToLower(strings.TrimSpace(raw)))
+		if status.IsValid() && !seen[status] {
+			seen[status] = true
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if from, err := time.ParseInLocation(orderDateLayout, strings.TrimSpace(s.DateFrom), time.Local); err == nil {
+		filter.CreatedAfter = from
+	}
+	// 结束日期包含当天
+	if to, err := time.ParseInLocation(orderDateLayout, strings.TrimSpace(s.DateTo), time.Local); err == nil {
+		filter.CreatedBefore = to.AddDate(0, 0, 1)
+	}
+	if !filter.CreatedAfter.IsZero() && !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		filter.CreatedAfter, filter.CreatedBefore = time.Time{}, time.Time{}
+	}
+
+	courseName := strings.TrimSpace(s.CourseName)
+	if len([]rune(courseName)) <= maxCourseNameLength {
+		filter.CourseName = courseName
+	}
+
+	aggregation := OrderAggregation(strings.ToLower(strings.TrimSpace(s.Aggregation)))
+	switch aggregation {
+	case OrderAggregationCount, OrderAggregationSum:
+	default:
+		aggregation = OrderAggregationList
+	}
+
+	return filter, aggregation
+}
+
+// hasConditions 过滤条件是否包含用户 ID 以外的条件
+func hasConditions(filter repository.OrderFilter) bool {
+	return len(filter.Statuses) > 0 ||
+		!filter.CreatedAfter.IsZero() ||
+		!filter.CreatedBefore.IsZero() ||
+		filter.CourseName != ""
+}
+
+// describeFilter 将过滤条件描述为自然语言，供 LLM 组织回答
+func (q *OrderQuerier) describeFilter(filter repository.OrderFilter) string {
+	var parts []string
+
+	if len(filter.Statuses) > 0 {
+		names := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			names[i] = q.formatOrderStatus(status)
+		}
+		parts = append(parts, "订单状态："+strings.Join(names, "、"))
+	}
+
+	if !filter.CreatedAfter.IsZero() || !filter.CreatedBefore.IsZero() {
+		from, to := "不限", "不限"
+		if !filter.CreatedAfter.IsZero() {
+			from = filter.CreatedAfter.Format(orderDateLayout)
+		}
+		if !filter.CreatedBefore.IsZero() {
+			to = filter.CreatedBefore.AddDate(0, 0, -1).Format(orderDateLayout)
+		}
+		parts = append(parts, fmt.Sprintf("下单日期：%s 至 %s", from, to))
+	}
+
+	if filter.CourseName != "" {
+		parts = append(parts, "课程名称包含："+filter.CourseName)
+	}
+
+	if len(parts) == 0 {
+		return "全部订单"
+	}
+	return strings.Join(parts, "；")
+}
+
+// extractQuerySpec 使用 LLM 将用户问题解析为结构化订单查询
+func (q *OrderQuerier) extractQuerySpec(ctx context.Context, query string) (*OrderQuerySpec, error) {
+	systemPrompt := fmt.Sprintf(`你是一个订单查询解析助手。将用户关于订单的问题解析为结构化查询条件。
+
+今天的日期是 %s。
+
+可用字段（只能使用以下字段，不要输出 SQL）：
+- order_id: 用户明确提到的订单号，没有则为空字符串
+- statuses: 订单状态列表，可选值 pending（待支付）、paid（已支付）、refund_requested（退款申请中）、refunded（已退款）、cancelled（已取消），不限则为空数组
+- date_from: 下单开始日期，格式 YYYY-MM-DD，不限则为空字符串
+- date_to: 下单结束日期（包含当天），格式 YYYY-MM-DD，不限则为空字符串
+- course_name: 课程名称关键词，不限则为空字符串
+- aggregation: list（列出订单）、count（统计数量）、sum（统计总金额）
+- needs_order_id: 用户询问某一个具体订单（如"这个订单什么时候开通"）但没有提供订单号时为 true，询问"我的订单"或按条件查询时为 false
+
+"上个月"、"今年"等相对时间请根据今天的日期换算为具体日期。
+
+请以 JSON 格式返回结果，例如：
+{
+  "order_id": "",
+  "statuses": ["pending"],
+  "date_from": "2025-10-01",
+  "date_to": "2025-10-31",
+  "course_name": "",
+  "aggregation": "list",
+  "needs_order_id": false
+}`, time.Now().Format(orderDateLayout))
+
+	userPrompt := fmt.Sprintf("用户查询：%s\n\n请解析查询条件。", query)
+
+	messages := []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(userPrompt),
+	}
+
+	resp, err := q.chatModel.Generate(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query spec: %w", err)
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var spec OrderQuerySpec
+	if err := json.Unmarshal([]byte(content), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse query spec: %w", err)
+	}
+
+	spec.OrderID = strings.TrimSpace(spec.OrderID)
+
+	return &spec, nil
+}
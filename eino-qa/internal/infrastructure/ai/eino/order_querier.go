@@ -2,9 +2,8 @@ package eino
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"eino-qa/internal/domain/entity"
@@ -14,105 +13,196 @@ import (
 	"github.com/cloudwego/eino/schema"
 )
 
+// ErrMissingSlot 处理请求缺少必要信息（如订单号）
+var ErrMissingSlot = errors.New("missing required slot")
+
+// MissingSlotError 缺少槽位错误，调用方可据此向用户追问并在下一轮恢复处理
+// 可通过 errors.Is(err, ErrMissingSlot) 判断
+type MissingSlotError struct {
+	Slot   string // 缺少的槽位名称，如 entity.SlotOrderID
+	Prompt string // 建议的追问话术
+}
+
+// Error 实现 error 接口
+func (e *MissingSlotError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrMissingSlot.Error(), e.Slot)
+}
+
+// Unwrap 返回底层哨兵错误
+func (e *MissingSlotError) Unwrap() error {
+	return ErrMissingSlot
+}
+
+// OrderRepositoryProvider 按租户获取订单仓储
+type OrderRepositoryProvider func(tenantID string) repository.OrderRepository
+
 // OrderQuerier 订单查询器
 type OrderQuerier struct {
-	chatModel model.ChatModel
-	orderRepo repository.OrderRepository
+	promptSource
+
+	chatModel  model.ChatModel
+	orderRepos OrderRepositoryProvider
 }
 
 // NewOrderQuerier 创建新的订单查询器
-func NewOrderQuerier(client *Client, orderRepo repository.OrderRepository) *OrderQuerier {
+// 每次查询按 ctx 中的租户获取订单仓储，订单号方案与仓储使用同一租户
+func NewOrderQuerier(client *Client, orderRepos OrderRepositoryProvider) *OrderQuerier {
 	return &OrderQuerier{
-		chatModel: client.GetChatModel(),
-		orderRepo: orderRepo,
+		chatModel:  client.GetChatModel(),
+		orderRepos: orderRepos,
 	}
 }
 
-// Query 查询订单信息
-func (q *OrderQuerier) Query(ctx context.Context, query string) (string, error) {
-	// 1. 提取订单 ID
-	orderID, err := q.extractOrderID(ctx, query)
+// maxListedOrders 订单列表回答中最多列出的订单数
+const maxListedOrders = 10
+
+// OrderQueryResult 订单查询结果
+type OrderQueryResult struct {
+	Answer string          // 自然语言回答
+	Orders []*entity.Order // 回答涉及的订单（单个订单或列表中列出的订单，统计查询时为空）
+}
+
+// Query 查询订单信息，只返回自然语言回答
+func (q *OrderQuerier) Query(ctx context.Context, userID, query string) (string, error) {
+	result, err := q.QueryOrders(ctx, userID, query)
 	if err != nil {
-		return "", fmt.Errorf("failed to extract order id: %w", err)
+		return "", err
+	}
+	return result.Answer, nil
+}
+
+// QueryOrders 查询订单信息，返回回答和回答涉及的订单
+// userID 为已校验的终端用户 ID，只有 Order.UserID 与之匹配的订单才会被返回
+// 支持单个订单查询，以及按状态、日期、课程名称过滤的订单列表和数量/金额统计
+func (q *OrderQuerier) QueryOrders(ctx context.Context, userID, query string) (*OrderQueryResult, error) {
+	// 未登录用户不能查询任何订单
+	if userID == "" {
+		return &OrderQueryResult{Answer: "查询订单需要先登录，请登录后再试。"}, nil
 	}
 
-	if orderID == "" {
-		return "抱歉，我没有在您的问题中找到订单号。请提供订单号，格式如：#20251114001", nil
+	// 1. 优先按租户的订单号方案提取订单号
+	scheme := q.orderIDScheme(ctx)
+	if orderID, ok := scheme.Extract(query); ok {
+		return q.queryOrderByID(ctx, userID, orderID, query)
 	}
 
-	// 2. 查询订单
-	order, err := q.orderRepo.FindByID(ctx, orderID)
+	// 2. 使用 LLM 解析结构化查询条件
+	spec, err := q.extractQuerySpec(ctx, query)
 	if err != nil {
-		if err == entity.ErrOrderNotFound {
-			return fmt.Sprintf("抱歉，未找到订单号为 %s 的订单。请确认订单号是否正确。", orderID), nil
+		return nil, fmt.Errorf("failed to extract order query: %w", err)
+	}
+
+	if spec.OrderID != "" {
+		orderID, ok := scheme.Normalize(spec.OrderID)
+		if !ok {
+			return &OrderQueryResult{Answer: fmt.Sprintf("抱歉，%s 不是有效的订单号。请确认订单号是否正确。", spec.OrderID)}, nil
 		}
-		return "", fmt.Errorf("failed to query order: %w", err)
+		return q.queryOrderByID(ctx, userID, orderID, query)
+	}
+
+	// 询问某个具体订单但没有提供订单号时追问
+	if spec.NeedsOrderID {
+		return nil, &MissingSlotError{Slot: entity.SlotOrderID, Prompt: "请提供您要查询的订单号。"}
+	}
+
+	// 3. 按过滤条件查询当前用户的订单
+	filter, aggregation := spec.ToFilter(userID)
+	if aggregation != OrderAggregationList {
+		answer, err := q.queryOrderAggregate(ctx, query, filter, aggregation)
+		if err != nil {
+			return nil, err
+		}
+		return &OrderQueryResult{Answer: answer}, nil
+	}
+
+	return q.queryOrderList(ctx, query, filter)
+}
+
+// queryOrderByID 查询单个订单
+func (q *OrderQuerier) queryOrderByID(ctx context.Context, userID, orderID, query string) (*OrderQueryResult, error) {
+	order, reply, err := q.findOwnedOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return &OrderQueryResult{Answer: reply}, nil
 	}
 
-	// 3. 格式化订单信息为自然语言
 	answer, err := q.formatOrderInfo(ctx, query, order)
 	if err != nil {
-		return "", fmt.Errorf("failed to format order info: %w", err)
+		return nil, fmt.Errorf("failed to format order info: %w", err)
 	}
 
-	return answer, nil
+	return &OrderQueryResult{Answer: answer, Orders: []*entity.Order{order}}, nil
 }
 
-// extractOrderID 从用户查询中提取订单 ID
-func (q *OrderQuerier) extractOrderID(ctx context.Context, query string) (string, error) {
-	// 首先尝试使用正则表达式提取
-	orderID := q.extractOrderIDByRegex(query)
-	if orderID != "" {
-		return orderID, nil
+// findOwnedOrder 查询属于当前用户的订单
+// 不属于当前用户的订单与不存在的订单回复一致
+func (q *OrderQuerier) findOwnedOrder(ctx context.Context, userID, orderID string) (*entity.Order, string, error) {
+	order, err := q.orderRepository(ctx).FindByID(ctx, orderID)
+	if err != nil && !errors.Is(err, entity.ErrOrderNotFound) {
+		return nil, "", fmt.Errorf("failed to query order: %w", err)
+	}
+
+	if err != nil || order.UserID != userID {
+		return nil, fmt.Sprintf("抱歉，未找到订单号为 %s 的订单。请确认订单号是否正确。", orderID), nil
 	}
 
-	// 如果正则提取失败，使用 LLM 提取
-	return q.extractOrderIDByLLM(ctx, query)
+	return order, "", nil
 }
 
-// extractOrderIDByRegex 使用正则表达式提取订单 ID
-func (q *OrderQuerier) extractOrderIDByRegex(query string) string {
-	// 匹配订单号格式：#20251114001 或 20251114001
-	patterns := []string{
-		`#(\d{11,})`,          // #开头的订单号
-		`订单号[：:]\s*(\d{11,})`, // 订单号：xxxxx
-		`订单[：:]\s*(\d{11,})`,  // 订单：xxxxx
-		`\b(\d{11,})\b`,       // 独立的11位以上数字
+// queryOrderList 查询当前用户符合条件的订单列表
+func (q *OrderQuerier) queryOrderList(ctx context.Context, query string, filter repository.OrderFilter) (*OrderQueryResult, error) {
+	var orders []*entity.Order
+	var err error
+
+	// 没有过滤条件时即"我的订单"
+	if hasConditions(filter) {
+		orders, err = q.orderRepository(ctx).FindByFilter(ctx, filter)
+	} else {
+		orders, err = q.orderRepository(ctx).FindByUserID(ctx, filter.UserID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user orders: %w", err)
 	}
 
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(query)
-		if len(matches) > 1 {
-			return matches[1]
+	if len(orders) == 0 {
+		if hasConditions(filter) {
+			return &OrderQueryResult{Answer: fmt.Sprintf("没有找到符合条件的订单（%s）。", q.describeFilter(filter))}, nil
 		}
+		return &OrderQueryResult{Answer: "您目前还没有订单记录。"}, nil
 	}
 
-	return ""
+	answer, err := q.formatOrderList(ctx, query, q.describeFilter(filter), orders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format order list: %w", err)
+	}
+
+	if len(orders) > maxListedOrders {
+		orders = orders[:maxListedOrders]
+	}
+	return &OrderQueryResult{Answer: answer, Orders: orders}, nil
 }
 
-// extractOrderIDByLLM 使用 LLM 提取订单 ID
-func (q *OrderQuerier) extractOrderIDByLLM(ctx context.Context, query string) (string, error) {
-	systemPrompt := `你是一个订单号提取助手。从用户的查询中提取订单号。
+// queryOrderAggregate 统计当前用户符合条件的订单数量或金额
+func (q *OrderQuerier) queryOrderAggregate(ctx context.Context, query string, filter repository.OrderFilter, aggregation OrderAggregation) (string, error) {
+	stats, err := q.orderRepository(ctx).Aggregate(ctx, filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to aggregate orders: %w", err)
+	}
 
-订单号特征：
-- 通常是11位或更长的数字
-- 可能以 # 开头
-- 可能在"订单号"、"订单"等词后面
+	statsInfo := fmt.Sprintf(`订单统计（%s）：
+- 订单数量：%d 个
+- 订单总金额：%.2f 元`, q.describeFilter(filter), stats.Count, stats.TotalAmount)
 
-请以 JSON 格式返回结果：
-{
-  "order_id": "提取到的订单号（只包含数字，不包含#）",
-  "found": true/false
-}
+	focus := "订单数量"
+	if aggregation == OrderAggregationSum {
+		focus = "订单总金额"
+	}
 
-如果没有找到订单号，返回：
-{
-  "order_id": "",
-  "found": false
-}`
+	systemPrompt := q.systemPrompt(ctx, entity.PromptOrderStats)
 
-	userPrompt := fmt.Sprintf("用户查询：%s\n\n请提取订单号。", query)
+	userPrompt := fmt.Sprintf("%s\n\n用户问题：%s\n\n请重点回答%s。", statsInfo, query, focus)
 
 	messages := []*schema.Message{
 		schema.SystemMessage(systemPrompt),
@@ -121,30 +211,28 @@ func (q *OrderQuerier) extractOrderIDByLLM(ctx context.Context, query string) (s
 
 	resp, err := q.chatModel.Generate(ctx, messages)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate extraction: %w", err)
+		return "", fmt.Errorf("failed to generate answer: %w", err)
 	}
 
-	// 解析结果
-	content := strings.TrimSpace(resp.Content)
-	content = strings.TrimPrefix(content, "```json")
-	content = strings.TrimPrefix(content, "```")
-	content = strings.TrimSuffix(content, "```")
-	content = strings.TrimSpace(content)
+	return resp.Content, nil
+}
 
-	var result struct {
-		OrderID string `json:"order_id"`
-		Found   bool   `json:"found"`
-	}
+// orderIDScheme 获取当前租户的订单号方案
+func (q *OrderQuerier) orderIDScheme(ctx context.Context) entity.OrderIDScheme {
+	return entity.OrderIDSchemeFor(tenantFromContext(ctx))
+}
 
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		return "", fmt.Errorf("failed to parse extraction result: %w", err)
-	}
+// orderRepository 获取当前租户的订单仓储
+func (q *OrderQuerier) orderRepository(ctx context.Context) repository.OrderRepository {
+	return q.orderRepos(tenantFromContext(ctx))
+}
 
-	if !result.Found {
-		return "", nil
+// tenantFromContext 获取 ctx 中的租户 ID，未设置时为默认租户
+func tenantFromContext(ctx context.Context) string {
+	if tenantID, _ := ctx.Value("tenant_id").(string); tenantID != "" {
+		return tenantID
 	}
-
-	return result.OrderID, nil
+	return "default"
 }
 
 // formatOrderInfo 将订单信息格式化为自然语言
@@ -163,14 +251,13 @@ func (q *OrderQuerier) formatOrderInfo(ctx context.Context, query string, order
 		order.CreatedAt.Format("2006-01-02 15:04:05"),
 	)
 
-	// 使用 LLM 生成自然语言回复
-	systemPrompt := `你是一个专业的客服助手。根据订单信息，用自然、友好的语言回答用户的问题。
+	// 附加订单时间线（获取失败时不影响回答）
+	if events, err := q.orderRepository(ctx).FindEvents(ctx, order.ID); err == nil && len(events) > 0 {
+		orderInfo += "\n\n" + q.formatOrderTimeline(events)
+	}
 
-要求：
-1. 语气友好、专业
-2. 信息准确、完整
-3. 根据用户的具体问题重点回答
-4. 如果订单状态异常，提供相应的建议`
+	// 使用 LLM 生成自然语言回复
+	systemPrompt := q.systemPrompt(ctx, entity.PromptOrderDetail)
 
 	userPrompt := fmt.Sprintf("%s\n\n用户问题：%s\n\n请根据订单信息回答用户问题。", orderInfo, query)
 
@@ -187,20 +274,77 @@ func (q *OrderQuerier) formatOrderInfo(ctx context.Context, query string, order
 	return resp.Content, nil
 }
 
-// formatOrderStatus 格式化订单状态
-func (q *OrderQuerier) formatOrderStatus(status entity.OrderStatus) string {
-	statusMap := map[entity.OrderStatus]string{
-		entity.OrderStatusPending:   "待支付",
-		entity.OrderStatusPaid:      "已支付",
-		entity.OrderStatusRefunded:  "已退款",
-		entity.OrderStatusCancelled: "已取消",
+// formatOrderTimeline 将订单事件格式化为时间线
+func (q *OrderQuerier) formatOrderTimeline(events []*entity.OrderEvent) string {
+	var sb strings.Builder
+	sb.WriteString("订单时间线：")
+
+	for _, event := range events {
+		action := "订单状态变为" + q.formatOrderStatus(event.ToStatus)
+		switch {
+		case event.IsCreation():
+			action = "创建订单"
+		case event.ToStatus == entity.OrderStatusPaid && event.FromStatus == entity.OrderStatusRefundRequested:
+			action = "退款申请被驳回，恢复为已支付"
+		case event.ToStatus == entity.OrderStatusPaid:
+			action = "完成支付"
+		case event.ToStatus == entity.OrderStatusRefundRequested:
+			action = "申请退款"
+		case event.ToStatus == entity.OrderStatusRefunded:
+			action = "退款完成"
+		case event.ToStatus == entity.OrderStatusCancelled:
+			action = "取消订单"
+		}
+
+		fmt.Fprintf(&sb, "\n- %s %s", event.CreatedAt.Format("2006-01-02 15:04:05"), action)
+		if event.Reason != "" && !event.IsCreation() {
+			fmt.Fprintf(&sb, "（原因：%s）", event.Reason)
+		}
+	}
+
+	return sb.String()
+}
+
+// formatOrderList 将用户的订单列表格式化为自然语言
+func (q *OrderQuerier) formatOrderList(ctx context.Context, query, conditions string, orders []*entity.Order) (string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "符合条件（%s）的订单共 %d 个", conditions, len(orders))
+	if len(orders) > maxListedOrders {
+		fmt.Fprintf(&sb, "，以下为最近的 %d 个", maxListedOrders)
+		orders = orders[:maxListedOrders]
 	}
+	sb.WriteString("：\n")
+
+	for _, order := range orders {
+		fmt.Fprintf(&sb, "- 订单号：%s，课程名称：%s，订单金额：%.2f 元，订单状态：%s，创建时间：%s\n",
+			order.ID,
+			order.CourseName,
+			order.Amount,
+			q.formatOrderStatus(order.Status),
+			order.CreatedAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	systemPrompt := q.systemPrompt(ctx, entity.PromptOrderList)
 
-	if formatted, ok := statusMap[status]; ok {
-		return formatted
+	userPrompt := fmt.Sprintf("%s\n用户问题：%s\n\n请根据订单列表回答用户问题。", sb.String(), query)
+
+	messages := []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(userPrompt),
 	}
 
-	return string(status)
+	resp, err := q.chatModel.Generate(ctx, messages)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate answer: %w", err)
+	}
+
+	return resp.Content, nil
+}
+
+// formatOrderStatus 格式化订单状态
+func (q *OrderQuerier) formatOrderStatus(status entity.OrderStatus) string {
+	return status.DisplayName()
 }
 
 // ValidateSQL 验证 SQL 查询的安全性
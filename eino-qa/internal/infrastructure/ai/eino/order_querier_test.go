@@ -0,0 +1,580 @@
+package eino
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoChatModel 将最后一条消息原样返回，便于断言传给 LLM 的内容
+// 订单查询解析请求返回预设的 spec（默认无任何条件），订单操作识别请求返回预设的 action
+type echoChatModel struct {
+	calls  int
+	spec   string
+	action string
+}
+
+func (m *echoChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.calls++
+	if strings.Contains(input[0].Content, "订单操作识别助手") {
+		if m.action == "" {
+			return schema.AssistantMessage(`{}`, nil), nil
+		}
+		return schema.AssistantMessage(m.action, nil), nil
+	}
+	if strings.Contains(input[0].Content, "订单查询解析助手") {
+		if m.spec == "" {
+			return schema.AssistantMessage(`{}`, nil), nil
+		}
+		return schema.AssistantMessage(m.spec, nil), nil
+	}
+	return schema.AssistantMessage(input[len(input)-1].Content, nil), nil
+}
+
+func (m *echoChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (m *echoChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+// memoryOrderRepository 内存订单仓储
+type memoryOrderRepository struct {
+	orders []*entity.Order
+	events []*entity.OrderEvent
+}
+
+func (r *memoryOrderRepository) FindByID(ctx context.Context, orderID string) (*entity.Order, error) {
+	for _, o := range r.orders {
+		if o.ID == orderID {
+			return o, nil
+		}
+	}
+	return nil, entity.ErrOrderNotFound
+}
+
+func (r *memoryOrderRepository) FindByUserID(ctx context.Context, userID string) ([]*entity.Order, error) {
+	var result []*entity.Order
+	for _, o := range r.orders {
+		if o.UserID == userID {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryOrderRepository) FindByStatus(ctx context.Context, status entity.OrderStatus) ([]*entity.Order, error) {
+	var result []*entity.Order
+	for _, o := range r.orders {
+		if o.Status == status {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryOrderRepository) FindByFilter(ctx context.Context, filter repository.OrderFilter) ([]*entity.Order, error) {
+	var result []*entity.Order
+	for _, o := range r.orders {
+		if matchOrderFilter(o, filter) {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryOrderRepository) Aggregate(ctx context.Context, filter repository.OrderFilter) (*repository.OrderAggregate, error) {
+	stats := &repository.OrderAggregate{}
+	for _, o := range r.orders {
+		if matchOrderFilter(o, filter) {
+			stats.Count++
+			stats.TotalAmount += o.Amount
+		}
+	}
+	return stats, nil
+}
+
+func matchOrderFilter(o *entity.Order, filter repository.OrderFilter) bool {
+	if filter.UserID != "" && o.UserID != filter.UserID {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, o.Status) {
+		return false
+	}
+	if !filter.CreatedAfter.IsZero() && o.CreatedAt.Before(filter.CreatedAfter) {
+		return false
+	}
+	if !filter.CreatedBefore.IsZero() && !o.CreatedAt.Before(filter.CreatedBefore) {
+		return false
+	}
+	return filter.CourseName == "" || strings.Contains(o.CourseName, filter.CourseName)
+}
+
+func (r *memoryOrderRepository) Create(ctx context.Context, order *entity.Order) error {
+	r.orders = append(r.orders, order)
+	return nil
+}
+
+func (r *memoryOrderRepository) Update(ctx context.Context, order *entity.Order) error {
+	return nil
+}
+
+func (r *memoryOrderRepository) SaveTransition(ctx context.Context, order *entity.Order, event *entity.OrderEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memoryOrderRepository) Upsert(ctx context.Context, order *entity.Order, actor string) (repository.OrderUpsertResult, error) {
+	for i, o := range r.orders {
+		if o.ID == order.ID {
+			r.orders[i] = order
+			return repository.OrderUpsertUpdated, nil
+		}
+	}
+	r.orders = append(r.orders, order)
+	return repository.OrderUpsertCreated, nil
+}
+
+func (r *memoryOrderRepository) FindEvents(ctx context.Context, orderID string) ([]*entity.OrderEvent, error) {
+	var result []*entity.OrderEvent
+	for _, e := range r.events {
+		if e.OrderID == orderID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryOrderRepository) Delete(ctx context.Context, orderID string) error {
+	return nil
+}
+
+func (r *memoryOrderRepository) List(ctx context.Context, offset, limit int) ([]*entity.Order, error) {
+	return r.orders, nil
+}
+
+func (r *memoryOrderRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.orders)), nil
+}
+
+func newTestOrder(id, userID, courseName string) *entity.Order {
+	return &entity.Order{
+		ID:         id,
+		UserID:     userID,
+		CourseName: courseName,
+		Amount:     99,
+		Status:     entity.OrderStatusPaid,
+		TenantID:   "default",
+		CreatedAt:  time.Date(2025, 11, 14, 10, 0, 0, 0, time.Local),
+	}
+}
+
+func newTestOrderQuerier() (*OrderQuerier, *echoChatModel) {
+	chatModel := &echoChatModel{}
+
+	pending := newTestOrder("#20251020001", "alice", "Go 语言进阶")
+	pending.Status = entity.OrderStatusPending
+	pending.Amount = 199
+	pending.CreatedAt = time.Date(2025, 10, 20, 9, 0, 0, 0, time.Local)
+
+	repo := &memoryOrderRepository{orders: []*entity.Order{
+		newTestOrder("#20251114001", "alice", "Go 语言进阶"),
+		newTestOrder("#20251114002", "alice", "分布式系统"),
+		newTestOrder("#20251114003", "bob", "机器学习入门"),
+		pending,
+	}}
+	return &OrderQuerier{chatModel: chatModel, orderRepos: func(string) repository.OrderRepository { return repo }}, chatModel
+}
+
+// testOrderRepository 获取测试查询器使用的内存仓储
+func testOrderRepository(querier *OrderQuerier) *memoryOrderRepository {
+	return querier.orderRepository(context.Background()).(*memoryOrderRepository)
+}
+
+func TestOrderQuerier_QueryOwnership(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("匿名用户需要登录", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		answer, err := querier.Query(ctx, "", "查询订单#20251114001")
+		require.NoError(t, err)
+		assert.Contains(t, answer, "登录")
+		assert.Zero(t, chatModel.calls)
+	})
+
+	t.Run("查询本人订单", func(t *testing.T) {
+		querier, _ := newTestOrderQuerier()
+		answer, err := querier.Query(ctx, "alice", "查询订单#20251114001")
+		require.NoError(t, err)
+		assert.Contains(t, answer, "Go 语言进阶")
+	})
+
+	t.Run("他人订单与不存在的订单回复一致", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		others, err := querier.Query(ctx, "alice", "查询订单#20251114003")
+		require.NoError(t, err)
+		assert.NotContains(t, others, "机器学习入门")
+		assert.Zero(t, chatModel.calls)
+
+		missing, err := querier.Query(ctx, "alice", "查询订单#20251114999")
+		require.NoError(t, err)
+		assert.Equal(t, "抱歉，未找到订单号为 #20251114999 的订单。请确认订单号是否正确。", missing)
+		assert.Equal(t, "抱歉，未找到订单号为 #20251114003 的订单。请确认订单号是否正确。", others)
+	})
+
+	t.Run("我的订单只列出本人订单", func(t *testing.T) {
+		querier, _ := newTestOrderQuerier()
+		answer, err := querier.Query(ctx, "alice", "我的订单")
+		require.NoError(t, err)
+		assert.Contains(t, answer, "20251114001")
+		assert.Contains(t, answer, "20251114002")
+		assert.NotContains(t, answer, "20251114003")
+	})
+
+	t.Run("没有订单", func(t *testing.T) {
+		querier, _ := newTestOrderQuerier()
+		answer, err := querier.Query(ctx, "carol", "我的订单")
+		require.NoError(t, err)
+		assert.Equal(t, "您目前还没有订单记录。", answer)
+	})
+}
+
+func TestOrderQuerier_QueryWithFilter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("上个月未支付的订单", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.spec = `{"statuses": ["pending"], "date_from": "2025-10-01", "date_to": "2025-10-31", "aggregation": "list"}`
+
+		answer, err := querier.Query(ctx, "alice", "我上个月有哪些未支付的订单")
+		require.NoError(t, err)
+		assert.Contains(t, answer, "20251020001")
+		assert.NotContains(t, answer, "20251114001")
+		assert.Contains(t, answer, "下单日期：2025-10-01 至 2025-10-31")
+	})
+
+	t.Run("统计总消费金额", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.spec = "```json\n{\"statuses\": [\"paid\"], \"aggregation\": \"sum\"}\n```"
+
+		answer, err := querier.Query(ctx, "alice", "how much have I spent in total")
+		require.NoError(t, err)
+		assert.Contains(t, answer, "订单数量：2 个")
+		assert.Contains(t, answer, "订单总金额：198.00 元")
+	})
+
+	t.Run("没有符合条件的订单", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.spec = `{"statuses": ["refunded"]}`
+
+		answer, err := querier.Query(ctx, "alice", "我有退款的订单吗")
+		require.NoError(t, err)
+		assert.Equal(t, "没有找到符合条件的订单（订单状态：已退款）。", answer)
+	})
+
+	t.Run("LLM 解析出订单号时仍校验归属", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.spec = `{"order_id": "#20251114003"}`
+
+		answer, err := querier.Query(ctx, "alice", "帮我查一下 bob 的那个订单")
+		require.NoError(t, err)
+		assert.Equal(t, "抱歉，未找到订单号为 #20251114003 的订单。请确认订单号是否正确。", answer)
+	})
+}
+
+func TestOrderQuerier_QueryOrders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("单个订单", func(t *testing.T) {
+		querier, _ := newTestOrderQuerier()
+		result, err := querier.QueryOrders(ctx, "alice", "查询订单#20251114001")
+		require.NoError(t, err)
+		require.Len(t, result.Orders, 1)
+		assert.Equal(t, "#20251114001", result.Orders[0].ID)
+		assert.Contains(t, result.Answer, "Go 语言进阶")
+	})
+
+	t.Run("他人订单不返回订单数据", func(t *testing.T) {
+		querier, _ := newTestOrderQuerier()
+		result, err := querier.QueryOrders(ctx, "alice", "查询订单#20251114003")
+		require.NoError(t, err)
+		assert.Empty(t, result.Orders)
+	})
+
+	t.Run("订单列表", func(t *testing.T) {
+		querier, _ := newTestOrderQuerier()
+		result, err := querier.QueryOrders(ctx, "alice", "我的订单")
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Orders)
+		for _, order := range result.Orders {
+			assert.Equal(t, "alice", order.UserID)
+		}
+	})
+}
+
+func TestOrderQuerySpec_ToFilter(t *testing.T) {
+	spec := &OrderQuerySpec{
+		Statuses:    []string{"PENDING", "paid", "unknown", "paid"},
+		DateFrom:    "2025-10-01",
+		DateTo:      "not-a-date",
+		CourseName:  "  Go  ",
+		Aggregation: "DROP TABLE orders",
+	}
+
+	filter, aggregation := spec.ToFilter("alice")
+
+	assert.Equal(t, "alice", filter.UserID)
+	assert.Equal(t, []entity.OrderStatus{entity.OrderStatusPending, entity.OrderStatusPaid}, filter.Statuses)
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local), filter.CreatedAfter)
+	assert.True(t, filter.CreatedBefore.IsZero())
+	assert.Equal(t, "Go", filter.CourseName)
+	assert.Equal(t, OrderAggregationList, aggregation)
+
+	// 结束日期早于开始日期时忽略日期条件
+	reversed := &OrderQuerySpec{DateFrom: "2025-10-31", DateTo: "2025-10-01", Aggregation: "count"}
+	filter, aggregation = reversed.ToFilter("alice")
+	assert.True(t, filter.CreatedAfter.IsZero())
+	assert.True(t, filter.CreatedBefore.IsZero())
+	assert.Equal(t, OrderAggregationCount, aggregation)
+}
+
+func TestOrderQuerier_TenantOrderIDScheme(t *testing.T) {
+	scheme, err := entity.NewPatternOrderIDScheme(entity.OrderIDSchemeConfig{
+		Prefix:          "SO-",
+		Pattern:         `[A-Z]{2}\d{6}`,
+		CaseInsensitive: true,
+	})
+	require.NoError(t, err)
+	entity.RegisterOrderIDScheme("shop", scheme)
+	defer entity.RegisterOrderIDScheme("shop", nil)
+
+	querier, chatModel := newTestOrderQuerier()
+	imported := newTestOrder("SO-AB123456", "alice", "导入的课程")
+	imported.TenantID = "shop"
+	testOrderRepository(querier).orders = append(testOrderRepository(querier).orders, imported)
+
+	ctx := context.WithValue(context.Background(), "tenant_id", "shop")
+
+	// 用户输入小写且省略前缀，也能找到导入的订单
+	answer, err := querier.Query(ctx, "alice", "订单 ab123456 到哪了")
+	require.NoError(t, err)
+	assert.Contains(t, answer, "导入的课程")
+
+	// LLM 解析出的订单号不符合租户方案时不查询
+	chatModel.spec = `{"order_id": "20251114001"}`
+	answer, err = querier.Query(ctx, "alice", "帮我查一下上次那个订单")
+	require.NoError(t, err)
+	assert.Equal(t, "抱歉，20251114001 不是有效的订单号。请确认订单号是否正确。", answer)
+}
+
+func TestOrderQuerier_TenantRepository(t *testing.T) {
+	querier, _ := newTestOrderQuerier()
+	defaultRepo := testOrderRepository(querier)
+	shopRepo := &memoryOrderRepository{orders: []*entity.Order{newTestOrder("#20251114005", "alice", "店铺课程")}}
+	querier.orderRepos = func(tenantID string) repository.OrderRepository {
+		if tenantID == "shop" {
+			return shopRepo
+		}
+		return defaultRepo
+	}
+	shop := context.WithValue(context.Background(), "tenant_id", "shop")
+
+	// 只查询当前租户的订单，同名用户在其他租户的订单不可见
+	answer, err := querier.Query(shop, "alice", "我的订单")
+	require.NoError(t, err)
+	assert.Contains(t, answer, "20251114005")
+	assert.NotContains(t, answer, "20251114001")
+
+	answer, err = querier.Query(shop, "alice", "查询订单#20251114001")
+	require.NoError(t, err)
+	assert.Equal(t, "抱歉，未找到订单号为 #20251114001 的订单。请确认订单号是否正确。", answer)
+
+	// 订单操作同样写入当前租户的仓储
+	action := entity.NewPendingOrderAction(entity.OrderActionRefund, "#20251114005", "alice", "", 0)
+	_, event, err := querier.ExecuteAction(shop, action)
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Equal(t, entity.OrderActionRefund.TargetStatus(), shopRepo.orders[0].Status)
+
+	// 未设置租户时使用默认租户
+	answer, err = querier.Query(context.Background(), "alice", "查询订单#20251114001")
+	require.NoError(t, err)
+	assert.Contains(t, answer, "Go 语言进阶")
+}
+
+func TestOrderQuerier_OrderTimeline(t *testing.T) {
+	querier, _ := newTestOrderQuerier()
+	repo := testOrderRepository(querier)
+
+	order := repo.orders[0]
+	order.Status = entity.OrderStatusPending
+	repo.events = append(repo.events, entity.NewOrderEvent(order, "", "system", "订单创建"))
+
+	steps := []struct {
+		to     entity.OrderStatus
+		at     time.Time
+		reason string
+	}{
+		{entity.OrderStatusPaid, time.Date(2025, 11, 14, 10, 5, 0, 0, time.Local), "微信支付"},
+		{entity.OrderStatusRefundRequested, time.Date(2025, 11, 16, 9, 30, 0, 0, time.Local), "课程内容不符"},
+	}
+	for _, step := range steps {
+		event, err := order.Transition(step.to, "user:alice", step.reason)
+		require.NoError(t, err)
+		event.CreatedAt = step.at
+		require.NoError(t, repo.SaveTransition(context.Background(), order, event))
+	}
+
+	answer, err := querier.Query(context.Background(), "alice", "订单#20251114001 退款进度")
+	require.NoError(t, err)
+	assert.Contains(t, answer, "订单状态：退款申请中")
+	assert.Contains(t, answer, "订单时间线：")
+	assert.Contains(t, answer, "2025-11-14 10:05:00 完成支付（原因：微信支付）")
+	assert.Contains(t, answer, "2025-11-16 09:30:00 申请退款（原因：课程内容不符）")
+	assert.Less(t, strings.Index(answer, "完成支付"), strings.Index(answer, "申请退款"))
+}
+
+func TestOrderQuerier_PlanAction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("不含操作关键词时不调用 LLM", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		action, reply, err := querier.PlanAction(ctx, "alice", "订单#20251114001 到哪了")
+		require.NoError(t, err)
+		assert.Nil(t, action)
+		assert.Empty(t, reply)
+		assert.Zero(t, chatModel.calls)
+	})
+
+	t.Run("只是查询退款订单", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		action, reply, err := querier.PlanAction(ctx, "alice", "我有退款的订单吗")
+		require.NoError(t, err)
+		assert.Nil(t, action)
+		assert.Empty(t, reply)
+		assert.Equal(t, 1, chatModel.calls)
+	})
+
+	t.Run("取消待支付订单需要确认", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.action = `{"action": "cancel", "reason": "买错了"}`
+
+		action, reply, err := querier.PlanAction(ctx, "alice", "帮我取消订单 #20251020001，买错了")
+		require.NoError(t, err)
+		require.NotNil(t, action)
+		assert.Equal(t, entity.OrderActionCancel, action.Type)
+		assert.Equal(t, "#20251020001", action.OrderID)
+		assert.Equal(t, "alice", action.UserID)
+		assert.True(t, action.ExpiresAt.After(time.Now()))
+		assert.Contains(t, reply, "请确认以下操作")
+		assert.Contains(t, reply, "原因：买错了")
+
+		// 生成确认摘要时不修改订单
+		order, _ := testOrderRepository(querier).FindByID(ctx, "#20251020001")
+		assert.Equal(t, entity.OrderStatusPending, order.Status)
+	})
+
+	t.Run("已支付订单不能取消", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.action = `{"action": "cancel"}`
+
+		action, reply, err := querier.PlanAction(ctx, "alice", "取消订单 #20251114001")
+		require.NoError(t, err)
+		assert.Nil(t, action)
+		assert.Contains(t, reply, "已支付，无法直接取消")
+	})
+
+	t.Run("他人订单与不存在的订单回复一致", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.action = `{"action": "refund"}`
+
+		action, reply, err := querier.PlanAction(ctx, "alice", "订单 #20251114003 申请退款")
+		require.NoError(t, err)
+		assert.Nil(t, action)
+		assert.Equal(t, "抱歉，未找到订单号为 #20251114003 的订单。请确认订单号是否正确。", reply)
+	})
+
+	t.Run("匿名用户需要登录", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.action = `{"action": "refund"}`
+
+		action, reply, err := querier.PlanAction(ctx, "", "订单 #20251114001 申请退款")
+		require.NoError(t, err)
+		assert.Nil(t, action)
+		assert.Contains(t, reply, "登录")
+	})
+}
+
+func TestOrderQuerier_MissingOrderID(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("询问具体订单但未提供订单号", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.spec = `{"needs_order_id": true}`
+
+		_, err := querier.Query(ctx, "alice", "这个订单什么时候开通")
+		var missing *MissingSlotError
+		require.ErrorAs(t, err, &missing)
+		assert.ErrorIs(t, err, ErrMissingSlot)
+		assert.Equal(t, entity.SlotOrderID, missing.Slot)
+		assert.NotEmpty(t, missing.Prompt)
+	})
+
+	t.Run("订单操作未提供订单号", func(t *testing.T) {
+		querier, chatModel := newTestOrderQuerier()
+		chatModel.action = `{"action": "refund"}`
+
+		action, _, err := querier.PlanAction(ctx, "alice", "我要申请退款")
+		assert.Nil(t, action)
+		var missing *MissingSlotError
+		require.ErrorAs(t, err, &missing)
+		assert.Equal(t, "请提供需要申请退款的订单号。", missing.Prompt)
+	})
+}
+
+func TestOrderQuerier_ExecuteAction(t *testing.T) {
+	ctx := context.Background()
+	querier, _ := newTestOrderQuerier()
+	repo := testOrderRepository(querier)
+
+	action := entity.NewPendingOrderAction(entity.OrderActionRefund, "#20251114001", "alice", "课程内容不符", 0)
+
+	reply, event, err := querier.ExecuteAction(ctx, action)
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	assert.Contains(t, reply, "退款申请")
+	assert.Equal(t, entity.OrderStatusPaid, event.FromStatus)
+	assert.Equal(t, entity.OrderStatusRefundRequested, event.ToStatus)
+	assert.Equal(t, "user:alice", event.Actor)
+	assert.Equal(t, "课程内容不符", event.Reason)
+
+	// 重复确认同一操作不会重复记录事件
+	reply, event, err = querier.ExecuteAction(ctx, action)
+	require.NoError(t, err)
+	assert.Nil(t, event)
+	assert.Contains(t, reply, "无需重复申请")
+	assert.Len(t, repo.events, 1)
+
+	// 执行前重新校验归属
+	stolen := entity.NewPendingOrderAction(entity.OrderActionRefund, "#20251114002", "bob", "", 0)
+	reply, event, err = querier.ExecuteAction(ctx, stolen)
+	require.NoError(t, err)
+	assert.Nil(t, event)
+	assert.Contains(t, reply, "未找到订单号")
+	assert.Len(t, repo.events, 1)
+}
@@ -0,0 +1,150 @@
+package eino
+
+import (
+	"context"
+	"fmt"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// PromptRenderer 按租户渲染系统提示词
+// 由提示词模板用例实现：优先使用租户启用的模板版本，否则使用内置默认模板并代入租户变量
+type PromptRenderer interface {
+	// RenderPrompt 渲染当前租户（从 ctx 读取）的系统提示词
+	// 返回 false 时组件使用不带租户变量的内置默认提示词
+	RenderPrompt(ctx context.Context, name entity.PromptName) (string, bool)
+}
+
+// DefaultPromptTemplates 内置默认提示词模板（Go text/template 语法）
+// 未配置任何变量时渲染结果与原先固定的提示词一致
+var DefaultPromptTemplates = map[entity.PromptName]string{
+	entity.PromptIntent: `你是一个{{if .BrandName}}{{.BrandName}}的{{end}}智能客服意图识别助手。你的任务是分析用户的查询，判断用户的意图类型。
+
+意图类型定义：
+1. course - 课程咨询：用户询问{{if .ProductScope}}{{.ProductScope}}的{{end}}课程内容、课程安排、学习资料等与课程相关的问题
+2. order - 订单查询：用户查询订单状态、订单详情、退款等与订单相关的问题
+3. direct - 直接回答：简单的问候、闲聊或可以直接回答的一般性问题
+4. handoff - 人工转接：复杂问题、投诉、或需要人工处理的情况
+
+请以 JSON 格式返回结果，包含以下字段：
+{
+  "intent": "意图类型（course/order/direct/handoff）",
+  "confidence": 置信度分数（0-1之间的浮点数）,
+  "reason": "判断理由"
+}
+
+注意：
+- 只返回 JSON，不要包含其他文字
+- confidence 必须是 0 到 1 之间的数字
+- 如果不确定，将 confidence 设置为较低的值`,
+
+	entity.PromptRAG: `你是一个{{if .BrandName}}{{.BrandName}}的{{end}}专业的课程咨询助手。你的任务是根据提供的知识库文档，准确回答用户关于{{if .ProductScope}}{{.ProductScope}}{{else}}课程{{end}}的问题。
+
+回答要求：
+1. 基于提供的文档内容回答，不要编造信息
+2. 如果文档中没有相关信息，明确告知用户
+3. 回答要清晰、准确、有条理
+4. 使用友好、专业的语气
+5. 如果需要，可以引用文档中的具体内容
+6. 在使用了文档内容的句子末尾用 [n] 标注来源，n 是知识库文档的编号，如"课程共 12 周[1]"；多个来源写作 [1][2]
+7. 只能引用提供的文档编号，不要编造编号
+
+注意：
+- 只回答与课程相关的问题
+- 不要回答与课程无关的问题
+- 如果问题超出知识库范围，建议用户联系人工客服{{if .Language}}
+- 使用{{.Language}}回答{{end}}`,
+
+	entity.PromptRAGStrict: `你是一个{{if .BrandName}}{{.BrandName}}的{{end}}严谨的课程咨询助手。上一次的回答包含知识库文档无法支持的内容，请重新回答。
+
+严格要求：
+1. 每一句话都必须能在提供的文档中找到依据，尽量使用文档中的原话
+2. 不要推测、补充或概括文档中没有的信息（包括价格、时长、优惠、承诺等）
+3. 每一句话末尾用 [n] 标注来源文档编号，n 是知识库文档的编号
+4. 文档无法回答的部分，直接说明"知识库中没有相关信息"，并建议用户联系人工客服
+5. 回答宁可简短，也不要包含没有依据的内容{{if .Language}}
+6. 使用{{.Language}}回答{{end}}`,
+
+	entity.PromptOrderDetail: `你是一个{{if .BrandName}}{{.BrandName}}的{{end}}专业的客服助手。根据订单信息，用自然、友好的语言回答用户的问题。
+
+要求：
+1. 语气友好、专业
+2. 信息准确、完整
+3. 根据用户的具体问题重点回答
+4. 如果订单状态异常，提供相应的建议
+5. 如果提供了订单时间线，说明关键节点的时间（如"X 日支付，Y 日申请退款"）{{if .Language}}
+6. 使用{{.Language}}回答{{end}}`,
+
+	entity.PromptOrderList: `你是一个{{if .BrandName}}{{.BrandName}}的{{end}}专业的客服助手。根据用户的订单列表，用自然、友好的语言回答用户的问题。
+
+要求：
+1. 语气友好、专业
+2. 只使用列表中的订单信息，不要编造订单
+3. 根据用户的具体问题重点回答，必要时列出订单号便于用户继续查询{{if .Language}}
+4. 使用{{.Language}}回答{{end}}`,
+
+	entity.PromptOrderStats: `你是一个{{if .BrandName}}{{.BrandName}}的{{end}}专业的客服助手。根据订单统计结果，用自然、友好的语言回答用户的问题。
+
+要求：
+1. 语气友好、专业
+2. 只使用给出的统计数字，不要自行计算或编造
+3. 说明统计范围{{if .Language}}
+4. 使用{{.Language}}回答{{end}}`,
+
+	entity.PromptResponse: `你是一个{{if .BrandName}}{{.BrandName}}的{{end}}友好、专业的智能客服助手。你的任务是回答用户的问题，提供帮助和支持。
+
+回答要求：
+1. 语气友好、热情、专业
+2. 回答简洁明了，重点突出
+3. 对于简单的问候和闲聊，给予适当的回应
+4. 对于不确定的问题，诚实告知并建议联系人工客服
+5. 保持礼貌和耐心{{if .Language}}
+6. 使用{{.Language}}回答{{end}}
+
+注意事项：
+- 不要编造信息
+- 不要回答与{{if .ProductScope}}{{.ProductScope}}{{else}}业务{{end}}无关的问题
+- 如果问题超出能力范围，建议用户联系人工客服
+- 保护用户隐私，不要询问敏感信息`,
+}
+
+// defaultPrompts 不带租户变量渲染的默认提示词
+var defaultPrompts = renderDefaultPrompts()
+
+// renderDefaultPrompts 渲染所有默认提示词，模板有误时直接 panic（属于编码错误）
+func renderDefaultPrompts() map[entity.PromptName]string {
+	prompts := make(map[entity.PromptName]string, len(DefaultPromptTemplates))
+	for name, content := range DefaultPromptTemplates {
+		rendered, err := entity.RenderPrompt(content, entity.PromptData{})
+		if err != nil {
+			panic(fmt.Sprintf("invalid default prompt template %s: %v", name, err))
+		}
+		prompts[name] = rendered
+	}
+	return prompts
+}
+
+// DefaultPrompt 返回不带租户变量的默认提示词
+func DefaultPrompt(name entity.PromptName) string {
+	return defaultPrompts[name]
+}
+
+// promptSource 嵌入到使用系统提示词的组件中，提供按租户渲染的能力
+type promptSource struct {
+	renderer PromptRenderer
+}
+
+// SetPromptRenderer 设置租户提示词渲染器（可选）
+func (s *promptSource) SetPromptRenderer(renderer PromptRenderer) {
+	s.renderer = renderer
+}
+
+// systemPrompt 获取当前租户的系统提示词，未设置渲染器或渲染失败时使用默认提示词
+func (s *promptSource) systemPrompt(ctx context.Context, name entity.PromptName) string {
+	if s.renderer != nil {
+		if prompt, ok := s.renderer.RenderPrompt(ctx, name); ok {
+			return prompt
+		}
+	}
+	return DefaultPrompt(name)
+}
@@ -0,0 +1,44 @@
+package eino
+
+import (
+	"context"
+	"testing"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubPromptRenderer 按名称返回固定提示词的渲染器
+type stubPromptRenderer map[entity.PromptName]string
+
+func (r stubPromptRenderer) RenderPrompt(ctx context.Context, name entity.PromptName) (string, bool) {
+	prompt, ok := r[name]
+	return prompt, ok
+}
+
+func TestDefaultPromptTemplates(t *testing.T) {
+	for _, name := range entity.PromptNames {
+		content, ok := DefaultPromptTemplates[name]
+		assert.True(t, ok, "missing default template for %s", name)
+
+		// 代入全部变量也能渲染
+		rendered, err := entity.RenderPrompt(content, entity.SamplePromptData(nil))
+		assert.NoError(t, err)
+		assert.Contains(t, rendered, "示例教育")
+		assert.NotContains(t, DefaultPrompt(name), "示例教育")
+	}
+}
+
+func TestPromptSource_SystemPrompt(t *testing.T) {
+	ctx := context.Background()
+	querier := &OrderQuerier{}
+
+	// 未设置渲染器时使用默认提示词
+	assert.Equal(t, DefaultPrompt(entity.PromptOrderList), querier.systemPrompt(ctx, entity.PromptOrderList))
+
+	// 渲染器覆盖的提示词优先，未覆盖的回退到默认提示词
+	querier.SetPromptRenderer(stubPromptRenderer{entity.PromptOrderList: "租户订单列表提示词"})
+	assert.Equal(t, "租户订单列表提示词", querier.systemPrompt(ctx, entity.PromptOrderList))
+	assert.Equal(t, DefaultPrompt(entity.PromptOrderStats), querier.systemPrompt(ctx, entity.PromptOrderStats))
+}
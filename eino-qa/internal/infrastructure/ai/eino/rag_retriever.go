@@ -2,6 +2,7 @@ package eino
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -14,13 +15,37 @@ import (
 	"github.com/cloudwego/eino/schema"
 )
 
+// ErrNoRelevantDocuments 知识库中没有相似度达到阈值的文档
+var ErrNoRelevantDocuments = errors.New("no relevant documents found")
+
+// RetrievalMissError 检索未命中错误，携带用于分析的诊断信息
+// 可通过 errors.Is(err, ErrNoRelevantDocuments) 判断
+type RetrievalMissError struct {
+	Query    string  // 实际用于检索的查询
+	TopScore float64 // 检索结果中的最高相似度（无结果时为 0）
+}
+
+// Error 实现 error 接口
+func (e *RetrievalMissError) Error() string {
+	return fmt.Sprintf("%s (top score: %.4f)", ErrNoRelevantDocuments.Error(), e.TopScore)
+}
+
+// Unwrap 返回底层哨兵错误
+func (e *RetrievalMissError) Unwrap() error {
+	return ErrNoRelevantDocuments
+}
+
 // RAGRetriever RAG 检索器
 type RAGRetriever struct {
+	promptSource
+
 	embedder    embedding.Embedder
 	chatModel   model.ChatModel
 	vectorRepo  repository.VectorRepository
 	topK        int
 	scoreThresh float64
+
+	citationPolicy CitationPolicy
 }
 
 // NewRAGRetriever 创建新的 RAG 检索器
@@ -31,6 +56,7 @@ func NewRAGRetriever(
 ) *RAGRetriever {
 	topK := 5
 	scoreThresh := 0.7
+	citationPolicy := CitationPolicyFlag
 
 	if cfg != nil {
 		if cfg.TopK > 0 {
@@ -39,6 +65,9 @@ func NewRAGRetriever(
 		if cfg.ScoreThreshold > 0 {
 			scoreThresh = cfg.ScoreThreshold
 		}
+		if CitationPolicy(cfg.UncitedSources) == CitationPolicyDrop {
+			citationPolicy = CitationPolicyDrop
+		}
 	}
 
 	return &RAGRetriever{
@@ -47,21 +76,18 @@ func NewRAGRetriever(
 		vectorRepo:  vectorRepo,
 		topK:        topK,
 		scoreThresh: scoreThresh,
+
+		citationPolicy: citationPolicy,
 	}
 }
 
 // Retrieve 执行 RAG 检索并生成答案
+// 答案中的 [n] 引用标记经过校验，返回的来源文档与引用编号一一对应
 func (r *RAGRetriever) Retrieve(ctx context.Context, query string) (string, []*entity.Document, error) {
-	// 1. 生成查询向量
-	vector, err := r.generateQueryVector(ctx, query)
+	// 1-2. 生成查询向量并执行向量搜索
+	docs, err := r.Search(ctx, query, r.topK)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate query vector: %w", err)
-	}
-
-	// 2. 执行向量搜索
-	docs, err := r.vectorRepo.Search(ctx, vector, r.topK)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to search vectors: %w", err)
+		return "", nil, err
 	}
 
 	// 3. 过滤低分文档
@@ -69,16 +95,67 @@ func (r *RAGRetriever) Retrieve(ctx context.Context, query string) (string, []*e
 
 	// 4. 如果没有相关文档，返回未命中
 	if len(filteredDocs) == 0 {
-		return "", nil, fmt.Errorf("no relevant documents found")
+		return "", nil, &RetrievalMissError{
+			Query:    query,
+			TopScore: topScore(docs),
+		}
 	}
 
 	// 5. 使用检索到的文档生成答案
-	answer, err := r.generateAnswer(ctx, query, filteredDocs)
+	answer, err := r.generateAnswer(ctx, r.systemPrompt(ctx, entity.PromptRAG), query, filteredDocs)
 	if err != nil {
 		return "", filteredDocs, fmt.Errorf("failed to generate answer: %w", err)
 	}
 
-	return answer, filteredDocs, nil
+	// 6. 校验引用标记并整理来源文档
+	cited := ApplyCitations(answer, filteredDocs, r.citationPolicy)
+
+	return cited.Answer, cited.Sources, nil
+}
+
+// Search 执行向量检索，返回按相似度排序的前 topK 个文档（不做分数过滤，不生成答案）
+// 供离线评估计算召回率，topK <= 0 时使用配置的 top_k
+func (r *RAGRetriever) Search(ctx context.Context, query string, topK int) ([]*entity.Document, error) {
+	if topK <= 0 {
+		topK = r.topK
+	}
+
+	vector, err := r.generateQueryVector(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query vector: %w", err)
+	}
+
+	docs, err := r.vectorRepo.Search(ctx, vector, topK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search vectors: %w", err)
+	}
+	return docs, nil
+}
+
+// NeighbourChunks 获取来源文档的相邻分块（不含来源文档本身）
+// 最多查询 limit 个分块，查询失败的分块忽略
+func (r *RAGRetriever) NeighbourChunks(ctx context.Context, docs []*entity.Document, limit int) []*entity.Document {
+	seen := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		seen[doc.ID] = true
+	}
+
+	var neighbours []*entity.Document
+	for _, doc := range docs {
+		for _, id := range doc.NeighbourChunkIDs() {
+			if seen[id] || len(neighbours) >= limit {
+				continue
+			}
+			seen[id] = true
+
+			neighbour, err := r.vectorRepo.GetByID(ctx, id)
+			if err != nil || neighbour == nil {
+				continue
+			}
+			neighbours = append(neighbours, neighbour)
+		}
+	}
+	return neighbours
 }
 
 // generateQueryVector 生成查询向量
@@ -113,13 +190,35 @@ func (r *RAGRetriever) filterByScore(docs []*entity.Document) []*entity.Document
 	return filtered
 }
 
+// topScore 返回文档列表中的最高相似度
+func topScore(docs []*entity.Document) float64 {
+	top := 0.0
+	for _, doc := range docs {
+		if doc.Score > top {
+			top = doc.Score
+		}
+	}
+	return top
+}
+
+// RegenerateStrict 使用更严格的提示词，基于已检索的文档重新生成答案
+// 用于回答依据校验未通过时重试，返回的答案和来源文档同样经过引用校验
+func (r *RAGRetriever) RegenerateStrict(ctx context.Context, query string, docs []*entity.Document) (string, []*entity.Document, error) {
+	answer, err := r.generateAnswer(ctx, r.systemPrompt(ctx, entity.PromptRAGStrict), query, docs)
+	if err != nil {
+		return "", docs, fmt.Errorf("failed to regenerate answer: %w", err)
+	}
+
+	cited := ApplyCitations(answer, docs, r.citationPolicy)
+	return cited.Answer, cited.Sources, nil
+}
+
 // generateAnswer 使用检索到的文档生成答案
-func (r *RAGRetriever) generateAnswer(ctx context.Context, query string, docs []*entity.Document) (string, error) {
+func (r *RAGRetriever) generateAnswer(ctx context.Context, systemPrompt, query string, docs []*entity.Document) (string, error) {
 	// 构建上下文
 	context := r.buildContext(docs)
 
 	// 构建提示词
-	systemPrompt := r.buildSystemPrompt()
 	userPrompt := r.buildUserPrompt(query, context)
 
 	// 构建消息列表
@@ -142,7 +241,7 @@ func (r *RAGRetriever) buildContext(docs []*entity.Document) string {
 	var sb strings.Builder
 
 	for i, doc := range docs {
-		sb.WriteString(fmt.Sprintf("文档 %d (相似度: %.2f):\n", i+1, doc.Score))
+		sb.WriteString(fmt.Sprintf("文档 [%d] %s (相似度: %.2f):\n", i+1, doc.Title(), doc.Score))
 		sb.WriteString(doc.Content)
 		sb.WriteString("\n\n")
 	}
@@ -150,23 +249,6 @@ func (r *RAGRetriever) buildContext(docs []*entity.Document) string {
 	return sb.String()
 }
 
-// buildSystemPrompt 构建系统提示词
-func (r *RAGRetriever) buildSystemPrompt() string {
-	return `你是一个专业的课程咨询助手。你的任务是根据提供的知识库文档，准确回答用户关于课程的问题。
-
-回答要求：
-1. 基于提供的文档内容回答，不要编造信息
-2. 如果文档中没有相关信息，明确告知用户
-3. 回答要清晰、准确、有条理
-4. 使用友好、专业的语气
-5. 如果需要，可以引用文档中的具体内容
-
-注意：
-- 只回答与课程相关的问题
-- 不要回答与课程无关的问题
-- 如果问题超出知识库范围，建议用户联系人工客服`
-}
-
 // buildUserPrompt 构建用户提示词
 func (r *RAGRetriever) buildUserPrompt(query, context string) string {
 	var sb strings.Builder
@@ -175,7 +257,7 @@ func (r *RAGRetriever) buildUserPrompt(query, context string) string {
 	sb.WriteString(context)
 	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("用户问题：%s\n\n", query))
-	sb.WriteString("请基于上述知识库文档回答用户问题。")
+	sb.WriteString("请基于上述知识库文档回答用户问题，并用 [n] 标注引用的文档编号。")
 
 	return sb.String()
 }
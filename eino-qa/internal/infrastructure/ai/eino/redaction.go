@@ -0,0 +1,123 @@
+package eino
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"eino-qa/internal/infrastructure/redact"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// WithRedactor 设置个人信息脱敏服务（可选）
+// 设置后 GetChatModel 返回的模型在调用前按 ctx 中租户的策略将消息中的个人信息替换为占位符，并在回答中还原；
+// GetEmbedModel 返回的模型同样在调用前替换文本中的个人信息
+func (c *Client) WithRedactor(redactor *redact.Redactor) *Client {
+	c.redactor = redactor
+	return c
+}
+
+// redactingChatModel 调用前替换个人信息、调用后还原占位符的聊天模型
+type redactingChatModel struct {
+	inner    model.ChatModel
+	redactor *redact.Redactor
+}
+
+// Generate 替换消息中的个人信息后生成回答，并还原回答中的占位符
+func (m *redactingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	vault, input := m.tokenize(ctx, input)
+	output, err := m.inner.Generate(ctx, input, opts...)
+	if err != nil || vault == nil || output == nil {
+		return output, err
+	}
+
+	restored := *output
+	restored.Content = vault.Restore(output.Content)
+	return &restored, nil
+}
+
+// Stream 替换消息中的个人信息后流式生成回答，跨分片的占位符暂存到完整后再还原
+func (m *redactingChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	vault, input := m.tokenize(ctx, input)
+	stream, err := m.inner.Stream(ctx, input, opts...)
+	if err != nil || vault == nil {
+		return stream, err
+	}
+
+	reader, writer := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer stream.Close()
+		defer writer.Close()
+
+		restorer := vault.NewStreamRestorer()
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				if rest := restorer.Flush(); rest != "" {
+					writer.Send(&schema.Message{Role: schema.Assistant, Content: rest}, nil)
+				}
+				return
+			}
+			if err != nil {
+				writer.Send(nil, err)
+				return
+			}
+
+			restored := *chunk
+			restored.Content = restorer.Write(chunk.Content)
+			if closed := writer.Send(&restored, nil); closed {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+// BindTools 绑定工具到内部模型
+func (m *redactingChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return m.inner.BindTools(tools)
+}
+
+// tokenize 按租户策略替换消息中的个人信息，不需要替换时返回 nil 和原消息
+func (m *redactingChatModel) tokenize(ctx context.Context, input []*schema.Message) (*redact.Vault, []*schema.Message) {
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	vault := m.redactor.NewVault(tenantID)
+	if vault == nil {
+		return nil, input
+	}
+
+	messages := make([]*schema.Message, len(input))
+	for i, msg := range input {
+		copied := *msg
+		copied.Content = vault.Tokenize(msg.Content)
+		messages[i] = &copied
+	}
+	if vault.Len() == 0 {
+		return nil, input
+	}
+	return vault, messages
+}
+
+// redactingEmbedder 调用前替换个人信息的嵌入模型
+// 每条文本单独编号占位符，相同文本替换后仍然相同，不影响嵌入缓存命中
+type redactingEmbedder struct {
+	inner    embedding.Embedder
+	redactor *redact.Redactor
+}
+
+// EmbedStrings 按 ctx 中租户的策略替换文本中的个人信息后计算嵌入向量
+func (e *redactingEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	tokenized := make([]string, len(texts))
+	for i, text := range texts {
+		vault := e.redactor.NewVault(tenantID)
+		if vault == nil {
+			return e.inner.EmbedStrings(ctx, texts, opts...)
+		}
+		tokenized[i] = vault.Tokenize(text)
+	}
+	return e.inner.EmbedStrings(ctx, tokenized, opts...)
+}
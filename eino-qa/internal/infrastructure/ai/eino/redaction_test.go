@@ -0,0 +1,103 @@
+package eino
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"eino-qa/internal/infrastructure/redact"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkedChatModel 按分片流式返回固定内容的聊天模型
+type chunkedChatModel struct {
+	chunks []string
+	input  []*schema.Message
+}
+
+func (m *chunkedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.input = input
+	content := ""
+	for _, chunk := range m.chunks {
+		content += chunk
+	}
+	return schema.AssistantMessage(content, nil), nil
+}
+
+func (m *chunkedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	m.input = input
+	messages := make([]*schema.Message, len(m.chunks))
+	for i, chunk := range m.chunks {
+		messages[i] = schema.AssistantMessage(chunk, nil)
+	}
+	return schema.StreamReaderFromArray(messages), nil
+}
+
+func (m *chunkedChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+func TestRedactingChatModel(t *testing.T) {
+	inner := &chunkedChatModel{chunks: []string{"已为手机号 [PH", "ONE_1] 发送验证码，", "请勿泄露 [ID_CARD_1]"}}
+	client := NewClientWithModels(inner, nil, ClientConfig{}).WithRedactor(redact.New(func(tenantID string) redact.Policy {
+		return redact.Policy{Enabled: tenantID == "tenant1", TokenizeLLM: true}
+	}))
+	chatModel := client.GetChatModel()
+	messages := []*schema.Message{
+		schema.SystemMessage("你是客服助手"),
+		schema.UserMessage("我的手机号是13812345678，身份证号11010519491231002X"),
+	}
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+	// 模型只看到占位符，回答中的占位符还原为原值，原消息不被修改
+	resp, err := chatModel.Generate(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "我的手机号是[PHONE_1]，身份证号[ID_CARD_1]", inner.input[1].Content)
+	assert.Equal(t, "已为手机号 13812345678 发送验证码，请勿泄露 11010519491231002X", resp.Content)
+	assert.Equal(t, "我的手机号是13812345678，身份证号11010519491231002X", messages[1].Content)
+
+	// 流式回答中被拆开的占位符同样还原
+	stream, err := chatModel.Stream(ctx, messages)
+	require.NoError(t, err)
+	content := ""
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		content += chunk.Content
+	}
+	assert.Equal(t, "已为手机号 13812345678 发送验证码，请勿泄露 11010519491231002X", content)
+
+	// 未启用的租户原样调用
+	_, err = chatModel.Generate(context.WithValue(context.Background(), "tenant_id", "tenant2"), messages)
+	require.NoError(t, err)
+	assert.Equal(t, messages[1].Content, inner.input[1].Content)
+}
+
+func TestRedactingEmbedder(t *testing.T) {
+	inner := &recordingEmbedder{}
+	client := NewClientWithModels(nil, inner, ClientConfig{}).WithRedactor(redact.New(func(tenantID string) redact.Policy {
+		return redact.Policy{Enabled: tenantID == "tenant1", TokenizeLLM: true}
+	}))
+	embedder := client.GetEmbedModel()
+	texts := []string{"我的手机号是13812345678，订单退款了吗", "Go 课程多少钱"}
+
+	// 嵌入模型只看到占位符
+	vectors, err := embedder.EmbedStrings(context.WithValue(context.Background(), "tenant_id", "tenant1"), texts)
+	require.NoError(t, err)
+	assert.Len(t, vectors, 2)
+	assert.Equal(t, []string{"我的手机号是[PHONE_1]，订单退款了吗", "Go 课程多少钱"}, inner.batches[0])
+	assert.Equal(t, "我的手机号是13812345678，订单退款了吗", texts[0])
+
+	// 未启用的租户原样调用
+	_, err = embedder.EmbedStrings(context.WithValue(context.Background(), "tenant_id", "tenant2"), texts)
+	require.NoError(t, err)
+	assert.Equal(t, texts, inner.batches[1])
+}
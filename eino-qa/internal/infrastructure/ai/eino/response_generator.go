@@ -13,6 +13,8 @@ import (
 
 // ResponseGenerator 响应生成器
 type ResponseGenerator struct {
+	promptSource
+
 	chatModel model.ChatModel
 }
 
@@ -26,7 +28,7 @@ func NewResponseGenerator(client *Client) *ResponseGenerator {
 // Generate 生成直接回答
 func (g *ResponseGenerator) Generate(ctx context.Context, query string, history []*entity.Message) (string, error) {
 	// 构建提示词
-	systemPrompt := g.buildSystemPrompt()
+	systemPrompt := g.systemPrompt(ctx, entity.PromptResponse)
 	userPrompt := g.buildUserPrompt(query, history)
 
 	// 构建消息列表
@@ -65,7 +67,7 @@ func (g *ResponseGenerator) GenerateStream(ctx context.Context, query string, hi
 		defer close(errorChan)
 
 		// 构建提示词
-		systemPrompt := g.buildSystemPrompt()
+		systemPrompt := g.systemPrompt(ctx, entity.PromptResponse)
 		userPrompt := g.buildUserPrompt(query, history)
 
 		// 构建消息列表
@@ -92,7 +94,9 @@ func (g *ResponseGenerator) GenerateStream(ctx context.Context, query string, hi
 			return
 		}
 
-		// 读取流式响应
+		defer streamReader.Close()
+
+		// 读取流式响应（调用方取消时停止，例如回答审核中途截断）
 		for {
 			chunk, err := streamReader.Recv()
 			if err != nil {
@@ -103,7 +107,11 @@ func (g *ResponseGenerator) GenerateStream(ctx context.Context, query string, hi
 			}
 
 			if chunk.Content != "" {
-				resultChan <- chunk.Content
+				select {
+				case resultChan <- chunk.Content:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
@@ -111,24 +119,6 @@ func (g *ResponseGenerator) GenerateStream(ctx context.Context, query string, hi
 	return resultChan, errorChan
 }
 
-// buildSystemPrompt 构建系统提示词
-func (g *ResponseGenerator) buildSystemPrompt() string {
-	return `你是一个友好、专业的智能客服助手。你的任务是回答用户的问题，提供帮助和支持。
-
-回答要求：
-1. 语气友好、热情、专业
-2. 回答简洁明了，重点突出
-3. 对于简单的问候和闲聊，给予适当的回应
-4. 对于不确定的问题，诚实告知并建议联系人工客服
-5. 保持礼貌和耐心
-
-注意事项：
-- 不要编造信息
-- 不要回答与业务无关的问题
-- 如果问题超出能力范围，建议用户联系人工客服
-- 保护用户隐私，不要询问敏感信息`
-}
-
 // buildUserPrompt 构建用户提示词
 func (g *ResponseGenerator) buildUserPrompt(query string, history []*entity.Message) string {
 	// 对于直接回答，通常不需要额外的上下文构建
@@ -0,0 +1,97 @@
+package eino
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// maxSuggestionContextRunes 生成建议问题时每段参考内容的最大字符数，控制 LLM 输入长度
+const maxSuggestionContextRunes = 300
+
+// SuggestionGenerator 建议问题生成器
+// 根据本轮问答和相邻知识片段生成用户可能继续追问的问题
+type SuggestionGenerator struct {
+	chatModel model.ChatModel
+}
+
+// NewSuggestionGenerator 创建建议问题生成器
+func NewSuggestionGenerator(client *Client) *SuggestionGenerator {
+	return &SuggestionGenerator{
+		chatModel: client.GetChatModel(),
+	}
+}
+
+// Generate 生成最多 n 个建议问题
+// contexts 为可供追问的知识片段（如检索文档的相邻分块），每段截取开头部分以控制成本
+func (g *SuggestionGenerator) Generate(ctx context.Context, query, answer string, contexts []string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("用户问题：%s\n", query))
+	sb.WriteString(fmt.Sprintf("回答：%s\n\n", truncateText(answer, maxSuggestionContextRunes)))
+	if len(contexts) > 0 {
+		sb.WriteString("相关知识片段：\n")
+		for i, content := range contexts {
+			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, truncateText(content, maxSuggestionContextRunes)))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(fmt.Sprintf("请生成 %d 个建议问题。", n))
+
+	messages := []*schema.Message{
+		schema.SystemMessage(suggestionSystemPrompt),
+		schema.UserMessage(sb.String()),
+	}
+
+	resp, err := g.chatModel.Generate(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate suggestions: %w", err)
+	}
+
+	content := strings.TrimSpace(resp.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var result struct {
+		Questions []string `json:"questions"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse suggestions: %w", err)
+	}
+
+	questions := make([]string, 0, n)
+	for _, q := range result.Questions {
+		if q = strings.TrimSpace(q); q != "" && len(questions) < n {
+			questions = append(questions, q)
+		}
+	}
+	return questions, nil
+}
+
+// suggestionSystemPrompt 建议问题生成的系统提示词
+const suggestionSystemPrompt = `你是一个课程咨询助手。请根据用户的问题、回答和相关知识片段，生成用户接下来可能会问的问题。
+
+要求：
+1. 问题必须能由相关知识片段或回答中的内容回答，不要生成知识库以外的问题
+2. 不要重复用户已经问过的问题
+3. 每个问题不超过 20 个字，以用户的口吻提问
+4. 只输出 JSON，格式如下：
+{"questions": ["问题1", "问题2"]}`
+
+// truncateText 按字符截取文本
+func truncateText(text string, limit int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return string(runes[:limit]) + "…"
+}
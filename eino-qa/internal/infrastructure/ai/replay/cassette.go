@@ -0,0 +1,321 @@
+// Package replay 提供可录制和回放的聊天模型与嵌入模型，用于不依赖网络的可重复测试
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrUnrecordedCall 回放时请求没有对应的录制结果
+var ErrUnrecordedCall = errors.New("unrecorded model call")
+
+// ModeEnv 选择录制模式的环境变量，值为 record 时重新录制
+const ModeEnv = "EINO_REPLAY_MODE"
+
+// ignoredPlaceholder 忽略的内容在计算键时替换成的占位符
+const ignoredPlaceholder = "<ignored>"
+
+// Mode 录制回放模式
+type Mode string
+
+const (
+	// ModeReplay 从录制文件回放，未录制的调用按 Strict 处理
+	ModeReplay Mode = "replay"
+	// ModeRecord 调用真实模型并重新录制（丢弃旧的录制结果）
+	ModeRecord Mode = "record"
+)
+
+// ModeFromEnv 根据 EINO_REPLAY_MODE 环境变量选择模式，默认回放
+func ModeFromEnv() Mode {
+	if Mode(os.Getenv(ModeEnv)) == ModeRecord {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Options 录制回放选项
+type Options struct {
+	Mode Mode
+	// Strict 回放时遇到未录制的调用直接返回 ErrUnrecordedCall；
+	// 否则调用真实模型（如有）并追加到录制文件
+	Strict bool
+	// IgnorePatterns 计算键之前替换为占位符的内容，如提示词中的当前日期
+	IgnorePatterns []*regexp.Regexp
+}
+
+// Cassette 录制文件，保存聊天和嵌入请求到响应的映射
+// 聊天请求按规范化后的消息计算键，嵌入请求按单条文本计算键，与批次划分无关
+type Cassette struct {
+	path string
+	opts Options
+
+	mu         sync.Mutex
+	chats      map[string]*chatFixture
+	embeddings map[string]*embeddingFixture
+	dirty      bool
+}
+
+// cassetteFile 录制文件格式，条目按键排序，便于审阅和比对
+type cassetteFile struct {
+	Chats      []*chatFixture      `json:"chats"`
+	Embeddings []*embeddingFixture `json:"embeddings"`
+}
+
+// chatFixture 一次聊天请求的录制结果
+type chatFixture struct {
+	Key      string            `json:"key"`
+	Request  []*fixtureMessage `json:"request"`
+	Response *fixtureMessage   `json:"response"`
+}
+
+// embeddingFixture 一条文本的嵌入向量
+type embeddingFixture struct {
+	Key    string    `json:"key"`
+	Text   string    `json:"text"`
+	Vector []float64 `json:"vector"`
+}
+
+// fixtureMessage 录制的消息
+type fixtureMessage struct {
+	Role       string             `json:"role"`
+	Content    string             `json:"content"`
+	Name       string             `json:"name,omitempty"`
+	ToolCallID string             `json:"tool_call_id,omitempty"`
+	ToolCalls  []*fixtureToolCall `json:"tool_calls,omitempty"`
+}
+
+// fixtureToolCall 录制的工具调用
+type fixtureToolCall struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Open 打开录制文件，文件不存在时从空白开始；录制模式总是从空白开始
+func Open(path string, opts Options) (*Cassette, error) {
+	if opts.Mode == "" {
+		opts.Mode = ModeReplay
+	}
+
+	c := &Cassette{
+		path:       path,
+		opts:       opts,
+		chats:      make(map[string]*chatFixture),
+		embeddings: make(map[string]*embeddingFixture),
+	}
+	if opts.Mode == ModeRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+	for _, f := range file.Chats {
+		c.chats[f.Key] = f
+	}
+	for _, f := range file.Embeddings {
+		c.embeddings[f.Key] = f
+	}
+	return c, nil
+}
+
+// Mode 返回录制回放模式
+func (c *Cassette) Mode() Mode {
+	return c.opts.Mode
+}
+
+// Save 将新录制的结果写回文件，没有新录制时不写
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	file := cassetteFile{
+		Chats:      make([]*chatFixture, 0, len(c.chats)),
+		Embeddings: make([]*embeddingFixture, 0, len(c.embeddings)),
+	}
+	for _, f := range c.chats {
+		file.Chats = append(file.Chats, f)
+	}
+	for _, f := range c.embeddings {
+		file.Embeddings = append(file.Embeddings, f)
+	}
+	sort.Slice(file.Chats, func(i, j int) bool { return file.Chats[i].Key < file.Chats[j].Key })
+	sort.Slice(file.Embeddings, func(i, j int) bool { return file.Embeddings[i].Key < file.Embeddings[j].Key })
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	// 先写临时文件再重命名，避免中断时留下不完整的录制文件
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// lookupChat 查找聊天请求的录制结果
+func (c *Cassette) lookupChat(key string) (*chatFixture, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.opts.Mode == ModeRecord {
+		return nil, false
+	}
+	f, ok := c.chats[key]
+	return f, ok
+}
+
+// recordChat 保存聊天请求的录制结果
+func (c *Cassette) recordChat(key string, request []*fixtureMessage, response *schema.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chats[key] = &chatFixture{Key: key, Request: request, Response: toFixtureMessage(response)}
+	c.dirty = true
+}
+
+// lookupEmbedding 查找文本的录制向量
+func (c *Cassette) lookupEmbedding(key string) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.opts.Mode == ModeRecord {
+		return nil, false
+	}
+	f, ok := c.embeddings[key]
+	if !ok {
+		return nil, false
+	}
+	return f.Vector, true
+}
+
+// recordEmbedding 保存文本的向量
+func (c *Cassette) recordEmbedding(key, text string, vector []float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.embeddings[key] = &embeddingFixture{Key: key, Text: text, Vector: vector}
+	c.dirty = true
+}
+
+// canFallThrough 未录制的调用是否可以交给真实模型
+func (c *Cassette) canFallThrough(hasInner bool) bool {
+	return hasInner && (c.opts.Mode == ModeRecord || !c.opts.Strict)
+}
+
+// normalize 规范化文本：统一换行、去掉行尾空白和首尾空白，并替换忽略的内容
+func (c *Cassette) normalize(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, pattern := range c.opts.IgnorePatterns {
+		text = pattern.ReplaceAllString(text, ignoredPlaceholder)
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// normalizeMessages 规范化聊天请求的消息
+func (c *Cassette) normalizeMessages(input []*schema.Message) []*fixtureMessage {
+	messages := make([]*fixtureMessage, 0, len(input))
+	for _, msg := range input {
+		if msg == nil {
+			continue
+		}
+		m := toFixtureMessage(msg)
+		m.Content = c.normalize(m.Content)
+		for _, call := range m.ToolCalls {
+			call.ID = "" // 工具调用 ID 由模型随机生成，不参与匹配
+		}
+		m.ToolCallID = ""
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+// hashKey 计算请求的键
+func hashKey(kind string, v any) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(append([]byte(kind+":"), data...))
+	return kind + "_" + hex.EncodeToString(sum[:8])
+}
+
+// toFixtureMessage 转换为录制的消息
+func toFixtureMessage(msg *schema.Message) *fixtureMessage {
+	m := &fixtureMessage{
+		Role:       string(msg.Role),
+		Content:    msg.Content,
+		Name:       msg.Name,
+		ToolCallID: msg.ToolCallID,
+	}
+	for _, call := range msg.ToolCalls {
+		m.ToolCalls = append(m.ToolCalls, &fixtureToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return m
+}
+
+// toMessage 还原为 schema.Message
+func (m *fixtureMessage) toMessage() *schema.Message {
+	msg := &schema.Message{
+		Role:       schema.RoleType(m.Role),
+		Content:    m.Content,
+		Name:       m.Name,
+		ToolCallID: m.ToolCallID,
+	}
+	for _, call := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:       call.ID,
+			Function: schema.FunctionCall{Name: call.Name, Arguments: call.Arguments},
+		})
+	}
+	return msg
+}
+
+// preview 截取文本开头，用于错误信息
+func preview(text string) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) > 60 {
+		return string(runes[:60]) + "..."
+	}
+	return string(runes)
+}
@@ -0,0 +1,103 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ChatModel 可录制和回放的聊天模型
+// Generate 和 Stream 共用同一份录制结果：流式调用录制的是拼接后的完整消息，回放时作为单个分块返回
+type ChatModel struct {
+	inner    model.ChatModel
+	cassette *Cassette
+}
+
+// ChatModel 创建包装 inner 的聊天模型，纯回放时 inner 可以为 nil
+func (c *Cassette) ChatModel(inner model.ChatModel) *ChatModel {
+	return &ChatModel{inner: inner, cassette: c}
+}
+
+// Generate 返回录制的回答，未录制时按模式调用真实模型或返回 ErrUnrecordedCall
+func (m *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	request, key := m.requestKey(input)
+	if f, ok := m.cassette.lookupChat(key); ok {
+		return f.Response.toMessage(), nil
+	}
+	if !m.cassette.canFallThrough(m.inner != nil) {
+		return nil, m.unrecorded(key, input)
+	}
+
+	resp, err := m.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	m.cassette.recordChat(key, request, resp)
+	return resp, nil
+}
+
+// Stream 返回录制的回答（单个分块），未录制时按模式调用真实模型或返回 ErrUnrecordedCall
+func (m *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	request, key := m.requestKey(input)
+	if f, ok := m.cassette.lookupChat(key); ok {
+		return schema.StreamReaderFromArray([]*schema.Message{f.Response.toMessage()}), nil
+	}
+	if !m.cassette.canFallThrough(m.inner != nil) {
+		return nil, m.unrecorded(key, input)
+	}
+
+	stream, err := m.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	// 读完整个流后录制，再把原始分块返回给调用方
+	var chunks []*schema.Message
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if len(chunks) > 0 {
+		full, err := schema.ConcatMessages(chunks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to concat stream chunks: %w", err)
+		}
+		m.cassette.recordChat(key, request, full)
+	}
+	return schema.StreamReaderFromArray(chunks), nil
+}
+
+// BindTools 绑定工具到真实模型，纯回放时忽略
+func (m *ChatModel) BindTools(tools []*schema.ToolInfo) error {
+	if m.inner == nil {
+		return nil
+	}
+	return m.inner.BindTools(tools)
+}
+
+// requestKey 规范化请求消息并计算键
+func (m *ChatModel) requestKey(input []*schema.Message) ([]*fixtureMessage, string) {
+	request := m.cassette.normalizeMessages(input)
+	return request, hashKey("chat", request)
+}
+
+// unrecorded 返回未录制错误，附带最后一条消息的开头便于定位
+func (m *ChatModel) unrecorded(key string, input []*schema.Message) error {
+	last := ""
+	if len(input) > 0 && input[len(input)-1] != nil {
+		last = input[len(input)-1].Content
+	}
+	return fmt.Errorf("%w: chat %s (%q), set %s=record to record it", ErrUnrecordedCall, key, preview(last), ModeEnv)
+}
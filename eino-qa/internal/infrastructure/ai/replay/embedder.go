@@ -0,0 +1,61 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// Embedder 可录制和回放的嵌入模型，按单条文本录制向量
+type Embedder struct {
+	inner    embedding.Embedder
+	cassette *Cassette
+}
+
+// Embedder 创建包装 inner 的嵌入模型，纯回放时 inner 可以为 nil
+func (c *Cassette) Embedder(inner embedding.Embedder) *Embedder {
+	return &Embedder{inner: inner, cassette: c}
+}
+
+// EmbedStrings 返回录制的向量，未录制的文本按模式批量调用真实模型或返回 ErrUnrecordedCall
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	keys := make([]string, len(texts))
+
+	var missing []int
+	for i, text := range texts {
+		keys[i] = hashKey("embed", e.cassette.normalize(text))
+		if vector, ok := e.cassette.lookupEmbedding(keys[i]); ok {
+			vectors[i] = vector
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	if !e.cassette.canFallThrough(e.inner != nil) {
+		first := missing[0]
+		return nil, fmt.Errorf("%w: embedding %s (%q), set %s=record to record it", ErrUnrecordedCall, keys[first], preview(texts[first]), ModeEnv)
+	}
+
+	batch := make([]string, len(missing))
+	for i, idx := range missing {
+		batch[i] = texts[idx]
+	}
+	embedded, err := e.inner.EmbedStrings(ctx, batch, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(batch) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(embedded), len(batch))
+	}
+
+	for i, idx := range missing {
+		vectors[idx] = embedded[i]
+		e.cassette.recordEmbedding(keys[idx], e.cassette.normalize(texts[idx]), embedded[i])
+	}
+	return vectors, nil
+}
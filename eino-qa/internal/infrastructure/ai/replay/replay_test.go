@@ -0,0 +1,169 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingChatModel 回显最后一条消息并记录调用次数
+type countingChatModel struct {
+	calls int
+}
+
+func (m *countingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.calls++
+	return schema.AssistantMessage("echo: "+input[len(input)-1].Content, nil), nil
+}
+
+func (m *countingChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	m.calls++
+	return schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage("流式", nil),
+		schema.AssistantMessage("回答", nil),
+	}), nil
+}
+
+func (m *countingChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+// countingEmbedder 按文本长度生成向量并记录每次调用的文本数
+type countingEmbedder struct {
+	batches []int
+}
+
+func (e *countingEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	e.batches = append(e.batches, len(texts))
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len([]rune(text))), 1}
+	}
+	return vectors, nil
+}
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures", "chat.json")
+	ctx := context.Background()
+	datePattern := regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+	// 录制
+	recorder, err := Open(path, Options{Mode: ModeRecord, IgnorePatterns: []*regexp.Regexp{datePattern}})
+	require.NoError(t, err)
+	inner, innerEmbed := &countingChatModel{}, &countingEmbedder{}
+	chatModel, embedder := recorder.ChatModel(inner), recorder.Embedder(innerEmbed)
+
+	resp, err := chatModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage("今天是 2024-11-29"),
+		schema.UserMessage("Go 课程多少钱"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "echo: Go 课程多少钱", resp.Content)
+
+	stream, err := chatModel.Stream(ctx, []*schema.Message{schema.UserMessage("你好")})
+	require.NoError(t, err)
+	assert.Equal(t, "流式回答", readAll(t, stream))
+
+	_, err = embedder.EmbedStrings(ctx, []string{"Go", "Python"})
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+
+	// 回放：消息按规范化后的内容匹配，忽略的日期不影响匹配
+	player, err := Open(path, Options{Mode: ModeReplay, Strict: true, IgnorePatterns: []*regexp.Regexp{datePattern}})
+	require.NoError(t, err)
+	chatModel, embedder = player.ChatModel(nil), player.Embedder(nil)
+
+	resp, err = chatModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage("今天是 2025-01-01  \r\n"),
+		schema.UserMessage("  Go 课程多少钱\n"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "echo: Go 课程多少钱", resp.Content)
+
+	// 流式录制的结果也可以用非流式调用回放，反之亦然
+	resp, err = chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("你好")})
+	require.NoError(t, err)
+	assert.Equal(t, "流式回答", resp.Content)
+
+	stream, err = chatModel.Stream(ctx, []*schema.Message{
+		schema.SystemMessage("今天是 2024-11-29"),
+		schema.UserMessage("Go 课程多少钱"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "echo: Go 课程多少钱", readAll(t, stream))
+
+	// 嵌入按单条文本匹配，与批次划分和顺序无关
+	vectors, err := embedder.EmbedStrings(ctx, []string{"Python", "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{6, 1}, {2, 1}}, vectors)
+
+	// 严格模式下未录制的调用失败
+	_, err = chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("Python 课程多少钱")})
+	assert.True(t, errors.Is(err, ErrUnrecordedCall))
+	assert.Contains(t, err.Error(), "Python 课程多少钱")
+	_, err = embedder.EmbedStrings(ctx, []string{"Go", "Java"})
+	assert.ErrorIs(t, err, ErrUnrecordedCall)
+
+	// 没有新录制时不写文件
+	require.NoError(t, player.Save())
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestCassette_NonStrictReplayRecordsMisses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chat.json")
+	ctx := context.Background()
+
+	cassette, err := Open(path, Options{Mode: ModeReplay})
+	require.NoError(t, err)
+	inner, innerEmbed := &countingChatModel{}, &countingEmbedder{}
+	chatModel, embedder := cassette.ChatModel(inner), cassette.Embedder(innerEmbed)
+
+	for i := 0; i < 2; i++ {
+		resp, err := chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("你好")})
+		require.NoError(t, err)
+		assert.Equal(t, "echo: 你好", resp.Content)
+	}
+	assert.Equal(t, 1, inner.calls)
+
+	// 只为未录制的文本调用真实模型
+	_, err = embedder.EmbedStrings(ctx, []string{"Go"})
+	require.NoError(t, err)
+	vectors, err := embedder.EmbedStrings(ctx, []string{"Go", "Python", "Java"})
+	require.NoError(t, err)
+	assert.Len(t, vectors, 3)
+	assert.Equal(t, []int{1, 2}, innerEmbed.batches)
+
+	require.NoError(t, cassette.Save())
+	reopened, err := Open(path, Options{Mode: ModeReplay, Strict: true})
+	require.NoError(t, err)
+	_, err = reopened.ChatModel(nil).Generate(ctx, []*schema.Message{schema.UserMessage("你好")})
+	assert.NoError(t, err)
+
+	// 没有真实模型时未录制的调用同样失败
+	_, err = reopened.ChatModel(nil).Generate(ctx, []*schema.Message{schema.UserMessage("再见")})
+	assert.ErrorIs(t, err, ErrUnrecordedCall)
+}
+
+func readAll(t *testing.T, stream *schema.StreamReader[*schema.Message]) string {
+	t.Helper()
+	defer stream.Close()
+
+	content := ""
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return content
+		}
+		require.NoError(t, err)
+		content += chunk.Content
+	}
+}
@@ -0,0 +1,153 @@
+// Package cache 提供进程内的语义回答缓存
+package cache
+
+import (
+	"sync"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/pkg/utils"
+)
+
+// defaultMaxEntries 每个租户默认最多缓存的回答数
+const defaultMaxEntries = 1000
+
+// AnswerCache 进程内的语义回答缓存
+// 按租户隔离，每个租户维护知识库版本；知识库变更时版本递增并清空该租户的缓存，
+// 旧版本下生成的回答即使晚于变更写入也会被丢弃
+type AnswerCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	tenants    map[string]*tenantAnswers
+}
+
+// tenantAnswers 单个租户的缓存回答
+type tenantAnswers struct {
+	version uint64
+	entries []*entity.CachedAnswer // 按写入顺序排列，超出上限时淘汰命中次数最少的回答
+}
+
+// NewAnswerCache 创建语义回答缓存，maxEntries 为每个租户最多缓存的回答数
+func NewAnswerCache(maxEntries int) *AnswerCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	return &AnswerCache{
+		maxEntries: maxEntries,
+		tenants:    make(map[string]*tenantAnswers),
+	}
+}
+
+// Version 获取租户当前的知识库版本
+func (c *AnswerCache) Version(tenantID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tenant(tenantID).version
+}
+
+// Lookup 查找与问题向量最相似且不低于阈值的缓存回答，只匹配指定路由的回答
+// 返回回答的副本和相似度，未命中时返回 nil
+func (c *AnswerCache) Lookup(tenantID string, vector []float32, routes []entity.IntentType, threshold float64) (*entity.CachedAnswer, float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.tenant(tenantID)
+	var best *entity.CachedAnswer
+	bestScore := threshold
+	live := t.entries[:0]
+	for _, entry := range t.entries {
+		if entry.IsExpired() {
+			continue
+		}
+		live = append(live, entry)
+		if !containsRoute(routes, entry.Route) {
+			continue
+		}
+		if score := utils.CosineSimilarity(vector, entry.Vector); score >= bestScore {
+			best, bestScore = entry, score
+		}
+	}
+	// 顺便清理过期的回答
+	clear(t.entries[len(live):])
+	t.entries = live
+
+	if best == nil {
+		return nil, 0
+	}
+	best.Hits++
+	copied := *best
+	return &copied, bestScore
+}
+
+// Store 缓存回答，回答的知识库版本不是当前版本时丢弃并返回 false
+// 相同路由下规范化后问题相同的回答会被替换
+func (c *AnswerCache) Store(tenantID string, answer *entity.CachedAnswer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.tenant(tenantID)
+	if answer.KBVersion != t.version {
+		return false
+	}
+
+	for i, entry := range t.entries {
+		if entry.Route == answer.Route && entry.Query == answer.Query {
+			t.entries = append(t.entries[:i], t.entries[i+1:]...)
+			break
+		}
+	}
+	if len(t.entries) >= c.maxEntries {
+		t.evict()
+	}
+	t.entries = append(t.entries, answer)
+	return true
+}
+
+// InvalidateTenant 租户知识库变更时递增版本并清空缓存
+func (c *AnswerCache) InvalidateTenant(tenantID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.tenant(tenantID)
+	t.version++
+	t.entries = nil
+}
+
+// Len 获取租户当前缓存的回答数
+func (c *AnswerCache) Len(tenantID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.tenant(tenantID).entries)
+}
+
+// tenant 获取租户的缓存，不存在时创建（调用方需持有锁）
+func (c *AnswerCache) tenant(tenantID string) *tenantAnswers {
+	t, ok := c.tenants[tenantID]
+	if !ok {
+		t = &tenantAnswers{}
+		c.tenants[tenantID] = t
+	}
+	return t
+}
+
+// evict 淘汰命中次数最少的回答，次数相同时淘汰最早写入的
+func (t *tenantAnswers) evict() {
+	victim := 0
+	for i, entry := range t.entries {
+		if entry.Hits < t.entries[victim].Hits {
+			victim = i
+		}
+	}
+	t.entries = append(t.entries[:victim], t.entries[victim+1:]...)
+}
+
+// containsRoute 判断路由是否在允许的列表中
+func containsRoute(routes []entity.IntentType, route entity.IntentType) bool {
+	for _, r := range routes {
+		if r == route {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var courseOnly = []entity.IntentType{entity.IntentCourse}
+
+func TestAnswerCache_Lookup(t *testing.T) {
+	c := NewAnswerCache(10)
+
+	require.True(t, c.Store("tenant1", entity.NewCachedAnswer("Go 课程多少钱？", []float32{1, 0, 0}, entity.IntentCourse, "1999 元 [1]", 0, time.Hour)))
+	require.True(t, c.Store("tenant1", entity.NewCachedAnswer("你好", []float32{0, 1, 0}, entity.IntentDirect, "你好！", 0, time.Hour)))
+
+	// 相似度不低于阈值时命中最相似的回答
+	hit, score := c.Lookup("tenant1", []float32{0.99, 0.05, 0}, courseOnly, 0.95)
+	require.NotNil(t, hit)
+	assert.Equal(t, "go 课程多少钱", hit.Query)
+	assert.Equal(t, "1999 元 [1]", hit.Answer)
+	assert.Greater(t, score, 0.95)
+
+	// 低于阈值不命中
+	hit, _ = c.Lookup("tenant1", []float32{0.7, 0.7, 0}, courseOnly, 0.95)
+	assert.Nil(t, hit)
+
+	// 只匹配指定路由的回答
+	hit, _ = c.Lookup("tenant1", []float32{0, 1, 0}, courseOnly, 0.95)
+	assert.Nil(t, hit)
+	hit, _ = c.Lookup("tenant1", []float32{0, 1, 0}, []entity.IntentType{entity.IntentCourse, entity.IntentDirect}, 0.95)
+	require.NotNil(t, hit)
+	assert.Equal(t, entity.IntentDirect, hit.Route)
+
+	// 租户之间相互隔离
+	hit, _ = c.Lookup("tenant2", []float32{1, 0, 0}, courseOnly, 0.95)
+	assert.Nil(t, hit)
+}
+
+func TestAnswerCache_InvalidateTenant(t *testing.T) {
+	c := NewAnswerCache(10)
+
+	version := c.Version("tenant1")
+	require.True(t, c.Store("tenant1", entity.NewCachedAnswer("Go 课程多少钱", []float32{1, 0}, entity.IntentCourse, "1999 元", version, time.Hour)))
+	require.True(t, c.Store("tenant2", entity.NewCachedAnswer("Go 课程多少钱", []float32{1, 0}, entity.IntentCourse, "2999 元", c.Version("tenant2"), time.Hour)))
+
+	// 查询时读取的版本在生成回答期间知识库变更
+	stale := c.Version("tenant1")
+	c.InvalidateTenant("tenant1")
+
+	assert.Equal(t, 0, c.Len("tenant1"))
+	assert.Equal(t, 1, c.Len("tenant2"))
+	hit, _ := c.Lookup("tenant1", []float32{1, 0}, courseOnly, 0.9)
+	assert.Nil(t, hit)
+
+	// 旧版本下生成的回答不再写入
+	assert.False(t, c.Store("tenant1", entity.NewCachedAnswer("Python 课程多少钱", []float32{0, 1}, entity.IntentCourse, "999 元", stale, time.Hour)))
+	assert.True(t, c.Store("tenant1", entity.NewCachedAnswer("Python 课程多少钱", []float32{0, 1}, entity.IntentCourse, "999 元", c.Version("tenant1"), time.Hour)))
+}
+
+func TestAnswerCache_ExpiryAndEviction(t *testing.T) {
+	c := NewAnswerCache(2)
+
+	expired := entity.NewCachedAnswer("旧问题", []float32{1, 0}, entity.IntentCourse, "旧回答", 0, time.Hour)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	require.True(t, c.Store("tenant1", expired))
+
+	hit, _ := c.Lookup("tenant1", []float32{1, 0}, courseOnly, 0.9)
+	assert.Nil(t, hit)
+	assert.Equal(t, 0, c.Len("tenant1"))
+
+	// 相同问题替换旧回答
+	c.Store("tenant1", entity.NewCachedAnswer("问题 A", []float32{1, 0}, entity.IntentCourse, "回答 A1", 0, 0))
+	c.Store("tenant1", entity.NewCachedAnswer("问题 a？", []float32{1, 0}, entity.IntentCourse, "回答 A2", 0, 0))
+	assert.Equal(t, 1, c.Len("tenant1"))
+
+	// 超出上限时淘汰命中次数最少的回答
+	c.Store("tenant1", entity.NewCachedAnswer("问题 B", []float32{0, 1}, entity.IntentCourse, "回答 B", 0, 0))
+	hit, _ = c.Lookup("tenant1", []float32{1, 0}, courseOnly, 0.9)
+	require.NotNil(t, hit)
+	assert.Equal(t, "回答 A2", hit.Answer)
+
+	c.Store("tenant1", entity.NewCachedAnswer("问题 C", []float32{1, 1}, entity.IntentCourse, "回答 C", 0, 0))
+	assert.Equal(t, 2, c.Len("tenant1"))
+	hit, _ = c.Lookup("tenant1", []float32{0, 1}, courseOnly, 0.99)
+	assert.Nil(t, hit, "回答 B 应被淘汰")
+	hit, _ = c.Lookup("tenant1", []float32{1, 0}, courseOnly, 0.99)
+	assert.NotNil(t, hit)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"time"
 
@@ -19,12 +20,42 @@ type Config struct {
 	Session   SessionConfig   `yaml:"session"`
 	Security  SecurityConfig  `yaml:"security"`
 	Logging   LoggingConfig   `yaml:"logging"`
+	Webhook   WebhookConfig   `yaml:"webhook"`
+	Identity  IdentityConfig  `yaml:"identity"`
+
+	// Suggestions 回答后的建议问题，可按租户覆盖
+	Suggestions SuggestionsConfig `yaml:"suggestions"`
+
+	// AnswerCache 语义回答缓存，可按租户覆盖
+	AnswerCache AnswerCacheConfig `yaml:"answer_cache"`
+
+	// FAQ 标准问答，可按租户覆盖
+	FAQ FAQConfig `yaml:"faq"`
+
+	// Redaction 个人信息脱敏，可按租户覆盖
+	Redaction RedactionConfig `yaml:"redaction"`
+
+	// InjectionGuard 提示词注入检测，可按租户覆盖
+	InjectionGuard InjectionGuardConfig `yaml:"injection_guard"`
+
+	// Moderation 回答审核，可按租户覆盖
+	Moderation ModerationConfig `yaml:"moderation"`
+
+	// RateLimit 接口限流和模型调用并发上限，可按租户覆盖
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+
+	// Tenants 租户级配置覆盖，键为租户 ID
+	Tenants map[string]TenantConfig `yaml:"tenants"`
 }
 
 // ServerConfig HTTP 服务器配置
 type ServerConfig struct {
 	Port int    `yaml:"port"`
 	Mode string `yaml:"mode"` // debug, release
+
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，按 IP 限流时只有来自这些地址的请求才按 X-Forwarded-For
+	// 识别客户端 IP；未配置时客户端 IP 取连接的对端地址
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 // DashScopeConfig DashScope API 配置
@@ -35,6 +66,20 @@ type DashScopeConfig struct {
 	EmbeddingDimension int           `yaml:"embedding_dimension"`
 	MaxRetries         int           `yaml:"max_retries"`
 	Timeout            time.Duration `yaml:"timeout"`
+
+	// EmbeddingCache 嵌入向量缓存与批处理
+	EmbeddingCache EmbeddingCacheConfig `yaml:"embedding_cache"`
+}
+
+// EmbeddingCacheConfig 嵌入向量缓存与批处理配置
+// 向量按模型名和文本哈希缓存，并发的单条请求在 batch_window 内合并，大批量文本按 max_batch_size 拆分
+type EmbeddingCacheConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Size           int           `yaml:"size"`            // 内存 LRU 缓存的条目数，默认 10000
+	Dir            string        `yaml:"dir"`             // 磁盘缓存目录，为空时只使用内存缓存
+	BatchWindow    time.Duration `yaml:"batch_window"`    // 合并并发单条请求的等待窗口，0 表示不合并
+	MaxBatchSize   int           `yaml:"max_batch_size"`  // 单次调用的最大文本数，默认 25
+	MaxConcurrency int           `yaml:"max_concurrency"` // 最大并发调用数，默认 4
 }
 
 // MilvusConfig Milvus 向量数据库配置
@@ -55,6 +100,113 @@ type DatabaseConfig struct {
 type RAGConfig struct {
 	TopK           int     `yaml:"top_k"`
 	ScoreThreshold float64 `yaml:"score_threshold"`
+	// UncitedSources 回答未引用的来源文档的处理方式：flag（保留并标记，默认）、drop（移除）
+	UncitedSources string `yaml:"uncited_sources"`
+	// Groundedness 回答依据校验，可按租户覆盖
+	Groundedness GroundednessConfig `yaml:"groundedness"`
+}
+
+// GroundednessConfig 回答依据校验配置
+type GroundednessConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Method 校验方式：lexical（词汇重合，默认）、llm（LLM 逐句判断）
+	Method string `yaml:"method"`
+	// Threshold 最低依据分数（有依据句子的比例），默认 0.8
+	Threshold float64 `yaml:"threshold"`
+	// Action 依据不足时的处理：flag（元数据标记，默认）、regenerate（严格提示词重新生成）、handoff（回复无法确认并转人工）
+	Action string `yaml:"action"`
+}
+
+// SuggestionsConfig 建议问题配置
+type SuggestionsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxLLMCallsPerHour 每个租户每小时最多调用 LLM 生成建议的次数，0 表示只使用相邻分块和热门问题
+	MaxLLMCallsPerHour int `yaml:"max_llm_calls_per_hour"`
+}
+
+// AnswerCacheConfig 语义回答缓存配置
+type AnswerCacheConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Threshold 命中缓存的最低问题相似度，默认 0.95
+	Threshold float64 `yaml:"threshold"`
+	// TTL 缓存回答有效期，默认 24h
+	TTL time.Duration `yaml:"ttl"`
+	// Routes 可缓存的路由：course（默认）、direct（只缓存会话第一轮）
+	Routes []string `yaml:"routes"`
+	// MaxEntries 每个租户最多缓存的回答数，默认 1000（只在全局配置中生效）
+	MaxEntries int `yaml:"max_entries"`
+}
+
+// FAQConfig 标准问答配置，问答内容通过 /api/v1/faqs 管理
+type FAQConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Threshold 向量匹配的最低问题相似度，默认 0.92
+	Threshold float64 `yaml:"threshold"`
+}
+
+// RedactionConfig 个人信息脱敏配置
+type RedactionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Types 处理的信息类型：phone、id_card、bank_card、email，为空表示全部
+	Types []string `yaml:"types"`
+	// TokenizeLLM 调用模型前将个人信息替换为占位符（如 [PHONE_1]），并在回答中还原
+	TokenizeLLM bool `yaml:"tokenize_llm"`
+	// MaskLogs 日志消息和字段中的个人信息掩码显示（如 138****5678）
+	MaskLogs bool `yaml:"mask_logs"`
+	// MaskSessions 保存会话时掩码消息内容，后续轮次的对话历史使用掩码后的内容
+	MaskSessions bool `yaml:"mask_sessions"`
+}
+
+// InjectionGuardConfig 提示词注入检测配置，检测用户查询和写入知识库的文档
+type InjectionGuardConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Method 检测方式：heuristic（启发式规则）、llm（启发式规则加 LLM 分类，失败时降级为启发式）
+	Method string `yaml:"method"`
+	// Threshold 最低可疑分数（0~1），默认 0.5
+	Threshold float64 `yaml:"threshold"`
+	// Action 查询可疑时的处理方式：block、sanitize、flag（默认）、handoff
+	Action string `yaml:"action"`
+	// DocumentAction 文档可疑时的处理方式：quarantine（默认）、block、sanitize、flag
+	DocumentAction string `yaml:"document_action"`
+}
+
+// ModerationConfig 回答审核配置，回答发送给用户之前检查是否偏离业务范围
+type ModerationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Method 审核方式：rules（只按规则）、llm（规则未命中时再由审核模型判断，失败时降级为规则）
+	Method string `yaml:"method"`
+	// BannedTopics 禁止涉及的话题关键词，忽略大小写
+	BannedTopics []string `yaml:"banned_topics"`
+	// Competitors 不允许提及的竞品名称，忽略大小写
+	Competitors []string `yaml:"competitors"`
+	// Denylist 正则黑名单
+	Denylist []string `yaml:"denylist"`
+	// FallbackMessage 未通过审核时的回复，为空时使用默认回复
+	FallbackMessage string `yaml:"fallback_message"`
+}
+
+// RateLimitConfig 限流配置，按租户、API Key、终端用户和客户端 IP 分别使用令牌桶
+type RateLimitConfig struct {
+	Enabled bool            `yaml:"enabled"`
+	Tenant  RateLimitBucket `yaml:"tenant"`  // 每个租户
+	APIKey  RateLimitBucket `yaml:"api_key"` // 每个 API Key（管理接口）
+	User    RateLimitBucket `yaml:"user"`    // 每个已识别身份的终端用户
+	IP      RateLimitBucket `yaml:"ip"`      // 每个客户端 IP
+	// MaxConcurrentLLMCalls 租户同时进行的模型调用上限，0 表示不限制
+	MaxConcurrentLLMCalls int `yaml:"max_concurrent_llm_calls"`
+	// LLMQueueTimeout 模型调用名额已满时的最长排队时间，超时返回 429，0 表示一直等到请求结束
+	LLMQueueTimeout time.Duration `yaml:"llm_queue_timeout"`
+}
+
+// RateLimitBucket 令牌桶限额
+type RateLimitBucket struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"` // 每分钟补充的令牌数，0 表示不限制
+	Burst             int `yaml:"burst"`               // 桶容量，0 表示等于 requests_per_minute
+}
+
+// IsZero 是否未配置回答依据校验
+func (gc GroundednessConfig) IsZero() bool {
+	return gc == GroundednessConfig{}
 }
 
 // IntentConfig 意图识别配置
@@ -82,6 +234,189 @@ type LoggingConfig struct {
 	FilePath string `yaml:"file_path"`
 }
 
+// WebhookConfig Webhook 投递配置
+type WebhookConfig struct {
+	Timeout      time.Duration `yaml:"timeout"`       // 单次投递超时
+	MaxAttempts  int           `yaml:"max_attempts"`  // 最大投递次数（含首次）
+	InitialDelay time.Duration `yaml:"initial_delay"` // 首次重试延迟
+	MaxDelay     time.Duration `yaml:"max_delay"`     // 最大重试延迟
+}
+
+// IdentityConfig 终端用户身份校验配置
+type IdentityConfig struct {
+	JWTSecret    string        `yaml:"jwt_secret"`    // JWT HS256 签名密钥
+	JWTIssuer    string        `yaml:"jwt_issuer"`    // 期望的签发方（为空不校验）
+	HeaderSecret string        `yaml:"header_secret"` // X-User-Signature 的 HMAC 密钥
+	Leeway       time.Duration `yaml:"leeway"`        // 过期时间容差
+}
+
+// IsZero 是否未配置任何身份校验方式
+func (ic IdentityConfig) IsZero() bool {
+	return ic.JWTSecret == "" && ic.HeaderSecret == ""
+}
+
+// OrderIDConfig 订单号方案配置
+type OrderIDConfig struct {
+	Prefix          string `yaml:"prefix"`           // 规范形式的前缀，如 "#"、"SO-"
+	Pattern         string `yaml:"pattern"`          // 前缀之后部分的正则表达式
+	CaseInsensitive bool   `yaml:"case_insensitive"` // 是否忽略大小写（规范形式为大写）
+	Generator       string `yaml:"generator"`        // date_digits（默认）或 none（订单号由外部系统导入）
+	Digits          int    `yaml:"digits"`           // date_digits 生成器在日期后追加的随机数字位数
+}
+
+// SlotConfig 对话槽位配置
+type SlotConfig struct {
+	Name        string `yaml:"name"`         // 槽位名称；order_id 为内置订单号槽位，可覆盖其追问话术
+	Intent      string `yaml:"intent"`       // 所属意图：course、order、direct、handoff
+	Prompt      string `yaml:"prompt"`       // 追问话术
+	Pattern     string `yaml:"pattern"`      // 从回复中提取值的正则（为空时接受任意非空回复）
+	Required    bool   `yaml:"required"`     // 该意图处理前是否必须先填写
+	MaxAttempts int    `yaml:"max_attempts"` // 回复无法填充时重新追问的次数
+}
+
+// OrderSyncConfig 订单同步配置：定时从租户电商系统的 REST 接口增量拉取订单
+type OrderSyncConfig struct {
+	Name         string                  `yaml:"name"`           // 数据源名称，默认 rest
+	URL          string                  `yaml:"url"`            // 订单列表接口地址，为空表示不同步
+	Token        string                  `yaml:"token"`          // Bearer Token
+	Headers      map[string]string       `yaml:"headers"`        // 额外请求头
+	Interval     time.Duration           `yaml:"interval"`       // 同步间隔，为 0 时只能通过接口手动触发
+	PageSize     int                     `yaml:"page_size"`      // 每页数量，默认 100
+	SinceParam   string                  `yaml:"since_param"`    // 增量参数名，默认 updated_since
+	DataField    string                  `yaml:"data_field"`     // 响应中订单数组的字段，默认 data
+	HasMoreField string                  `yaml:"has_more_field"` // 响应中是否有下一页的字段，默认 has_more
+	Timeout      time.Duration           `yaml:"timeout"`        // 单次请求超时
+	Mapping      OrderFieldMappingConfig `yaml:"mapping"`        // 字段映射
+}
+
+// OrderFieldMappingConfig 外部订单字段映射配置，未配置的字段与订单字段同名
+type OrderFieldMappingConfig struct {
+	ID           string            `yaml:"id"`
+	UserID       string            `yaml:"user_id"`
+	CourseName   string            `yaml:"course_name"`
+	Amount       string            `yaml:"amount"`
+	Status       string            `yaml:"status"`
+	CreatedAt    string            `yaml:"created_at"`
+	UpdatedAt    string            `yaml:"updated_at"`
+	StatusValues map[string]string `yaml:"status_values"` // 外部状态值到订单状态的映射
+	TimeLayout   string            `yaml:"time_layout"`   // 时间格式，为空时自动识别
+}
+
+// TenantConfig 租户级配置，未配置的部分沿用全局配置
+type TenantConfig struct {
+	Identity  IdentityConfig  `yaml:"identity"`
+	OrderID   OrderIDConfig   `yaml:"order_id"`
+	Slots     []SlotConfig    `yaml:"slots"`
+	OrderSync OrderSyncConfig `yaml:"order_sync"`
+	// Groundedness 回答依据校验，未配置时使用 rag.groundedness
+	Groundedness GroundednessConfig `yaml:"groundedness"`
+	// Suggestions 建议问题开关和 LLM 调用上限，未配置时使用全局 suggestions
+	Suggestions *SuggestionsConfig `yaml:"suggestions"`
+	// AnswerCache 语义回答缓存，未配置时使用全局 answer_cache
+	AnswerCache *AnswerCacheConfig `yaml:"answer_cache"`
+	// FAQ 标准问答，未配置时使用全局 faq
+	FAQ *FAQConfig `yaml:"faq"`
+	// Redaction 个人信息脱敏，未配置时使用全局 redaction
+	Redaction *RedactionConfig `yaml:"redaction"`
+	// InjectionGuard 提示词注入检测，未配置时使用全局 injection_guard
+	InjectionGuard *InjectionGuardConfig `yaml:"injection_guard"`
+	// Moderation 回答审核，未配置时使用全局 moderation
+	Moderation *ModerationConfig `yaml:"moderation"`
+	// RateLimit 限流和模型调用并发上限，未配置时使用全局 rate_limit
+	RateLimit *RateLimitConfig `yaml:"rate_limit"`
+	// PromptVariables 提示词模板变量（brand_name、product_scope、language 和自定义变量）
+	PromptVariables map[string]string `yaml:"prompt_variables"`
+	// Experiments 提示词和模型的 A/B 实验，每个组件最多一个实验
+	Experiments []ExperimentConfig `yaml:"experiments"`
+}
+
+// ExperimentConfig A/B 实验配置
+type ExperimentConfig struct {
+	Name      string                    `yaml:"name"`
+	Component string                    `yaml:"component"` // 参与实验的组件：intent、rag、direct
+	Variants  []ExperimentVariantConfig `yaml:"variants"`
+}
+
+// ExperimentVariantConfig 实验分组配置
+type ExperimentVariantConfig struct {
+	Name          string `yaml:"name"`
+	Weight        int    `yaml:"weight"`         // 流量权重
+	PromptVersion int    `yaml:"prompt_version"` // 组件提示词的模板版本，0 表示使用当前启用的版本
+	Model         string `yaml:"model"`          // 聊天模型，为空表示使用默认模型
+}
+
+// TenantIdentity 获取租户的身份校验配置
+func (c *Config) TenantIdentity(tenantID string) IdentityConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && !tc.Identity.IsZero() {
+		return tc.Identity
+	}
+	return c.Identity
+}
+
+// TenantGroundedness 获取租户的回答依据校验配置
+func (c *Config) TenantGroundedness(tenantID string) GroundednessConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && !tc.Groundedness.IsZero() {
+		return tc.Groundedness
+	}
+	return c.RAG.Groundedness
+}
+
+// TenantSuggestions 获取租户的建议问题配置
+func (c *Config) TenantSuggestions(tenantID string) SuggestionsConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && tc.Suggestions != nil {
+		return *tc.Suggestions
+	}
+	return c.Suggestions
+}
+
+// TenantAnswerCache 获取租户的语义回答缓存配置
+func (c *Config) TenantAnswerCache(tenantID string) AnswerCacheConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && tc.AnswerCache != nil {
+		return *tc.AnswerCache
+	}
+	return c.AnswerCache
+}
+
+// TenantFAQ 获取租户的标准问答配置
+func (c *Config) TenantFAQ(tenantID string) FAQConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && tc.FAQ != nil {
+		return *tc.FAQ
+	}
+	return c.FAQ
+}
+
+// TenantRedaction 获取租户的个人信息脱敏配置，租户 ID 为空时返回全局配置
+func (c *Config) TenantRedaction(tenantID string) RedactionConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && tc.Redaction != nil {
+		return *tc.Redaction
+	}
+	return c.Redaction
+}
+
+// TenantInjectionGuard 获取租户的提示词注入检测配置
+func (c *Config) TenantInjectionGuard(tenantID string) InjectionGuardConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && tc.InjectionGuard != nil {
+		return *tc.InjectionGuard
+	}
+	return c.InjectionGuard
+}
+
+// TenantModeration 获取租户的回答审核配置
+func (c *Config) TenantModeration(tenantID string) ModerationConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && tc.Moderation != nil {
+		return *tc.Moderation
+	}
+	return c.Moderation
+}
+
+// TenantRateLimit 获取租户的限流配置
+func (c *Config) TenantRateLimit(tenantID string) RateLimitConfig {
+	if tc, ok := c.Tenants[tenantID]; ok && tc.RateLimit != nil {
+		return *tc.RateLimit
+	}
+	return c.RateLimit
+}
+
 // Load 从文件加载配置
 func Load(configPath string) (*Config, error) {
 	data, err := os.ReadFile(configPath)
@@ -111,6 +446,14 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid server port: %d", c.Server.Port)
 	}
 
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("invalid server trusted_proxies entry: %s", proxy)
+			}
+		}
+	}
+
 	if c.DashScope.APIKey == "" {
 		return fmt.Errorf("dashscope api_key is required")
 	}
@@ -133,6 +133,25 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid trusted proxy",
+			config: Config{
+				Server: ServerConfig{
+					Port:           8080,
+					TrustedProxies: []string{"10.0.0.0/8", "nginx"},
+				},
+				DashScope: DashScopeConfig{
+					APIKey: "test_key",
+				},
+				Milvus: MilvusConfig{
+					Host: "localhost",
+				},
+				Database: DatabaseConfig{
+					BasePath: "./data",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing api key",
 			config: Config{
@@ -8,16 +8,30 @@ import (
 	"eino-qa/internal/adapter/http"
 	"eino-qa/internal/adapter/http/handler"
 	"eino-qa/internal/adapter/http/middleware"
+	"eino-qa/internal/domain/entity"
 	"eino-qa/internal/domain/repository"
 	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/cache"
 	"eino-qa/internal/infrastructure/config"
+	"eino-qa/internal/infrastructure/identity"
 	"eino-qa/internal/infrastructure/logger"
 	"eino-qa/internal/infrastructure/metrics"
+	"eino-qa/internal/infrastructure/ordersync"
+	"eino-qa/internal/infrastructure/redact"
 	"eino-qa/internal/infrastructure/repository/milvus"
 	"eino-qa/internal/infrastructure/repository/sqlite"
 	"eino-qa/internal/infrastructure/tenant"
+	"eino-qa/internal/infrastructure/webhook"
 	"eino-qa/internal/usecase/chat"
+	"eino-qa/internal/usecase/experiment"
+	faquc "eino-qa/internal/usecase/faq"
+	"eino-qa/internal/usecase/feedback"
+	"eino-qa/internal/usecase/missedquery"
+	"eino-qa/internal/usecase/orderimport"
+	"eino-qa/internal/usecase/prompt"
 	"eino-qa/internal/usecase/vector"
+	webhookuc "eino-qa/internal/usecase/webhook"
+	apperrors "eino-qa/pkg/errors"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -31,6 +45,7 @@ type Container struct {
 	// 基础设施
 	Logger           logger.Logger
 	LogrusLogger     *logrus.Logger
+	Redactor         *redact.Redactor
 	MetricsCollector metrics.Metrics
 
 	// 外部服务客户端
@@ -39,32 +54,74 @@ type Container struct {
 
 	// 仓储层
 	VectorRepository  repository.VectorRepository
-	OrderRepository   repository.OrderRepository
 	SessionRepository repository.SessionRepository
 
+	// 事件通知
+	WebhookDispatcher *webhook.Dispatcher
+
+	// 语义回答缓存（进程内）
+	AnswerCache *cache.AnswerCache
+
+	// 订单定时同步
+	OrderSyncPoller *ordersync.Poller
+
 	// AI 组件
-	IntentRecognizer  *eino.IntentRecognizer
-	RAGRetriever      *eino.RAGRetriever
-	OrderQuerier      *eino.OrderQuerier
-	ResponseGenerator *eino.ResponseGenerator
+	IntentRecognizer    *eino.IntentRecognizer
+	RAGRetriever        *eino.RAGRetriever
+	GroundednessChecker *eino.GroundednessChecker
+	SuggestionGenerator *eino.SuggestionGenerator
+	InjectionGuard      *eino.InjectionGuard
+	Moderator           *eino.Moderator
+	OrderQuerier        *eino.OrderQuerier
+	ResponseGenerator   *eino.ResponseGenerator
 
 	// 用例层
-	ChatUseCase   chat.ChatUseCaseInterface
-	VectorUseCase vector.VectorUseCaseInterface
+	ChatUseCase        chat.ChatUseCaseInterface
+	VectorUseCase      vector.VectorUseCaseInterface
+	WebhookUseCase     webhookuc.WebhookUseCaseInterface
+	MissedQueryUseCase missedquery.MissedQueryUseCaseInterface
+	FeedbackUseCase    feedback.FeedbackUseCaseInterface
+	OrderImportUseCase orderimport.OrderImportUseCaseInterface
+	PromptUseCase      prompt.PromptUseCaseInterface
+	ExperimentUseCase  experiment.ExperimentUseCaseInterface
+	FAQUseCase         faquc.FAQUseCaseInterface
+	QuarantineUseCase  vector.QuarantineUseCaseInterface
 
 	// HTTP 层
-	ChatHandler   *handler.ChatHandler
-	VectorHandler *handler.VectorHandler
-	HealthHandler *handler.HealthHandler
-	ModelHandler  *handler.ModelHandler
+	ChatHandler        *handler.ChatHandler
+	VectorHandler      *handler.VectorHandler
+	HealthHandler      *handler.HealthHandler
+	ModelHandler       *handler.ModelHandler
+	WebhookHandler     *handler.WebhookHandler
+	MissedQueryHandler *handler.MissedQueryHandler
+	FeedbackHandler    *handler.FeedbackHandler
+	OrderImportHandler *handler.OrderImportHandler
+	PromptHandler      *handler.PromptHandler
+	ExperimentHandler  *handler.ExperimentHandler
+	FAQHandler         *handler.FAQHandler
+	QuarantineHandler  *handler.QuarantineHandler
 
 	// 中间件
-	TenantMiddleware   gin.HandlerFunc
-	SecurityMiddleware gin.HandlerFunc
-	LoggingMiddleware  gin.HandlerFunc
-	MetricsMiddleware  gin.HandlerFunc
-	ErrorMiddleware    gin.HandlerFunc
-	AuthMiddleware     gin.HandlerFunc
+	TenantMiddleware    gin.HandlerFunc
+	SecurityMiddleware  gin.HandlerFunc
+	LoggingMiddleware   gin.HandlerFunc
+	MetricsMiddleware   gin.HandlerFunc
+	ErrorMiddleware     gin.HandlerFunc
+	AuthMiddleware      gin.HandlerFunc
+	IdentityMiddleware  gin.HandlerFunc
+	RateLimitMiddleware gin.HandlerFunc
+
+	// 租户自定义对话槽位
+	tenantSlots map[string][]*entity.SlotDefinition
+
+	// 租户订单同步数据源
+	orderSyncSources map[string]*orderimport.SyncSource
+
+	// 租户 A/B 实验
+	tenantExperiments map[string][]*entity.Experiment
+
+	// 回答审核规则，键为租户 ID，全局规则的键为空字符串
+	moderationRules map[string]*eino.ModerationRules
 
 	// 多租户管理
 	TenantManager       *tenant.Manager
@@ -132,8 +189,13 @@ func New(cfg *config.Config) (*Container, error) {
 
 // initLogger 初始化日志
 func (c *Container) initLogger() error {
+	// 个人信息脱敏服务（日志、模型调用和会话保存共用）
+	c.Redactor = redact.New(c.redactionPolicy)
+	logHook := redact.NewLogHook(c.Redactor)
+
 	// 创建 logrus logger
 	c.LogrusLogger = logrus.New()
+	c.LogrusLogger.AddHook(logHook)
 
 	// 设置日志级别
 	level, err := logrus.ParseLevel(c.Config.Logging.Level)
@@ -159,6 +221,7 @@ func (c *Container) initLogger() error {
 		Format:   c.Config.Logging.Format,
 		Output:   c.Config.Logging.Output,
 		FilePath: c.Config.Logging.FilePath,
+		Hooks:    []logrus.Hook{logHook},
 	})
 	if err != nil {
 		return err
@@ -178,18 +241,29 @@ func (c *Container) initMetrics() error {
 
 // initEinoClient 初始化 Eino 客户端
 func (c *Container) initEinoClient() error {
-	client, err := eino.NewClient(eino.ClientConfig{
+	clientConfig := eino.ClientConfig{
 		APIKey:     c.Config.DashScope.APIKey,
 		ChatModel:  c.Config.DashScope.ChatModel,
 		EmbedModel: c.Config.DashScope.EmbedModel,
 		MaxRetries: c.Config.DashScope.MaxRetries,
 		Timeout:    c.Config.DashScope.Timeout,
-	})
+	}
+	if cacheConfig := c.Config.DashScope.EmbeddingCache; cacheConfig.Enabled {
+		clientConfig.EmbedCache = &eino.EmbedCacheConfig{
+			Size:           cacheConfig.Size,
+			Dir:            cacheConfig.Dir,
+			BatchWindow:    cacheConfig.BatchWindow,
+			MaxBatchSize:   cacheConfig.MaxBatchSize,
+			MaxConcurrency: cacheConfig.MaxConcurrency,
+		}
+	}
+
+	client, err := eino.NewClient(clientConfig)
 	if err != nil {
 		return err
 	}
 
-	c.EinoClient = client
+	c.EinoClient = client.WithRedactor(c.Redactor).WithConcurrencyLimit(c.llmConcurrencyLimit)
 	c.LogrusLogger.Info("eino client initialized")
 	return nil
 }
@@ -234,10 +308,126 @@ func (c *Container) initTenantManagement() error {
 		Logger:              c.LogrusLogger,
 	})
 
+	// 注册租户自定义订单号方案
+	if err := c.registerOrderIDSchemes(); err != nil {
+		return err
+	}
+
+	// 加载租户自定义对话槽位
+	if err := c.loadSlotDefinitions(); err != nil {
+		return err
+	}
+
+	// 创建租户订单同步数据源
+	if err := c.loadOrderSyncSources(); err != nil {
+		return err
+	}
+
+	// 加载租户 A/B 实验
+	if err := c.loadExperiments(); err != nil {
+		return err
+	}
+
+	// 加载回答审核规则
+	if err := c.loadModerationRules(); err != nil {
+		return err
+	}
+
 	c.LogrusLogger.Info("tenant management initialized")
 	return nil
 }
 
+// registerOrderIDSchemes 按租户配置注册订单号方案，未配置的租户使用默认方案
+func (c *Container) registerOrderIDSchemes() error {
+	for tenantID, tc := range c.Config.Tenants {
+		cfg := tc.OrderID
+		if cfg.Pattern == "" {
+			continue
+		}
+
+		schemeConfig := entity.OrderIDSchemeConfig{
+			Prefix:          cfg.Prefix,
+			Pattern:         cfg.Pattern,
+			CaseInsensitive: cfg.CaseInsensitive,
+		}
+
+		switch cfg.Generator {
+		case "", "date_digits":
+			digits := cfg.Digits
+			if digits <= 0 {
+				digits = 6
+			}
+			schemeConfig.Generator = entity.DateDigitsOrderIDGenerator(digits)
+		case "none":
+			// 订单号由租户电商系统导入
+		default:
+			return fmt.Errorf("tenant %s: unknown order id generator %q", tenantID, cfg.Generator)
+		}
+
+		scheme, err := entity.NewPatternOrderIDScheme(schemeConfig)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+
+		entity.RegisterOrderIDScheme(tenantID, scheme)
+		c.LogrusLogger.WithFields(logrus.Fields{
+			"tenant_id": tenantID,
+			"prefix":    cfg.Prefix,
+			"pattern":   cfg.Pattern,
+		}).Info("order id scheme registered")
+	}
+
+	return nil
+}
+
+// loadOrderSyncSources 按租户配置创建订单同步数据源
+func (c *Container) loadOrderSyncSources() error {
+	c.orderSyncSources = make(map[string]*orderimport.SyncSource)
+	for tenantID, tc := range c.Config.Tenants {
+		cfg := tc.OrderSync
+		if cfg.URL == "" {
+			continue
+		}
+
+		source, err := ordersync.NewRESTSource(ordersync.RESTConfig{
+			Name:         cfg.Name,
+			URL:          cfg.URL,
+			Token:        cfg.Token,
+			Headers:      cfg.Headers,
+			SinceParam:   cfg.SinceParam,
+			PageSize:     cfg.PageSize,
+			DataField:    cfg.DataField,
+			HasMoreField: cfg.HasMoreField,
+			Timeout:      cfg.Timeout,
+		})
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+
+		m := cfg.Mapping
+		c.orderSyncSources[tenantID] = &orderimport.SyncSource{
+			Source: source,
+			Mapping: &orderimport.FieldMapping{
+				ID:           m.ID,
+				UserID:       m.UserID,
+				CourseName:   m.CourseName,
+				Amount:       m.Amount,
+				Status:       m.Status,
+				CreatedAt:    m.CreatedAt,
+				UpdatedAt:    m.UpdatedAt,
+				StatusValues: m.StatusValues,
+				TimeLayout:   m.TimeLayout,
+			},
+		}
+	}
+	return nil
+}
+
+// orderSyncSource 获取租户的订单同步数据源
+func (c *Container) orderSyncSource(tenantID string) *orderimport.SyncSource {
+	return c.orderSyncSources[tenantID]
+}
+
 // initRepositories 初始化仓储层
 func (c *Container) initRepositories() error {
 	// 向量仓储（Milvus）
@@ -248,18 +438,91 @@ func (c *Container) initRepositories() error {
 		c.LogrusLogger,
 	)
 
-	// 订单仓储（SQLite）
-	// 使用默认租户初始化，实际使用时会通过 tenant context 切换
-	c.OrderRepository = sqlite.NewOrderRepository(c.DBManager, "default")
-
 	// 会话仓储（SQLite 实现）
 	// 使用默认租户初始化，实际使用时会通过 tenant context 切换
 	c.SessionRepository = sqlite.NewSessionRepository(c.DBManager, "default")
 
+	// Webhook 分发器（按租户获取 Webhook 仓储）
+	c.WebhookDispatcher = webhook.NewDispatcher(
+		c.webhookRepository,
+		webhook.Config{
+			Timeout: c.Config.Webhook.Timeout,
+			Retry: apperrors.RetryConfig{
+				MaxAttempts:  c.Config.Webhook.MaxAttempts,
+				InitialDelay: c.Config.Webhook.InitialDelay,
+				MaxDelay:     c.Config.Webhook.MaxDelay,
+			},
+		},
+		c.LogrusLogger,
+	)
+
 	c.LogrusLogger.Info("repositories initialized")
 	return nil
 }
 
+// webhookRepository 按租户创建 Webhook 仓储
+func (c *Container) webhookRepository(tenantID string) repository.WebhookRepository {
+	return sqlite.NewWebhookRepository(c.DBManager, tenantID)
+}
+
+// missedQueryRepository 按租户创建未命中查询仓储
+func (c *Container) missedQueryRepository(tenantID string) repository.MissedQueryRepository {
+	return sqlite.NewMissedQueryRepository(c.DBManager, tenantID)
+}
+
+// orderRepository 按租户创建订单仓储
+func (c *Container) orderRepository(tenantID string) repository.OrderRepository {
+	return sqlite.NewOrderRepository(c.DBManager, tenantID)
+}
+
+// orderSyncRepository 按租户创建订单同步状态仓储
+func (c *Container) orderSyncRepository(tenantID string) repository.OrderSyncRepository {
+	return sqlite.NewOrderSyncRepository(c.DBManager, tenantID)
+}
+
+// feedbackRepository 按租户创建用户反馈仓储
+func (c *Container) feedbackRepository(tenantID string) repository.FeedbackRepository {
+	return sqlite.NewFeedbackRepository(c.DBManager, tenantID)
+}
+
+// promptTemplateRepository 按租户创建提示词模板仓储
+func (c *Container) promptTemplateRepository(tenantID string) repository.PromptTemplateRepository {
+	return sqlite.NewPromptTemplateRepository(c.DBManager, tenantID)
+}
+
+// experimentRepository 按租户创建实验结果仓储
+func (c *Container) experimentRepository(tenantID string) repository.ExperimentRepository {
+	return sqlite.NewExperimentRepository(c.DBManager, tenantID)
+}
+
+// faqRepository 按租户创建标准问答仓储
+func (c *Container) faqRepository(tenantID string) repository.FAQRepository {
+	return sqlite.NewFAQRepository(c.DBManager, tenantID)
+}
+
+// quarantineRepository 按租户创建隔离文档仓储
+func (c *Container) quarantineRepository(tenantID string) repository.QuarantineRepository {
+	return sqlite.NewQuarantineRepository(c.DBManager, tenantID)
+}
+
+// promptVariables 获取租户的提示词模板变量
+func (c *Container) promptVariables(tenantID string) entity.PromptData {
+	data := entity.PromptData{Vars: make(map[string]string)}
+	for key, value := range c.Config.Tenants[tenantID].PromptVariables {
+		switch key {
+		case "brand_name":
+			data.BrandName = value
+		case "product_scope":
+			data.ProductScope = value
+		case "language":
+			data.Language = value
+		default:
+			data.Vars[key] = value
+		}
+	}
+	return data
+}
+
 // initAIComponents 初始化 AI 组件
 func (c *Container) initAIComponents() error {
 	// 意图识别器
@@ -275,10 +538,23 @@ func (c *Container) initAIComponents() error {
 		&c.Config.RAG,
 	)
 
+	// 回答依据校验器
+	c.GroundednessChecker = eino.NewGroundednessChecker(c.EinoClient)
+
+	// 建议问题生成器
+	c.SuggestionGenerator = eino.NewSuggestionGenerator(c.EinoClient)
+
+	// 提示词注入检测器
+	c.InjectionGuard = eino.NewInjectionGuard(c.EinoClient)
+
+	// 回答审核器
+	c.Moderator = eino.NewModerator(c.EinoClient)
+
 	// 订单查询器
+	// 订单仓储和订单号方案都按对话上下文中的租户选择
 	c.OrderQuerier = eino.NewOrderQuerier(
 		c.EinoClient,
-		c.OrderRepository,
+		c.orderRepository,
 	)
 
 	// 响应生成器
@@ -290,10 +566,227 @@ func (c *Container) initAIComponents() error {
 	return nil
 }
 
+// loadSlotDefinitions 按租户配置创建对话槽位定义
+func (c *Container) loadSlotDefinitions() error {
+	c.tenantSlots = make(map[string][]*entity.SlotDefinition)
+	for tenantID, tc := range c.Config.Tenants {
+		for _, cfg := range tc.Slots {
+			slot, err := entity.NewSlotDefinition(cfg.Name, entity.IntentType(cfg.Intent), cfg.Prompt, cfg.Pattern)
+			if err != nil {
+				return fmt.Errorf("tenant %s: %w", tenantID, err)
+			}
+			slot.Required = cfg.Required
+			slot.MaxAttempts = cfg.MaxAttempts
+			c.tenantSlots[tenantID] = append(c.tenantSlots[tenantID], slot)
+		}
+	}
+	return nil
+}
+
+// loadModerationRules 按全局和租户配置创建回答审核规则
+func (c *Container) loadModerationRules() error {
+	c.moderationRules = make(map[string]*eino.ModerationRules)
+	configs := map[string]config.ModerationConfig{"": c.Config.Moderation}
+	for tenantID, tc := range c.Config.Tenants {
+		if tc.Moderation != nil {
+			configs[tenantID] = *tc.Moderation
+		}
+	}
+	for tenantID, cfg := range configs {
+		rules, err := eino.NewModerationRules(cfg.BannedTopics, cfg.Competitors, cfg.Denylist)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+		c.moderationRules[tenantID] = rules
+	}
+	return nil
+}
+
+// slotDefinitions 获取租户自定义的对话槽位
+func (c *Container) slotDefinitions(tenantID string) []*entity.SlotDefinition {
+	return c.tenantSlots[tenantID]
+}
+
+// loadExperiments 按租户配置创建 A/B 实验，并预先创建各分组使用的聊天模型
+func (c *Container) loadExperiments() error {
+	c.tenantExperiments = make(map[string][]*entity.Experiment)
+	for tenantID, tc := range c.Config.Tenants {
+		components := make(map[entity.ExperimentComponent]bool)
+		for _, cfg := range tc.Experiments {
+			exp := &entity.Experiment{
+				Name:      cfg.Name,
+				Component: entity.ExperimentComponent(cfg.Component),
+			}
+			for _, vc := range cfg.Variants {
+				exp.Variants = append(exp.Variants, &entity.ExperimentVariant{
+					Name:          vc.Name,
+					Weight:        vc.Weight,
+					PromptVersion: vc.PromptVersion,
+					Model:         vc.Model,
+				})
+			}
+			if err := exp.Validate(); err != nil {
+				return fmt.Errorf("tenant %s experiment %s: %w", tenantID, cfg.Name, err)
+			}
+			if components[exp.Component] {
+				return fmt.Errorf("tenant %s: %w: %s", tenantID, entity.ErrDuplicateExperimentComponent, exp.Component)
+			}
+			components[exp.Component] = true
+
+			for _, variant := range exp.Variants {
+				if _, err := c.EinoClient.ChatModel(variant.Model); err != nil {
+					return fmt.Errorf("tenant %s experiment %s: %w", tenantID, cfg.Name, err)
+				}
+			}
+			c.tenantExperiments[tenantID] = append(c.tenantExperiments[tenantID], exp)
+		}
+	}
+	return nil
+}
+
+// experiments 获取租户的 A/B 实验
+func (c *Container) experiments(tenantID string) []*entity.Experiment {
+	return c.tenantExperiments[tenantID]
+}
+
+// groundednessPolicy 获取租户的回答依据校验策略
+func (c *Container) groundednessPolicy(tenantID string) chat.GroundednessPolicy {
+	cfg := c.Config.TenantGroundedness(tenantID)
+	return chat.GroundednessPolicy{
+		Enabled:   cfg.Enabled,
+		Method:    eino.GroundednessMethod(cfg.Method),
+		Threshold: cfg.Threshold,
+		Action:    chat.GroundednessAction(cfg.Action),
+	}
+}
+
+// suggestionPolicy 获取租户的建议问题策略
+func (c *Container) suggestionPolicy(tenantID string) chat.SuggestionPolicy {
+	cfg := c.Config.TenantSuggestions(tenantID)
+	return chat.SuggestionPolicy{
+		Enabled:            cfg.Enabled,
+		MaxLLMCallsPerHour: cfg.MaxLLMCallsPerHour,
+	}
+}
+
+// answerCachePolicy 获取租户的语义回答缓存策略
+func (c *Container) answerCachePolicy(tenantID string) chat.AnswerCachePolicy {
+	cfg := c.Config.TenantAnswerCache(tenantID)
+	policy := chat.AnswerCachePolicy{
+		Enabled:   cfg.Enabled,
+		Threshold: cfg.Threshold,
+		TTL:       cfg.TTL,
+	}
+	for _, route := range cfg.Routes {
+		policy.Routes = append(policy.Routes, entity.IntentType(route))
+	}
+	return policy
+}
+
+// faqPolicy 获取租户的标准问答策略
+func (c *Container) faqPolicy(tenantID string) chat.FAQPolicy {
+	cfg := c.Config.TenantFAQ(tenantID)
+	return chat.FAQPolicy{
+		Enabled:   cfg.Enabled,
+		Threshold: cfg.Threshold,
+	}
+}
+
+// redactionPolicy 获取租户的个人信息脱敏策略，租户 ID 为空时返回全局策略
+func (c *Container) redactionPolicy(tenantID string) redact.Policy {
+	cfg := c.Config.TenantRedaction(tenantID)
+	policy := redact.Policy{
+		Enabled:      cfg.Enabled,
+		TokenizeLLM:  cfg.TokenizeLLM,
+		MaskLogs:     cfg.MaskLogs,
+		MaskSessions: cfg.MaskSessions,
+	}
+	for _, t := range cfg.Types {
+		policy.Types = append(policy.Types, redact.Type(t))
+	}
+	return policy
+}
+
+// injectionPolicy 获取租户的查询提示词注入检测策略
+func (c *Container) injectionPolicy(tenantID string) chat.InjectionPolicy {
+	cfg := c.Config.TenantInjectionGuard(tenantID)
+	return chat.InjectionPolicy{
+		Enabled:   cfg.Enabled,
+		Method:    eino.InjectionMethod(cfg.Method),
+		Threshold: cfg.Threshold,
+		Action:    chat.InjectionAction(cfg.Action),
+	}
+}
+
+// moderationPolicy 获取租户的回答审核策略
+func (c *Container) moderationPolicy(tenantID string) chat.ModerationPolicy {
+	cfg := c.Config.TenantModeration(tenantID)
+	rules, ok := c.moderationRules[tenantID]
+	if !ok {
+		rules = c.moderationRules[""]
+	}
+	return chat.ModerationPolicy{
+		Enabled:         cfg.Enabled,
+		Method:          eino.ModerationMethod(cfg.Method),
+		Rules:           rules,
+		FallbackMessage: cfg.FallbackMessage,
+	}
+}
+
+// rateLimitPolicy 获取租户的限流策略
+func (c *Container) rateLimitPolicy(tenantID string) middleware.RateLimitPolicy {
+	cfg := c.Config.TenantRateLimit(tenantID)
+	limit := func(bucket config.RateLimitBucket) middleware.RateLimit {
+		return middleware.RateLimit{RequestsPerMinute: bucket.RequestsPerMinute, Burst: bucket.Burst}
+	}
+	return middleware.RateLimitPolicy{
+		Enabled: cfg.Enabled,
+		Tenant:  limit(cfg.Tenant),
+		APIKey:  limit(cfg.APIKey),
+		User:    limit(cfg.User),
+		IP:      limit(cfg.IP),
+	}
+}
+
+// llmConcurrencyLimit 获取租户的模型调用并发上限，未启用限流时不限制
+func (c *Container) llmConcurrencyLimit(tenantID string) eino.ConcurrencyLimit {
+	cfg := c.Config.TenantRateLimit(tenantID)
+	if !cfg.Enabled {
+		return eino.ConcurrencyLimit{}
+	}
+	return eino.ConcurrencyLimit{MaxConcurrent: cfg.MaxConcurrentLLMCalls, QueueTimeout: cfg.LLMQueueTimeout}
+}
+
+// documentInjectionPolicy 获取租户的文档提示词注入检测策略
+func (c *Container) documentInjectionPolicy(tenantID string) vector.InjectionPolicy {
+	cfg := c.Config.TenantInjectionGuard(tenantID)
+	return vector.InjectionPolicy{
+		Enabled:   cfg.Enabled,
+		Method:    eino.InjectionMethod(cfg.Method),
+		Threshold: cfg.Threshold,
+		Action:    vector.DocumentAction(cfg.DocumentAction),
+	}
+}
+
 // initUseCases 初始化用例层
 func (c *Container) initUseCases() error {
+	// 提示词模板用例（AI 组件通过它按租户渲染系统提示词）
+	promptUseCase := prompt.NewPromptUseCase(
+		c.promptTemplateRepository,
+		c.promptVariables,
+		c.LogrusLogger,
+	)
+	c.PromptUseCase = promptUseCase
+	c.IntentRecognizer.SetPromptRenderer(promptUseCase)
+	c.RAGRetriever.SetPromptRenderer(promptUseCase)
+	c.OrderQuerier.SetPromptRenderer(promptUseCase)
+	c.ResponseGenerator.SetPromptRenderer(promptUseCase)
+
+	// 语义回答缓存（知识库变更时由向量管理用例按租户失效）
+	c.AnswerCache = cache.NewAnswerCache(c.Config.AnswerCache.MaxEntries)
+
 	// 对话用例
-	c.ChatUseCase = chat.NewChatUseCase(
+	chatUseCase := chat.NewChatUseCase(
 		c.IntentRecognizer,
 		c.RAGRetriever,
 		c.OrderQuerier,
@@ -301,15 +794,89 @@ func (c *Container) initUseCases() error {
 		c.SessionRepository,
 		c.Config.Session.Timeout,
 		c.Logger,
+	).
+		WithEventPublisher(c.WebhookDispatcher).
+		WithMissedQueryRepository(c.missedQueryRepository).
+		WithSlotDefinitions(c.slotDefinitions).
+		WithGroundedness(c.GroundednessChecker, c.groundednessPolicy).
+		WithSuggestions(c.SuggestionGenerator, c.suggestionPolicy).
+		WithExperiments(c.experiments, c.experimentRepository).
+		WithAnswerCache(c.AnswerCache, c.EinoClient.GetEmbedModel(), c.answerCachePolicy).
+		WithFAQ(c.faqRepository, c.EinoClient.GetEmbedModel(), c.faqPolicy).
+		WithSessionMasker(c.Redactor.MaskSession).
+		WithInjectionGuard(c.InjectionGuard, c.injectionPolicy).
+		WithModeration(c.Moderator, c.moderationPolicy).
+		WithMetrics(c.MetricsCollector)
+	c.ChatUseCase = chatUseCase
+
+	// 标准问答管理用例（变更后清除对话用例中该租户的标准问答缓存）
+	c.FAQUseCase = faquc.NewFAQUseCase(
+		c.faqRepository,
+		c.EinoClient.GetEmbedModel(),
+		c.LogrusLogger,
+	).WithChangeListener(chatUseCase)
+
+	// A/B 实验结果用例
+	c.ExperimentUseCase = experiment.NewExperimentUseCase(
+		c.experiments,
+		c.experimentRepository,
+		c.LogrusLogger,
 	)
 
-	// 向量管理用例
-	c.VectorUseCase = vector.NewVectorManagementUseCase(
+	// 向量管理用例（写入的文档经过提示词注入检测，可疑文档进入隔离区）
+	vectorUseCase := vector.NewVectorManagementUseCase(
 		c.EinoClient.GetEmbedModel(),
 		c.VectorRepository,
 		c.LogrusLogger,
+	).
+		WithKnowledgeBaseListener(c.AnswerCache).
+		WithInjectionGuard(c.InjectionGuard, c.documentInjectionPolicy, c.quarantineRepository)
+	c.VectorUseCase = vectorUseCase
+	c.QuarantineUseCase = vectorUseCase
+
+	// Webhook 管理用例
+	c.WebhookUseCase = webhookuc.NewWebhookManagementUseCase(
+		c.webhookRepository,
+		c.WebhookDispatcher,
+		c.LogrusLogger,
+	)
+
+	// 未命中查询分析用例
+	c.MissedQueryUseCase = missedquery.NewMissedQueryUseCase(
+		c.EinoClient.GetEmbedModel(),
+		c.missedQueryRepository,
+		c.LogrusLogger,
 	)
 
+	// 用户反馈用例
+	c.FeedbackUseCase = feedback.NewFeedbackUseCase(
+		c.SessionRepository,
+		c.feedbackRepository,
+		c.VectorUseCase,
+		c.LogrusLogger,
+	).
+		WithMetrics(c.MetricsCollector).
+		WithEventPublisher(c.WebhookDispatcher)
+
+	// 订单导入与同步用例
+	orderImportUseCase := orderimport.NewOrderImportUseCase(
+		c.orderRepository,
+		c.orderSyncRepository,
+		c.orderSyncSource,
+		c.LogrusLogger,
+	).WithEventPublisher(c.WebhookDispatcher)
+	c.OrderImportUseCase = orderImportUseCase
+
+	// 订单定时同步（由 StartBackgroundJobs 启动）
+	intervals := make(map[string]time.Duration)
+	for tenantID := range c.orderSyncSources {
+		intervals[tenantID] = c.Config.Tenants[tenantID].OrderSync.Interval
+	}
+	c.OrderSyncPoller = ordersync.NewPoller(func(ctx context.Context, tenantID string) error {
+		_, err := orderImportUseCase.Sync(ctx, tenantID)
+		return err
+	}, intervals, c.LogrusLogger)
+
 	c.LogrusLogger.Info("use cases initialized")
 	return nil
 }
@@ -338,6 +905,23 @@ func (c *Container) initMiddlewares() error {
 	authMw := middleware.NewAuthMiddleware(c.Config.Security.APIKeys)
 	c.AuthMiddleware = authMw.Handler()
 
+	// 终端用户身份识别中间件（按租户读取校验密钥）
+	verifier := identity.NewVerifier(func(tenantID string) identity.Config {
+		ic := c.Config.TenantIdentity(tenantID)
+		return identity.Config{
+			JWTSecret:    ic.JWTSecret,
+			JWTIssuer:    ic.JWTIssuer,
+			HeaderSecret: ic.HeaderSecret,
+			Leeway:       ic.Leeway,
+		}
+	})
+	c.IdentityMiddleware = middleware.NewIdentityMiddleware(verifier).Handler()
+
+	// 限流中间件（按租户读取限额）
+	c.RateLimitMiddleware = middleware.NewRateLimitMiddleware(c.rateLimitPolicy).
+		WithTrustedProxies(c.Config.Server.TrustedProxies).
+		Handler()
+
 	c.LogrusLogger.Info("middlewares initialized")
 	return nil
 }
@@ -356,6 +940,30 @@ func (c *Container) initHandlers() error {
 		c.Config.DashScope.EmbedModel,
 	)
 
+	// Webhook 管理处理器
+	c.WebhookHandler = handler.NewWebhookHandler(c.WebhookUseCase)
+
+	// 未命中查询分析处理器
+	c.MissedQueryHandler = handler.NewMissedQueryHandler(c.MissedQueryUseCase)
+
+	// 用户反馈处理器
+	c.FeedbackHandler = handler.NewFeedbackHandler(c.FeedbackUseCase)
+
+	// 订单导入与同步处理器
+	c.OrderImportHandler = handler.NewOrderImportHandler(c.OrderImportUseCase)
+
+	// 提示词模板管理处理器
+	c.PromptHandler = handler.NewPromptHandler(c.PromptUseCase)
+
+	// A/B 实验结果处理器
+	c.ExperimentHandler = handler.NewExperimentHandler(c.ExperimentUseCase)
+
+	// 标准问答管理处理器
+	c.FAQHandler = handler.NewFAQHandler(c.FAQUseCase)
+
+	// 隔离文档管理处理器
+	c.QuarantineHandler = handler.NewQuarantineHandler(c.QuarantineUseCase)
+
 	// 健康检查处理器
 	c.HealthHandler = handler.NewHealthHandler().
 		WithMetricsProvider(c.MetricsCollector).
@@ -393,17 +1001,27 @@ func (c *Container) initHTTPServer() error {
 
 	// 配置路由
 	routerConfig := &http.RouterConfig{
-		ChatHandler:        c.ChatHandler,
-		VectorHandler:      c.VectorHandler,
-		HealthHandler:      c.HealthHandler,
-		ModelHandler:       c.ModelHandler,
-		TenantMiddleware:   c.TenantMiddleware,
-		SecurityMiddleware: c.SecurityMiddleware,
-		LoggingMiddleware:  c.LoggingMiddleware,
-		MetricsMiddleware:  c.MetricsMiddleware,
-		ErrorMiddleware:    c.ErrorMiddleware,
-		AuthMiddleware:     c.AuthMiddleware,
-		Mode:               c.Config.Server.Mode,
+		ChatHandler:         c.ChatHandler,
+		VectorHandler:       c.VectorHandler,
+		HealthHandler:       c.HealthHandler,
+		ModelHandler:        c.ModelHandler,
+		WebhookHandler:      c.WebhookHandler,
+		MissedQueryHandler:  c.MissedQueryHandler,
+		FeedbackHandler:     c.FeedbackHandler,
+		OrderImportHandler:  c.OrderImportHandler,
+		PromptHandler:       c.PromptHandler,
+		ExperimentHandler:   c.ExperimentHandler,
+		FAQHandler:          c.FAQHandler,
+		QuarantineHandler:   c.QuarantineHandler,
+		TenantMiddleware:    c.TenantMiddleware,
+		SecurityMiddleware:  c.SecurityMiddleware,
+		LoggingMiddleware:   c.LoggingMiddleware,
+		MetricsMiddleware:   c.MetricsMiddleware,
+		ErrorMiddleware:     c.ErrorMiddleware,
+		AuthMiddleware:      c.AuthMiddleware,
+		IdentityMiddleware:  c.IdentityMiddleware,
+		RateLimitMiddleware: c.RateLimitMiddleware,
+		Mode:                c.Config.Server.Mode,
 	}
 
 	c.Router = http.SetupRouter(routerConfig)
@@ -425,12 +1043,33 @@ func (c *Container) initHTTPServer() error {
 	return nil
 }
 
+// StartBackgroundJobs 启动后台任务（订单定时同步）
+func (c *Container) StartBackgroundJobs() {
+	if c.OrderSyncPoller != nil {
+		c.OrderSyncPoller.Start()
+	}
+}
+
 // Close 关闭容器，释放所有资源
 func (c *Container) Close() error {
 	c.LogrusLogger.Info("closing container...")
 
 	var errs []error
 
+	// 停止订单定时同步（先于数据库关闭，保证同步状态落库）
+	if c.OrderSyncPoller != nil {
+		if err := c.OrderSyncPoller.Close(10 * time.Second); err != nil {
+			c.LogrusLogger.WithError(err).Warn("order sync poller closed with sync still running")
+		}
+	}
+
+	// 等待进行中的 Webhook 投递（先于数据库关闭，保证死信落库）
+	if c.WebhookDispatcher != nil {
+		if err := c.WebhookDispatcher.Close(5 * time.Second); err != nil {
+			c.LogrusLogger.WithError(err).Warn("webhook dispatcher closed with pending deliveries")
+		}
+	}
+
 	// 关闭租户管理器
 	if c.TenantManager != nil {
 		if err := c.TenantManager.Close(); err != nil {
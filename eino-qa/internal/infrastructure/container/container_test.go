@@ -74,7 +74,6 @@ func TestContainer_ComponentsInitialized(t *testing.T) {
 
 	// 验证仓储
 	assert.NotNil(t, c.VectorRepository, "VectorRepository should be initialized")
-	assert.NotNil(t, c.OrderRepository, "OrderRepository should be initialized")
 	assert.NotNil(t, c.SessionRepository, "SessionRepository should be initialized")
 
 	// 验证 AI 组件
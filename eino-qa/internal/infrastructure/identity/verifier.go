@@ -0,0 +1,154 @@
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 身份校验错误
+var (
+	ErrNotConfigured    = errors.New("identity verification is not configured for tenant")
+	ErrInvalidToken     = errors.New("invalid identity token")
+	ErrTokenExpired     = errors.New("identity token expired")
+	ErrInvalidSignature = errors.New("invalid identity signature")
+	ErrTenantMismatch   = errors.New("identity token issued for another tenant")
+)
+
+// Config 单个租户的身份校验配置
+type Config struct {
+	JWTSecret    string        // JWT HS256 签名密钥
+	JWTIssuer    string        // 期望的签发方（为空不校验）
+	HeaderSecret string        // X-User-Signature 的 HMAC 密钥
+	Leeway       time.Duration // 过期时间容差
+}
+
+// ConfigProvider 按租户获取身份校验配置
+type ConfigProvider func(tenantID string) Config
+
+// Verifier 终端用户身份校验器
+// 支持两种方式：租户签发的 HS256 JWT，以及租户后端用共享密钥签名的用户 ID 请求头
+type Verifier struct {
+	configs ConfigProvider
+	now     func() time.Time
+}
+
+// NewVerifier 创建身份校验器
+func NewVerifier(configs ConfigProvider) *Verifier {
+	return &Verifier{
+		configs: configs,
+		now:     time.Now,
+	}
+}
+
+// claims JWT 中使用到的声明
+type claims struct {
+	Subject   string `json:"sub"`
+	Issuer    string `json:"iss"`
+	TenantID  string `json:"tenant_id"`
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
+
+// VerifyToken 校验 JWT 并返回其中的用户 ID（sub）
+func (v *Verifier) VerifyToken(tenantID, token string) (string, error) {
+	cfg := v.configs(tenantID)
+	if cfg.JWTSecret == "" {
+		return "", ErrNotConfigured
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return "", err
+	}
+	// 只接受 HS256，避免 alg=none 等降级攻击
+	if header.Alg != "HS256" {
+		return "", fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	mac := hmac.New(sha256.New, []byte(cfg.JWTSecret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", ErrInvalidSignature
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return "", err
+	}
+
+	now := v.now()
+	if c.ExpiresAt != nil && now.After(time.Unix(*c.ExpiresAt, 0).Add(cfg.Leeway)) {
+		return "", ErrTokenExpired
+	}
+	if c.NotBefore != nil && now.Add(cfg.Leeway).Before(time.Unix(*c.NotBefore, 0)) {
+		return "", fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+	if cfg.JWTIssuer != "" && c.Issuer != cfg.JWTIssuer {
+		return "", fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if c.TenantID != "" && c.TenantID != tenantID {
+		return "", ErrTenantMismatch
+	}
+	if c.Subject == "" {
+		return "", fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return c.Subject, nil
+}
+
+// VerifyHeader 校验租户后端签名的用户 ID
+// 签名为 hex(HMAC-SHA256(header_secret, "{tenant_id}:{user_id}"))
+func (v *Verifier) VerifyHeader(tenantID, userID, signature string) error {
+	cfg := v.configs(tenantID)
+	if cfg.HeaderSecret == "" {
+		return ErrNotConfigured
+	}
+
+	if userID == "" {
+		return ErrInvalidSignature
+	}
+
+	expected := SignUserID(cfg.HeaderSecret, tenantID, userID)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// SignUserID 计算用户 ID 请求头签名（供租户后端和测试使用）
+func SignUserID(secret, tenantID, userID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(tenantID + ":" + userID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// decodeSegment 解码 JWT 的 base64url 段
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
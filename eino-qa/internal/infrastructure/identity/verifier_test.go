@@ -0,0 +1,127 @@
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signToken(t *testing.T, secret string, header, payload map[string]any) string {
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	p, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(p)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTestVerifier() *Verifier {
+	v := NewVerifier(func(tenantID string) Config {
+		if tenantID == "unconfigured" {
+			return Config{}
+		}
+		return Config{JWTSecret: "jwt-secret", JWTIssuer: "shop", HeaderSecret: "header-secret"}
+	})
+	v.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return v
+}
+
+func TestVerifier_VerifyToken(t *testing.T) {
+	v := newTestVerifier()
+	hs256 := map[string]any{"alg": "HS256", "typ": "JWT"}
+
+	tests := []struct {
+		name    string
+		tenant  string
+		token   string
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			tenant: "tenant1",
+			token:  signToken(t, "jwt-secret", hs256, map[string]any{"sub": "u1", "iss": "shop", "tenant_id": "tenant1", "exp": 1700000100}),
+		},
+		{
+			name:    "wrong secret",
+			tenant:  "tenant1",
+			token:   signToken(t, "other", hs256, map[string]any{"sub": "u1", "iss": "shop"}),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "expired",
+			tenant:  "tenant1",
+			token:   signToken(t, "jwt-secret", hs256, map[string]any{"sub": "u1", "iss": "shop", "exp": 1699999000}),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "alg none",
+			tenant:  "tenant1",
+			token:   signToken(t, "jwt-secret", map[string]any{"alg": "none"}, map[string]any{"sub": "u1", "iss": "shop"}),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "wrong issuer",
+			tenant:  "tenant1",
+			token:   signToken(t, "jwt-secret", hs256, map[string]any{"sub": "u1", "iss": "evil"}),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "other tenant",
+			tenant:  "tenant1",
+			token:   signToken(t, "jwt-secret", hs256, map[string]any{"sub": "u1", "iss": "shop", "tenant_id": "tenant2"}),
+			wantErr: ErrTenantMismatch,
+		},
+		{
+			name:    "missing subject",
+			tenant:  "tenant1",
+			token:   signToken(t, "jwt-secret", hs256, map[string]any{"iss": "shop"}),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "malformed",
+			tenant:  "tenant1",
+			token:   "not-a-jwt",
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "not configured",
+			tenant:  "unconfigured",
+			token:   signToken(t, "jwt-secret", hs256, map[string]any{"sub": "u1"}),
+			wantErr: ErrNotConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := v.VerifyToken(tt.tenant, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, userID)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "u1", userID)
+		})
+	}
+}
+
+func TestVerifier_VerifyHeader(t *testing.T) {
+	v := newTestVerifier()
+	signature := SignUserID("header-secret", "tenant1", "u1")
+
+	assert.NoError(t, v.VerifyHeader("tenant1", "u1", signature))
+	// 签名绑定租户和用户，不能挪用
+	assert.ErrorIs(t, v.VerifyHeader("tenant2", "u1", signature), ErrInvalidSignature)
+	assert.ErrorIs(t, v.VerifyHeader("tenant1", "u2", signature), ErrInvalidSignature)
+	assert.ErrorIs(t, v.VerifyHeader("tenant1", "", signature), ErrInvalidSignature)
+	assert.ErrorIs(t, v.VerifyHeader("unconfigured", "u1", signature), ErrNotConfigured)
+}
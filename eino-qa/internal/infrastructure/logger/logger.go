@@ -31,6 +31,9 @@ type Config struct {
 	Format   string // json, text
 	Output   string // stdout, file
 	FilePath string // 日志文件路径
+
+	// Hooks 额外的 logrus hook（如个人信息脱敏，见 infrastructure/redact）
+	Hooks []logrus.Hook
 }
 
 // New 创建新的日志实例
@@ -87,6 +90,10 @@ func New(config Config) (Logger, error) {
 	}
 	logger.SetOutput(output)
 
+	for _, hook := range config.Hooks {
+		logger.AddHook(hook)
+	}
+
 	return &logrusLogger{
 		logger: logger,
 		fields: logrus.Fields{},
@@ -11,6 +11,10 @@ type Metrics interface {
 	RecordRequest(route string, statusCode int, duration time.Duration)
 	// 记录错误
 	RecordError(route string, errorType string)
+	// 记录用户反馈（按回答路由和意图统计）
+	RecordFeedback(route string, intent string, positive bool)
+	// 记录语义回答缓存查询（命中时按回答路由统计）
+	RecordCacheLookup(route string, hit bool)
 	// 获取统计信息（返回 interface{} 以兼容 MetricsProvider）
 	GetStats() interface{}
 	// 重置统计信息
@@ -37,6 +41,10 @@ type Stats struct {
 	RouteStats map[string]*RouteStats `json:"route_stats"`
 	// 错误统计
 	ErrorStats map[string]int64 `json:"error_stats"`
+	// 用户反馈统计（键为 "route:intent"）
+	FeedbackStats map[string]*FeedbackStats `json:"feedback_stats"`
+	// 语义回答缓存统计
+	AnswerCache *CacheStats `json:"answer_cache"`
 	// 统计开始时间
 	StartTime time.Time `json:"start_time"`
 	// 最后更新时间
@@ -59,6 +67,28 @@ type RouteStats struct {
 	ErrorCount int64 `json:"error_count"`
 }
 
+// FeedbackStats 用户反馈统计信息
+type FeedbackStats struct {
+	// 正面反馈数
+	Positive int64 `json:"positive"`
+	// 负面反馈数
+	Negative int64 `json:"negative"`
+	// 满意率（正面 / 总数）
+	SatisfactionRate float64 `json:"satisfaction_rate"`
+}
+
+// CacheStats 语义回答缓存统计信息
+type CacheStats struct {
+	// 命中数
+	Hits int64 `json:"hits"`
+	// 未命中数
+	Misses int64 `json:"misses"`
+	// 命中率（命中 / 查询总数）
+	HitRate float64 `json:"hit_rate"`
+	// 按回答路由分类的命中数
+	HitsByRoute map[string]int64 `json:"hits_by_route"`
+}
+
 // memoryMetrics 内存指标收集器实现
 type memoryMetrics struct {
 	mu sync.RWMutex
@@ -83,6 +113,14 @@ type memoryMetrics struct {
 	// 错误统计
 	errorStats map[string]int64
 
+	// 用户反馈统计
+	feedbackStats map[string]*FeedbackStats
+
+	// 语义回答缓存统计
+	cacheHits        int64
+	cacheMisses      int64
+	cacheHitsByRoute map[string]int64
+
 	// 统计开始时间
 	startTime time.Time
 	// 最后更新时间
@@ -125,6 +163,8 @@ func New(config Config) Metrics {
 		responseTimes:          make([]int64, 0, config.MaxResponseTimeSamples),
 		routeStats:             make(map[string]*routeStatsInternal),
 		errorStats:             make(map[string]int64),
+		feedbackStats:          make(map[string]*FeedbackStats),
+		cacheHitsByRoute:       make(map[string]int64),
 		startTime:              time.Now(),
 		lastUpdate:             time.Now(),
 		maxResponseTimeSamples: config.MaxResponseTimeSamples,
@@ -198,6 +238,41 @@ func (m *memoryMetrics) RecordError(route string, errorType string) {
 	m.lastUpdate = time.Now()
 }
 
+// RecordFeedback 记录用户反馈
+func (m *memoryMetrics) RecordFeedback(route string, intent string, positive bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := route + ":" + intent
+	stat, exists := m.feedbackStats[key]
+	if !exists {
+		stat = &FeedbackStats{}
+		m.feedbackStats[key] = stat
+	}
+
+	if positive {
+		stat.Positive++
+	} else {
+		stat.Negative++
+	}
+	stat.SatisfactionRate = float64(stat.Positive) / float64(stat.Positive+stat.Negative)
+	m.lastUpdate = time.Now()
+}
+
+// RecordCacheLookup 记录语义回答缓存查询
+func (m *memoryMetrics) RecordCacheLookup(route string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if hit {
+		m.cacheHits++
+		m.cacheHitsByRoute[route]++
+	} else {
+		m.cacheMisses++
+	}
+	m.lastUpdate = time.Now()
+}
+
 // GetStats 获取统计信息
 // 需求: 7.5 - 返回系统状态和关键指标快照
 func (m *memoryMetrics) GetStats() interface{} {
@@ -216,6 +291,7 @@ func (m *memoryMetrics) getStatsInternal() *Stats {
 		ServerErrors:    m.serverErrors,
 		RouteStats:      make(map[string]*RouteStats),
 		ErrorStats:      make(map[string]int64),
+		FeedbackStats:   make(map[string]*FeedbackStats),
 		StartTime:       m.startTime,
 		LastUpdate:      m.lastUpdate,
 	}
@@ -253,6 +329,25 @@ func (m *memoryMetrics) getStatsInternal() *Stats {
 		stats.ErrorStats[key] = count
 	}
 
+	// 复制反馈统计
+	for key, stat := range m.feedbackStats {
+		copied := *stat
+		stats.FeedbackStats[key] = &copied
+	}
+
+	// 复制缓存统计
+	stats.AnswerCache = &CacheStats{
+		Hits:        m.cacheHits,
+		Misses:      m.cacheMisses,
+		HitsByRoute: make(map[string]int64),
+	}
+	if lookups := m.cacheHits + m.cacheMisses; lookups > 0 {
+		stats.AnswerCache.HitRate = float64(m.cacheHits) / float64(lookups)
+	}
+	for route, hits := range m.cacheHitsByRoute {
+		stats.AnswerCache.HitsByRoute[route] = hits
+	}
+
 	return stats
 }
 
@@ -269,6 +364,10 @@ func (m *memoryMetrics) Reset() {
 	m.responseTimes = make([]int64, 0, m.maxResponseTimeSamples)
 	m.routeStats = make(map[string]*routeStatsInternal)
 	m.errorStats = make(map[string]int64)
+	m.feedbackStats = make(map[string]*FeedbackStats)
+	m.cacheHits = 0
+	m.cacheMisses = 0
+	m.cacheHitsByRoute = make(map[string]int64)
 	m.startTime = time.Now()
 	m.lastUpdate = time.Now()
 }
@@ -0,0 +1,93 @@
+package ordersync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SyncFunc 执行一次租户订单同步
+type SyncFunc func(ctx context.Context, tenantID string) error
+
+// Poller 按租户配置的间隔定时执行订单同步
+type Poller struct {
+	sync      SyncFunc
+	intervals map[string]time.Duration
+	logger    *logrus.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPoller 创建订单同步轮询器
+// intervals: 租户 ID 到同步间隔的映射，间隔不大于 0 的租户不定时同步（仍可手动触发）
+func NewPoller(syncFn SyncFunc, intervals map[string]time.Duration, logger *logrus.Logger) *Poller {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Poller{
+		sync:      syncFn,
+		intervals: intervals,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Start 为每个租户启动定时同步，启动后立即执行一次
+func (p *Poller) Start() {
+	for tenantID, interval := range p.intervals {
+		if interval <= 0 {
+			continue
+		}
+
+		p.wg.Add(1)
+		go func(tenantID string, interval time.Duration) {
+			defer p.wg.Done()
+			p.run(tenantID, interval)
+		}(tenantID, interval)
+	}
+}
+
+// run 执行单个租户的同步循环
+func (p *Poller) run(tenantID string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.sync(p.ctx, tenantID); err != nil && p.ctx.Err() == nil {
+			p.logger.WithError(err).WithField("tenant_id", tenantID).Warn("scheduled order sync failed")
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close 停止定时同步，等待进行中的同步结束，超时后返回错误
+func (p *Poller) Close(timeout time.Duration) error {
+	p.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("order sync poller closed with sync still running")
+	}
+}
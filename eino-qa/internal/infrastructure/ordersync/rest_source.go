@@ -0,0 +1,173 @@
+package ordersync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxResponseSize 单页响应的最大字节数
+const maxResponseSize = 32 << 20
+
+// RESTConfig REST 拉取数据源配置
+// 请求形如 GET {URL}?updated_since=...&page=1&page_size=100，响应为订单数组，
+// 或 {"data": [...], "has_more": true} 形式的对象
+type RESTConfig struct {
+	Name          string            // 数据源名称
+	URL           string            // 订单列表接口地址
+	Token         string            // 以 Authorization: Bearer 发送，为空时不发送
+	Headers       map[string]string // 额外请求头
+	SinceParam    string            // 增量参数名，默认 updated_since
+	SinceLayout   string            // 增量参数时间格式，默认 RFC3339
+	PageParam     string            // 页码参数名，默认 page
+	PageSizeParam string            // 每页数量参数名，默认 page_size
+	PageSize      int               // 每页数量，默认 100
+	DataField     string            // 响应对象中订单数组的字段，默认 data
+	HasMoreField  string            // 响应对象中是否有下一页的字段，默认 has_more；缺失时按返回条数是否等于每页数量判断
+	Timeout       time.Duration     // 单次请求超时，默认 30 秒
+}
+
+// RESTSource 轮询外部电商系统 REST 接口的订单数据源
+type RESTSource struct {
+	config     RESTConfig
+	httpClient *http.Client
+}
+
+// NewRESTSource 创建 REST 订单数据源
+func NewRESTSource(config RESTConfig) (*RESTSource, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("order sync url cannot be empty")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid order sync url: %w", err)
+	}
+
+	if config.Name == "" {
+		config.Name = "rest"
+	}
+	if config.SinceParam == "" {
+		config.SinceParam = "updated_since"
+	}
+	if config.SinceLayout == "" {
+		config.SinceLayout = time.RFC3339
+	}
+	if config.PageParam == "" {
+		config.PageParam = "page"
+	}
+	if config.PageSizeParam == "" {
+		config.PageSizeParam = "page_size"
+	}
+	if config.PageSize <= 0 {
+		config.PageSize = 100
+	}
+	if config.DataField == "" {
+		config.DataField = "data"
+	}
+	if config.HasMoreField == "" {
+		config.HasMoreField = "has_more"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &RESTSource{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Name 数据源名称
+func (s *RESTSource) Name() string {
+	return s.config.Name
+}
+
+// Fetch 拉取一页 since 之后更新的订单
+func (s *RESTSource) Fetch(ctx context.Context, since time.Time, page int) ([]map[string]any, bool, error) {
+	endpoint, err := url.Parse(s.config.URL)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid order sync url: %w", err)
+	}
+
+	query := endpoint.Query()
+	if !since.IsZero() {
+		query.Set(s.config.SinceParam, since.Format(s.config.SinceLayout))
+	}
+	query.Set(s.config.PageParam, strconv.Itoa(page))
+	query.Set(s.config.PageSizeParam, strconv.Itoa(s.config.PageSize))
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+	for key, value := range s.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to request orders: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, false, fmt.Errorf("order source returned status %d", resp.StatusCode)
+	}
+
+	return s.parse(body)
+}
+
+// parse 解析响应中的订单记录和分页标记
+func (s *RESTSource) parse(body []byte) ([]map[string]any, bool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	items, hasMore, explicit := payload, false, false
+	if object, ok := payload.(map[string]any); ok {
+		items = object[s.config.DataField]
+		hasMore, explicit = object[s.config.HasMoreField].(bool)
+	}
+
+	list, ok := items.([]any)
+	if !ok {
+		if items == nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("response field %q is not an array", s.config.DataField)
+	}
+
+	records := make([]map[string]any, 0, len(list))
+	for i, item := range list {
+		record, ok := item.(map[string]any)
+		if !ok {
+			return nil, false, fmt.Errorf("order record %d is not an object", i)
+		}
+		records = append(records, record)
+	}
+
+	if !explicit {
+		hasMore = len(records) >= s.config.PageSize
+	}
+
+	return records, hasMore, nil
+}
@@ -0,0 +1,115 @@
+package ordersync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTSource_Fetch(t *testing.T) {
+	var lastQuery atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Shop-ID") != "42" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		lastQuery.Store(r.URL.Query())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			json.NewEncoder(w).Encode(map[string]any{
+				"orders":   []any{map[string]any{"id": "#20251114001", "amount": 199.5}},
+				"has_more": true,
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"orders": []any{}})
+		}
+	}))
+	defer server.Close()
+
+	source, err := NewRESTSource(RESTConfig{
+		Name:      "shop",
+		URL:       server.URL + "/orders?shop=42",
+		Token:     "secret",
+		Headers:   map[string]string{"X-Shop-ID": "42"},
+		PageSize:  50,
+		DataField: "orders",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "shop", source.Name())
+
+	ctx := context.Background()
+	since := time.Date(2025, 11, 14, 10, 0, 0, 0, time.UTC)
+
+	records, hasMore, err := source.Fetch(ctx, since, 1)
+	require.NoError(t, err)
+	assert.True(t, hasMore)
+	require.Len(t, records, 1)
+	assert.Equal(t, json.Number("199.5"), records[0]["amount"])
+
+	query := lastQuery.Load().(url.Values)
+	assert.Equal(t, []string{"2025-11-14T10:00:00Z"}, query["updated_since"])
+	assert.Equal(t, []string{"50"}, query["page_size"])
+	assert.Equal(t, []string{"42"}, query["shop"])
+
+	records, hasMore, err = source.Fetch(ctx, time.Time{}, 2)
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, records)
+	assert.NotContains(t, lastQuery.Load().(url.Values), "updated_since")
+}
+
+func TestRESTSource_FetchArrayAndErrors(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		// 顶层数组：按返回条数是否等于每页数量判断是否有下一页
+		w.Write([]byte(`[{"id": "#20251114001"}, {"id": "#20251114002"}]`))
+	}))
+	defer server.Close()
+
+	source, err := NewRESTSource(RESTConfig{URL: server.URL, PageSize: 2})
+	require.NoError(t, err)
+
+	records, hasMore, err := source.Fetch(context.Background(), time.Time{}, 1)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.True(t, hasMore)
+
+	status.Store(http.StatusBadGateway)
+	_, _, err = source.Fetch(context.Background(), time.Time{}, 1)
+	assert.ErrorContains(t, err, "502")
+
+	_, err = NewRESTSource(RESTConfig{})
+	assert.Error(t, err)
+}
+
+func TestPoller(t *testing.T) {
+	var calls atomic.Int32
+	poller := NewPoller(func(ctx context.Context, tenantID string) error {
+		assert.Equal(t, "tenant1", tenantID)
+		calls.Add(1)
+		return nil
+	}, map[string]time.Duration{"tenant1": 10 * time.Millisecond, "tenant2": 0}, nil)
+
+	poller.Start()
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, poller.Close(time.Second))
+
+	stopped := calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, calls.Load())
+}
@@ -0,0 +1,81 @@
+package redact
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// LogHook 日志脱敏 hook，按日志中 tenant_id 字段对应的租户策略掩码消息和字符串字段
+// 没有 tenant_id 字段的日志使用全局策略
+type LogHook struct {
+	redactor *Redactor
+}
+
+// NewLogHook 创建日志脱敏 hook
+func NewLogHook(redactor *Redactor) *LogHook {
+	return &LogHook{redactor: redactor}
+}
+
+// Levels 对所有级别生效
+func (h *LogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire 掩码日志消息和字段（logrus 为每条日志复制字段，可以直接修改）
+func (h *LogHook) Fire(entry *logrus.Entry) error {
+	tenantID, _ := entry.Data["tenant_id"].(string)
+	policy := h.redactor.Policy(tenantID)
+	if !policy.Enabled || !policy.MaskLogs {
+		return nil
+	}
+
+	entry.Message = Mask(entry.Message, policy.Types...)
+	for key, value := range entry.Data {
+		if masked, ok := maskValue(value, policy.Types); ok {
+			entry.Data[key] = masked
+		}
+	}
+	return nil
+}
+
+// maskValue 掩码字符串、字符串切片、错误类型和嵌套 map 的字段值，值没有变化时返回 false
+// 嵌套的 map 复制后再修改，不影响调用方传入的 map
+func maskValue(value interface{}, types []Type) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		masked := Mask(v, types...)
+		return masked, masked != v
+	case []string:
+		var result []string
+		for i, s := range v {
+			masked := Mask(s, types...)
+			if masked != s && result == nil {
+				result = append([]string(nil), v...)
+			}
+			if result != nil {
+				result[i] = masked
+			}
+		}
+		return result, result != nil
+	case error:
+		text := v.Error()
+		masked := Mask(text, types...)
+		return masked, masked != text
+	case map[string]interface{}:
+		var result map[string]interface{}
+		for key, item := range v {
+			masked, ok := maskValue(item, types)
+			if !ok {
+				continue
+			}
+			if result == nil {
+				result = make(map[string]interface{}, len(v))
+				for k, original := range v {
+					result[k] = original
+				}
+			}
+			result[key] = masked
+		}
+		return result, result != nil
+	}
+	return nil, false
+}
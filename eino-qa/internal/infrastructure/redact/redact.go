@@ -0,0 +1,394 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Type 个人信息类型
+type Type string
+
+const (
+	TypePhone    Type = "phone"     // 中国大陆手机号，可带 +86 前缀和空格、短横线分隔
+	TypeIDCard   Type = "id_card"   // 18 位居民身份证号（校验码校验）
+	TypeBankCard Type = "bank_card" // 16-19 位银行卡号（Luhn 校验）
+	TypeEmail    Type = "email"     // 邮箱
+)
+
+// AllTypes 支持的全部个人信息类型，按识别顺序排列
+var AllTypes = []Type{TypeIDCard, TypeBankCard, TypePhone, TypeEmail}
+
+// detector 个人信息识别规则
+// 在 SecurityMiddleware 的正则基础上增加校验位和边界检查：Go 正则的 \b 只识别 ASCII 单词边界，
+// 紧邻中文时需要手动检查前后字符，避免把更长的数字串截成手机号或证件号
+type detector struct {
+	pattern  *regexp.Regexp
+	boundary func(r rune) bool // 返回 true 表示该字符不能紧邻匹配结果（避免截取十六进制 ID 等更长的串）
+	validate func(match string) bool
+	mask     func(match string) string
+}
+
+var detectors = map[Type]detector{
+	TypeIDCard: {
+		pattern:  regexp.MustCompile(`[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]`),
+		boundary: isWordChar,
+		validate: validIDCard,
+		mask:     maskIDCard,
+	},
+	TypeBankCard: {
+		pattern:  regexp.MustCompile(`[1-9]\d{3}(?:[ -]?\d{4}){3}(?:[ -]?\d{1,3})?`),
+		boundary: isWordChar,
+		validate: validBankCard,
+		mask:     maskBankCard,
+	},
+	TypePhone: {
+		pattern:  regexp.MustCompile(`(?:(?:\+|00)86[ -]?)?1[3-9]\d(?:[ -]?\d{4}){2}`),
+		boundary: isWordChar,
+		mask:     maskPhone,
+	},
+	TypeEmail: {
+		pattern:  regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		boundary: isEmailChar,
+		mask:     maskEmail,
+	},
+}
+
+// Policy 租户的个人信息脱敏策略
+type Policy struct {
+	Enabled bool
+	Types   []Type // 处理的信息类型，为空表示全部
+
+	TokenizeLLM  bool // 调用模型前替换为占位符，并在回答中还原
+	MaskLogs     bool // 日志中掩码显示
+	MaskSessions bool // 保存会话时掩码消息内容
+}
+
+// PolicyProvider 按租户获取脱敏策略，租户 ID 为空时应返回全局策略
+type PolicyProvider func(tenantID string) Policy
+
+// Redactor 个人信息脱敏服务
+// 模型调用（见 ai/eino 的脱敏聊天模型）、日志（见 LogHook）和会话保存共用同一套识别规则和租户策略
+type Redactor struct {
+	policies PolicyProvider
+}
+
+// New 创建个人信息脱敏服务
+func New(policies PolicyProvider) *Redactor {
+	return &Redactor{policies: policies}
+}
+
+// Policy 获取租户的脱敏策略，未设置策略时不脱敏
+func (r *Redactor) Policy(tenantID string) Policy {
+	if r == nil || r.policies == nil {
+		return Policy{}
+	}
+	return r.policies(tenantID)
+}
+
+// MaskLog 按租户策略掩码日志内容
+func (r *Redactor) MaskLog(tenantID, text string) string {
+	policy := r.Policy(tenantID)
+	if !policy.Enabled || !policy.MaskLogs {
+		return text
+	}
+	return Mask(text, policy.Types...)
+}
+
+// MaskSession 按租户策略掩码会话消息内容
+func (r *Redactor) MaskSession(tenantID, text string) string {
+	policy := r.Policy(tenantID)
+	if !policy.Enabled || !policy.MaskSessions {
+		return text
+	}
+	return Mask(text, policy.Types...)
+}
+
+// NewVault 按租户策略创建模型调用的占位符映射，不需要替换时返回 nil
+func (r *Redactor) NewVault(tenantID string) *Vault {
+	policy := r.Policy(tenantID)
+	if !policy.Enabled || !policy.TokenizeLLM {
+		return nil
+	}
+	return NewVault(policy.Types...)
+}
+
+// Match 识别到的个人信息
+type Match struct {
+	Type  Type
+	Value string
+	Start int // 字节偏移
+	End   int
+}
+
+// Find 识别文本中的个人信息，types 为空表示全部类型，结果按位置排序且互不重叠
+func Find(text string, types ...Type) []Match {
+	if text == "" {
+		return nil
+	}
+
+	var matches []Match
+	for _, t := range selectTypes(types) {
+		d := detectors[t]
+		for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if overlaps(matches, start, end) || !bounded(text, start, end, d.boundary) {
+				continue
+			}
+			value := text[start:end]
+			if d.validate != nil && !d.validate(value) {
+				continue
+			}
+			matches = append(matches, Match{Type: t, Value: value, Start: start, End: end})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// Mask 掩码文本中的个人信息（保留部分字符便于排查，如 138****5678），types 为空表示全部类型
+func Mask(text string, types ...Type) string {
+	return replace(text, Find(text, types...), func(m Match) string {
+		return detectors[m.Type].mask(m.Value)
+	})
+}
+
+// Vault 一次模型调用的占位符映射
+// 同一个值在多条消息中使用相同的占位符，模型回答中的占位符可还原为原值
+type Vault struct {
+	types  []Type
+	tokens map[string]string // 原值 -> 占位符
+	values map[string]string // 占位符 -> 原值
+	counts map[Type]int
+}
+
+// NewVault 创建占位符映射，types 为空表示全部类型
+func NewVault(types ...Type) *Vault {
+	return &Vault{
+		types:  types,
+		tokens: make(map[string]string),
+		values: make(map[string]string),
+		counts: make(map[Type]int),
+	}
+}
+
+// Tokenize 将文本中的个人信息替换为占位符，如 [PHONE_1]
+func (v *Vault) Tokenize(text string) string {
+	return replace(text, Find(text, v.types...), func(m Match) string {
+		if token, ok := v.tokens[m.Value]; ok {
+			return token
+		}
+		v.counts[m.Type]++
+		token := fmt.Sprintf("[%s_%d]", strings.ToUpper(string(m.Type)), v.counts[m.Type])
+		v.tokens[m.Value] = token
+		v.values[token] = m.Value
+		return token
+	})
+}
+
+// Len 已替换的不同个人信息数量
+func (v *Vault) Len() int {
+	return len(v.values)
+}
+
+// Restore 将文本中的占位符还原为原值
+func (v *Vault) Restore(text string) string {
+	if len(v.values) == 0 || !strings.Contains(text, "[") {
+		return text
+	}
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		if value, ok := v.values[token]; ok {
+			return value
+		}
+		return token
+	})
+}
+
+// NewStreamRestorer 创建流式回答的占位符还原器
+func (v *Vault) NewStreamRestorer() *StreamRestorer {
+	return &StreamRestorer{vault: v}
+}
+
+// tokenPattern 占位符格式
+var tokenPattern = regexp.MustCompile(`\[[A-Z_]+_\d+\]`)
+
+// maxTokenLen 占位符的最大长度，超过时不再等待后续分片
+const maxTokenLen = 24
+
+// StreamRestorer 流式回答的占位符还原器
+// 占位符可能被拆到多个分片中，分片末尾未闭合的 [ 之后的内容会暂存到下一个分片
+type StreamRestorer struct {
+	vault   *Vault
+	pending string
+}
+
+// Write 写入一个分片，返回可以输出的已还原内容（可能为空）
+func (s *StreamRestorer) Write(chunk string) string {
+	text := s.pending + chunk
+	s.pending = ""
+
+	if i := strings.LastIndex(text, "["); i >= 0 && !strings.Contains(text[i:], "]") && len(text)-i < maxTokenLen {
+		s.pending = text[i:]
+		text = text[:i]
+	}
+	return s.vault.Restore(text)
+}
+
+// Flush 输出暂存的内容
+func (s *StreamRestorer) Flush() string {
+	text := s.pending
+	s.pending = ""
+	return s.vault.Restore(text)
+}
+
+// replace 按识别结果替换文本
+func replace(text string, matches []Match, fn func(Match) string) string {
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(fn(m))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// selectTypes 按识别顺序返回需要处理的类型
+// 身份证号和银行卡号先于手机号识别，避免长数字串中的片段被识别为手机号
+func selectTypes(types []Type) []Type {
+	if len(types) == 0 {
+		return AllTypes
+	}
+
+	selected := make([]Type, 0, len(types))
+	for _, t := range AllTypes {
+		for _, want := range types {
+			if t == want {
+				selected = append(selected, t)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// overlaps 是否与已识别的结果重叠
+func overlaps(matches []Match, start, end int) bool {
+	for _, m := range matches {
+		if start < m.End && m.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// bounded 匹配结果前后是否没有紧邻的同类字符
+func bounded(text string, start, end int, boundary func(rune) bool) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); boundary(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		if r, _ := utf8.DecodeRuneInString(text[end:]); boundary(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isWordChar(r rune) bool {
+	return isDigit(r) || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+}
+
+func isEmailChar(r rune) bool {
+	return isWordChar(r) || strings.ContainsRune(".%+-@", r)
+}
+
+// digitsOf 去掉分隔符，只保留数字（身份证号保留末位 X）
+func digitsOf(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if isDigit(r) || r == 'X' || r == 'x' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// idCardWeights 身份证号前 17 位的加权因子（GB 11643）
+var idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// validIDCard 校验 18 位身份证号的校验码
+func validIDCard(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+
+	sum := 0
+	for i, w := range idCardWeights {
+		sum += int(s[i]-'0') * w
+	}
+	return "10X98765432"[sum%11] == strings.ToUpper(s[17:])[0]
+}
+
+// validBankCard 校验银行卡号的位数和 Luhn 校验位
+func validBankCard(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 16 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// maskPhone 保留前 3 位和后 4 位，如 +86 138****5678
+func maskPhone(s string) string {
+	digits := digitsOf(s)
+	local := digits[len(digits)-11:]
+	prefix := ""
+	if len(digits) > 11 {
+		prefix = "+86 "
+	}
+	return prefix + local[:3] + "****" + local[7:]
+}
+
+// maskIDCard 保留前 3 位和后 4 位
+func maskIDCard(s string) string {
+	return s[:3] + strings.Repeat("*", len(s)-7) + s[len(s)-4:]
+}
+
+// maskBankCard 保留前 4 位和后 4 位
+func maskBankCard(s string) string {
+	digits := digitsOf(s)
+	return digits[:4] + strings.Repeat("*", len(digits)-8) + digits[len(digits)-4:]
+}
+
+// maskEmail 保留用户名首字符和域名
+func maskEmail(s string) string {
+	at := strings.LastIndex(s, "@")
+	return s[:1] + "***" + s[at:]
+}
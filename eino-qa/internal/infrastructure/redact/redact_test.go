@@ -0,0 +1,121 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"手机号紧邻中文", "手机号13812345678，请回电", "手机号138****5678，请回电"},
+		{"手机号带区号和分隔符", "电话 +86 138-1234-5678", "电话 +86 138****5678"},
+		{"手机号空格分隔", "打 159 1234 5678 找我", "打 159****5678 找我"},
+		{"身份证号", "身份证号：11010519491231002X", "身份证号：110***********002X"},
+		{"身份证号小写校验位", "证件440524188001010014和11010519491231002x", "证件440***********0014和110***********002x"},
+		{"身份证号校验码错误不掩码", "编号110105194912310021", "编号110105194912310021"},
+		{"16 位银行卡号", "卡号6222021234567894", "卡号6222********7894"},
+		{"19 位银行卡号带空格", "卡号 6217 0000 1234 5678 907 已绑定", "卡号 6217***********8907 已绑定"},
+		{"银行卡号 Luhn 校验失败不掩码", "单号6222021234567890", "单号6222021234567890"},
+		{"邮箱", "邮箱user.name@example.com", "邮箱u***@example.com"},
+		{"更长数字串中的片段不掩码", "订单号2025111413812345678", "订单号2025111413812345678"},
+		{"十六进制 ID 中的片段不掩码", "sess_ab13812345678cd", "sess_ab13812345678cd"},
+		{"多种信息", "手机13812345678 卡6222021234567894", "手机138****5678 卡6222********7894"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Mask(tt.input))
+		})
+	}
+
+	// 只处理指定类型
+	assert.Equal(t, "手机138****5678 卡6222021234567894", Mask("手机13812345678 卡6222021234567894", TypePhone))
+}
+
+func TestVault(t *testing.T) {
+	vault := NewVault()
+
+	// 同一个值使用相同的占位符
+	text := vault.Tokenize("我的手机是13812345678，备用15912345678，卡号6222021234567894")
+	assert.Equal(t, "我的手机是[PHONE_1]，备用[PHONE_2]，卡号[BANK_CARD_1]", text)
+	assert.Equal(t, "换绑到[PHONE_2]", vault.Tokenize("换绑到15912345678"))
+	assert.Equal(t, 3, vault.Len())
+
+	// 未知占位符保持原样
+	assert.Equal(t, "已将 13812345678 换绑为 15912345678 [PHONE_9]", vault.Restore("已将 [PHONE_1] 换绑为 [PHONE_2] [PHONE_9]"))
+
+	// 流式分片中被拆开的占位符暂存到完整后还原
+	restorer := vault.NewStreamRestorer()
+	var out string
+	for _, chunk := range []string{"号码 [", "PHO", "NE_1] 已", "绑定 [注意", "] 卡号 [BANK_CARD_1"} {
+		out += restorer.Write(chunk)
+	}
+	assert.Equal(t, "号码 13812345678 已绑定 [注意] 卡号 ", out)
+	assert.Equal(t, "[BANK_CARD_1", restorer.Flush())
+}
+
+func TestRedactor_Policy(t *testing.T) {
+	redactor := New(func(tenantID string) Policy {
+		if tenantID == "tenant1" {
+			return Policy{Enabled: true, Types: []Type{TypeIDCard}, MaskLogs: true}
+		}
+		return Policy{Enabled: true, TokenizeLLM: true, MaskSessions: true}
+	})
+	input := "手机13812345678 身份证11010519491231002X"
+
+	assert.Equal(t, "手机13812345678 身份证110***********002X", redactor.MaskLog("tenant1", input))
+	assert.Equal(t, input, redactor.MaskSession("tenant1", input))
+	assert.Nil(t, redactor.NewVault("tenant1"))
+
+	assert.Equal(t, input, redactor.MaskLog("tenant2", input))
+	assert.Equal(t, "手机138****5678 身份证110***********002X", redactor.MaskSession("tenant2", input))
+	assert.NotNil(t, redactor.NewVault("tenant2"))
+
+	// 未设置策略时不脱敏
+	var none *Redactor
+	assert.Equal(t, input, none.MaskLog("tenant1", input))
+}
+
+func TestLogHook(t *testing.T) {
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&buf)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(NewLogHook(New(func(tenantID string) Policy {
+		return Policy{Enabled: tenantID != "tenant2", MaskLogs: true}
+	})))
+
+	data := map[string]interface{}{"reason": "退款到 a.user@example.com", "attempts": 2}
+	log.WithFields(logrus.Fields{
+		"tenant_id": "tenant1",
+		"query":     "我的手机号13812345678",
+		"queries":   []string{"卡号6222021234567894", "普通问题"},
+		"error":     errors.New("invalid id 11010519491231002X"),
+		"count":     3,
+		"data":      data,
+	}).Info("query from 13812345678")
+	out := buf.String()
+	assert.NotContains(t, out, "13812345678")
+	assert.NotContains(t, out, "6222021234567894")
+	assert.NotContains(t, out, "11010519491231002X")
+	assert.Contains(t, out, "138****5678")
+	assert.Contains(t, out, "6222********7894")
+	assert.Contains(t, out, "110***********002X")
+	assert.Contains(t, out, `"count":3`)
+	assert.NotContains(t, out, "a.user@example.com")
+	assert.Contains(t, out, `"attempts":2`)
+	assert.Equal(t, "退款到 a.user@example.com", data["reason"]) // 不修改调用方的 map
+
+	// 未启用的租户原样记录
+	buf.Reset()
+	log.WithField("tenant_id", "tenant2").Info("query from 13812345678")
+	assert.Contains(t, buf.String(), "13812345678")
+}
@@ -86,8 +86,17 @@ func (m *DBManager) getDBPath(tenantID string) string {
 func (m *DBManager) autoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&OrderModel{},
+		&OrderEventModel{},
+		&OrderSyncStateModel{},
 		&SessionModel{},
 		&MissedQueryModel{},
+		&WebhookEndpointModel{},
+		&WebhookDeadLetterModel{},
+		&FeedbackModel{},
+		&PromptTemplateModel{},
+		&ExperimentOutcomeModel{},
+		&FAQModel{},
+		&QuarantinedDocumentModel{},
 	)
 }
 
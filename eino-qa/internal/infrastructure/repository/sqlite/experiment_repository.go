@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// ExperimentRepository SQLite A/B 实验结果仓储实现
+type ExperimentRepository struct {
+	dbManager *DBManager
+	tenantID  string
+}
+
+// NewExperimentRepository 创建实验结果仓储
+func NewExperimentRepository(dbManager *DBManager, tenantID string) repository.ExperimentRepository {
+	return &ExperimentRepository{
+		dbManager: dbManager,
+		tenantID:  tenantID,
+	}
+}
+
+// getDB 获取当前租户的数据库连接
+func (r *ExperimentRepository) getDB() (*gorm.DB, error) {
+	return r.dbManager.GetDB(r.tenantID)
+}
+
+// SaveOutcome 保存一轮对话的实验结果
+func (r *ExperimentRepository) SaveOutcome(ctx context.Context, outcome *entity.ExperimentOutcome) error {
+	// 确保租户 ID 匹配
+	if outcome.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, outcome.TenantID)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	var model ExperimentOutcomeModel
+	model.FromEntity(outcome)
+
+	if err := db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save experiment outcome: %w", err)
+	}
+	return nil
+}
+
+// Results 按分组汇总实验结果
+// 用户反馈按助手消息 ID 关联 feedback 表，同一消息只有一条反馈
+func (r *ExperimentRepository) Results(ctx context.Context, experiment string, since time.Time) ([]*entity.VariantResult, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.WithContext(ctx).
+		Table("experiment_outcomes AS o").
+		Select(`o.variant AS variant,
+			COUNT(*) AS requests,
+			COALESCE(AVG(o.latency_ms), 0) AS avg_latency_ms,
+			COALESCE(SUM(CASE WHEN o.handoff THEN 1 ELSE 0 END), 0) AS handoffs,
+			COALESCE(SUM(CASE WHEN o.route = ? THEN 1 ELSE 0 END), 0) AS rag_requests,
+			COALESCE(SUM(CASE WHEN o.rag_miss THEN 1 ELSE 0 END), 0) AS rag_misses,
+			COALESCE(SUM(CASE WHEN f.rating = ? THEN 1 ELSE 0 END), 0) AS positive,
+			COALESCE(SUM(CASE WHEN f.rating = ? THEN 1 ELSE 0 END), 0) AS negative`,
+			string(entity.IntentCourse), string(entity.FeedbackUp), string(entity.FeedbackDown)).
+		Joins("LEFT JOIN feedback AS f ON f.message_id = o.message_id AND o.message_id <> ''").
+		Where("o.tenant_id = ? AND o.experiment = ?", r.tenantID, experiment)
+	if !since.IsZero() {
+		query = query.Where("o.created_at >= ?", since)
+	}
+
+	var rows []struct {
+		Variant      string
+		Requests     int64
+		AvgLatencyMs float64
+		Handoffs     int64
+		RAGRequests  int64 `gorm:"column:rag_requests"`
+		RAGMisses    int64 `gorm:"column:rag_misses"`
+		Positive     int64
+		Negative     int64
+	}
+	if err := query.Group("o.variant").Order("o.variant ASC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate experiment results: %w", err)
+	}
+
+	results := make([]*entity.VariantResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, &entity.VariantResult{
+			Variant:      row.Variant,
+			Requests:     row.Requests,
+			AvgLatencyMs: row.AvgLatencyMs,
+			Handoffs:     row.Handoffs,
+			RAGRequests:  row.RAGRequests,
+			RAGMisses:    row.RAGMisses,
+			Positive:     row.Positive,
+			Negative:     row.Negative,
+		})
+	}
+	return results, nil
+}
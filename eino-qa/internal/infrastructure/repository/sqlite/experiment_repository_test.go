@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimentRepository_Results(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "experiment_repo_test_*")
+	require.NoError(t, err)
+
+	dbManager := NewDBManager(tempDir)
+	t.Cleanup(func() {
+		dbManager.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	repo := NewExperimentRepository(dbManager, "tenant1")
+	feedbackRepo := NewFeedbackRepository(dbManager, "tenant1")
+	ctx := context.Background()
+
+	save := func(variant, messageID, route string, latency int64, handoff, miss bool) {
+		outcome := entity.NewExperimentOutcome("tenant1", &entity.ExperimentAssignment{
+			Experiment: "rag_prompt",
+			Variant:    variant,
+		}, "sess_1", messageID, route)
+		outcome.LatencyMs = latency
+		outcome.Handoff = handoff
+		outcome.RAGMiss = miss
+		require.NoError(t, repo.SaveOutcome(ctx, outcome))
+	}
+
+	save("control", "msg_1", "course", 100, false, false)
+	save("control", "msg_2", "course", 300, false, true)
+	save("treatment", "msg_3", "course", 200, true, false)
+	save("treatment", "msg_4", "direct", 400, false, false)
+
+	// 反馈按消息 ID 关联
+	require.NoError(t, feedbackRepo.Save(ctx, entity.NewFeedback("tenant1", "sess_1", "msg_1", entity.FeedbackUp)))
+	require.NoError(t, feedbackRepo.Save(ctx, entity.NewFeedback("tenant1", "sess_1", "msg_3", entity.FeedbackDown)))
+
+	results, err := repo.Results(ctx, "rag_prompt", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	control, treatment := results[0], results[1]
+	assert.Equal(t, "control", control.Variant)
+	assert.Equal(t, int64(2), control.Requests)
+	assert.InDelta(t, 200, control.AvgLatencyMs, 0.01)
+	assert.Equal(t, int64(2), control.RAGRequests)
+	assert.InDelta(t, 0.5, control.RAGMissRate(), 0.001)
+	assert.Equal(t, int64(1), control.Positive)
+	assert.InDelta(t, 1.0, control.SatisfactionRate(), 0.001)
+
+	assert.Equal(t, "treatment", treatment.Variant)
+	assert.InDelta(t, 0.5, treatment.HandoffRate(), 0.001)
+	assert.Equal(t, int64(1), treatment.RAGRequests)
+	assert.Equal(t, int64(1), treatment.Negative)
+
+	// 时间过滤
+	results, err = repo.Results(ctx, "rag_prompt", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
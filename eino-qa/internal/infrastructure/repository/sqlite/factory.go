@@ -27,10 +27,20 @@ func (f *RepositoryFactory) GetSessionRepository(tenantID string) repository.Ses
 }
 
 // GetMissedQueryRepository 获取未命中查询仓储
-func (f *RepositoryFactory) GetMissedQueryRepository(tenantID string) *MissedQueryRepository {
+func (f *RepositoryFactory) GetMissedQueryRepository(tenantID string) repository.MissedQueryRepository {
 	return NewMissedQueryRepository(f.dbManager, tenantID)
 }
 
+// GetWebhookRepository 获取 Webhook 仓储
+func (f *RepositoryFactory) GetWebhookRepository(tenantID string) repository.WebhookRepository {
+	return NewWebhookRepository(f.dbManager, tenantID)
+}
+
+// GetFeedbackRepository 获取用户反馈仓储
+func (f *RepositoryFactory) GetFeedbackRepository(tenantID string) repository.FeedbackRepository {
+	return NewFeedbackRepository(f.dbManager, tenantID)
+}
+
 // GetDBManager 获取数据库管理器
 func (f *RepositoryFactory) GetDBManager() *DBManager {
 	return f.dbManager
@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// FAQRepository SQLite 标准问答仓储实现
+type FAQRepository struct {
+	dbManager *DBManager
+	tenantID  string
+}
+
+// NewFAQRepository 创建标准问答仓储
+func NewFAQRepository(dbManager *DBManager, tenantID string) repository.FAQRepository {
+	return &FAQRepository{
+		dbManager: dbManager,
+		tenantID:  tenantID,
+	}
+}
+
+// getDB 获取当前租户的数据库连接
+func (r *FAQRepository) getDB() (*gorm.DB, error) {
+	return r.dbManager.GetDB(r.tenantID)
+}
+
+// Create 创建标准问答
+func (r *FAQRepository) Create(ctx context.Context, faq *entity.FAQ) error {
+	model, err := r.toModel(faq)
+	if err != nil {
+		return err
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	if err := db.WithContext(ctx).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create faq: %w", err)
+	}
+	return nil
+}
+
+// Update 更新标准问答
+func (r *FAQRepository) Update(ctx context.Context, faq *entity.FAQ) error {
+	model, err := r.toModel(faq)
+	if err != nil {
+		return err
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	// Select("*") 保证 Enabled=false、生效时间清空等零值也会写入
+	result := db.WithContext(ctx).
+		Model(&FAQModel{}).
+		Where("id = ? AND tenant_id = ?", faq.ID, r.tenantID).
+		Select("*").
+		Omit("created_at").
+		Updates(model)
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to update faq: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", entity.ErrFAQNotFound, faq.ID)
+	}
+	return nil
+}
+
+// FindByID 根据 ID 查询标准问答
+func (r *FAQRepository) FindByID(ctx context.Context, id string) (*entity.FAQ, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model FAQModel
+	result := db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, r.tenantID).
+		First(&model)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", entity.ErrFAQNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find faq: %w", result.Error)
+	}
+
+	faq, err := model.ToEntity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert faq: %w", err)
+	}
+	return faq, nil
+}
+
+// List 列出租户的所有标准问答
+func (r *FAQRepository) List(ctx context.Context) ([]*entity.FAQ, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []FAQModel
+	result := db.WithContext(ctx).
+		Where("tenant_id = ?", r.tenantID).
+		Order("created_at ASC").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list faqs: %w", result.Error)
+	}
+
+	faqs := make([]*entity.FAQ, 0, len(models))
+	for i := range models {
+		faq, err := models[i].ToEntity()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert faq: %w", err)
+		}
+		faqs = append(faqs, faq)
+	}
+
+	return faqs, nil
+}
+
+// Delete 删除标准问答
+func (r *FAQRepository) Delete(ctx context.Context, id string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	result := db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, r.tenantID).
+		Delete(&FAQModel{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete faq: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", entity.ErrFAQNotFound, id)
+	}
+	return nil
+}
+
+// toModel 校验并转换为 GORM 模型
+func (r *FAQRepository) toModel(faq *entity.FAQ) (*FAQModel, error) {
+	if err := faq.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid faq: %w", err)
+	}
+
+	// 确保租户 ID 匹配
+	if faq.TenantID != r.tenantID {
+		return nil, fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, faq.TenantID)
+	}
+
+	var model FAQModel
+	if err := model.FromEntity(faq); err != nil {
+		return nil, fmt.Errorf("failed to convert faq: %w", err)
+	}
+	return &model, nil
+}
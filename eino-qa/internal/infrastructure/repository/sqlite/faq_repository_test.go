@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFAQRepository_CRUD(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "faq_repo_test_*")
+	require.NoError(t, err)
+
+	dbManager := NewDBManager(tempDir)
+	t.Cleanup(func() {
+		dbManager.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	repo := NewFAQRepository(dbManager, "tenant1")
+	ctx := context.Background()
+
+	from := time.Now().Add(time.Hour).Truncate(time.Second)
+	refund := entity.NewFAQ("tenant1", []string{"退款政策是什么", "怎么退款"}, "购买 7 天内未学习可全额退款。")
+	refund.Vectors = [][]float32{{0.1, 0.2}, {0.3, 0.4}}
+	refund.EffectiveFrom = &from
+	require.NoError(t, repo.Create(ctx, refund))
+
+	price := entity.NewFAQ("tenant1", []string{"会员多少钱"}, "年度会员 699 元。")
+	require.NoError(t, repo.Create(ctx, price))
+
+	// 其他租户的问答不可见，租户不匹配时拒绝写入
+	require.Error(t, NewFAQRepository(dbManager, "tenant2").Create(ctx, entity.NewFAQ("tenant1", []string{"q"}, "a")))
+	others, err := NewFAQRepository(dbManager, "tenant2").List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, others)
+
+	found, err := repo.FindByID(ctx, refund.ID)
+	require.NoError(t, err)
+	assert.Equal(t, refund.Questions, found.Questions)
+	assert.Equal(t, refund.Vectors, found.Vectors)
+	require.NotNil(t, found.EffectiveFrom)
+	assert.True(t, from.Equal(*found.EffectiveFrom))
+	assert.Nil(t, found.EffectiveTo)
+
+	// 更新时零值（停用、清空生效时间和向量）也会写入
+	found.Enabled = false
+	found.EffectiveFrom = nil
+	found.Vectors = nil
+	found.Answer = "购买 14 天内未学习可全额退款。"
+	require.NoError(t, repo.Update(ctx, found))
+
+	updated, err := repo.FindByID(ctx, refund.ID)
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Nil(t, updated.EffectiveFrom)
+	assert.Empty(t, updated.Vectors)
+	assert.Equal(t, "购买 14 天内未学习可全额退款。", updated.Answer)
+
+	faqs, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, faqs, 2)
+	assert.Equal(t, refund.ID, faqs[0].ID)
+
+	require.NoError(t, repo.Delete(ctx, price.ID))
+	assert.ErrorIs(t, repo.Delete(ctx, price.ID), entity.ErrFAQNotFound)
+	_, err = repo.FindByID(ctx, price.ID)
+	assert.ErrorIs(t, err, entity.ErrFAQNotFound)
+
+	missing := entity.NewFAQ("tenant1", []string{"q"}, "a")
+	assert.ErrorIs(t, repo.Update(ctx, missing), entity.ErrFAQNotFound)
+}
@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// FeedbackRepository SQLite 用户反馈仓储实现
+type FeedbackRepository struct {
+	dbManager *DBManager
+	tenantID  string
+}
+
+// NewFeedbackRepository 创建用户反馈仓储
+func NewFeedbackRepository(dbManager *DBManager, tenantID string) repository.FeedbackRepository {
+	return &FeedbackRepository{
+		dbManager: dbManager,
+		tenantID:  tenantID,
+	}
+}
+
+// getDB 获取当前租户的数据库连接
+func (r *FeedbackRepository) getDB() (*gorm.DB, error) {
+	return r.dbManager.GetDB(r.tenantID)
+}
+
+// Save 保存反馈（存在则更新）
+func (r *FeedbackRepository) Save(ctx context.Context, feedback *entity.Feedback) error {
+	if err := feedback.Validate(); err != nil {
+		return fmt.Errorf("invalid feedback: %w", err)
+	}
+
+	// 确保租户 ID 匹配
+	if feedback.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, feedback.TenantID)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	var model FeedbackModel
+	model.FromEntity(feedback)
+
+	result := db.WithContext(ctx).Save(&model)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save feedback: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindByID 根据 ID 查询反馈
+func (r *FeedbackRepository) FindByID(ctx context.Context, id string) (*entity.Feedback, error) {
+	return r.findOne(ctx, "id = ? AND tenant_id = ?", id)
+}
+
+// FindByMessageID 根据消息 ID 查询反馈
+func (r *FeedbackRepository) FindByMessageID(ctx context.Context, messageID string) (*entity.Feedback, error) {
+	return r.findOne(ctx, "message_id = ? AND tenant_id = ?", messageID)
+}
+
+// findOne 按条件查询单条反馈
+func (r *FeedbackRepository) findOne(ctx context.Context, query string, value string) (*entity.Feedback, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model FeedbackModel
+	result := db.WithContext(ctx).Where(query, value, r.tenantID).First(&model)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", entity.ErrFeedbackNotFound, value)
+		}
+		return nil, fmt.Errorf("failed to find feedback: %w", result.Error)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// List 列出反馈（支持过滤和分页）
+func (r *FeedbackRepository) List(ctx context.Context, filter repository.FeedbackFilter, offset, limit int) ([]*entity.Feedback, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.WithContext(ctx).Where("tenant_id = ?", r.tenantID)
+	if filter.Rating != "" {
+		query = query.Where("rating = ?", string(filter.Rating))
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.HasCorrection {
+		query = query.Where("correction <> ''")
+	}
+
+	var models []FeedbackModel
+	result := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", result.Error)
+	}
+
+	feedbacks := make([]*entity.Feedback, 0, len(models))
+	for i := range models {
+		feedbacks = append(feedbacks, models[i].ToEntity())
+	}
+
+	return feedbacks, nil
+}
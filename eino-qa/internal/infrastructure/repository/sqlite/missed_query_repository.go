@@ -6,16 +6,10 @@ import (
 	"time"
 
 	"gorm.io/gorm"
-)
 
-// MissedQuery 未命中查询实体
-type MissedQuery struct {
-	ID        uint
-	TenantID  string
-	Query     string
-	Intent    string
-	CreatedAt time.Time
-}
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
 
 // MissedQueryRepository 未命中查询仓储
 type MissedQueryRepository struct {
@@ -24,7 +18,7 @@ type MissedQueryRepository struct {
 }
 
 // NewMissedQueryRepository 创建未命中查询仓储
-func NewMissedQueryRepository(dbManager *DBManager, tenantID string) *MissedQueryRepository {
+func NewMissedQueryRepository(dbManager *DBManager, tenantID string) repository.MissedQueryRepository {
 	return &MissedQueryRepository{
 		dbManager: dbManager,
 		tenantID:  tenantID,
@@ -37,28 +31,35 @@ func (r *MissedQueryRepository) getDB() (*gorm.DB, error) {
 }
 
 // Create 创建未命中查询记录
-func (r *MissedQueryRepository) Create(ctx context.Context, query, intent string) error {
+func (r *MissedQueryRepository) Create(ctx context.Context, query *entity.MissedQuery) error {
+	if err := query.Validate(); err != nil {
+		return fmt.Errorf("invalid missed query: %w", err)
+	}
+
+	// 确保租户 ID 匹配
+	if query.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, query.TenantID)
+	}
+
 	db, err := r.getDB()
 	if err != nil {
 		return err
 	}
 
-	model := MissedQueryModel{
-		TenantID: r.tenantID,
-		Query:    query,
-		Intent:   intent,
-	}
+	var model MissedQueryModel
+	model.FromEntity(query)
 
 	result := db.WithContext(ctx).Create(&model)
 	if result.Error != nil {
 		return fmt.Errorf("failed to create missed query: %w", result.Error)
 	}
 
+	query.ID = model.ID
 	return nil
 }
 
 // List 列出未命中查询（支持分页）
-func (r *MissedQueryRepository) List(ctx context.Context, offset, limit int) ([]*MissedQuery, error) {
+func (r *MissedQueryRepository) List(ctx context.Context, offset, limit int) ([]*entity.MissedQuery, error) {
 	db, err := r.getDB()
 	if err != nil {
 		return nil, err
@@ -76,18 +77,31 @@ func (r *MissedQueryRepository) List(ctx context.Context, offset, limit int) ([]
 		return nil, fmt.Errorf("failed to list missed queries: %w", result.Error)
 	}
 
-	queries := make([]*MissedQuery, 0, len(models))
-	for _, model := range models {
-		queries = append(queries, &MissedQuery{
-			ID:        model.ID,
-			TenantID:  model.TenantID,
-			Query:     model.Query,
-			Intent:    model.Intent,
-			CreatedAt: model.CreatedAt,
-		})
+	return toMissedQueryEntities(models), nil
+}
+
+// FindByIDs 根据 ID 批量查询未命中记录
+func (r *MissedQueryRepository) FindByIDs(ctx context.Context, ids []uint) ([]*entity.MissedQuery, error) {
+	if len(ids) == 0 {
+		return []*entity.MissedQuery{}, nil
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []MissedQueryModel
+	result := db.WithContext(ctx).
+		Where("id IN ? AND tenant_id = ?", ids, r.tenantID).
+		Order("created_at DESC").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find missed queries: %w", result.Error)
 	}
 
-	return queries, nil
+	return toMissedQueryEntities(models), nil
 }
 
 // Count 获取未命中查询总数
@@ -127,3 +141,12 @@ func (r *MissedQueryRepository) DeleteOlderThan(ctx context.Context, before time
 
 	return int(result.RowsAffected), nil
 }
+
+// toMissedQueryEntities 批量转换为领域实体
+func toMissedQueryEntities(models []MissedQueryModel) []*entity.MissedQuery {
+	queries := make([]*entity.MissedQuery, 0, len(models))
+	for i := range models {
+		queries = append(queries, models[i].ToEntity())
+	}
+	return queries
+}
@@ -72,6 +72,109 @@ func (m *OrderModel) FromEntity(order *entity.Order) error {
 	return nil
 }
 
+// OrderEventModel GORM 订单事件模型
+type OrderEventModel struct {
+	ID         string    `gorm:"primaryKey;type:varchar(50)"`
+	OrderID    string    `gorm:"type:varchar(50);index;not null"`
+	TenantID   string    `gorm:"type:varchar(100);index;not null"`
+	FromStatus string    `gorm:"type:varchar(20)"`
+	ToStatus   string    `gorm:"type:varchar(20);not null"`
+	Actor      string    `gorm:"type:varchar(100);not null"`
+	Reason     string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+// TableName 指定表名
+func (OrderEventModel) TableName() string {
+	return "order_events"
+}
+
+// ToEntity 转换为领域实体
+func (m *OrderEventModel) ToEntity() *entity.OrderEvent {
+	return &entity.OrderEvent{
+		ID:         m.ID,
+		OrderID:    m.OrderID,
+		TenantID:   m.TenantID,
+		FromStatus: entity.OrderStatus(m.FromStatus),
+		ToStatus:   entity.OrderStatus(m.ToStatus),
+		Actor:      m.Actor,
+		Reason:     m.Reason,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// FromEntity 从领域实体转换
+func (m *OrderEventModel) FromEntity(event *entity.OrderEvent) {
+	m.ID = event.ID
+	m.OrderID = event.OrderID
+	m.TenantID = event.TenantID
+	m.FromStatus = string(event.FromStatus)
+	m.ToStatus = string(event.ToStatus)
+	m.Actor = event.Actor
+	m.Reason = event.Reason
+	m.CreatedAt = event.CreatedAt
+}
+
+// OrderSyncStateModel GORM 订单同步状态模型
+type OrderSyncStateModel struct {
+	Source         string `gorm:"primaryKey;type:varchar(100)"`
+	TenantID       string `gorm:"type:varchar(100);index;not null"`
+	Status         string `gorm:"type:varchar(20);not null"`
+	Cursor         time.Time
+	LastStartedAt  time.Time
+	LastFinishedAt time.Time
+	LastSuccessAt  time.Time
+	LastError      string `gorm:"type:text"`
+	Total          int
+	Created        int
+	Updated        int
+	Unchanged      int
+	Failed         int
+}
+
+// TableName 指定表名
+func (OrderSyncStateModel) TableName() string {
+	return "order_sync_states"
+}
+
+// ToEntity 转换为领域实体
+func (m *OrderSyncStateModel) ToEntity() *entity.OrderSyncState {
+	return &entity.OrderSyncState{
+		TenantID:       m.TenantID,
+		Source:         m.Source,
+		Status:         entity.OrderSyncStatus(m.Status),
+		Cursor:         m.Cursor,
+		LastStartedAt:  m.LastStartedAt,
+		LastFinishedAt: m.LastFinishedAt,
+		LastSuccessAt:  m.LastSuccessAt,
+		LastError:      m.LastError,
+		LastStats: entity.OrderImportStats{
+			Total:     m.Total,
+			Created:   m.Created,
+			Updated:   m.Updated,
+			Unchanged: m.Unchanged,
+			Failed:    m.Failed,
+		},
+	}
+}
+
+// FromEntity 从领域实体转换
+func (m *OrderSyncStateModel) FromEntity(state *entity.OrderSyncState) {
+	m.Source = state.Source
+	m.TenantID = state.TenantID
+	m.Status = string(state.Status)
+	m.Cursor = state.Cursor
+	m.LastStartedAt = state.LastStartedAt
+	m.LastFinishedAt = state.LastFinishedAt
+	m.LastSuccessAt = state.LastSuccessAt
+	m.LastError = state.LastError
+	m.Total = state.LastStats.Total
+	m.Created = state.LastStats.Created
+	m.Updated = state.LastStats.Updated
+	m.Unchanged = state.LastStats.Unchanged
+	m.Failed = state.LastStats.Failed
+}
+
 // SessionModel GORM 会话模型
 type SessionModel struct {
 	ID        string    `gorm:"primaryKey;type:varchar(100)"`
@@ -148,14 +251,443 @@ func (m *SessionModel) FromEntity(session *entity.Session) error {
 
 // MissedQueryModel GORM 未命中查询模型
 type MissedQueryModel struct {
-	ID        uint      `gorm:"primaryKey;autoIncrement"`
-	TenantID  string    `gorm:"type:varchar(100);index;not null"`
-	Query     string    `gorm:"type:text;not null"`
-	Intent    string    `gorm:"type:varchar(50)"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
+	ID             uint      `gorm:"primaryKey;autoIncrement"`
+	TenantID       string    `gorm:"type:varchar(100);index;not null"`
+	Query          string    `gorm:"type:text;not null"`
+	RewrittenQuery string    `gorm:"type:text"`
+	Intent         string    `gorm:"type:varchar(50)"`
+	TopScore       float64   `gorm:"default:0"`
+	CreatedAt      time.Time `gorm:"autoCreateTime;index"`
 }
 
 // TableName 指定表名
 func (MissedQueryModel) TableName() string {
 	return "missed_queries"
 }
+
+// ToEntity 转换为领域实体
+func (m *MissedQueryModel) ToEntity() *entity.MissedQuery {
+	return &entity.MissedQuery{
+		ID:             m.ID,
+		TenantID:       m.TenantID,
+		Query:          m.Query,
+		RewrittenQuery: m.RewrittenQuery,
+		Intent:         m.Intent,
+		TopScore:       m.TopScore,
+		CreatedAt:      m.CreatedAt,
+	}
+}
+
+// FromEntity 从领域实体转换
+func (m *MissedQueryModel) FromEntity(query *entity.MissedQuery) {
+	m.ID = query.ID
+	m.TenantID = query.TenantID
+	m.Query = query.Query
+	m.RewrittenQuery = query.RewrittenQuery
+	m.Intent = query.Intent
+	m.TopScore = query.TopScore
+	m.CreatedAt = query.CreatedAt
+}
+
+// WebhookEndpointModel GORM Webhook 端点模型
+type WebhookEndpointModel struct {
+	ID        string    `gorm:"primaryKey;type:varchar(50)"`
+	TenantID  string    `gorm:"type:varchar(100);index;not null"`
+	URL       string    `gorm:"type:varchar(500);not null"`
+	Secret    string    `gorm:"type:varchar(100);not null"`
+	Events    string    `gorm:"type:text;not null"`
+	Enabled   bool      `gorm:"not null;default:true"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (WebhookEndpointModel) TableName() string {
+	return "webhook_endpoints"
+}
+
+// ToEntity 转换为领域实体
+func (m *WebhookEndpointModel) ToEntity() (*entity.WebhookEndpoint, error) {
+	endpoint := &entity.WebhookEndpoint{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		URL:       m.URL,
+		Secret:    m.Secret,
+		Events:    make([]entity.WebhookEventType, 0),
+		Enabled:   m.Enabled,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+
+	// 解析 Events JSON
+	if m.Events != "" {
+		if err := json.Unmarshal([]byte(m.Events), &endpoint.Events); err != nil {
+			return nil, err
+		}
+	}
+
+	return endpoint, nil
+}
+
+// FromEntity 从领域实体创建
+func (m *WebhookEndpointModel) FromEntity(endpoint *entity.WebhookEndpoint) error {
+	m.ID = endpoint.ID
+	m.TenantID = endpoint.TenantID
+	m.URL = endpoint.URL
+	m.Secret = endpoint.Secret
+	m.Enabled = endpoint.Enabled
+	m.CreatedAt = endpoint.CreatedAt
+	m.UpdatedAt = endpoint.UpdatedAt
+
+	// 序列化 Events
+	eventsBytes, err := json.Marshal(endpoint.Events)
+	if err != nil {
+		return err
+	}
+	m.Events = string(eventsBytes)
+
+	return nil
+}
+
+// WebhookDeadLetterModel GORM Webhook 死信模型
+type WebhookDeadLetterModel struct {
+	ID         string    `gorm:"primaryKey;type:varchar(50)"`
+	TenantID   string    `gorm:"type:varchar(100);index;not null"`
+	EndpointID string    `gorm:"type:varchar(50);index;not null"`
+	EventID    string    `gorm:"type:varchar(50);not null"`
+	EventType  string    `gorm:"type:varchar(50);index;not null"`
+	Payload    string    `gorm:"type:text;not null"`
+	Attempts   int       `gorm:"not null"`
+	LastError  string    `gorm:"type:text"`
+	Status     string    `gorm:"type:varchar(20);index;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (WebhookDeadLetterModel) TableName() string {
+	return "webhook_dead_letters"
+}
+
+// ToEntity 转换为领域实体
+func (m *WebhookDeadLetterModel) ToEntity() *entity.WebhookDelivery {
+	return &entity.WebhookDelivery{
+		ID:         m.ID,
+		TenantID:   m.TenantID,
+		EndpointID: m.EndpointID,
+		EventID:    m.EventID,
+		EventType:  entity.WebhookEventType(m.EventType),
+		Payload:    m.Payload,
+		Attempts:   m.Attempts,
+		LastError:  m.LastError,
+		Status:     entity.WebhookDeliveryStatus(m.Status),
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+// FromEntity 从领域实体创建
+func (m *WebhookDeadLetterModel) FromEntity(delivery *entity.WebhookDelivery) {
+	m.ID = delivery.ID
+	m.TenantID = delivery.TenantID
+	m.EndpointID = delivery.EndpointID
+	m.EventID = delivery.EventID
+	m.EventType = string(delivery.EventType)
+	m.Payload = delivery.Payload
+	m.Attempts = delivery.Attempts
+	m.LastError = delivery.LastError
+	m.Status = string(delivery.Status)
+	m.CreatedAt = delivery.CreatedAt
+	m.UpdatedAt = delivery.UpdatedAt
+}
+
+// FeedbackModel GORM 用户反馈模型
+type FeedbackModel struct {
+	ID         string    `gorm:"primaryKey;type:varchar(50)"`
+	TenantID   string    `gorm:"type:varchar(100);index;not null"`
+	SessionID  string    `gorm:"type:varchar(100);index;not null"`
+	MessageID  string    `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Rating     string    `gorm:"type:varchar(10);index;not null"`
+	Reason     string    `gorm:"type:varchar(50)"`
+	Comment    string    `gorm:"type:text"`
+	Correction string    `gorm:"type:text"`
+	Query      string    `gorm:"type:text"`
+	Answer     string    `gorm:"type:text"`
+	Route      string    `gorm:"type:varchar(50);index"`
+	Intent     string    `gorm:"type:varchar(50);index"`
+	Status     string    `gorm:"type:varchar(20);index;not null"`
+	DocumentID string    `gorm:"type:varchar(100)"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (FeedbackModel) TableName() string {
+	return "feedback"
+}
+
+// ToEntity 转换为领域实体
+func (m *FeedbackModel) ToEntity() *entity.Feedback {
+	return &entity.Feedback{
+		ID:         m.ID,
+		TenantID:   m.TenantID,
+		SessionID:  m.SessionID,
+		MessageID:  m.MessageID,
+		Rating:     entity.FeedbackRating(m.Rating),
+		Reason:     entity.FeedbackReason(m.Reason),
+		Comment:    m.Comment,
+		Correction: m.Correction,
+		Query:      m.Query,
+		Answer:     m.Answer,
+		Route:      m.Route,
+		Intent:     m.Intent,
+		Status:     entity.FeedbackStatus(m.Status),
+		DocumentID: m.DocumentID,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+// FromEntity 从领域实体转换
+func (m *FeedbackModel) FromEntity(feedback *entity.Feedback) {
+	m.ID = feedback.ID
+	m.TenantID = feedback.TenantID
+	m.SessionID = feedback.SessionID
+	m.MessageID = feedback.MessageID
+	m.Rating = string(feedback.Rating)
+	m.Reason = string(feedback.Reason)
+	m.Comment = feedback.Comment
+	m.Correction = feedback.Correction
+	m.Query = feedback.Query
+	m.Answer = feedback.Answer
+	m.Route = feedback.Route
+	m.Intent = feedback.Intent
+	m.Status = string(feedback.Status)
+	m.DocumentID = feedback.DocumentID
+	m.CreatedAt = feedback.CreatedAt
+	m.UpdatedAt = feedback.UpdatedAt
+}
+
+// PromptTemplateModel GORM 提示词模板版本模型
+type PromptTemplateModel struct {
+	ID          string     `gorm:"primaryKey;type:varchar(50)"`
+	TenantID    string     `gorm:"type:varchar(100);uniqueIndex:idx_prompt_version;not null"`
+	Name        string     `gorm:"type:varchar(50);uniqueIndex:idx_prompt_version;not null"`
+	Version     int        `gorm:"uniqueIndex:idx_prompt_version;not null"`
+	Content     string     `gorm:"type:text;not null"`
+	Comment     string     `gorm:"type:varchar(500)"`
+	Active      bool       `gorm:"index;not null;default:false"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	ActivatedAt *time.Time `gorm:"default:null"`
+}
+
+// TableName 指定表名
+func (PromptTemplateModel) TableName() string {
+	return "prompt_templates"
+}
+
+// ToEntity 转换为领域实体
+func (m *PromptTemplateModel) ToEntity() *entity.PromptTemplate {
+	return &entity.PromptTemplate{
+		ID:          m.ID,
+		TenantID:    m.TenantID,
+		Name:        entity.PromptName(m.Name),
+		Version:     m.Version,
+		Content:     m.Content,
+		Comment:     m.Comment,
+		Active:      m.Active,
+		CreatedAt:   m.CreatedAt,
+		ActivatedAt: m.ActivatedAt,
+	}
+}
+
+// FromEntity 从领域实体转换
+func (m *PromptTemplateModel) FromEntity(template *entity.PromptTemplate) {
+	m.ID = template.ID
+	m.TenantID = template.TenantID
+	m.Name = string(template.Name)
+	m.Version = template.Version
+	m.Content = template.Content
+	m.Comment = template.Comment
+	m.Active = template.Active
+	m.CreatedAt = template.CreatedAt
+	m.ActivatedAt = template.ActivatedAt
+}
+
+// ExperimentOutcomeModel GORM 实验结果模型
+type ExperimentOutcomeModel struct {
+	ID         string    `gorm:"primaryKey;type:varchar(50)"`
+	TenantID   string    `gorm:"type:varchar(100);index:idx_experiment_variant;not null"`
+	Experiment string    `gorm:"type:varchar(100);index:idx_experiment_variant;not null"`
+	Variant    string    `gorm:"type:varchar(100);index:idx_experiment_variant;not null"`
+	SessionID  string    `gorm:"type:varchar(100);index;not null"`
+	MessageID  string    `gorm:"type:varchar(100);index"`
+	Route      string    `gorm:"type:varchar(50)"`
+	LatencyMs  int64     `gorm:"not null;default:0"`
+	Handoff    bool      `gorm:"not null;default:false"`
+	RAGMiss    bool      `gorm:"column:rag_miss;not null;default:false"`
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+// TableName 指定表名
+func (ExperimentOutcomeModel) TableName() string {
+	return "experiment_outcomes"
+}
+
+// FromEntity 从领域实体转换
+func (m *ExperimentOutcomeModel) FromEntity(outcome *entity.ExperimentOutcome) {
+	m.ID = outcome.ID
+	m.TenantID = outcome.TenantID
+	m.Experiment = outcome.Experiment
+	m.Variant = outcome.Variant
+	m.SessionID = outcome.SessionID
+	m.MessageID = outcome.MessageID
+	m.Route = outcome.Route
+	m.LatencyMs = outcome.LatencyMs
+	m.Handoff = outcome.Handoff
+	m.RAGMiss = outcome.RAGMiss
+	m.CreatedAt = outcome.CreatedAt
+}
+
+// FAQModel GORM 标准问答模型
+type FAQModel struct {
+	ID            string     `gorm:"primaryKey;type:varchar(50)"`
+	TenantID      string     `gorm:"type:varchar(100);index;not null"`
+	Questions     string     `gorm:"type:text;not null"` // JSON 数组
+	Vectors       string     `gorm:"type:text"`          // JSON 数组，与 Questions 一一对应
+	Answer        string     `gorm:"type:text;not null"`
+	EffectiveFrom *time.Time `gorm:"default:null"`
+	EffectiveTo   *time.Time `gorm:"default:null"`
+	Enabled       bool       `gorm:"index;not null;default:true"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (FAQModel) TableName() string {
+	return "faqs"
+}
+
+// ToEntity 转换为领域实体
+func (m *FAQModel) ToEntity() (*entity.FAQ, error) {
+	faq := &entity.FAQ{
+		ID:            m.ID,
+		TenantID:      m.TenantID,
+		Answer:        m.Answer,
+		EffectiveFrom: m.EffectiveFrom,
+		EffectiveTo:   m.EffectiveTo,
+		Enabled:       m.Enabled,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(m.Questions), &faq.Questions); err != nil {
+		return nil, err
+	}
+	if m.Vectors != "" {
+		if err := json.Unmarshal([]byte(m.Vectors), &faq.Vectors); err != nil {
+			return nil, err
+		}
+	}
+
+	return faq, nil
+}
+
+// FromEntity 从领域实体转换
+func (m *FAQModel) FromEntity(faq *entity.FAQ) error {
+	m.ID = faq.ID
+	m.TenantID = faq.TenantID
+	m.Answer = faq.Answer
+	m.EffectiveFrom = faq.EffectiveFrom
+	m.EffectiveTo = faq.EffectiveTo
+	m.Enabled = faq.Enabled
+	m.CreatedAt = faq.CreatedAt
+	m.UpdatedAt = faq.UpdatedAt
+
+	questions, err := json.Marshal(faq.Questions)
+	if err != nil {
+		return err
+	}
+	m.Questions = string(questions)
+
+	m.Vectors = ""
+	if len(faq.Vectors) > 0 {
+		vectors, err := json.Marshal(faq.Vectors)
+		if err != nil {
+			return err
+		}
+		m.Vectors = string(vectors)
+	}
+
+	return nil
+}
+
+// QuarantinedDocumentModel GORM 隔离文档模型
+type QuarantinedDocumentModel struct {
+	ID        string    `gorm:"primaryKey;type:varchar(50)"`
+	TenantID  string    `gorm:"type:varchar(100);index;not null"`
+	Content   string    `gorm:"type:text;not null"`
+	Metadata  string    `gorm:"type:text"` // JSON 对象
+	Score     float64   `gorm:"not null"`
+	Rules     string    `gorm:"type:text"` // JSON 数组
+	Method    string    `gorm:"type:varchar(20)"`
+	Reason    string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+// TableName 指定表名
+func (QuarantinedDocumentModel) TableName() string {
+	return "quarantined_documents"
+}
+
+// ToEntity 转换为领域实体
+func (m *QuarantinedDocumentModel) ToEntity() (*entity.QuarantinedDocument, error) {
+	doc := &entity.QuarantinedDocument{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Content:   m.Content,
+		Metadata:  make(map[string]any),
+		Score:     m.Score,
+		Method:    m.Method,
+		Reason:    m.Reason,
+		CreatedAt: m.CreatedAt,
+	}
+
+	if m.Metadata != "" {
+		if err := json.Unmarshal([]byte(m.Metadata), &doc.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	if m.Rules != "" {
+		if err := json.Unmarshal([]byte(m.Rules), &doc.Rules); err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// FromEntity 从领域实体转换
+func (m *QuarantinedDocumentModel) FromEntity(doc *entity.QuarantinedDocument) error {
+	m.ID = doc.ID
+	m.TenantID = doc.TenantID
+	m.Content = doc.Content
+	m.Score = doc.Score
+	m.Method = doc.Method
+	m.Reason = doc.Reason
+	m.CreatedAt = doc.CreatedAt
+
+	metadata, err := json.Marshal(doc.Metadata)
+	if err != nil {
+		return err
+	}
+	m.Metadata = string(metadata)
+
+	rules, err := json.Marshal(doc.Rules)
+	if err != nil {
+		return err
+	}
+	m.Rules = string(rules)
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -11,6 +12,9 @@ import (
 	"eino-qa/internal/domain/repository"
 )
 
+// orderCreationActor 订单创建事件的操作者
+const orderCreationActor = "system"
+
 // OrderRepository SQLite 订单仓储实现
 type OrderRepository struct {
 	dbManager *DBManager
@@ -31,6 +35,7 @@ func (r *OrderRepository) getDB() (*gorm.DB, error) {
 }
 
 // FindByID 根据订单 ID 查询订单
+// 订单 ID 会先按租户的订单号方案规范化（如补全前缀、统一大小写）
 func (r *OrderRepository) FindByID(ctx context.Context, orderID string) (*entity.Order, error) {
 	db, err := r.getDB()
 	if err != nil {
@@ -38,11 +43,11 @@ func (r *OrderRepository) FindByID(ctx context.Context, orderID string) (*entity
 	}
 
 	var model OrderModel
-	result := db.WithContext(ctx).Where("id = ? AND tenant_id = ?", orderID, r.tenantID).First(&model)
+	result := db.WithContext(ctx).Where("id = ? AND tenant_id = ?", r.normalizeOrderID(orderID), r.tenantID).First(&model)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("order not found: %s", orderID)
+			return nil, entity.ErrOrderNotFound
 		}
 		return nil, fmt.Errorf("failed to find order: %w", result.Error)
 	}
@@ -108,6 +113,97 @@ func (r *OrderRepository) FindByStatus(ctx context.Context, status entity.OrderS
 	return orders, nil
 }
 
+// FindByFilter 按过滤条件查询订单列表
+func (r *OrderRepository) FindByFilter(ctx context.Context, filter repository.OrderFilter) ([]*entity.Order, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.applyFilter(db.WithContext(ctx).Model(&OrderModel{}), filter).
+		Order("created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var models []OrderModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to find orders by filter: %w", err)
+	}
+
+	orders := make([]*entity.Order, 0, len(models))
+	for _, model := range models {
+		order, err := model.ToEntity()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert order model: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// Aggregate 按过滤条件统计订单数量和总金额
+func (r *OrderRepository) Aggregate(ctx context.Context, filter repository.OrderFilter) (*repository.OrderAggregate, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var row struct {
+		Count       int64
+		TotalAmount float64
+	}
+	result := r.applyFilter(db.WithContext(ctx).Model(&OrderModel{}), filter).
+		Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total_amount").
+		Scan(&row)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to aggregate orders: %w", result.Error)
+	}
+
+	return &repository.OrderAggregate{
+		Count:       row.Count,
+		TotalAmount: row.TotalAmount,
+	}, nil
+}
+
+// applyFilter 将过滤条件转换为参数化查询条件
+func (r *OrderRepository) applyFilter(query *gorm.DB, filter repository.OrderFilter) *gorm.DB {
+	query = query.Where("tenant_id = ?", r.tenantID)
+
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedAfter)
+	}
+
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+
+	if filter.CourseName != "" {
+		query = query.Where("course_name LIKE ? ESCAPE '\\'", "%"+escapeLike(filter.CourseName)+"%")
+	}
+
+	return query
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // Create 创建新订单
 func (r *OrderRepository) Create(ctx context.Context, order *entity.Order) error {
 	if err := order.Validate(); err != nil {
@@ -129,12 +225,22 @@ func (r *OrderRepository) Create(ctx context.Context, order *entity.Order) error
 		return fmt.Errorf("failed to convert order entity: %w", err)
 	}
 
-	result := db.WithContext(ctx).Create(&model)
-	if result.Error != nil {
-		return fmt.Errorf("failed to create order: %w", result.Error)
-	}
+	// 订单和创建事件在同一事务中写入
+	var eventModel OrderEventModel
+	eventModel.FromEntity(entity.NewOrderEvent(order, "", orderCreationActor, "订单创建"))
+	eventModel.CreatedAt = order.CreatedAt
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		if err := tx.Create(&eventModel).Error; err != nil {
+			return fmt.Errorf("failed to create order event: %w", err)
+		}
+		return nil
+	})
 
-	return nil
+	return err
 }
 
 // Update 更新订单
@@ -158,41 +264,221 @@ func (r *OrderRepository) Update(ctx context.Context, order *entity.Order) error
 		return fmt.Errorf("failed to convert order entity: %w", err)
 	}
 
-	result := db.WithContext(ctx).
-		Where("id = ? AND tenant_id = ?", order.ID, r.tenantID).
-		Updates(&model)
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current OrderModel
+		if err := tx.Where("id = ? AND tenant_id = ?", order.ID, r.tenantID).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %s", entity.ErrOrderNotFound, order.ID)
+			}
+			return fmt.Errorf("failed to find order: %w", err)
+		}
 
-	if result.Error != nil {
-		return fmt.Errorf("failed to update order: %w", result.Error)
+		// 状态变更必须经过 SaveTransition 以保留历史
+		if current.Status != model.Status {
+			return fmt.Errorf("%w: status changes must use SaveTransition", entity.ErrInvalidOrderTransition)
+		}
+
+		if err := tx.Where("id = ? AND tenant_id = ?", order.ID, r.tenantID).Updates(&model).Error; err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveTransition 在同一事务中更新订单状态并记录订单事件
+func (r *OrderRepository) SaveTransition(ctx context.Context, order *entity.Order, event *entity.OrderEvent) error {
+	if err := event.Validate(); err != nil {
+		return fmt.Errorf("invalid order event: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("order not found: %s", order.ID)
+	if event.OrderID != order.ID || event.ToStatus != order.Status {
+		return fmt.Errorf("order event does not match order %s", order.ID)
 	}
 
-	return nil
-}
+	if order.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, order.TenantID)
+	}
 
-// Delete 删除订单
-func (r *OrderRepository) Delete(ctx context.Context, orderID string) error {
 	db, err := r.getDB()
 	if err != nil {
 		return err
 	}
 
+	var eventModel OrderEventModel
+	eventModel.FromEntity(event)
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 以迁移前状态作为条件，防止并发迁移覆盖
+		result := tx.Model(&OrderModel{}).
+			Where("id = ? AND tenant_id = ? AND status = ?", order.ID, r.tenantID, string(event.FromStatus)).
+			Updates(map[string]any{
+				"status":     string(order.Status),
+				"updated_at": order.UpdatedAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update order status: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&OrderModel{}).Where("id = ? AND tenant_id = ?", order.ID, r.tenantID).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to find order: %w", err)
+			}
+			if count == 0 {
+				return fmt.Errorf("%w: %s", entity.ErrOrderNotFound, order.ID)
+			}
+			return fmt.Errorf("%w: %s", entity.ErrOrderStatusConflict, order.ID)
+		}
+
+		if err := tx.Create(&eventModel).Error; err != nil {
+			return fmt.Errorf("failed to create order event: %w", err)
+		}
+		return nil
+	})
+}
+
+// Upsert 按订单 ID 新建或更新订单
+func (r *OrderRepository) Upsert(ctx context.Context, order *entity.Order, actor string) (repository.OrderUpsertResult, error) {
+	if err := order.Validate(); err != nil {
+		return "", fmt.Errorf("invalid order: %w", err)
+	}
+
+	if actor == "" {
+		return "", entity.ErrEmptyOrderEventActor
+	}
+
+	// 确保租户 ID 匹配
+	if order.TenantID != r.tenantID {
+		return "", fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, order.TenantID)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return "", err
+	}
+
+	var model OrderModel
+	if err := model.FromEntity(order); err != nil {
+		return "", fmt.Errorf("failed to convert order entity: %w", err)
+	}
+
+	var result repository.OrderUpsertResult
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current OrderModel
+		err := tx.Where("id = ? AND tenant_id = ?", order.ID, r.tenantID).First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result = repository.OrderUpsertCreated
+			if err := tx.Create(&model).Error; err != nil {
+				return fmt.Errorf("failed to create order: %w", err)
+			}
+
+			var eventModel OrderEventModel
+			eventModel.FromEntity(entity.NewOrderEvent(order, "", actor, "订单导入"))
+			eventModel.CreatedAt = order.CreatedAt
+			if err := tx.Create(&eventModel).Error; err != nil {
+				return fmt.Errorf("failed to create order event: %w", err)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find order: %w", err)
+		}
+
+		// 本地记录更新（如用户在对话中申请了退款）或内容一致时不覆盖
+		if current.UpdatedAt.After(model.UpdatedAt) || sameOrderContent(&current, &model) {
+			result = repository.OrderUpsertUnchanged
+			return nil
+		}
+
+		result = repository.OrderUpsertUpdated
+		updates := map[string]any{
+			"user_id":     model.UserID,
+			"course_name": model.CourseName,
+			"amount":      model.Amount,
+			"status":      model.Status,
+			"updated_at":  model.UpdatedAt,
+		}
+		if model.Metadata != "" {
+			updates["metadata"] = model.Metadata
+		}
+		if err := tx.Model(&OrderModel{}).Where("id = ? AND tenant_id = ?", order.ID, r.tenantID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		// 外部系统中的状态变化同样记录到订单时间线
+		if current.Status != model.Status {
+			var eventModel OrderEventModel
+			eventModel.FromEntity(entity.NewOrderEvent(order, entity.OrderStatus(current.Status), actor, "外部系统同步"))
+			if err := tx.Create(&eventModel).Error; err != nil {
+				return fmt.Errorf("failed to create order event: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// sameOrderContent 判断导入数据与已有订单的业务字段是否一致
+func sameOrderContent(current, incoming *OrderModel) bool {
+	return current.UserID == incoming.UserID &&
+		current.CourseName == incoming.CourseName &&
+		current.Amount == incoming.Amount &&
+		current.Status == incoming.Status &&
+		(incoming.Metadata == "" || current.Metadata == incoming.Metadata)
+}
+
+// FindEvents 查询订单的状态迁移记录
+func (r *OrderRepository) FindEvents(ctx context.Context, orderID string) ([]*entity.OrderEvent, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []OrderEventModel
 	result := db.WithContext(ctx).
-		Where("id = ? AND tenant_id = ?", orderID, r.tenantID).
-		Delete(&OrderModel{})
+		Where("order_id = ? AND tenant_id = ?", r.normalizeOrderID(orderID), r.tenantID).
+		Order("created_at ASC, rowid ASC").
+		Find(&models)
 
 	if result.Error != nil {
-		return fmt.Errorf("failed to delete order: %w", result.Error)
+		return nil, fmt.Errorf("failed to find order events: %w", result.Error)
+	}
+
+	events := make([]*entity.OrderEvent, 0, len(models))
+	for _, model := range models {
+		events = append(events, model.ToEntity())
 	}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("order not found: %s", orderID)
+	return events, nil
+}
+
+// Delete 删除订单
+func (r *OrderRepository) Delete(ctx context.Context, orderID string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
 	}
 
-	return nil
+	orderID = r.normalizeOrderID(orderID)
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND tenant_id = ?", orderID, r.tenantID).Delete(&OrderModel{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete order: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s", entity.ErrOrderNotFound, orderID)
+		}
+
+		if err := tx.Where("order_id = ? AND tenant_id = ?", orderID, r.tenantID).Delete(&OrderEventModel{}).Error; err != nil {
+			return fmt.Errorf("failed to delete order events: %w", err)
+		}
+		return nil
+	})
 }
 
 // List 列出所有订单（支持分页）
@@ -245,3 +531,11 @@ func (r *OrderRepository) Count(ctx context.Context) (int64, error) {
 
 	return count, nil
 }
+
+// normalizeOrderID 按租户的订单号方案规范化查询用的订单号（去除空白、补全前缀、统一大小写），无法识别时原样返回
+func (r *OrderRepository) normalizeOrderID(orderID string) string {
+	if normalized, ok := entity.OrderIDSchemeFor(r.tenantID).Normalize(orderID); ok {
+		return normalized
+	}
+	return orderID
+}
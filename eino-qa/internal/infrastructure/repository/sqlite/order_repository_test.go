@@ -0,0 +1,297 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupOrderRepository(t *testing.T) repository.OrderRepository {
+	tempDir, err := os.MkdirTemp("", "order_repo_test_*")
+	require.NoError(t, err)
+
+	dbManager := NewDBManager(tempDir)
+	t.Cleanup(func() {
+		dbManager.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	return NewOrderRepository(dbManager, "tenant1")
+}
+
+func createTestOrder(t *testing.T, repo repository.OrderRepository, id, userID, courseName string, amount float64, status entity.OrderStatus, createdAt time.Time) {
+	order := &entity.Order{
+		ID:         id,
+		UserID:     userID,
+		CourseName: courseName,
+		Amount:     amount,
+		Status:     status,
+		TenantID:   "tenant1",
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+	require.NoError(t, repo.Create(context.Background(), order))
+}
+
+func TestOrderRepository_FindByFilterAndAggregate(t *testing.T) {
+	repo := setupOrderRepository(t)
+	ctx := context.Background()
+
+	createTestOrder(t, repo, "#20251001001", "alice", "Go 语言进阶", 199, entity.OrderStatusPending, time.Date(2025, 10, 5, 10, 0, 0, 0, time.Local))
+	createTestOrder(t, repo, "#20251001002", "alice", "Go 并发编程", 99, entity.OrderStatusPaid, time.Date(2025, 10, 31, 23, 0, 0, 0, time.Local))
+	createTestOrder(t, repo, "#20251001003", "alice", "分布式系统", 299, entity.OrderStatusPaid, time.Date(2025, 11, 1, 8, 0, 0, 0, time.Local))
+	createTestOrder(t, repo, "#20251001004", "bob", "Go 语言进阶", 199, entity.OrderStatusPending, time.Date(2025, 10, 6, 10, 0, 0, 0, time.Local))
+	createTestOrder(t, repo, "#20251001005", "alice", "100%_通关", 10, entity.OrderStatusPaid, time.Date(2025, 9, 1, 10, 0, 0, 0, time.Local))
+
+	october := repository.OrderFilter{
+		UserID:        "alice",
+		CreatedAfter:  time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local),
+		CreatedBefore: time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local),
+	}
+
+	orders, err := repo.FindByFilter(ctx, october)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, "#20251001002", orders[0].ID) // 按创建时间倒序
+	assert.Equal(t, "#20251001001", orders[1].ID)
+
+	pending := october
+	pending.Statuses = []entity.OrderStatus{entity.OrderStatusPending}
+	orders, err = repo.FindByFilter(ctx, pending)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "#20251001001", orders[0].ID)
+
+	stats, err := repo.Aggregate(ctx, repository.OrderFilter{UserID: "alice", Statuses: []entity.OrderStatus{entity.OrderStatusPaid}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Count)
+	assert.InDelta(t, 408.0, stats.TotalAmount, 0.001)
+
+	// 课程名称模糊匹配
+	orders, err = repo.FindByFilter(ctx, repository.OrderFilter{UserID: "alice", CourseName: "Go"})
+	require.NoError(t, err)
+	assert.Len(t, orders, 2)
+
+	// LIKE 通配符按字面匹配
+	orders, err = repo.FindByFilter(ctx, repository.OrderFilter{UserID: "alice", CourseName: "%"})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "#20251001005", orders[0].ID)
+
+	orders, err = repo.FindByFilter(ctx, repository.OrderFilter{UserID: "alice", Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, orders, 1)
+
+	// 无匹配时聚合结果为零
+	stats, err = repo.Aggregate(ctx, repository.OrderFilter{UserID: "carol"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.Count)
+	assert.Zero(t, stats.TotalAmount)
+}
+
+func TestOrderRepository_FindByIDNotFound(t *testing.T) {
+	repo := setupOrderRepository(t)
+
+	_, err := repo.FindByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, entity.ErrOrderNotFound)
+}
+
+func TestOrderRepository_FindByIDNormalizesOrderID(t *testing.T) {
+	repo := setupOrderRepository(t)
+	ctx := context.Background()
+
+	createTestOrder(t, repo, "#20251001001", "alice", "Go 语言进阶", 199, entity.OrderStatusPaid, time.Now())
+
+	order, err := repo.FindByID(ctx, "20251001001")
+	require.NoError(t, err)
+	assert.Equal(t, "#20251001001", order.ID)
+
+	// 不符合订单号方案的订单无法创建
+	invalid := entity.NewOrder("alice", "Go 语言进阶", 199, "tenant1")
+	invalid.ID = "o1"
+	assert.ErrorIs(t, repo.Create(ctx, invalid), entity.ErrInvalidOrderID)
+}
+
+func TestOrderRepository_FindEventsNormalizesOrderID(t *testing.T) {
+	scheme, err := entity.NewPatternOrderIDScheme(entity.OrderIDSchemeConfig{
+		Prefix:          "SO-",
+		Pattern:         `[A-Z]{2}\d{6}`,
+		CaseInsensitive: true,
+	})
+	require.NoError(t, err)
+	entity.RegisterOrderIDScheme("tenant1", scheme)
+	defer entity.RegisterOrderIDScheme("tenant1", nil)
+
+	repo := setupOrderRepository(t)
+	ctx := context.Background()
+	createTestOrder(t, repo, "SO-AB123456", "alice", "Go 语言进阶", 199, entity.OrderStatusPaid, time.Now())
+
+	// 能查到订单的写法同样能查到订单的状态迁移记录
+	for _, orderID := range []string{"SO-AB123456", " so-ab123456 ", "ab123456"} {
+		order, err := repo.FindByID(ctx, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, "SO-AB123456", order.ID)
+
+		events, err := repo.FindEvents(ctx, orderID)
+		require.NoError(t, err)
+		assert.Len(t, events, 1, orderID)
+	}
+}
+
+func TestOrderRepository_SaveTransition(t *testing.T) {
+	repo := setupOrderRepository(t)
+	ctx := context.Background()
+
+	createdAt := time.Date(2025, 11, 14, 10, 0, 0, 0, time.Local)
+	createTestOrder(t, repo, "#20251114001", "alice", "Go 语言进阶", 199, entity.OrderStatusPending, createdAt)
+
+	// 创建订单时记录创建事件
+	events, err := repo.FindEvents(ctx, "#20251114001")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.True(t, events[0].IsCreation())
+	assert.Equal(t, entity.OrderStatusPending, events[0].ToStatus)
+
+	order, err := repo.FindByID(ctx, "#20251114001")
+	require.NoError(t, err)
+
+	// Update 不能绕过状态机修改状态
+	order.Status = entity.OrderStatusRefunded
+	assert.ErrorIs(t, repo.Update(ctx, order), entity.ErrInvalidOrderTransition)
+	order.Status = entity.OrderStatusPending
+
+	event, err := order.Transition(entity.OrderStatusPaid, "user:alice", "微信支付")
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveTransition(ctx, order, event))
+
+	// 基于过期状态的并发迁移被拒绝
+	stale, err := repo.FindByID(ctx, "#20251114001")
+	require.NoError(t, err)
+	stale.Status = entity.OrderStatusPending
+	staleEvent, err := stale.Transition(entity.OrderStatusCancelled, "user:alice", "不想要了")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.SaveTransition(ctx, stale, staleEvent), entity.ErrOrderStatusConflict)
+
+	event, err = order.Transition(entity.OrderStatusRefundRequested, "user:alice", "课程内容不符")
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveTransition(ctx, order, event))
+
+	saved, err := repo.FindByID(ctx, "#20251114001")
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusRefundRequested, saved.Status)
+
+	events, err = repo.FindEvents(ctx, "#20251114001")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, entity.OrderStatusPaid, events[1].ToStatus)
+	assert.Equal(t, entity.OrderStatusRefundRequested, events[2].ToStatus)
+	assert.Equal(t, entity.OrderStatusPaid, events[2].FromStatus)
+	assert.Equal(t, "user:alice", events[2].Actor)
+	assert.Equal(t, "课程内容不符", events[2].Reason)
+
+	// 删除订单时一并删除事件
+	require.NoError(t, repo.Delete(ctx, "#20251114001"))
+	events, err = repo.FindEvents(ctx, "#20251114001")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestOrderRepository_Upsert(t *testing.T) {
+	repo := setupOrderRepository(t)
+	ctx := context.Background()
+
+	updatedAt := time.Date(2025, 11, 14, 10, 0, 0, 0, time.Local)
+	order := &entity.Order{
+		ID:         "#20251114001",
+		UserID:     "alice",
+		CourseName: "Go 语言进阶",
+		Amount:     199,
+		Status:     entity.OrderStatusPaid,
+		TenantID:   "tenant1",
+		CreatedAt:  updatedAt,
+		UpdatedAt:  updatedAt,
+	}
+
+	result, err := repo.Upsert(ctx, order, "sync:shop")
+	require.NoError(t, err)
+	assert.Equal(t, repository.OrderUpsertCreated, result)
+
+	// 重复导入相同数据不修改
+	result, err = repo.Upsert(ctx, order, "sync:shop")
+	require.NoError(t, err)
+	assert.Equal(t, repository.OrderUpsertUnchanged, result)
+
+	// 外部系统中的状态变化直接应用并记录事件
+	refunded := *order
+	refunded.Status = entity.OrderStatusRefunded
+	refunded.UpdatedAt = updatedAt.Add(time.Hour)
+	result, err = repo.Upsert(ctx, &refunded, "sync:shop")
+	require.NoError(t, err)
+	assert.Equal(t, repository.OrderUpsertUpdated, result)
+
+	saved, err := repo.FindByID(ctx, "#20251114001")
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusRefunded, saved.Status)
+
+	// 比已有订单旧的数据不覆盖
+	result, err = repo.Upsert(ctx, order, "sync:shop")
+	require.NoError(t, err)
+	assert.Equal(t, repository.OrderUpsertUnchanged, result)
+
+	events, err := repo.FindEvents(ctx, "#20251114001")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.True(t, events[0].IsCreation())
+	assert.Equal(t, entity.OrderStatusPaid, events[1].FromStatus)
+	assert.Equal(t, entity.OrderStatusRefunded, events[1].ToStatus)
+	assert.Equal(t, "sync:shop", events[1].Actor)
+
+	// 订单号不符合租户方案时拒绝写入
+	invalid := *order
+	invalid.ID = "SO-1"
+	_, err = repo.Upsert(ctx, &invalid, "sync:shop")
+	assert.ErrorIs(t, err, entity.ErrInvalidOrderID)
+}
+
+func TestOrderSyncRepository(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "order_sync_repo_test_*")
+	require.NoError(t, err)
+
+	dbManager := NewDBManager(tempDir)
+	t.Cleanup(func() {
+		dbManager.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	repo := NewOrderSyncRepository(dbManager, "tenant1")
+	ctx := context.Background()
+
+	_, err = repo.GetState(ctx, "shop")
+	assert.ErrorIs(t, err, entity.ErrOrderSyncStateNotFound)
+
+	cursor := time.Date(2025, 11, 14, 10, 0, 0, 0, time.Local)
+	state := entity.NewOrderSyncState("tenant1", "shop")
+	state.Start()
+	state.Finish(entity.OrderImportStats{Total: 3, Created: 2, Failed: 1}, cursor, nil)
+	require.NoError(t, repo.SaveState(ctx, state))
+
+	state.Start()
+	state.Finish(entity.OrderImportStats{}, cursor.Add(time.Hour), assert.AnError)
+	require.NoError(t, repo.SaveState(ctx, state))
+
+	saved, err := repo.GetState(ctx, "shop")
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderSyncFailed, saved.Status)
+	assert.True(t, saved.Cursor.Equal(cursor), "失败时游标不推进")
+	assert.Equal(t, assert.AnError.Error(), saved.LastError)
+	assert.False(t, saved.LastSuccessAt.IsZero())
+
+	assert.Error(t, repo.SaveState(ctx, entity.NewOrderSyncState("tenant2", "shop")))
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// OrderSyncRepository 订单同步状态仓储
+type OrderSyncRepository struct {
+	dbManager *DBManager
+	tenantID  string
+}
+
+// NewOrderSyncRepository 创建订单同步状态仓储
+func NewOrderSyncRepository(dbManager *DBManager, tenantID string) repository.OrderSyncRepository {
+	return &OrderSyncRepository{
+		dbManager: dbManager,
+		tenantID:  tenantID,
+	}
+}
+
+// getDB 获取当前租户的数据库连接
+func (r *OrderSyncRepository) getDB() (*gorm.DB, error) {
+	return r.dbManager.GetDB(r.tenantID)
+}
+
+// GetState 获取数据源的同步状态
+func (r *OrderSyncRepository) GetState(ctx context.Context, source string) (*entity.OrderSyncState, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model OrderSyncStateModel
+	result := db.WithContext(ctx).Where("source = ?", source).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrOrderSyncStateNotFound
+		}
+		return nil, fmt.Errorf("failed to get order sync state: %w", result.Error)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// SaveState 保存同步状态
+func (r *OrderSyncRepository) SaveState(ctx context.Context, state *entity.OrderSyncState) error {
+	if err := state.Validate(); err != nil {
+		return fmt.Errorf("invalid order sync state: %w", err)
+	}
+
+	// 确保租户 ID 匹配
+	if state.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, state.TenantID)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	var model OrderSyncStateModel
+	model.FromEntity(state)
+
+	if err := db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save order sync state: %w", err)
+	}
+
+	return nil
+}
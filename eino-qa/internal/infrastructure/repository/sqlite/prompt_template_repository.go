@@ -0,0 +1,206 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// PromptTemplateRepository SQLite 提示词模板仓储实现
+type PromptTemplateRepository struct {
+	dbManager *DBManager
+	tenantID  string
+}
+
+// NewPromptTemplateRepository 创建提示词模板仓储
+func NewPromptTemplateRepository(dbManager *DBManager, tenantID string) repository.PromptTemplateRepository {
+	return &PromptTemplateRepository{
+		dbManager: dbManager,
+		tenantID:  tenantID,
+	}
+}
+
+// getDB 获取当前租户的数据库连接
+func (r *PromptTemplateRepository) getDB() (*gorm.DB, error) {
+	return r.dbManager.GetDB(r.tenantID)
+}
+
+// Create 保存新的模板版本，版本号在事务内分配
+func (r *PromptTemplateRepository) Create(ctx context.Context, template *entity.PromptTemplate) error {
+	if err := template.Validate(); err != nil {
+		return fmt.Errorf("invalid prompt template: %w", err)
+	}
+
+	// 确保租户 ID 匹配
+	if template.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, template.TenantID)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&PromptTemplateModel{}).
+			Where("tenant_id = ? AND name = ?", r.tenantID, string(template.Name)).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return fmt.Errorf("failed to find latest prompt version: %w", err)
+		}
+
+		template.Version = latest + 1
+		if template.Active {
+			// 新版本直接启用时停用其他版本
+			if err := r.deactivateAll(tx, template.Name); err != nil {
+				return err
+			}
+			now := time.Now()
+			template.ActivatedAt = &now
+		}
+
+		var model PromptTemplateModel
+		model.FromEntity(template)
+		if err := tx.Create(&model).Error; err != nil {
+			return fmt.Errorf("failed to create prompt template: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindActive 查询启用中的模板版本
+func (r *PromptTemplateRepository) FindActive(ctx context.Context, name entity.PromptName) (*entity.PromptTemplate, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model PromptTemplateModel
+	result := db.WithContext(ctx).
+		Where("tenant_id = ? AND name = ? AND active = ?", r.tenantID, string(name), true).
+		First(&model)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", entity.ErrPromptTemplateNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to find active prompt template: %w", result.Error)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// FindVersion 查询指定版本
+func (r *PromptTemplateRepository) FindVersion(ctx context.Context, name entity.PromptName, version int) (*entity.PromptTemplate, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model PromptTemplateModel
+	result := db.WithContext(ctx).
+		Where("tenant_id = ? AND name = ? AND version = ?", r.tenantID, string(name), version).
+		First(&model)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s v%d", entity.ErrPromptTemplateNotFound, name, version)
+		}
+		return nil, fmt.Errorf("failed to find prompt template: %w", result.Error)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListVersions 列出模板的所有版本（按版本号倒序）
+func (r *PromptTemplateRepository) ListVersions(ctx context.Context, name entity.PromptName) ([]*entity.PromptTemplate, error) {
+	return r.list(ctx, "tenant_id = ? AND name = ?", r.tenantID, string(name))
+}
+
+// ListActive 列出租户所有启用中的模板
+func (r *PromptTemplateRepository) ListActive(ctx context.Context) ([]*entity.PromptTemplate, error) {
+	return r.list(ctx, "tenant_id = ? AND active = ?", r.tenantID, true)
+}
+
+// list 按条件列出模板版本
+func (r *PromptTemplateRepository) list(ctx context.Context, query string, args ...interface{}) ([]*entity.PromptTemplate, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []PromptTemplateModel
+	result := db.WithContext(ctx).
+		Where(query, args...).
+		Order("name ASC, version DESC").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", result.Error)
+	}
+
+	templates := make([]*entity.PromptTemplate, 0, len(models))
+	for i := range models {
+		templates = append(templates, models[i].ToEntity())
+	}
+
+	return templates, nil
+}
+
+// Activate 启用指定版本，同名的其他版本自动停用
+func (r *PromptTemplateRepository) Activate(ctx context.Context, name entity.PromptName, version int) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model PromptTemplateModel
+		if err := tx.Where("tenant_id = ? AND name = ? AND version = ?", r.tenantID, string(name), version).
+			First(&model).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %s v%d", entity.ErrPromptTemplateNotFound, name, version)
+			}
+			return fmt.Errorf("failed to find prompt template: %w", err)
+		}
+
+		if err := r.deactivateAll(tx, name); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model).Updates(map[string]interface{}{
+			"active":       true,
+			"activated_at": time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to activate prompt template: %w", err)
+		}
+		return nil
+	})
+}
+
+// Deactivate 停用模板的所有版本
+func (r *PromptTemplateRepository) Deactivate(ctx context.Context, name entity.PromptName) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	return r.deactivateAll(db.WithContext(ctx), name)
+}
+
+// deactivateAll 停用同名模板的所有版本
+func (r *PromptTemplateRepository) deactivateAll(tx *gorm.DB, name entity.PromptName) error {
+	if err := tx.Model(&PromptTemplateModel{}).
+		Where("tenant_id = ? AND name = ? AND active = ?", r.tenantID, string(name), true).
+		Update("active", false).Error; err != nil {
+		return fmt.Errorf("failed to deactivate prompt templates: %w", err)
+	}
+	return nil
+}
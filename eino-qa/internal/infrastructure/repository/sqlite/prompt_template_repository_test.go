@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptTemplateRepository_Versions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "prompt_repo_test_*")
+	require.NoError(t, err)
+
+	dbManager := NewDBManager(tempDir)
+	t.Cleanup(func() {
+		dbManager.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	repo := NewPromptTemplateRepository(dbManager, "tenant1")
+	ctx := context.Background()
+
+	// 没有启用版本
+	_, err = repo.FindActive(ctx, entity.PromptRAG)
+	assert.ErrorIs(t, err, entity.ErrPromptTemplateNotFound)
+
+	// 版本号按名称递增
+	v1 := entity.NewPromptTemplate("tenant1", entity.PromptRAG, 0, "你是 {{.BrandName}} 的课程顾问。", "初版")
+	v1.Active = true
+	require.NoError(t, repo.Create(ctx, v1))
+	v2 := entity.NewPromptTemplate("tenant1", entity.PromptRAG, 0, "你是课程顾问。", "")
+	require.NoError(t, repo.Create(ctx, v2))
+	other := entity.NewPromptTemplate("tenant1", entity.PromptIntent, 0, "意图识别", "")
+	require.NoError(t, repo.Create(ctx, other))
+
+	assert.Equal(t, 1, v1.Version)
+	assert.Equal(t, 2, v2.Version)
+	assert.Equal(t, 1, other.Version)
+
+	active, err := repo.FindActive(ctx, entity.PromptRAG)
+	require.NoError(t, err)
+	assert.Equal(t, 1, active.Version)
+	assert.NotNil(t, active.ActivatedAt)
+
+	// 启用新版本时停用旧版本
+	require.NoError(t, repo.Activate(ctx, entity.PromptRAG, 2))
+	active, err = repo.FindActive(ctx, entity.PromptRAG)
+	require.NoError(t, err)
+	assert.Equal(t, 2, active.Version)
+
+	versions, err := repo.ListVersions(ctx, entity.PromptRAG)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.False(t, versions[1].Active)
+
+	err = repo.Activate(ctx, entity.PromptRAG, 9)
+	assert.ErrorIs(t, err, entity.ErrPromptTemplateNotFound)
+
+	// 停用后回到默认模板
+	require.NoError(t, repo.Deactivate(ctx, entity.PromptRAG))
+	_, err = repo.FindActive(ctx, entity.PromptRAG)
+	assert.ErrorIs(t, err, entity.ErrPromptTemplateNotFound)
+
+	all, err := repo.ListActive(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// QuarantineRepository SQLite 隔离文档仓储实现
+type QuarantineRepository struct {
+	dbManager *DBManager
+	tenantID  string
+}
+
+// NewQuarantineRepository 创建隔离文档仓储
+func NewQuarantineRepository(dbManager *DBManager, tenantID string) repository.QuarantineRepository {
+	return &QuarantineRepository{
+		dbManager: dbManager,
+		tenantID:  tenantID,
+	}
+}
+
+// getDB 获取当前租户的数据库连接
+func (r *QuarantineRepository) getDB() (*gorm.DB, error) {
+	return r.dbManager.GetDB(r.tenantID)
+}
+
+// Create 保存隔离文档
+func (r *QuarantineRepository) Create(ctx context.Context, doc *entity.QuarantinedDocument) error {
+	if err := doc.Validate(); err != nil {
+		return fmt.Errorf("invalid quarantined document: %w", err)
+	}
+
+	// 确保租户 ID 匹配
+	if doc.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, doc.TenantID)
+	}
+
+	var model QuarantinedDocumentModel
+	if err := model.FromEntity(doc); err != nil {
+		return fmt.Errorf("failed to convert quarantined document: %w", err)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	if err := db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create quarantined document: %w", err)
+	}
+	return nil
+}
+
+// FindByID 根据 ID 查询隔离文档
+func (r *QuarantineRepository) FindByID(ctx context.Context, id string) (*entity.QuarantinedDocument, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model QuarantinedDocumentModel
+	result := db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, r.tenantID).
+		First(&model)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", entity.ErrQuarantinedDocumentNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find quarantined document: %w", result.Error)
+	}
+
+	doc, err := model.ToEntity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert quarantined document: %w", err)
+	}
+	return doc, nil
+}
+
+// List 列出租户的隔离文档
+func (r *QuarantineRepository) List(ctx context.Context) ([]*entity.QuarantinedDocument, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []QuarantinedDocumentModel
+	result := db.WithContext(ctx).
+		Where("tenant_id = ?", r.tenantID).
+		Order("created_at DESC").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list quarantined documents: %w", result.Error)
+	}
+
+	docs := make([]*entity.QuarantinedDocument, 0, len(models))
+	for i := range models {
+		doc, err := models[i].ToEntity()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert quarantined document: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// Delete 删除隔离文档
+func (r *QuarantineRepository) Delete(ctx context.Context, id string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	result := db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, r.tenantID).
+		Delete(&QuarantinedDocumentModel{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete quarantined document: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", entity.ErrQuarantinedDocumentNotFound, id)
+	}
+	return nil
+}
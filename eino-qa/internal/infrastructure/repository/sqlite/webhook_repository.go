@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// WebhookRepository SQLite Webhook 仓储实现
+type WebhookRepository struct {
+	dbManager *DBManager
+	tenantID  string
+}
+
+// NewWebhookRepository 创建 Webhook 仓储
+func NewWebhookRepository(dbManager *DBManager, tenantID string) repository.WebhookRepository {
+	return &WebhookRepository{
+		dbManager: dbManager,
+		tenantID:  tenantID,
+	}
+}
+
+// getDB 获取当前租户的数据库连接
+func (r *WebhookRepository) getDB() (*gorm.DB, error) {
+	return r.dbManager.GetDB(r.tenantID)
+}
+
+// CreateEndpoint 创建 Webhook 端点
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	if err := endpoint.Validate(); err != nil {
+		return fmt.Errorf("invalid webhook endpoint: %w", err)
+	}
+
+	// 确保租户 ID 匹配
+	if endpoint.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, endpoint.TenantID)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	var model WebhookEndpointModel
+	if err := model.FromEntity(endpoint); err != nil {
+		return fmt.Errorf("failed to convert webhook endpoint entity: %w", err)
+	}
+
+	result := db.WithContext(ctx).Create(&model)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create webhook endpoint: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindEndpointByID 根据 ID 查询端点
+func (r *WebhookRepository) FindEndpointByID(ctx context.Context, id string) (*entity.WebhookEndpoint, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model WebhookEndpointModel
+	result := db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, r.tenantID).First(&model)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", entity.ErrWebhookNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find webhook endpoint: %w", result.Error)
+	}
+
+	return model.ToEntity()
+}
+
+// ListEndpoints 列出租户的所有端点
+func (r *WebhookRepository) ListEndpoints(ctx context.Context) ([]*entity.WebhookEndpoint, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []WebhookEndpointModel
+	result := db.WithContext(ctx).
+		Where("tenant_id = ?", r.tenantID).
+		Order("created_at ASC").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", result.Error)
+	}
+
+	endpoints := make([]*entity.WebhookEndpoint, 0, len(models))
+	for _, model := range models {
+		endpoint, err := model.ToEntity()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert webhook endpoint model: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+// DeleteEndpoint 删除端点
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	result := db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, r.tenantID).
+		Delete(&WebhookEndpointModel{})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook endpoint: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", entity.ErrWebhookNotFound, id)
+	}
+
+	return nil
+}
+
+// SaveDeadLetter 保存死信记录（存在则更新）
+func (r *WebhookRepository) SaveDeadLetter(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	// 确保租户 ID 匹配
+	if delivery.TenantID != r.tenantID {
+		return fmt.Errorf("tenant ID mismatch: expected %s, got %s", r.tenantID, delivery.TenantID)
+	}
+
+	db, err := r.getDB()
+	if err != nil {
+		return err
+	}
+
+	var model WebhookDeadLetterModel
+	model.FromEntity(delivery)
+
+	result := db.WithContext(ctx).Save(&model)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save webhook dead letter: %w", result.Error)
+	}
+
+	return nil
+}
+
+// FindDeadLetterByID 根据 ID 查询死信记录
+func (r *WebhookRepository) FindDeadLetterByID(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var model WebhookDeadLetterModel
+	result := db.WithContext(ctx).Where("id = ? AND tenant_id = ?", id, r.tenantID).First(&model)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: dead letter %s", entity.ErrWebhookNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find webhook dead letter: %w", result.Error)
+	}
+
+	return model.ToEntity(), nil
+}
+
+// ListDeadLetters 列出死信记录（支持分页）
+func (r *WebhookRepository) ListDeadLetters(ctx context.Context, offset, limit int) ([]*entity.WebhookDelivery, error) {
+	db, err := r.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []WebhookDeadLetterModel
+	result := db.WithContext(ctx).
+		Where("tenant_id = ?", r.tenantID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", result.Error)
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, 0, len(models))
+	for _, model := range models {
+		deliveries = append(deliveries, model.ToEntity())
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,277 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	apperrors "eino-qa/pkg/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RepositoryProvider 按租户获取 Webhook 仓储
+type RepositoryProvider func(tenantID string) repository.WebhookRepository
+
+// Config 分发器配置
+type Config struct {
+	// Timeout 单次 HTTP 投递超时
+	Timeout time.Duration
+	// Retry 重试策略，耗尽后写入死信表
+	Retry apperrors.RetryConfig
+}
+
+// DefaultConfig 默认分发器配置
+func DefaultConfig() Config {
+	return Config{
+		Timeout: 10 * time.Second,
+		Retry: apperrors.RetryConfig{
+			MaxAttempts:  5,
+			InitialDelay: time.Second,
+			MaxDelay:     30 * time.Second,
+			Multiplier:   2.0,
+		},
+	}
+}
+
+// Dispatcher Webhook 事件分发器
+// 负责签名、带退避重试的投递、死信落库和重放
+type Dispatcher struct {
+	httpClient *http.Client
+	repos      RepositoryProvider
+	retry      apperrors.RetryConfig
+	logger     *logrus.Logger
+
+	// 异步投递的生命周期控制
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher 创建 Webhook 分发器
+func NewDispatcher(repos RepositoryProvider, config Config, logger *logrus.Logger) *Dispatcher {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	defaults := DefaultConfig()
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.Retry.MaxAttempts <= 0 {
+		config.Retry.MaxAttempts = defaults.Retry.MaxAttempts
+	}
+	if config.Retry.InitialDelay <= 0 {
+		config.Retry.InitialDelay = defaults.Retry.InitialDelay
+	}
+	if config.Retry.MaxDelay <= 0 {
+		config.Retry.MaxDelay = defaults.Retry.MaxDelay
+	}
+	if config.Retry.Multiplier <= 0 {
+		config.Retry.Multiplier = defaults.Retry.Multiplier
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		httpClient: &http.Client{Timeout: config.Timeout},
+		repos:      repos,
+		retry:      config.Retry,
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// eventPayload 投递给接收方的 JSON 负载
+type eventPayload struct {
+	ID        string                  `json:"id"`
+	Type      entity.WebhookEventType `json:"type"`
+	TenantID  string                  `json:"tenant_id"`
+	CreatedAt time.Time               `json:"created_at"`
+	Data      map[string]any          `json:"data"`
+}
+
+// Publish 发布事件，异步投递到租户所有订阅了该事件的端点
+// 返回的错误仅表示端点查询失败，投递失败会进入死信表
+func (d *Dispatcher) Publish(ctx context.Context, tenantID string, eventType entity.WebhookEventType, data map[string]any) error {
+	if !eventType.IsValid() {
+		return entity.ErrInvalidWebhookEvent
+	}
+
+	endpoints, err := d.repos(tenantID).ListEndpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	event := entity.NewWebhookEvent(tenantID, eventType, data)
+	body, err := json.Marshal(eventPayload{
+		ID:        event.ID,
+		Type:      event.Type,
+		TenantID:  event.TenantID,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(eventType) {
+			continue
+		}
+
+		d.wg.Add(1)
+		go func(endpoint *entity.WebhookEndpoint) {
+			defer d.wg.Done()
+			d.deliverOrDeadLetter(endpoint, event, body)
+		}(endpoint)
+	}
+
+	return nil
+}
+
+// deliverOrDeadLetter 带重试投递，重试耗尽后写入死信表
+func (d *Dispatcher) deliverOrDeadLetter(endpoint *entity.WebhookEndpoint, event *entity.WebhookEvent, body []byte) {
+	attempts, err := d.deliverWithRetry(d.ctx, endpoint, event.ID, event.Type, body)
+	if err == nil {
+		d.logger.WithFields(logrus.Fields{
+			"tenant_id":   endpoint.TenantID,
+			"endpoint_id": endpoint.ID,
+			"event_id":    event.ID,
+			"event_type":  event.Type,
+			"attempts":    attempts,
+		}).Debug("webhook delivered")
+		return
+	}
+
+	d.logger.WithError(err).WithFields(logrus.Fields{
+		"tenant_id":   endpoint.TenantID,
+		"endpoint_id": endpoint.ID,
+		"event_id":    event.ID,
+		"event_type":  event.Type,
+		"attempts":    attempts,
+	}).Warn("webhook delivery failed, moving to dead letter")
+
+	delivery := entity.NewWebhookDelivery(endpoint, event, string(body), attempts, err)
+
+	// 即使分发器正在关闭，也要保证死信落库
+	if saveErr := d.repos(endpoint.TenantID).SaveDeadLetter(context.Background(), delivery); saveErr != nil {
+		d.logger.WithError(saveErr).WithField("event_id", event.ID).Error("failed to save webhook dead letter")
+	}
+}
+
+// deliverWithRetry 使用指数退避重试投递，返回实际尝试次数
+func (d *Dispatcher) deliverWithRetry(ctx context.Context, endpoint *entity.WebhookEndpoint, eventID string, eventType entity.WebhookEventType, body []byte) (int, error) {
+	attempts := 0
+	err := apperrors.RetryWithBackoff(ctx, d.retry, func() error {
+		attempts++
+		return d.send(ctx, endpoint, eventID, eventType, body)
+	})
+	return attempts, err
+}
+
+// send 执行一次 HTTP 投递
+// 网络错误、429 和 5xx 视为可重试，其他非 2xx 状态码不再重试
+func (d *Dispatcher) send(ctx context.Context, endpoint *entity.WebhookEndpoint, eventID string, eventType entity.WebhookEventType, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return apperrors.NewWithCategory(0, "failed to build webhook request", err, apperrors.CategoryClient, false)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "eino-qa-webhook/1.0")
+	req.Header.Set(HeaderEvent, string(eventType))
+	req.Header.Set(HeaderID, eventID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return apperrors.NewWithCategory(0, "webhook request failed", err, apperrors.CategoryExternal, true)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return apperrors.NewWithCategory(
+		resp.StatusCode,
+		fmt.Sprintf("webhook endpoint responded with status %d", resp.StatusCode),
+		nil,
+		apperrors.CategoryExternal,
+		retryable,
+	)
+}
+
+// Replay 重放死信记录，使用原始负载和事件 ID 重新投递
+// 已重放成功的记录直接返回，保证重复调用幂等
+func (d *Dispatcher) Replay(ctx context.Context, tenantID, deadLetterID string) (*entity.WebhookDelivery, error) {
+	repo := d.repos(tenantID)
+
+	delivery, err := repo.FindDeadLetterByID(ctx, deadLetterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.IsReplayed() {
+		return delivery, nil
+	}
+
+	endpoint, err := repo.FindEndpointByID(ctx, delivery.EndpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load endpoint for dead letter: %w", err)
+	}
+
+	attempts, sendErr := d.deliverWithRetry(ctx, endpoint, delivery.EventID, delivery.EventType, []byte(delivery.Payload))
+
+	delivery.Attempts += attempts
+	delivery.UpdatedAt = time.Now()
+	if sendErr != nil {
+		delivery.LastError = sendErr.Error()
+	} else {
+		delivery.Status = entity.DeliveryStatusReplayed
+		delivery.LastError = ""
+	}
+
+	if err := repo.SaveDeadLetter(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to update dead letter: %w", err)
+	}
+
+	if sendErr != nil {
+		return delivery, fmt.Errorf("webhook replay failed: %w", sendErr)
+	}
+
+	return delivery, nil
+}
+
+// Close 等待进行中的投递完成，超时后取消剩余重试
+func (d *Dispatcher) Close(timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-time.After(timeout):
+		d.cancel()
+		<-done
+		return fmt.Errorf("webhook dispatcher closed with pending deliveries cancelled")
+	}
+}
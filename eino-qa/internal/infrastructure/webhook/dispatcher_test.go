@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/infrastructure/repository/sqlite"
+	apperrors "eino-qa/pkg/errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTenant = "tenant1"
+
+// receivedRequest 接收方收到的请求
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// testReceiver 基于 httptest 的 Webhook 接收方
+type testReceiver struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []receivedRequest
+	calls    int32
+	// statusFor 根据第 n 次调用（从 1 开始）返回状态码
+	statusFor func(n int32) int
+}
+
+func newTestReceiver(t *testing.T, statusFor func(n int32) int) *testReceiver {
+	r := &testReceiver{statusFor: statusFor}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		n := atomic.AddInt32(&r.calls, 1)
+
+		r.mu.Lock()
+		r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+		r.mu.Unlock()
+
+		w.WriteHeader(r.statusFor(n))
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *testReceiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+func setupTestDispatcher(t *testing.T) (*Dispatcher, repository.WebhookRepository) {
+	tempDir, err := os.MkdirTemp("", "webhook_test_*")
+	require.NoError(t, err)
+
+	dbManager := sqlite.NewDBManager(tempDir)
+	t.Cleanup(func() {
+		dbManager.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	provider := func(tenantID string) repository.WebhookRepository {
+		return sqlite.NewWebhookRepository(dbManager, tenantID)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	dispatcher := NewDispatcher(provider, Config{
+		Timeout: 2 * time.Second,
+		Retry: apperrors.RetryConfig{
+			MaxAttempts:  3,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     5 * time.Millisecond,
+			Multiplier:   2.0,
+		},
+	}, logger)
+
+	return dispatcher, provider(testTenant)
+}
+
+func createEndpoint(t *testing.T, repo repository.WebhookRepository, url string, events ...entity.WebhookEventType) *entity.WebhookEndpoint {
+	endpoint := entity.NewWebhookEndpoint(testTenant, url, events)
+	require.NoError(t, repo.CreateEndpoint(context.Background(), endpoint))
+	return endpoint
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	sig := Sign("secret", 1700000000, body)
+
+	assert.NoError(t, Verify("secret", "1700000000", body, sig))
+	assert.Error(t, Verify("other", "1700000000", body, sig))
+	assert.Error(t, Verify("secret", "1700000001", body, sig))
+	assert.Error(t, Verify("secret", "1700000000", []byte(`{}`), sig))
+	assert.Error(t, Verify("secret", "bad", body, sig))
+}
+
+func TestDispatcher_PublishSignedPayload(t *testing.T) {
+	dispatcher, repo := setupTestDispatcher(t)
+	receiver := newTestReceiver(t, func(int32) int { return http.StatusOK })
+
+	endpoint := createEndpoint(t, repo, receiver.server.URL, entity.EventHandoffCreated)
+	// 未订阅该事件的端点不应收到请求
+	other := newTestReceiver(t, func(int32) int { return http.StatusOK })
+	createEndpoint(t, repo, other.server.URL, entity.EventRAGMiss)
+
+	err := dispatcher.Publish(context.Background(), testTenant, entity.EventHandoffCreated, map[string]any{
+		"session_id": "sess_1",
+	})
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Close(time.Second))
+
+	requests := receiver.received()
+	require.Len(t, requests, 1)
+	assert.Empty(t, other.received())
+
+	req := requests[0]
+	assert.Equal(t, "handoff.created", req.header.Get(HeaderEvent))
+	assert.NoError(t, Verify(endpoint.Secret, req.header.Get(HeaderTimestamp), req.body, req.header.Get(HeaderSignature)))
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	assert.Equal(t, req.header.Get(HeaderID), payload["id"])
+	assert.Equal(t, "handoff.created", payload["type"])
+	assert.Equal(t, testTenant, payload["tenant_id"])
+	assert.Equal(t, "sess_1", payload["data"].(map[string]any)["session_id"])
+}
+
+func TestDispatcher_PublishInvalidEvent(t *testing.T) {
+	dispatcher, _ := setupTestDispatcher(t)
+
+	err := dispatcher.Publish(context.Background(), testTenant, entity.WebhookEventType("unknown"), nil)
+	assert.ErrorIs(t, err, entity.ErrInvalidWebhookEvent)
+}
+
+func TestDispatcher_RetryThenSucceed(t *testing.T) {
+	dispatcher, repo := setupTestDispatcher(t)
+	receiver := newTestReceiver(t, func(n int32) int {
+		if n < 3 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	createEndpoint(t, repo, receiver.server.URL, entity.EventRAGMiss)
+
+	require.NoError(t, dispatcher.Publish(context.Background(), testTenant, entity.EventRAGMiss, map[string]any{"query": "q"}))
+	require.NoError(t, dispatcher.Close(time.Second))
+
+	requests := receiver.received()
+	require.Len(t, requests, 3)
+	// 重试使用相同的事件 ID，便于接收方去重
+	assert.Equal(t, requests[0].header.Get(HeaderID), requests[2].header.Get(HeaderID))
+
+	deadLetters, err := repo.ListDeadLetters(context.Background(), 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deadLetters)
+}
+
+func TestDispatcher_DeadLetterAndReplay(t *testing.T) {
+	dispatcher, repo := setupTestDispatcher(t)
+
+	var healthy atomic.Bool
+	receiver := newTestReceiver(t, func(int32) int {
+		if healthy.Load() {
+			return http.StatusOK
+		}
+		return http.StatusInternalServerError
+	})
+	createEndpoint(t, repo, receiver.server.URL, entity.EventJobFinished)
+
+	require.NoError(t, dispatcher.Publish(context.Background(), testTenant, entity.EventJobFinished, map[string]any{"job_id": "job_1"}))
+	dispatcher.wg.Wait()
+
+	assert.Len(t, receiver.received(), 3)
+
+	deadLetters, err := repo.ListDeadLetters(context.Background(), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+
+	dl := deadLetters[0]
+	assert.Equal(t, entity.DeliveryStatusFailed, dl.Status)
+	assert.Equal(t, 3, dl.Attempts)
+	assert.Contains(t, dl.LastError, "status 500")
+
+	// 接收方恢复后重放
+	healthy.Store(true)
+	replayed, err := dispatcher.Replay(context.Background(), testTenant, dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DeliveryStatusReplayed, replayed.Status)
+	assert.Equal(t, 4, replayed.Attempts)
+	assert.Empty(t, replayed.LastError)
+
+	requests := receiver.received()
+	require.Len(t, requests, 4)
+	assert.Equal(t, dl.EventID, requests[3].header.Get(HeaderID))
+	assert.Equal(t, dl.Payload, string(requests[3].body))
+
+	// 再次重放保持幂等，不会重复投递
+	again, err := dispatcher.Replay(context.Background(), testTenant, dl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.DeliveryStatusReplayed, again.Status)
+	assert.Len(t, receiver.received(), 4)
+
+	require.NoError(t, dispatcher.Close(time.Second))
+}
+
+func TestDispatcher_NonRetryableStatus(t *testing.T) {
+	dispatcher, repo := setupTestDispatcher(t)
+	receiver := newTestReceiver(t, func(int32) int { return http.StatusBadRequest })
+	createEndpoint(t, repo, receiver.server.URL, entity.EventFeedbackNegative)
+
+	require.NoError(t, dispatcher.Publish(context.Background(), testTenant, entity.EventFeedbackNegative, map[string]any{}))
+	require.NoError(t, dispatcher.Close(time.Second))
+
+	// 4xx 不重试，直接进入死信
+	assert.Len(t, receiver.received(), 1)
+
+	deadLetters, err := repo.ListDeadLetters(context.Background(), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	assert.Equal(t, 1, deadLetters[0].Attempts)
+}
+
+func TestDispatcher_ReplayNotFound(t *testing.T) {
+	dispatcher, _ := setupTestDispatcher(t)
+
+	_, err := dispatcher.Replay(context.Background(), testTenant, "dlq_missing")
+	assert.ErrorIs(t, err, entity.ErrWebhookNotFound)
+}
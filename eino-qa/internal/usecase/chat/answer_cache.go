@@ -0,0 +1,202 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/redact"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+const (
+	// defaultAnswerCacheThreshold 默认的最低问题相似度
+	defaultAnswerCacheThreshold = 0.95
+	// defaultAnswerCacheTTL 默认的缓存回答有效期
+	defaultAnswerCacheTTL = 24 * time.Hour
+)
+
+// AnswerCachePolicy 租户的语义回答缓存策略
+type AnswerCachePolicy struct {
+	Enabled   bool
+	Threshold float64       // 最低问题相似度，0 表示使用默认值
+	TTL       time.Duration // 缓存回答有效期，0 表示使用默认值
+	// Routes 可缓存的路由，为空时只缓存课程咨询
+	// 直接回答依赖对话历史，只在会话的第一轮查询和写入缓存；订单查询因用户而异，不缓存
+	Routes []entity.IntentType
+}
+
+// cacheableRoutes 可以缓存回答的路由
+var cacheableRoutes = map[entity.IntentType]bool{
+	entity.IntentCourse: true,
+	entity.IntentDirect: true,
+}
+
+// answerCacheLookup 本轮的语义缓存查询结果
+type answerCacheLookup struct {
+	tenantID   string
+	query      string
+	vector     []float32
+	version    uint64 // 查询时的知识库版本，回答只在该版本仍有效时写入缓存
+	routes     []entity.IntentType
+	ttl        time.Duration
+	hit        *entity.CachedAnswer
+	similarity float64
+}
+
+// WithAnswerCache 设置语义回答缓存、问题嵌入模型和租户策略（可选）
+func (uc *ChatUseCase) WithAnswerCache(cache AnswerCache, embedder embedding.Embedder, provider AnswerCachePolicyProvider) *ChatUseCase {
+	uc.answerCache = cache
+	uc.answerEmbedder = embedder
+	uc.answerCachePolicies = provider
+	return uc
+}
+
+// WithMetrics 设置指标记录器（可选）
+func (uc *ChatUseCase) WithMetrics(metrics MetricsRecorder) *ChatUseCase {
+	uc.metrics = metrics
+	return uc
+}
+
+// lookupAnswerCache 在意图识别之前查询语义回答缓存
+// 未启用、参与 A/B 实验或会话处于订单操作确认、槽位追问状态时返回 nil，不查询也不写入缓存
+// 查询包含个人信息时同样跳过：嵌入模型可能只看到占位符，不同用户的手机号、证件号会得到相同的向量
+func (uc *ChatUseCase) lookupAnswerCache(ctx context.Context, session *entity.Session, query string, assignments []*entity.ExperimentAssignment) *answerCacheLookup {
+	if uc.answerCache == nil || uc.answerEmbedder == nil || uc.answerCachePolicies == nil {
+		return nil
+	}
+	if len(assignments) > 0 || session.PendingOrderAction() != nil || session.ExpectedSlot() != nil {
+		return nil
+	}
+	if len(redact.Find(query)) > 0 {
+		return nil
+	}
+
+	tenantID := session.TenantID
+	policy := uc.answerCachePolicies(tenantID)
+	if !policy.Enabled {
+		return nil
+	}
+
+	routes := answerCacheRoutes(policy, session.GetMessageCount() == 0)
+	if len(routes) == 0 {
+		return nil
+	}
+
+	lookup := &answerCacheLookup{
+		tenantID: tenantID,
+		query:    entity.NormalizeCacheQuery(query),
+		version:  uc.answerCache.Version(tenantID),
+		routes:   routes,
+		ttl:      policy.TTL,
+	}
+	if lookup.ttl <= 0 {
+		lookup.ttl = defaultAnswerCacheTTL
+	}
+
+	vectors, err := uc.answerEmbedder.EmbedStrings(ctx, []string{lookup.query})
+	if err != nil || len(vectors) != 1 {
+		uc.logger.Warn(ctx, "failed to embed query for answer cache", map[string]interface{}{"error": err})
+		return nil
+	}
+	lookup.vector = make([]float32, len(vectors[0]))
+	for i, v := range vectors[0] {
+		lookup.vector[i] = float32(v)
+	}
+
+	threshold := policy.Threshold
+	if threshold <= 0 {
+		threshold = defaultAnswerCacheThreshold
+	}
+	lookup.hit, lookup.similarity = uc.answerCache.Lookup(tenantID, lookup.vector, routes, threshold)
+
+	route := ""
+	if lookup.hit != nil {
+		route = string(lookup.hit.Route)
+		uc.logger.Info(ctx, "answer cache hit", map[string]interface{}{
+			"route":        route,
+			"similarity":   lookup.similarity,
+			"cached_query": lookup.hit.Query,
+		})
+	}
+	if uc.metrics != nil {
+		uc.metrics.RecordCacheLookup(route, lookup.hit != nil)
+	}
+
+	return lookup
+}
+
+// answerCacheRoutes 本轮可以查询和写入缓存的路由
+func answerCacheRoutes(policy AnswerCachePolicy, firstTurn bool) []entity.IntentType {
+	configured := policy.Routes
+	if len(configured) == 0 {
+		configured = []entity.IntentType{entity.IntentCourse}
+	}
+
+	var routes []entity.IntentType
+	for _, route := range configured {
+		if !cacheableRoutes[route] || (route == entity.IntentDirect && !firstTurn) {
+			continue
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// turn 命中缓存时的对话回合，跳过意图识别
+func (l *answerCacheLookup) turn(query string) *dialogTurn {
+	intent := entity.NewIntent(l.hit.Route, l.hit.Confidence)
+	intent.Metadata["cache_hit"] = true
+	return &dialogTurn{intent: intent, query: query, cached: l.hit}
+}
+
+// metadata 合并到响应元数据的缓存信息
+func (l *answerCacheLookup) metadata() map[string]any {
+	if l == nil {
+		return nil
+	}
+	if l.hit == nil {
+		return map[string]any{"cache_hit": false}
+	}
+	return map[string]any{
+		"cache_hit":        true,
+		"cache_similarity": l.similarity,
+	}
+}
+
+// storeAnswer 缓存本轮生成的回答
+// 只缓存未命中缓存、路由允许且正常生成（非降级、非依据不足）的回答，包含个人信息的回答不缓存
+func (uc *ChatUseCase) storeAnswer(ctx context.Context, lookup *answerCacheLookup, intent *entity.Intent, answer string, sources []*entity.Document, suggestions []string) {
+	if lookup == nil || lookup.hit != nil || answer == "" || len(redact.Find(answer)) > 0 {
+		return
+	}
+	allowed := false
+	for _, route := range lookup.routes {
+		allowed = allowed || route == intent.Type
+	}
+	if !allowed {
+		return
+	}
+
+	cached := entity.NewCachedAnswer(lookup.query, lookup.vector, intent.Type, answer, lookup.version, lookup.ttl)
+	cached.Confidence = intent.Confidence
+	cached.Sources = sources
+	cached.Suggestions = suggestions
+	if !uc.answerCache.Store(lookup.tenantID, cached) {
+		uc.logger.Info(ctx, "knowledge base changed, answer not cached", map[string]interface{}{"route": intent.Type})
+	}
+}
+
+// cacheable 课程咨询的回答是否可以缓存（检索未命中、依据不足或转人工时不缓存）
+func (r *courseAnswer) cacheable() bool {
+	if len(r.sources) == 0 || len(r.blocks) > 0 {
+		return false
+	}
+	for _, key := range []string{"rag_miss", "low_groundedness", "handoff"} {
+		if _, ok := r.metadata[key]; ok {
+			return false
+		}
+	}
+	return true
+}
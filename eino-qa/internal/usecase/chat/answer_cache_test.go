@@ -0,0 +1,211 @@
+package chat
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/cache"
+	"eino-qa/internal/infrastructure/logger"
+	"eino-qa/internal/infrastructure/redact"
+	"eino-qa/internal/infrastructure/repository/sqlite"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedChatModel 按提示词返回固定结果的聊天模型，记录调用次数
+type scriptedChatModel struct {
+	calls int
+}
+
+func (m *scriptedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.calls++
+	last := input[len(input)-1].Content
+	switch {
+	case strings.Contains(last, "请分析用户意图"):
+		return schema.AssistantMessage(`{"intent":"course","confidence":0.95,"reason":"课程咨询"}`, nil), nil
+	case strings.Contains(last, "知识库文档"):
+		return schema.AssistantMessage("Go 语言进阶课程价格为 1999 元 [1]。", nil), nil
+	}
+	return schema.AssistantMessage("你好！", nil), nil
+}
+
+func (m *scriptedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	resp, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{resp}), nil
+}
+
+func (m *scriptedChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+// runeEmbedder 按字符统计生成向量，相同文本得到相同向量
+type runeEmbedder struct{}
+
+func (runeEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vector := make([]float64, 16)
+		for _, r := range text {
+			vector[int(r)%16]++
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+// cacheMetrics 记录缓存查询指标
+type cacheMetrics struct {
+	hits   map[string]int
+	misses int
+}
+
+func (m *cacheMetrics) RecordCacheLookup(route string, hit bool) {
+	if hit {
+		m.hits[route]++
+	} else {
+		m.misses++
+	}
+}
+
+func TestChatUseCase_AnswerCache(t *testing.T) {
+	chatModel := &scriptedChatModel{}
+	client := eino.NewClientWithModels(chatModel, runeEmbedder{}, eino.ClientConfig{})
+
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+
+	goCourse := entity.NewDocument("Go 语言进阶课程共 40 课时，价格 1999 元。", "tenant1")
+	vectorRepo := &memoryVectorRepository{docs: []*entity.Document{goCourse}}
+
+	answerCache := cache.NewAnswerCache(0)
+	metrics := &cacheMetrics{hits: make(map[string]int)}
+	policies := map[string]AnswerCachePolicy{"tenant1": {Enabled: true, TTL: time.Hour}}
+
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		eino.NewRAGRetriever(client, vectorRepo, nil),
+		nil,
+		eino.NewResponseGenerator(client),
+		sqlite.NewSessionRepository(dbManager, "tenant1"),
+		time.Hour,
+		log,
+	).
+		WithAnswerCache(answerCache, client.GetEmbedModel(), func(tenantID string) AnswerCachePolicy { return policies[tenantID] }).
+		WithMetrics(metrics)
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+	// 第一次提问：识别意图并检索生成，回答写入缓存
+	resp, err := uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱？", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, false, resp.Metadata["cache_hit"])
+	assert.Equal(t, 2, chatModel.calls)
+
+	// 规范化后相同的问题直接命中缓存，不再调用模型
+	resp, err = uc.Execute(ctx, &ChatRequest{Query: "go 进阶课程多少钱", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, true, resp.Metadata["cache_hit"])
+	assert.InDelta(t, 1.0, resp.Metadata["cache_similarity"], 0.0001)
+	assert.Equal(t, string(entity.IntentCourse), resp.Route)
+	assert.Contains(t, resp.Answer, "1999")
+	require.Len(t, resp.Sources, 1)
+	assert.Equal(t, goCourse.ID, resp.Sources[0].ID)
+	require.Len(t, resp.Blocks, 1)
+	assert.Equal(t, BlockSourceCitation, resp.Blocks[0].Type)
+	assert.Equal(t, 2, chatModel.calls)
+
+	// 流式请求同样命中
+	chunks, err := uc.ExecuteStream(ctx, &ChatRequest{Query: "Go 进阶课程多少钱", TenantID: "tenant1", Stream: true})
+	require.NoError(t, err)
+	var done *StreamChunk
+	for chunk := range chunks {
+		if chunk.Done {
+			done = chunk
+		}
+	}
+	require.NotNil(t, done)
+	assert.Equal(t, true, done.Metadata["cache_hit"])
+	assert.Equal(t, 2, chatModel.calls)
+
+	// 其他租户不共享缓存，未启用时不查询
+	_, err = uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱？", TenantID: "tenant2"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, chatModel.calls)
+
+	// 知识库变更后缓存失效
+	answerCache.InvalidateTenant("tenant1")
+	resp, err = uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱？", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, false, resp.Metadata["cache_hit"])
+	assert.Equal(t, 6, chatModel.calls)
+
+	assert.Equal(t, map[string]int{"course": 2}, metrics.hits)
+	assert.Equal(t, 2, metrics.misses)
+}
+
+func TestChatUseCase_AnswerCacheSkipsPersonalInfo(t *testing.T) {
+	chatModel := &scriptedChatModel{}
+	client := eino.NewClientWithModels(chatModel, runeEmbedder{}, eino.ClientConfig{}).
+		WithRedactor(redact.New(func(tenantID string) redact.Policy {
+			return redact.Policy{Enabled: true, TokenizeLLM: true}
+		}))
+
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+
+	goCourse := entity.NewDocument("Go 语言进阶课程共 40 课时，价格 1999 元。", "tenant1")
+	answerCache := cache.NewAnswerCache(0)
+
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		eino.NewRAGRetriever(client, &memoryVectorRepository{docs: []*entity.Document{goCourse}}, nil),
+		nil,
+		eino.NewResponseGenerator(client),
+		sqlite.NewSessionRepository(dbManager, "tenant1"),
+		time.Hour,
+		log,
+	).WithAnswerCache(answerCache, client.GetEmbedModel(), func(tenantID string) AnswerCachePolicy {
+		return AnswerCachePolicy{Enabled: true, TTL: time.Hour}
+	})
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+	// 嵌入模型只看到占位符，两个只有手机号不同的问题向量相同，第二个问题仍不能命中第一个问题的回答
+	_, err := uc.Execute(ctx, &ChatRequest{Query: "我的手机号是13812345678，Go 进阶课程多少钱", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, chatModel.calls)
+
+	resp, err := uc.Execute(ctx, &ChatRequest{Query: "我的手机号是13987654321，Go 进阶课程多少钱", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.NotEqual(t, true, resp.Metadata["cache_hit"])
+	assert.Equal(t, 4, chatModel.calls)
+
+	// 不含个人信息的问题照常缓存
+	_, err = uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱", TenantID: "tenant1"})
+	require.NoError(t, err)
+	resp, err = uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, true, resp.Metadata["cache_hit"])
+}
+
+func TestAnswerCacheRoutes(t *testing.T) {
+	// 默认只缓存课程咨询
+	assert.Equal(t, []entity.IntentType{entity.IntentCourse}, answerCacheRoutes(AnswerCachePolicy{}, true))
+
+	// 直接回答只在会话第一轮缓存，订单查询不缓存
+	policy := AnswerCachePolicy{Routes: []entity.IntentType{entity.IntentCourse, entity.IntentDirect, entity.IntentOrder}}
+	assert.Equal(t, []entity.IntentType{entity.IntentCourse, entity.IntentDirect}, answerCacheRoutes(policy, true))
+	assert.Equal(t, []entity.IntentType{entity.IntentCourse}, answerCacheRoutes(policy, false))
+}
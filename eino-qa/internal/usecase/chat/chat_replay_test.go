@@ -0,0 +1,175 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/ai/replay"
+	"eino-qa/internal/infrastructure/logger"
+	"eino-qa/internal/infrastructure/repository/sqlite"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replayFixture ChatUseCase 端到端测试的录制文件
+// 重新录制：EINO_REPLAY_MODE=record DASHSCOPE_API_KEY=... go test ./internal/usecase/chat -run TestChatUseCase_Replay
+const replayFixture = "testdata/replay/chat_usecase.json"
+
+// memoryVectorRepository 内存向量仓储，按插入顺序返回固定相似度的文档
+type memoryVectorRepository struct {
+	docs []*entity.Document
+}
+
+func (r *memoryVectorRepository) Search(ctx context.Context, vector []float32, topK int) ([]*entity.Document, error) {
+	var result []*entity.Document
+	for _, doc := range r.docs {
+		if len(result) >= topK {
+			break
+		}
+		copied := *doc
+		copied.Score = 0.9
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (r *memoryVectorRepository) Insert(ctx context.Context, docs []*entity.Document) error {
+	r.docs = append(r.docs, docs...)
+	return nil
+}
+
+func (r *memoryVectorRepository) Delete(ctx context.Context, ids []string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryVectorRepository) GetByID(ctx context.Context, id string) (*entity.Document, error) {
+	for _, doc := range r.docs {
+		if doc.ID == id {
+			return doc, nil
+		}
+	}
+	return nil, fmt.Errorf("document not found: %s", id)
+}
+
+func (r *memoryVectorRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.docs)), nil
+}
+
+func (r *memoryVectorRepository) CreateCollection(ctx context.Context, collectionName string, dimension int) error {
+	return nil
+}
+
+func (r *memoryVectorRepository) CollectionExists(ctx context.Context, collectionName string) (bool, error) {
+	return true, nil
+}
+
+func (r *memoryVectorRepository) DropCollection(ctx context.Context, collectionName string) error {
+	return nil
+}
+
+// newReplayClient 创建接入录制回放模型的客户端
+// 录制模式下调用真实的 DashScope（需要 DASHSCOPE_API_KEY），回放模式下不访问网络
+func newReplayClient(t *testing.T) (*eino.Client, *replay.Cassette) {
+	t.Helper()
+
+	cassette, err := replay.Open(replayFixture, replay.Options{Mode: replay.ModeFromEnv(), Strict: true})
+	require.NoError(t, err)
+
+	var chatModel model.ChatModel
+	var embedModel embedding.Embedder
+	if cassette.Mode() == replay.ModeRecord {
+		apiKey := os.Getenv("DASHSCOPE_API_KEY")
+		if apiKey == "" {
+			t.Skip("DASHSCOPE_API_KEY is required to record fixtures")
+		}
+		client, err := eino.NewClient(eino.ClientConfig{APIKey: apiKey})
+		require.NoError(t, err)
+		chatModel, embedModel = client.GetChatModel(), client.GetEmbedModel()
+	}
+
+	t.Cleanup(func() {
+		if err := cassette.Save(); err != nil {
+			t.Errorf("failed to save fixture: %v", err)
+		}
+	})
+
+	return eino.NewClientWithModels(cassette.ChatModel(chatModel), cassette.Embedder(embedModel), eino.ClientConfig{}), cassette
+}
+
+func TestChatUseCase_Replay(t *testing.T) {
+	client, cassette := newReplayClient(t)
+
+	tempDir := t.TempDir()
+	dbManager := sqlite.NewDBManager(filepath.Join(tempDir, "db"))
+	t.Cleanup(func() { dbManager.Close() })
+
+	goCourse := entity.NewDocument("Go 语言进阶课程共 40 课时，价格 1999 元，包含并发编程和性能优化专题。", "tenant1")
+	goCourse.Metadata["title"] = "Go 语言进阶课程"
+	vectorRepo := &memoryVectorRepository{docs: []*entity.Document{goCourse}}
+
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		eino.NewRAGRetriever(client, vectorRepo, nil),
+		nil,
+		eino.NewResponseGenerator(client),
+		sqlite.NewSessionRepository(dbManager, "tenant1"),
+		time.Hour,
+		log,
+	)
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+	// 课程咨询：检索知识库并引用来源
+	resp, err := uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱？", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, string(entity.IntentCourse), resp.Route)
+	assert.Contains(t, resp.Answer, "1999")
+	require.Len(t, resp.Sources, 1)
+	assert.Equal(t, goCourse.ID, resp.Sources[0].ID)
+
+	// 同一会话的后续问题带上对话历史
+	resp, err = uc.Execute(ctx, &ChatRequest{Query: "谢谢你的解答", TenantID: "tenant1", SessionID: resp.SessionID})
+	require.NoError(t, err)
+	assert.Equal(t, string(entity.IntentDirect), resp.Route)
+	assert.NotEmpty(t, resp.Answer)
+	sessionID := resp.SessionID
+
+	// 流式回答
+	chunks, err := uc.ExecuteStream(ctx, &ChatRequest{Query: "你好，你是谁？", TenantID: "tenant1", Stream: true})
+	require.NoError(t, err)
+	var answer strings.Builder
+	var done *StreamChunk
+	for chunk := range chunks {
+		require.NoError(t, chunk.Error)
+		answer.WriteString(chunk.Content)
+		if chunk.Done {
+			done = chunk
+		}
+	}
+	require.NotNil(t, done)
+	assert.Equal(t, entity.IntentDirect, done.Metadata["intent"])
+	assert.NotEmpty(t, answer.String())
+
+	// 会话已持久化
+	messages, err := sqlite.NewSessionRepository(dbManager, "tenant1").GetMessages(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Len(t, messages, 4)
+
+	// 严格回放模式下未录制的请求失败，而不是访问网络
+	if cassette.Mode() == replay.ModeReplay {
+		_, err = uc.Execute(ctx, &ChatRequest{Query: "一个没有录制过的问题", TenantID: "tenant1"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, replay.ErrUnrecordedCall))
+	}
+}
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,8 @@ import (
 	"eino-qa/internal/domain/repository"
 	"eino-qa/internal/infrastructure/ai/eino"
 	"eino-qa/internal/infrastructure/logger"
+
+	"github.com/cloudwego/eino/components/embedding"
 )
 
 // ChatUseCase 对话用例
@@ -20,6 +23,38 @@ type ChatUseCase struct {
 	sessionRepo       repository.SessionRepository
 	sessionTTL        time.Duration
 	logger            logger.Logger
+	eventPublisher    EventPublisher
+	missedQueryRepos  MissedQueryRepositoryProvider
+	slotDefinitions   SlotDefinitionProvider
+
+	groundednessChecker  *eino.GroundednessChecker
+	groundednessPolicies GroundednessPolicyProvider
+
+	suggestionGenerator *eino.SuggestionGenerator
+	suggestionPolicies  SuggestionPolicyProvider
+	popularQueries      *popularQueries
+	suggestionBudget    *llmBudget
+
+	experiments     ExperimentProvider
+	experimentRepos ExperimentRepositoryProvider
+
+	answerCache         AnswerCache
+	answerEmbedder      embedding.Embedder
+	answerCachePolicies AnswerCachePolicyProvider
+	metrics             MetricsRecorder
+
+	faqRepos    FAQRepositoryProvider
+	faqEmbedder embedding.Embedder
+	faqPolicies FAQPolicyProvider
+	faqCache    *faqCache
+
+	sessionMasker SessionMasker
+
+	injectionGuard    *eino.InjectionGuard
+	injectionPolicies InjectionPolicyProvider
+
+	moderator          *eino.Moderator
+	moderationPolicies ModerationPolicyProvider
 }
 
 // NewChatUseCase 创建新的对话用例
@@ -47,6 +82,24 @@ func NewChatUseCase(
 	}
 }
 
+// WithEventPublisher 设置业务事件发布器（可选）
+func (uc *ChatUseCase) WithEventPublisher(publisher EventPublisher) *ChatUseCase {
+	uc.eventPublisher = publisher
+	return uc
+}
+
+// WithMissedQueryRepository 设置未命中查询仓储（可选），用于记录知识库检索未命中
+func (uc *ChatUseCase) WithMissedQueryRepository(provider MissedQueryRepositoryProvider) *ChatUseCase {
+	uc.missedQueryRepos = provider
+	return uc
+}
+
+// WithSlotDefinitions 设置租户自定义的对话槽位（可选）
+func (uc *ChatUseCase) WithSlotDefinitions(provider SlotDefinitionProvider) *ChatUseCase {
+	uc.slotDefinitions = provider
+	return uc
+}
+
 // Execute 执行对话用例
 func (uc *ChatUseCase) Execute(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
 	// 验证请求
@@ -68,6 +121,17 @@ func (uc *ChatUseCase) Execute(ctx context.Context, req *ChatRequest) (*ChatResp
 		uc.logger.Error(ctx, "failed to load session", map[string]interface{}{"error": err})
 		return nil, fmt.Errorf("failed to load session: %w", err)
 	}
+	ctx = withSessionContext(ctx, req.TenantID, session.ID)
+	ctx, assignments := uc.assignExperiments(ctx, req.TenantID, session.ID)
+	req, injection := uc.guardQuery(ctx, req)
+	var faq *faqMatch
+	var cacheLookup *answerCacheLookup
+	if !injection.stops() {
+		faq = uc.matchFAQ(ctx, session, req.Query)
+		if faq == nil {
+			cacheLookup = uc.lookupAnswerCache(ctx, session, req.Query, assignments)
+		}
+	}
 
 	// 2. 添加用户消息到会话
 	userMessage := entity.NewMessage(req.Query, "user")
@@ -76,36 +140,54 @@ func (uc *ChatUseCase) Execute(ctx context.Context, req *ChatRequest) (*ChatResp
 		return nil, fmt.Errorf("failed to add user message: %w", err)
 	}
 
-	// 3. 识别意图
-	intent, err := uc.intentRecognizer.Recognize(ctx, req.Query, session.GetMessages())
+	// 3. 识别意图（查询被拦截、命中标准问答、语义缓存、订单操作确认回合、槽位追问时直接得到回答）
+	turn, err := uc.routeTurn(ctx, session, req, injection, faq, cacheLookup)
 	if err != nil {
 		uc.logger.Error(ctx, "failed to recognize intent", map[string]interface{}{"error": err})
 		return nil, fmt.Errorf("failed to recognize intent: %w", err)
 	}
 
+	intent, answer := turn.intent, turn.answer
 	uc.logger.Info(ctx, "intent recognized", map[string]interface{}{
 		"intent":     intent.Type,
 		"confidence": intent.Confidence,
 	})
 
 	// 4. 根据意图路由到不同的处理流程
-	var answer string
 	var sources []*entity.Document
+	var blocks []*ResponseBlock
+	var routeMetadata map[string]any
 	var routeErr error
-
-	switch intent.Type {
-	case entity.IntentCourse:
-		answer, sources, routeErr = uc.handleCourseIntent(ctx, req.Query)
-	case entity.IntentOrder:
-		answer, routeErr = uc.handleOrderIntent(ctx, req.Query)
-	case entity.IntentDirect:
-		answer, routeErr = uc.handleDirectIntent(ctx, req.Query, session.GetMessages())
-	case entity.IntentHandoff:
-		answer = uc.handleHandoffIntent(ctx, intent)
+	cacheable := false
+
+	switch {
+	case turn.cached != nil:
+		answer, sources = turn.cached.Answer, turn.cached.Sources
+		blocks = citationBlocks(sources)
+	case turn.answered:
+		// 查询被拦截、标准问答、订单操作确认回合、槽位追问已生成回答
+	case intent.Type == entity.IntentCourse:
+		course := uc.handleCourseIntent(ctx, req.Query, turn.query)
+		answer, sources, routeMetadata, routeErr = course.answer, course.sources, course.metadata, course.err
+		blocks = append(citationBlocks(sources), course.blocks...)
+		cacheable = course.cacheable()
+	case intent.Type == entity.IntentOrder:
+		answer, blocks, routeErr = uc.handleOrderIntent(ctx, session, req.UserID, turn.query)
+	case intent.Type == entity.IntentDirect:
+		answer, routeErr = uc.handleDirectIntent(ctx, turn.query, session.GetMessages())
+		cacheable = routeErr == nil
+	case intent.Type == entity.IntentHandoff:
+		answer, blocks = uc.handleHandoffIntent(ctx, turn.query, intent)
 	default:
 		answer = uc.responseGenerator.GenerateFallbackMessage()
 	}
 
+	// 模型调用并发名额不足时不生成错误回答，返回错误由接口层告知客户端稍后重试
+	if errors.Is(routeErr, entity.ErrLLMConcurrencyLimit) {
+		uc.logger.Warn(ctx, "llm concurrency limit reached", map[string]interface{}{"intent": intent.Type})
+		return nil, fmt.Errorf("failed to handle %s intent: %w", intent.Type, routeErr)
+	}
+
 	// 处理路由错误
 	if routeErr != nil {
 		uc.logger.Error(ctx, "route handling failed", map[string]interface{}{
@@ -115,15 +197,35 @@ func (uc *ChatUseCase) Execute(ctx context.Context, req *ChatRequest) (*ChatResp
 		answer = uc.responseGenerator.GenerateErrorMessage(routeErr)
 	}
 
+	// 回答审核（已生成回答的回合和命中缓存的回答不审核），未通过时改用兜底回复
+	var moderation *moderationVerdict
+	if !turn.answered && turn.cached == nil {
+		if moderation = uc.moderateAnswer(ctx, answer); moderation != nil {
+			answer, sources, blocks, cacheable = moderation.fallback, nil, nil, false
+		}
+	}
+
+	// 建议问题（订单操作确认、槽位追问、出错和未通过审核的回合不推荐，命中缓存时沿用缓存的建议）
+	var suggestions []string
+	switch {
+	case turn.cached != nil:
+		suggestions = turn.cached.Suggestions
+	case !turn.answered && routeErr == nil && moderation == nil:
+		suggestions = uc.suggestFollowUps(ctx, intent.Type, turn.query, answer, sources, blocks)
+	}
+	if cacheable {
+		uc.storeAnswer(ctx, cacheLookup, intent, answer, sources, suggestions)
+	}
+
 	// 5. 添加助手消息到会话
-	assistantMessage := entity.NewMessage(answer, "assistant")
+	assistantMessage := newAssistantMessage(answer, string(intent.Type), intent)
 	if err := session.AddMessage(assistantMessage); err != nil {
 		uc.logger.Error(ctx, "failed to add assistant message", map[string]interface{}{"error": err})
 		// 不返回错误，因为回答已经生成
 	}
 
 	// 6. 保存会话
-	if err := uc.sessionRepo.Save(ctx, session); err != nil {
+	if err := uc.saveSession(ctx, session); err != nil {
 		uc.logger.Error(ctx, "failed to save session", map[string]interface{}{"error": err})
 		// 不返回错误，因为回答已经生成
 	}
@@ -133,19 +235,33 @@ func (uc *ChatUseCase) Execute(ctx context.Context, req *ChatRequest) (*ChatResp
 	uc.logger.Info(ctx, "chat request completed", map[string]interface{}{
 		"duration_ms": duration.Milliseconds(),
 		"intent":      intent.Type,
+		"experiments": experimentMetadata(assignments),
 	})
+	uc.recordExperimentOutcomes(ctx, assignments, newTurnOutcome(intent, assistantMessage.ID, duration, routeMetadata))
 
 	// 7. 构建响应
+	metadata := mergeMetadata(map[string]any{
+		"intent":      intent.Type,
+		"confidence":  intent.Confidence,
+		"duration_ms": duration.Milliseconds(),
+	}, routeMetadata)
+	mergeMetadata(metadata, cacheLookup.metadata())
+	mergeMetadata(metadata, faq.metadata())
+	mergeMetadata(metadata, injection.metadata())
+	mergeMetadata(metadata, moderation.metadata())
+	if variants := experimentMetadata(assignments); variants != nil {
+		metadata["experiments"] = variants
+	}
+
 	response := &ChatResponse{
-		Answer:    answer,
-		Route:     string(intent.Type),
-		Sources:   sources,
-		SessionID: session.ID,
-		Metadata: map[string]any{
-			"intent":      intent.Type,
-			"confidence":  intent.Confidence,
-			"duration_ms": duration.Milliseconds(),
-		},
+		Answer:      answer,
+		Route:       string(intent.Type),
+		Sources:     sources,
+		Blocks:      blocks,
+		Suggestions: suggestions,
+		SessionID:   session.ID,
+		MessageID:   assistantMessage.ID,
+		Metadata:    metadata,
 	}
 
 	return response, nil
@@ -176,32 +292,57 @@ func (uc *ChatUseCase) loadOrCreateSession(ctx context.Context, tenantID, sessio
 }
 
 // handleCourseIntent 处理课程咨询意图
-func (uc *ChatUseCase) handleCourseIntent(ctx context.Context, query string) (string, []*entity.Document, error) {
+// userQuery 为用户本轮输入，query 为实际检索的查询（槽位填充后为补全后的原始问题）
+// RAG 回答经过依据校验（如已启用），检索失败时返回降级消息，模型调用并发名额不足时返回错误
+func (uc *ChatUseCase) handleCourseIntent(ctx context.Context, userQuery, query string) *courseAnswer {
 	uc.logger.Info(ctx, "handling course intent", map[string]interface{}{"query": query})
 
+	result := &courseAnswer{metadata: make(map[string]any)}
+
 	// 使用 RAG 检索器
-	answer, sources, err := uc.ragRetriever.Retrieve(ctx, query)
+	answer, sources, err := uc.ragRetriever.Retrieve(experimentScope(ctx, entity.ExperimentRAG), query)
 	if err != nil {
 		uc.logger.Error(ctx, "RAG retrieval failed", map[string]interface{}{"error": err})
+		if errors.Is(err, entity.ErrLLMConcurrencyLimit) {
+			result.err = err
+			return result
+		}
+		uc.handleRetrieveError(ctx, userQuery, err)
+		if errors.Is(err, eino.ErrNoRelevantDocuments) {
+			result.metadata["rag_miss"] = true
+		}
 		// 如果 RAG 失败，返回降级消息
-		return uc.responseGenerator.GenerateFallbackMessage(), nil, nil
+		result.answer = uc.responseGenerator.GenerateFallbackMessage()
+		return result
 	}
 
-	return answer, sources, nil
+	result.answer, result.sources = answer, sources
+	uc.verifyGroundedness(ctx, query, result)
+
+	return result
 }
 
-// handleOrderIntent 处理订单查询意图
-func (uc *ChatUseCase) handleOrderIntent(ctx context.Context, query string) (string, error) {
+// handleOrderIntent 处理订单查询意图，返回回答和订单卡片
+// 取消订单、申请退款等操作请求会先生成确认摘要，待用户下一轮确认后执行
+func (uc *ChatUseCase) handleOrderIntent(ctx context.Context, session *entity.Session, userID, query string) (string, []*ResponseBlock, error) {
 	uc.logger.Info(ctx, "handling order intent", map[string]interface{}{"query": query})
 
-	// 使用订单查询器
-	answer, err := uc.orderQuerier.Query(ctx, query)
+	if reply, blocks, ok := uc.handleOrderActionRequest(ctx, session, userID, query); ok {
+		return reply, blocks, nil
+	}
+
+	// 使用订单查询器（只返回属于当前用户的订单）
+	result, err := uc.orderQuerier.QueryOrders(ctx, userID, query)
+	var missing *eino.MissingSlotError
+	if errors.As(err, &missing) {
+		return uc.askForSlot(ctx, session, entity.IntentOrder, query, missing.Slot, missing.Prompt), nil, nil
+	}
 	if err != nil {
 		uc.logger.Error(ctx, "order query failed", map[string]interface{}{"error": err})
-		return "", err
+		return "", nil, err
 	}
 
-	return answer, nil
+	return result.Answer, orderCardBlocks(result.Orders), nil
 }
 
 // handleDirectIntent 处理直接回答意图
@@ -209,7 +350,7 @@ func (uc *ChatUseCase) handleDirectIntent(ctx context.Context, query string, his
 	uc.logger.Info(ctx, "handling direct intent", map[string]interface{}{"query": query})
 
 	// 使用响应生成器
-	answer, err := uc.responseGenerator.Generate(ctx, query, history)
+	answer, err := uc.responseGenerator.Generate(experimentScope(ctx, entity.ExperimentDirect), query, history)
 	if err != nil {
 		uc.logger.Error(ctx, "response generation failed", map[string]interface{}{"error": err})
 		return "", err
@@ -218,8 +359,8 @@ func (uc *ChatUseCase) handleDirectIntent(ctx context.Context, query string, his
 	return answer, nil
 }
 
-// handleHandoffIntent 处理人工转接意图
-func (uc *ChatUseCase) handleHandoffIntent(ctx context.Context, intent *entity.Intent) string {
+// handleHandoffIntent 处理人工转接意图，返回回答和转人工提示
+func (uc *ChatUseCase) handleHandoffIntent(ctx context.Context, query string, intent *entity.Intent) (string, []*ResponseBlock) {
 	uc.logger.Info(ctx, "handling handoff intent", map[string]interface{}{})
 
 	reason := ""
@@ -227,5 +368,96 @@ func (uc *ChatUseCase) handleHandoffIntent(ctx context.Context, intent *entity.I
 		reason = r
 	}
 
-	return uc.responseGenerator.GenerateHandoffMessage(reason)
+	uc.publishEvent(ctx, entity.EventHandoffCreated, map[string]any{
+		"query":      query,
+		"reason":     reason,
+		"confidence": intent.Confidence,
+	})
+
+	message := uc.responseGenerator.GenerateHandoffMessage(reason)
+	return message, []*ResponseBlock{handoffBlock(reason, message)}
+}
+
+// handleRetrieveError 处理 RAG 检索错误
+// 未命中时记录到 missed_queries 表并发布 rag.miss 事件
+// 实际检索的查询与用户输入不同时（如槽位填充补全）才记录改写后的查询
+func (uc *ChatUseCase) handleRetrieveError(ctx context.Context, userQuery string, err error) {
+	if !errors.Is(err, eino.ErrNoRelevantDocuments) {
+		return
+	}
+
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	miss := entity.NewMissedQuery(tenantID, userQuery, string(entity.IntentCourse))
+
+	var missErr *eino.RetrievalMissError
+	if errors.As(err, &missErr) {
+		if missErr.Query != userQuery {
+			miss.RewrittenQuery = missErr.Query
+		}
+		miss.TopScore = missErr.TopScore
+	}
+
+	uc.recordMissedQuery(ctx, miss)
+
+	uc.publishEvent(ctx, entity.EventRAGMiss, map[string]any{
+		"query":           miss.Query,
+		"rewritten_query": miss.RewrittenQuery,
+		"intent":          miss.Intent,
+		"top_score":       miss.TopScore,
+	})
+}
+
+// recordMissedQuery 记录未命中查询（未配置仓储时忽略，失败不影响对话）
+func (uc *ChatUseCase) recordMissedQuery(ctx context.Context, miss *entity.MissedQuery) {
+	if uc.missedQueryRepos == nil || miss.TenantID == "" {
+		return
+	}
+
+	if err := uc.missedQueryRepos(miss.TenantID).Create(ctx, miss); err != nil {
+		uc.logger.Warn(ctx, "failed to record missed query", map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// publishEvent 发布业务事件（未配置发布器时忽略）
+func (uc *ChatUseCase) publishEvent(ctx context.Context, eventType entity.WebhookEventType, data map[string]any) {
+	if uc.eventPublisher == nil {
+		return
+	}
+
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	if sessionID, ok := ctx.Value("session_id").(string); ok {
+		data["session_id"] = sessionID
+	}
+
+	if err := uc.eventPublisher.Publish(ctx, tenantID, eventType, data); err != nil {
+		uc.logger.Warn(ctx, "failed to publish event", map[string]interface{}{
+			"event_type": eventType,
+			"error":      err,
+		})
+	}
+}
+
+// mergeMetadata 将路由处理产生的元数据合并到响应元数据
+func mergeMetadata(dst, src map[string]any) map[string]any {
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// newAssistantMessage 创建助手消息，并记录路由和意图供反馈统计使用
+func newAssistantMessage(answer, route string, intent *entity.Intent) *entity.Message {
+	message := entity.NewMessage(answer, "assistant")
+	message.Metadata["route"] = route
+	message.Metadata["intent"] = string(intent.Type)
+	message.Metadata["confidence"] = intent.Confidence
+	return message
+}
+
+// withSessionContext 将租户和会话 ID 写入 context，供日志和事件使用
+func withSessionContext(ctx context.Context, tenantID, sessionID string) context.Context {
+	ctx = context.WithValue(ctx, "tenant_id", tenantID)
+	return context.WithValue(ctx, "session_id", sessionID)
 }
@@ -2,14 +2,23 @@ package chat
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/infrastructure/ai/eino"
 	"eino-qa/internal/infrastructure/logger"
+	"eino-qa/internal/infrastructure/repository/sqlite"
 
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSessionRepository 模拟会话仓储
@@ -196,5 +205,707 @@ func TestNewChatUseCase(t *testing.T) {
 	})
 }
 
+// MockMissedQueryRepository 模拟未命中查询仓储
+type MockMissedQueryRepository struct {
+	mock.Mock
+}
+
+func (m *MockMissedQueryRepository) Create(ctx context.Context, query *entity.MissedQuery) error {
+	args := m.Called(ctx, query)
+	return args.Error(0)
+}
+
+func (m *MockMissedQueryRepository) List(ctx context.Context, offset, limit int) ([]*entity.MissedQuery, error) {
+	args := m.Called(ctx, offset, limit)
+	return args.Get(0).([]*entity.MissedQuery), args.Error(1)
+}
+
+func (m *MockMissedQueryRepository) FindByIDs(ctx context.Context, ids []uint) ([]*entity.MissedQuery, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]*entity.MissedQuery), args.Error(1)
+}
+
+func (m *MockMissedQueryRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMissedQueryRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+// MockEventPublisher 模拟事件发布器
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, tenantID string, eventType entity.WebhookEventType, data map[string]any) error {
+	args := m.Called(ctx, tenantID, eventType, data)
+	return args.Error(0)
+}
+
+// TestChatUseCase_handleRetrieveError 测试检索未命中的记录和事件发布
+func TestChatUseCase_handleRetrieveError(t *testing.T) {
+	log, _ := logger.New(logger.Config{
+		Level:  "info",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	missedRepo := new(MockMissedQueryRepository)
+	publisher := new(MockEventPublisher)
+
+	uc := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), 0, log).
+		WithEventPublisher(publisher).
+		WithMissedQueryRepository(func(tenantID string) repository.MissedQueryRepository {
+			assert.Equal(t, "tenant1", tenantID)
+			return missedRepo
+		})
+
+	ctx := withSessionContext(context.Background(), "tenant1", "sess_1")
+
+	t.Run("miss is recorded with score and rewritten query", func(t *testing.T) {
+		missedRepo.On("Create", mock.Anything, mock.MatchedBy(func(q *entity.MissedQuery) bool {
+			return q.TenantID == "tenant1" &&
+				q.Query == "退款要多久" &&
+				q.RewrittenQuery == "课程退款需要多久" &&
+				q.Intent == string(entity.IntentCourse) &&
+				q.TopScore == 0.42
+		})).Return(nil).Once()
+		publisher.On("Publish", mock.Anything, "tenant1", entity.EventRAGMiss, mock.MatchedBy(func(data map[string]any) bool {
+			return data["session_id"] == "sess_1" && data["top_score"] == 0.42
+		})).Return(nil).Once()
+
+		err := &eino.RetrievalMissError{Query: "课程退款需要多久", TopScore: 0.42}
+		uc.handleRetrieveError(ctx, "退款要多久", err)
+
+		missedRepo.AssertExpectations(t)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("rewritten query is empty when retrieval used the user's text", func(t *testing.T) {
+		missedRepo.On("Create", mock.Anything, mock.MatchedBy(func(q *entity.MissedQuery) bool {
+			return q.Query == "退款要多久" && q.RewrittenQuery == ""
+		})).Return(nil).Once()
+		publisher.On("Publish", mock.Anything, "tenant1", entity.EventRAGMiss, mock.MatchedBy(func(data map[string]any) bool {
+			return data["rewritten_query"] == ""
+		})).Return(nil).Once()
+
+		err := &eino.RetrievalMissError{Query: "退款要多久", TopScore: 0.3}
+		uc.handleRetrieveError(ctx, "退款要多久", err)
+
+		missedRepo.AssertExpectations(t)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("other errors are ignored", func(t *testing.T) {
+		uc.handleRetrieveError(ctx, "退款要多久", assert.AnError)
+
+		missedRepo.AssertNumberOfCalls(t, "Create", 2)
+		publisher.AssertNumberOfCalls(t, "Publish", 2)
+	})
+}
+
 // 注意：完整的集成测试需要实际的 AI 组件和数据库连接
 // 这里只提供了基本的单元测试示例
+
+// TestChatUseCase_resolvePendingOrderAction 测试订单操作确认回合中不执行操作的分支
+func TestChatUseCase_resolvePendingOrderAction(t *testing.T) {
+	log, _ := logger.New(logger.Config{
+		Level:  "info",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	uc := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), 0, log)
+	ctx := withSessionContext(context.Background(), "tenant1", "sess_1")
+
+	newSession := func(ttl time.Duration) *entity.Session {
+		session := entity.NewSession("tenant1", time.Hour)
+		session.SetPendingOrderAction(entity.NewPendingOrderAction(entity.OrderActionCancel, "#20251114001", "alice", "", ttl))
+		return session
+	}
+
+	t.Run("no pending action", func(t *testing.T) {
+		answer, ok := uc.resolvePendingOrderAction(ctx, entity.NewSession("tenant1", time.Hour), "alice", "确认")
+		assert.False(t, ok)
+		assert.Empty(t, answer)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		session := newSession(time.Minute)
+		answer, ok := uc.resolvePendingOrderAction(ctx, session, "alice", "算了。")
+		assert.True(t, ok)
+		assert.Contains(t, answer, "已放弃")
+		assert.Nil(t, session.PendingOrderAction())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		session := newSession(time.Minute)
+		session.PendingOrderAction().ExpiresAt = time.Now().Add(-time.Second)
+		answer, ok := uc.resolvePendingOrderAction(ctx, session, "alice", "确认")
+		assert.True(t, ok)
+		assert.Contains(t, answer, "超时")
+		assert.Nil(t, session.PendingOrderAction())
+	})
+
+	t.Run("other reply abandons the action", func(t *testing.T) {
+		session := newSession(time.Minute)
+		answer, ok := uc.resolvePendingOrderAction(ctx, session, "alice", "Go 语言课程有哪些")
+		assert.False(t, ok)
+		assert.Empty(t, answer)
+		assert.Nil(t, session.PendingOrderAction())
+	})
+
+	t.Run("different user cannot confirm", func(t *testing.T) {
+		session := newSession(time.Minute)
+		answer, ok := uc.resolvePendingOrderAction(ctx, session, "bob", "确认")
+		assert.False(t, ok)
+		assert.Empty(t, answer)
+		assert.Nil(t, session.PendingOrderAction())
+	})
+}
+
+// TestChatUseCase_slotFilling 测试多轮槽位填充
+func TestChatUseCase_slotFilling(t *testing.T) {
+	log, _ := logger.New(logger.Config{
+		Level:  "info",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	phone, err := entity.NewSlotDefinition("phone", entity.IntentHandoff, "请留下您的手机号。", `1[3-9]\d{9}`)
+	assert.NoError(t, err)
+	phone.Required = true
+	phone.MaxAttempts = 1
+
+	// 意图识别器为 nil：填充槽位的回合不应再调用意图识别
+	uc := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), 0, log).
+		WithSlotDefinitions(func(tenantID string) []*entity.SlotDefinition {
+			if tenantID == "tenant1" {
+				return []*entity.SlotDefinition{phone}
+			}
+			return nil
+		})
+	ctx := withSessionContext(context.Background(), "tenant1", "sess_1")
+
+	t.Run("order id reply resumes order flow", func(t *testing.T) {
+		session := entity.NewSession("tenant1", time.Hour)
+		prompt := uc.askForSlot(ctx, session, entity.IntentOrder, "这个订单什么时候开通", entity.SlotOrderID, "请提供您要查询的订单号。")
+		assert.Equal(t, "请提供您要查询的订单号。", prompt)
+
+		turn, err := uc.recognizeIntent(ctx, session, &ChatRequest{Query: "20251114001", TenantID: "tenant1", UserID: "alice"})
+		assert.NoError(t, err)
+		assert.Equal(t, entity.IntentOrder, turn.intent.Type)
+		assert.False(t, turn.answered)
+		assert.Equal(t, "这个订单什么时候开通 #20251114001", turn.query)
+		assert.Equal(t, "#20251114001", session.SlotValue(entity.SlotOrderID))
+		assert.Nil(t, session.ExpectedSlot())
+	})
+
+	t.Run("tenant slot asks again then gives up", func(t *testing.T) {
+		session := entity.NewSession("tenant1", time.Hour)
+		assert.Equal(t, phone, uc.missingRequiredSlot(session, "tenant1", entity.IntentHandoff))
+		assert.Nil(t, uc.missingRequiredSlot(session, "tenant2", entity.IntentHandoff))
+
+		// 租户定义的追问话术优先
+		prompt := uc.askForSlot(ctx, session, entity.IntentHandoff, "转人工", "phone", "")
+		assert.Equal(t, "请留下您的手机号。", prompt)
+
+		turn := uc.resumeExpectedSlot(ctx, session, "tenant1", "不方便")
+		assert.NotNil(t, turn)
+		assert.True(t, turn.answered)
+		assert.Equal(t, "请留下您的手机号。", turn.answer)
+		assert.NotNil(t, session.ExpectedSlot())
+
+		// 超过重试次数后按正常流程处理
+		assert.Nil(t, uc.resumeExpectedSlot(ctx, session, "tenant1", "算了"))
+		assert.Nil(t, session.ExpectedSlot())
+	})
+
+	t.Run("tenant slot filled", func(t *testing.T) {
+		session := entity.NewSession("tenant1", time.Hour)
+		uc.askForSlot(ctx, session, entity.IntentHandoff, "转人工", "phone", "")
+
+		turn := uc.resumeExpectedSlot(ctx, session, "tenant1", "13800138000")
+		assert.NotNil(t, turn)
+		assert.Equal(t, entity.IntentHandoff, turn.intent.Type)
+		assert.Equal(t, "转人工 13800138000", turn.query)
+		assert.Nil(t, uc.missingRequiredSlot(session, "tenant1", entity.IntentHandoff))
+	})
+
+	t.Run("expired slot is ignored", func(t *testing.T) {
+		session := entity.NewSession("tenant1", time.Hour)
+		uc.askForSlot(ctx, session, entity.IntentOrder, "这个订单什么时候开通", entity.SlotOrderID, "请提供订单号。")
+		session.ExpectedSlot().ExpiresAt = time.Now().Add(-time.Second)
+
+		assert.Nil(t, uc.resumeExpectedSlot(ctx, session, "tenant1", "20251114001"))
+		assert.Nil(t, session.ExpectedSlot())
+	})
+}
+
+// TestResponseBlocks 测试结构化响应块生成
+func TestResponseBlocks(t *testing.T) {
+	t.Run("single order card with actions", func(t *testing.T) {
+		order := entity.NewOrder("alice", "Go 进阶课程", 299, "tenant1")
+		order.ID = "#20251114001"
+
+		blocks := orderCardBlocks([]*entity.Order{order})
+		assert.Len(t, blocks, 2)
+		assert.Equal(t, BlockOrderCard, blocks[0].Type)
+		assert.Equal(t, "#20251114001", blocks[0].OrderCard.OrderID)
+		assert.Equal(t, "待支付", blocks[0].OrderCard.StatusLabel)
+		assert.Equal(t, BlockQuickReplies, blocks[1].Type)
+		assert.Equal(t, []string{"取消订单 #20251114001"}, blocks[1].QuickReplies.Options)
+	})
+
+	t.Run("terminal order has no quick replies", func(t *testing.T) {
+		order := entity.NewOrder("alice", "Go 进阶课程", 299, "tenant1")
+		order.Status = entity.OrderStatusRefunded
+
+		blocks := orderCardBlocks([]*entity.Order{order})
+		assert.Len(t, blocks, 1)
+	})
+
+	t.Run("order list has no quick replies", func(t *testing.T) {
+		orders := []*entity.Order{
+			entity.NewOrder("alice", "Go 课程", 199, "tenant1"),
+			entity.NewOrder("alice", "Python 课程", 99, "tenant1"),
+		}
+		blocks := orderCardBlocks(orders)
+		assert.Len(t, blocks, 2)
+		for _, block := range blocks {
+			assert.Equal(t, BlockOrderCard, block.Type)
+		}
+	})
+
+	t.Run("citations", func(t *testing.T) {
+		titled := entity.NewDocument("Go 课程共 12 周，包含并发编程与工程实践", "tenant1")
+		titled.AddMetadata("title", "Go 课程大纲")
+		titled.AddMetadata("url", "https://example.com/go")
+		faq := entity.NewDocument("可以在购买后 7 天内申请退款", "tenant1")
+		faq.AddMetadata("question", "如何退款？")
+		plain := entity.NewDocument("无标题文档内容", "tenant1")
+
+		blocks := citationBlocks([]*entity.Document{titled, faq, plain})
+		assert.Len(t, blocks, 3)
+		assert.Equal(t, 1, blocks[0].SourceCitation.Index)
+		assert.Equal(t, titled.ID, blocks[0].SourceCitation.DocumentID)
+		assert.Equal(t, "Go 课程大纲", blocks[0].SourceCitation.Title)
+		assert.Equal(t, "https://example.com/go", blocks[0].SourceCitation.URL)
+		assert.Equal(t, "如何退款？", blocks[1].SourceCitation.Title)
+		assert.Equal(t, "无标题文档内容", blocks[2].SourceCitation.Title)
+		assert.Equal(t, 3, blocks[2].SourceCitation.Index)
+	})
+
+	t.Run("action confirmation", func(t *testing.T) {
+		action := entity.NewPendingOrderAction(entity.OrderActionRefund, "#20251114001", "alice", "不想学了", time.Minute)
+
+		blocks := actionConfirmationBlocks(action)
+		assert.Len(t, blocks, 2)
+		assert.Equal(t, BlockActionConfirmation, blocks[0].Type)
+		assert.Equal(t, "申请退款", blocks[0].ActionConfirmation.ActionLabel)
+		assert.Equal(t, "#20251114001", blocks[0].ActionConfirmation.OrderID)
+		assert.Equal(t, []string{"确认", "算了"}, blocks[1].QuickReplies.Options)
+	})
+
+	t.Run("empty quick replies", func(t *testing.T) {
+		assert.Nil(t, quickRepliesBlock())
+	})
+}
+
+// TestChatUseCase_verifyGroundedness 测试回答依据校验策略
+func TestChatUseCase_verifyGroundedness(t *testing.T) {
+	log, _ := logger.New(logger.Config{
+		Level:  "info",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	policies := map[string]GroundednessPolicy{
+		"flag":     {Enabled: true, Method: eino.GroundednessMethodLexical, Action: GroundednessActionFlag},
+		"handoff":  {Enabled: true, Method: eino.GroundednessMethodLexical, Action: GroundednessActionHandoff},
+		"disabled": {Action: GroundednessActionHandoff},
+	}
+	uc := NewChatUseCase(nil, nil, nil, &eino.ResponseGenerator{}, new(MockSessionRepository), 0, log).
+		WithGroundedness(&eino.GroundednessChecker{}, func(tenantID string) GroundednessPolicy {
+			return policies[tenantID]
+		})
+
+	newResult := func(answer string) *courseAnswer {
+		return &courseAnswer{
+			answer:   answer,
+			sources:  []*entity.Document{{ID: "doc_1", Content: "Go 语言进阶课程共 12 周，包含并发编程和微服务实战。"}},
+			metadata: make(map[string]any),
+		}
+	}
+	const grounded = "Go 语言进阶课程共 12 周[1]。"
+	const hallucinated = "Go 语言进阶课程共 12 周[1]。报名即送价值 999 元的机械键盘一把！"
+
+	t.Run("grounded answer passes", func(t *testing.T) {
+		result := newResult(grounded)
+		uc.verifyGroundedness(withSessionContext(context.Background(), "handoff", "sess_1"), "Go 课程多久", result)
+		assert.Equal(t, grounded, result.answer)
+		assert.Equal(t, 1.0, result.metadata["groundedness_score"])
+		assert.Nil(t, result.metadata["low_groundedness"])
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		result := newResult(hallucinated)
+		uc.verifyGroundedness(withSessionContext(context.Background(), "flag", "sess_1"), "Go 课程有什么优惠", result)
+		assert.Equal(t, hallucinated, result.answer)
+		assert.Len(t, result.sources, 1)
+		assert.Equal(t, 0.5, result.metadata["groundedness_score"])
+		assert.Equal(t, "lexical", result.metadata["groundedness_method"])
+		assert.Equal(t, true, result.metadata["low_groundedness"])
+	})
+
+	t.Run("handoff", func(t *testing.T) {
+		result := newResult(hallucinated)
+		uc.verifyGroundedness(withSessionContext(context.Background(), "handoff", "sess_1"), "Go 课程有什么优惠", result)
+		assert.Contains(t, result.answer, "无法根据知识库确认")
+		assert.Nil(t, result.sources)
+		assert.Len(t, result.blocks, 1)
+		assert.Equal(t, BlockHandoffNotice, result.blocks[0].Type)
+		assert.Equal(t, true, result.metadata["handoff"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		result := newResult(hallucinated)
+		uc.verifyGroundedness(withSessionContext(context.Background(), "disabled", "sess_1"), "Go 课程有什么优惠", result)
+		assert.Equal(t, hallucinated, result.answer)
+		assert.Empty(t, result.metadata)
+	})
+}
+
+// TestChatUseCase_suggestFollowUps 测试建议问题
+func TestChatUseCase_suggestFollowUps(t *testing.T) {
+	log, _ := logger.New(logger.Config{
+		Level:  "info",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	uc := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), 0, log).
+		WithSuggestions(nil, func(tenantID string) SuggestionPolicy {
+			return SuggestionPolicy{Enabled: tenantID != "disabled"}
+		})
+	ctx := withSessionContext(context.Background(), "tenant1", "sess_1")
+
+	t.Run("order status aware", func(t *testing.T) {
+		order := entity.NewOrder("alice", "Go 进阶课程", 299, "tenant1")
+		order.Status = entity.OrderStatusPaid
+
+		suggestions := uc.suggestFollowUps(ctx, entity.IntentOrder, "查询订单", "您的订单已支付", nil, orderCardBlocks([]*entity.Order{order}))
+		assert.Equal(t, []string{"课程什么时候开通？", "如何申请退款？", "可以开发票吗？"}, suggestions)
+	})
+
+	t.Run("order list", func(t *testing.T) {
+		suggestions := uc.suggestFollowUps(ctx, entity.IntentOrder, "我一共花了多少钱", "订单总金额：198.00 元", nil, nil)
+		assert.Equal(t, []string{"我有哪些未支付的订单？", "如何申请退款？"}, suggestions)
+	})
+
+	t.Run("course from popular queries", func(t *testing.T) {
+		sources := []*entity.Document{{ID: "doc_1", Content: "Go 语言进阶课程共 12 周，包含并发编程和微服务实战，购买后 7 天内可以退款。"}}
+		record := func(query string, sessions ...string) {
+			for _, sessionID := range sessions {
+				uc.popularQueries.record("tenant1", sessionID, query)
+			}
+		}
+		record("Go 语言进阶课程共几周？", "s1", "s2", "s3")
+		record("Go 语言进阶课程共几周", "s4")
+		record("Go 课程包含微服务实战吗？", "s1", "s2", "s3")
+		record("Python 课程多少钱？", "s1", "s2", "s3")
+		record("7 天内可以退款吗？", "s1", "s2", "s3")
+
+		// 同一会话重复提问只计一次，不足最少会话数的问题不推荐
+		record("Go 课程 12 周能学完并发编程吗？", "s5", "s5", "s5", "s6")
+
+		suggestions := uc.suggestFollowUps(ctx, entity.IntentCourse, "Go 课程讲什么", "Go 语言进阶课程包含并发编程和微服务实战[1]。", sources, nil)
+		assert.Equal(t, []string{"Go 语言进阶课程共几周？", "7 天内可以退款吗？", "Go 课程包含微服务实战吗？"}, suggestions)
+
+		// 本轮问题计入热门问题，但不推荐给提问者本人
+		suggestions = uc.suggestFollowUps(ctx, entity.IntentCourse, "Go 语言进阶课程共几周", "共 12 周[1]。", sources, nil)
+		assert.NotContains(t, suggestions, "Go 语言进阶课程共几周？")
+	})
+
+	t.Run("popular queries with personal information are not recorded", func(t *testing.T) {
+		sources := []*entity.Document{{ID: "doc_1", Content: "Go 语言进阶课程共 12 周，包含并发编程和微服务实战，购买后 7 天内可以退款。"}}
+		query := "Go 课程 12 周，手机号 13812345678 能退款吗"
+		for _, sessionID := range []string{"s1", "s2", "s3"} {
+			uc.suggestFollowUps(withSessionContext(context.Background(), "pii", sessionID), entity.IntentCourse, query, "可以[1]。", sources, nil)
+		}
+		assert.Empty(t, uc.popularQueries.top("pii", maxPopularQueries, 1))
+	})
+
+	t.Run("popular queries flagged by moderation rules", func(t *testing.T) {
+		rules, err := eino.NewModerationRules(nil, []string{"XX学堂"}, nil)
+		require.NoError(t, err)
+		moderated := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), 0, log).
+			WithSuggestions(nil, func(string) SuggestionPolicy { return SuggestionPolicy{Enabled: true} }).
+			WithModeration(eino.NewModerator(nil), func(string) ModerationPolicy { return ModerationPolicy{Enabled: true, Rules: rules} })
+
+		sources := []*entity.Document{{ID: "doc_1", Content: "Go 语言进阶课程共 12 周，包含并发编程和微服务实战，购买后 7 天内可以退款。"}}
+		for _, sessionID := range []string{"s1", "s2", "s3"} {
+			moderated.popularQueries.record("tenant1", sessionID, "Go 语言进阶课程共几周？")
+			moderated.popularQueries.record("tenant1", sessionID, "Go 课程比 XX学堂 的微服务实战好吗？")
+			moderated.popularQueries.record("tenant1", sessionID, "7 天内可以退款吗？")
+		}
+
+		suggestions := moderated.suggestFollowUps(ctx, entity.IntentCourse, "Go 课程讲什么", "Go 语言进阶课程包含并发编程和微服务实战[1]。", sources, nil)
+		assert.Equal(t, []string{"7 天内可以退款吗？", "Go 语言进阶课程共几周？"}, suggestions)
+	})
+
+	t.Run("suggestions flagged by moderation", func(t *testing.T) {
+		rules, err := eino.NewModerationRules([]string{"发票"}, nil, nil)
+		require.NoError(t, err)
+		moderated := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), 0, log).
+			WithSuggestions(nil, func(string) SuggestionPolicy { return SuggestionPolicy{Enabled: true} }).
+			WithModeration(eino.NewModerator(nil), func(tenantID string) ModerationPolicy {
+				return ModerationPolicy{Enabled: tenantID == "tenant1", Rules: rules}
+			})
+
+		order := entity.NewOrder("alice", "Go 进阶课程", 299, "tenant1")
+		order.Status = entity.OrderStatusPaid
+		blocks := orderCardBlocks([]*entity.Order{order})
+
+		suggestions := moderated.suggestFollowUps(ctx, entity.IntentOrder, "查询订单", "您的订单已支付", nil, blocks)
+		assert.Equal(t, []string{"课程什么时候开通？", "如何申请退款？"}, suggestions)
+
+		// 未启用审核的租户不受影响
+		other := withSessionContext(context.Background(), "tenant2", "sess_1")
+		suggestions = moderated.suggestFollowUps(other, entity.IntentOrder, "查询订单", "您的订单已支付", nil, blocks)
+		assert.Equal(t, []string{"课程什么时候开通？", "如何申请退款？", "可以开发票吗？"}, suggestions)
+
+		// 丢弃后不足最少数量时不返回建议
+		order.Status = entity.OrderStatusCancelled
+		blocks = orderCardBlocks([]*entity.Order{order})
+		rules, err = eino.NewModerationRules([]string{"重新购买"}, nil, nil)
+		require.NoError(t, err)
+		assert.Len(t, moderated.suggestFollowUps(other, entity.IntentOrder, "查询订单", "订单已取消", nil, blocks), 2)
+		assert.Nil(t, moderated.suggestFollowUps(ctx, entity.IntentOrder, "查询订单", "订单已取消", nil, blocks))
+	})
+
+	t.Run("fallback course answer", func(t *testing.T) {
+		assert.Nil(t, uc.suggestFollowUps(ctx, entity.IntentCourse, "Go 课程讲什么", "抱歉，暂时无法回答", nil, nil))
+	})
+
+	t.Run("too few suggestions", func(t *testing.T) {
+		order := entity.NewOrder("alice", "Go 进阶课程", 299, "tenant1")
+		order.Status = entity.OrderStatusRefunded
+		suggestions := uc.suggestFollowUps(ctx, entity.IntentOrder, "退款多久到账", "已退款", nil, orderCardBlocks([]*entity.Order{order}))
+		assert.Nil(t, suggestions)
+	})
+
+	t.Run("disabled tenant", func(t *testing.T) {
+		disabled := withSessionContext(context.Background(), "disabled", "sess_1")
+		assert.Nil(t, uc.suggestFollowUps(disabled, entity.IntentOrder, "我的订单", "", nil, nil))
+	})
+}
+
+// TestLLMBudget 测试 LLM 调用预算
+func TestLLMBudget(t *testing.T) {
+	budget := newLLMBudget(time.Hour)
+	now := time.Now()
+
+	assert.False(t, budget.allow("tenant1", 0, now))
+	assert.True(t, budget.allow("tenant1", 2, now))
+	assert.True(t, budget.allow("tenant1", 2, now.Add(time.Minute)))
+	assert.False(t, budget.allow("tenant1", 2, now.Add(2*time.Minute)))
+	assert.True(t, budget.allow("tenant2", 2, now))
+	assert.True(t, budget.allow("tenant1", 2, now.Add(time.Hour)))
+}
+
+// MockExperimentRepository 记录保存的实验结果
+type MockExperimentRepository struct {
+	outcomes []*entity.ExperimentOutcome
+}
+
+func (m *MockExperimentRepository) SaveOutcome(ctx context.Context, outcome *entity.ExperimentOutcome) error {
+	m.outcomes = append(m.outcomes, outcome)
+	return nil
+}
+
+func (m *MockExperimentRepository) Results(ctx context.Context, experiment string, since time.Time) ([]*entity.VariantResult, error) {
+	return nil, nil
+}
+
+// TestChatUseCase_experiments 测试 A/B 实验分组和结果记录
+func TestChatUseCase_experiments(t *testing.T) {
+	log, _ := logger.New(logger.Config{
+		Level:  "info",
+		Format: "text",
+		Output: "stdout",
+	})
+
+	experiments := []*entity.Experiment{
+		{
+			Name:      "intent_model",
+			Component: entity.ExperimentIntent,
+			Variants: []*entity.ExperimentVariant{
+				{Name: "control", Weight: 1},
+				{Name: "treatment", Weight: 1, Model: "doubao-pro"},
+			},
+		},
+		{
+			Name:      "rag_prompt",
+			Component: entity.ExperimentRAG,
+			Variants: []*entity.ExperimentVariant{
+				{Name: "control", Weight: 0},
+				{Name: "treatment", Weight: 1, PromptVersion: 2},
+			},
+		},
+	}
+	repo := &MockExperimentRepository{}
+	uc := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), 0, log).
+		WithExperiments(
+			func(tenantID string) []*entity.Experiment {
+				if tenantID == "tenant1" {
+					return experiments
+				}
+				return nil
+			},
+			func(string) repository.ExperimentRepository { return repo },
+		)
+
+	ctx, assignments := uc.assignExperiments(withSessionContext(context.Background(), "tenant1", "sess_1"), "tenant1", "sess_1")
+	require.Len(t, assignments, 2)
+
+	// 同一会话总是分到同一分组
+	_, again := uc.assignExperiments(withSessionContext(context.Background(), "tenant1", "sess_1"), "tenant1", "sess_1")
+	assert.Equal(t, assignments, again)
+
+	metadata := experimentMetadata(assignments)
+	assert.Equal(t, "treatment", metadata["rag_prompt"])
+	assert.Contains(t, []string{"control", "treatment"}, metadata["intent_model"])
+
+	// 分组只在对应组件的调用范围内生效
+	assert.Nil(t, eino.ExperimentVariantFromContext(ctx))
+	variant := eino.ExperimentVariantFromContext(experimentScope(ctx, entity.ExperimentRAG))
+	require.NotNil(t, variant)
+	assert.Equal(t, 2, variant.PromptVersion)
+	assert.Nil(t, eino.ExperimentVariantFromContext(experimentScope(ctx, entity.ExperimentDirect)))
+
+	// 直接回答的轮次只记录意图识别实验
+	intent := entity.NewIntent(entity.IntentDirect, 0.9)
+	uc.recordExperimentOutcomes(ctx, assignments, newTurnOutcome(intent, "msg_1", 120*time.Millisecond, nil))
+	require.Len(t, repo.outcomes, 1)
+	assert.Equal(t, "intent_model", repo.outcomes[0].Experiment)
+	assert.Equal(t, "sess_1", repo.outcomes[0].SessionID)
+	assert.Equal(t, int64(120), repo.outcomes[0].LatencyMs)
+
+	// 课程咨询的轮次记录两个实验，并带上未命中和转人工标记
+	intent = entity.NewIntent(entity.IntentCourse, 0.9)
+	uc.recordExperimentOutcomes(ctx, assignments, newTurnOutcome(intent, "msg_2", time.Second, map[string]any{"rag_miss": true, "handoff": true}))
+	require.Len(t, repo.outcomes, 3)
+	for _, outcome := range repo.outcomes[1:] {
+		assert.Equal(t, "msg_2", outcome.MessageID)
+		assert.True(t, outcome.RAGMiss)
+		assert.True(t, outcome.Handoff)
+	}
+
+	// 未配置实验的租户不分组
+	_, none := uc.assignExperiments(context.Background(), "tenant2", "sess_1")
+	assert.Empty(t, none)
+}
+
+// failingChatModel 意图识别正常返回，其余调用均返回指定错误
+type failingChatModel struct {
+	intent entity.IntentType
+	err    error
+}
+
+func (m *failingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if strings.Contains(input[len(input)-1].Content, "请分析用户意图") {
+		return schema.AssistantMessage(fmt.Sprintf(`{"intent":%q,"confidence":0.95,"reason":"测试"}`, m.intent), nil), nil
+	}
+	return nil, m.err
+}
+
+func (m *failingChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, m.err
+}
+
+func (m *failingChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+// TestChatUseCase_LLMConcurrencyLimit 测试各路由的模型调用并发名额不足时返回错误而不是错误回答
+func TestChatUseCase_LLMConcurrencyLimit(t *testing.T) {
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	goCourse := entity.NewDocument("Go 语言进阶课程共 40 课时，价格 1999 元。", "tenant1")
+
+	for _, intent := range []entity.IntentType{entity.IntentCourse, entity.IntentDirect} {
+		t.Run(string(intent), func(t *testing.T) {
+			limited := &failingChatModel{intent: intent, err: fmt.Errorf("%w: limit 1", entity.ErrLLMConcurrencyLimit)}
+			client := eino.NewClientWithModels(limited, runeEmbedder{}, eino.ClientConfig{})
+			dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+			t.Cleanup(func() { dbManager.Close() })
+
+			uc := NewChatUseCase(
+				eino.NewIntentRecognizer(client, nil),
+				eino.NewRAGRetriever(client, &memoryVectorRepository{docs: []*entity.Document{goCourse}}, nil),
+				nil,
+				eino.NewResponseGenerator(client),
+				sqlite.NewSessionRepository(dbManager, "tenant1"),
+				time.Hour,
+				log,
+			)
+			ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+			_, err := uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱", TenantID: "tenant1"})
+			assert.ErrorIs(t, err, entity.ErrLLMConcurrencyLimit)
+
+			chunks, err := uc.ExecuteStream(ctx, &ChatRequest{Query: "Go 进阶课程多少钱", TenantID: "tenant1", Stream: true})
+			require.NoError(t, err)
+			var contents []string
+			var last *StreamChunk
+			for chunk := range chunks {
+				if chunk.Content != "" {
+					contents = append(contents, chunk.Content)
+				}
+				last = chunk
+			}
+			require.NotNil(t, last)
+			assert.ErrorIs(t, last.Error, entity.ErrLLMConcurrencyLimit)
+			assert.Empty(t, contents)
+		})
+	}
+}
+
+// TestChatUseCase_streamRouteErrorSkipsSuggestions 测试流式直接回答失败时不推荐建议问题
+func TestChatUseCase_streamRouteErrorSkipsSuggestions(t *testing.T) {
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	client := eino.NewClientWithModels(&failingChatModel{intent: entity.IntentDirect, err: assert.AnError}, runeEmbedder{}, eino.ClientConfig{})
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+
+	policyCalls := 0
+	uc := NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		nil,
+		nil,
+		eino.NewResponseGenerator(client),
+		sqlite.NewSessionRepository(dbManager, "tenant1"),
+		time.Hour,
+		log,
+	).WithSuggestions(eino.NewSuggestionGenerator(client), func(string) SuggestionPolicy {
+		policyCalls++
+		return SuggestionPolicy{Enabled: true}
+	})
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+
+	chunks, err := uc.ExecuteStream(ctx, &ChatRequest{Query: "你好", TenantID: "tenant1", Stream: true})
+	require.NoError(t, err)
+	var done *StreamChunk
+	for chunk := range chunks {
+		if chunk.Done {
+			done = chunk
+		}
+	}
+	require.NotNil(t, done)
+	assert.NoError(t, done.Error)
+	assert.Empty(t, done.Suggestions)
+	assert.Zero(t, policyCalls)
+}
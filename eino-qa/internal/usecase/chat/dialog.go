@@ -0,0 +1,169 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// dialogTurn 本轮对话的路由结果
+type dialogTurn struct {
+	intent   *entity.Intent
+	query    string // 交给意图处理流程的查询（槽位填充后为补全后的原始问题）
+	answer   string // 已生成的回答（查询被拦截、标准问答、订单操作确认回合、槽位追问）
+	answered bool
+	cached   *entity.CachedAnswer // 命中的语义缓存回答
+	faq      *faqMatch            // 命中的标准问答
+}
+
+// routeTurn 查询被拦截或转人工、命中标准问答或语义缓存时直接得到回合，否则识别本轮对话的意图
+func (uc *ChatUseCase) routeTurn(ctx context.Context, session *entity.Session, req *ChatRequest, injection *injectionVerdict, faq *faqMatch, cacheLookup *answerCacheLookup) (*dialogTurn, error) {
+	if turn := injection.turn(req.Query); turn != nil {
+		return turn, nil
+	}
+	if faq != nil {
+		return faq.turn(req.Query), nil
+	}
+	if cacheLookup != nil && cacheLookup.hit != nil {
+		return cacheLookup.turn(req.Query), nil
+	}
+	return uc.recognizeIntent(ctx, session, req)
+}
+
+// recognizeIntent 识别本轮对话的意图
+// 依次处理：待确认的订单操作、等待填写的槽位、意图识别、意图的必填槽位
+func (uc *ChatUseCase) recognizeIntent(ctx context.Context, session *entity.Session, req *ChatRequest) (*dialogTurn, error) {
+	if answer, ok := uc.resolvePendingOrderAction(ctx, session, req.UserID, req.Query); ok {
+		intent := entity.NewIntent(entity.IntentOrder, 1.0)
+		intent.Metadata["order_action_confirmation"] = true
+		return &dialogTurn{intent: intent, query: req.Query, answer: answer, answered: true}, nil
+	}
+
+	if turn := uc.resumeExpectedSlot(ctx, session, req.TenantID, req.Query); turn != nil {
+		return turn, nil
+	}
+
+	intent, err := uc.intentRecognizer.Recognize(experimentScope(ctx, entity.ExperimentIntent), req.Query, session.GetMessages())
+	if err != nil {
+		return nil, err
+	}
+
+	turn := &dialogTurn{intent: intent, query: req.Query}
+	if slot := uc.missingRequiredSlot(session, req.TenantID, intent.Type); slot != nil {
+		turn.answer = uc.askForSlot(ctx, session, intent.Type, req.Query, slot.Name, slot.Prompt)
+		turn.answered = true
+	}
+	return turn, nil
+}
+
+// resumeExpectedSlot 用本轮回复填充会话中等待的槽位，并恢复原意图的处理
+// 没有等待的槽位、已过期或回复无法填充（且不再追问）时返回 nil，按正常流程识别意图
+func (uc *ChatUseCase) resumeExpectedSlot(ctx context.Context, session *entity.Session, tenantID, query string) *dialogTurn {
+	expected := session.ExpectedSlot()
+	if expected == nil {
+		return nil
+	}
+	session.ClearExpectedSlot()
+
+	fields := map[string]interface{}{
+		"slot":   expected.Name,
+		"intent": expected.Intent,
+	}
+
+	if expected.IsExpired() {
+		uc.logger.Info(ctx, "expected slot expired", fields)
+		return nil
+	}
+
+	slot := uc.slotDefinition(tenantID, expected.Name, expected.Intent)
+	intent := entity.NewIntent(expected.Intent, 1.0)
+	intent.Metadata["slot"] = expected.Name
+
+	value, ok := slot.Fill(tenantID, query)
+	if !ok {
+		// 允许重试时继续追问，否则视为用户换了话题
+		if expected.Attempts < slot.MaxAttempts {
+			expected.Attempts++
+			session.SetExpectedSlot(expected)
+			uc.logger.Info(ctx, "slot not filled, asking again", fields)
+			return &dialogTurn{intent: intent, query: query, answer: slot.Prompt, answered: true}
+		}
+		uc.logger.Info(ctx, "slot not filled, resuming normal flow", fields)
+		return nil
+	}
+
+	session.SetSlotValue(expected.Name, value)
+	uc.logger.Info(ctx, "slot filled", fields)
+
+	intent.Metadata["slot_filled"] = true
+	return &dialogTurn{intent: intent, query: expected.Query + " " + value}
+}
+
+// askForSlot 记录等待填写的槽位并返回追问话术
+// 租户定义了同名槽位时使用租户的追问话术，否则使用处理流程给出的话术
+func (uc *ChatUseCase) askForSlot(ctx context.Context, session *entity.Session, intentType entity.IntentType, query, name, prompt string) string {
+	if slot := uc.tenantSlotDefinition(session.TenantID, name, intentType); slot != nil {
+		prompt = slot.Prompt
+	} else if prompt == "" {
+		prompt = uc.slotDefinition(session.TenantID, name, intentType).Prompt
+	}
+
+	session.SetExpectedSlot(&entity.ExpectedSlot{
+		Name:      name,
+		Intent:    intentType,
+		Query:     query,
+		ExpiresAt: time.Now().Add(entity.DefaultExpectedSlotTTL),
+	})
+
+	uc.logger.Info(ctx, "asking for slot", map[string]interface{}{
+		"slot":   name,
+		"intent": intentType,
+	})
+
+	return prompt
+}
+
+// missingRequiredSlot 返回意图的第一个尚未填写的必填槽位
+func (uc *ChatUseCase) missingRequiredSlot(session *entity.Session, tenantID string, intentType entity.IntentType) *entity.SlotDefinition {
+	if uc.slotDefinitions == nil {
+		return nil
+	}
+
+	for _, slot := range uc.slotDefinitions(tenantID) {
+		if slot.Required && slot.Intent == intentType && session.SlotValue(slot.Name) == "" {
+			return slot
+		}
+	}
+	return nil
+}
+
+// tenantSlotDefinition 查找租户定义的槽位，未定义时返回 nil
+func (uc *ChatUseCase) tenantSlotDefinition(tenantID, name string, intentType entity.IntentType) *entity.SlotDefinition {
+	if uc.slotDefinitions == nil {
+		return nil
+	}
+
+	for _, slot := range uc.slotDefinitions(tenantID) {
+		if slot.Name == name && slot.Intent == intentType {
+			return slot
+		}
+	}
+	return nil
+}
+
+// slotDefinition 查找槽位定义：租户定义优先，其次内置定义
+// 都没有时返回只接受非空回复的临时定义，保证处理流程给出的任意槽位都能恢复
+func (uc *ChatUseCase) slotDefinition(tenantID, name string, intentType entity.IntentType) *entity.SlotDefinition {
+	if slot := uc.tenantSlotDefinition(tenantID, name, intentType); slot != nil {
+		return slot
+	}
+
+	for _, slot := range entity.DefaultSlotDefinitions() {
+		if slot.Name == name && slot.Intent == intentType {
+			return slot
+		}
+	}
+
+	return &entity.SlotDefinition{Name: name, Intent: intentType}
+}
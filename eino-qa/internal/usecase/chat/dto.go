@@ -7,24 +7,31 @@ type ChatRequest struct {
 	Query     string // 用户查询
 	TenantID  string // 租户 ID
 	SessionID string // 会话 ID
+	UserID    string // 已校验的终端用户 ID（匿名时为空）
 	Stream    bool   // 是否流式响应
 }
 
 // ChatResponse 对话响应
 type ChatResponse struct {
-	Answer    string             // 回答内容
-	Route     string             // 路由类型（意图类型）
-	Sources   []*entity.Document // 来源文档（RAG 检索结果）
-	SessionID string             // 会话 ID
-	Metadata  map[string]any     // 元数据
+	Answer      string             // 回答内容
+	Route       string             // 路由类型（意图类型）
+	Sources     []*entity.Document // 来源文档（RAG 检索结果）
+	Blocks      []*ResponseBlock   // 结构化响应块（订单卡片、来源引用、快捷回复等）
+	Suggestions []string           // 建议的后续问题（2~4 个，未启用或不足时为空）
+	SessionID   string             // 会话 ID
+	MessageID   string             // 助手消息 ID（用于反馈）
+	Metadata    map[string]any     // 元数据
 }
 
 // StreamChunk 流式响应块
 type StreamChunk struct {
-	Content  string         // 内容片段
-	Done     bool           // 是否完成
-	Error    error          // 错误信息
-	Metadata map[string]any // 元数据
+	Content     string         // 内容片段
+	Replace     bool           // Content 替换此前发送的全部内容（回答未通过审核）
+	Block       *ResponseBlock // 结构化响应块（与内容片段互斥）
+	Done        bool           // 是否完成
+	Suggestions []string       // 建议的后续问题（仅完成块）
+	Error       error          // 错误信息
+	Metadata    map[string]any // 元数据
 }
 
 // Validate 验证请求
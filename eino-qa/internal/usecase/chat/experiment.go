@@ -0,0 +1,119 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+)
+
+// experimentsKey context 中保存本轮对话实验分组的键
+type experimentsKey struct{}
+
+// turnOutcome 本轮对话用于实验统计的结果
+type turnOutcome struct {
+	messageID string
+	route     string
+	latency   time.Duration
+	handoff   bool
+	ragMiss   bool
+}
+
+// WithExperiments 设置租户的 A/B 实验配置和结果仓储（可选）
+func (uc *ChatUseCase) WithExperiments(provider ExperimentProvider, repos ExperimentRepositoryProvider) *ChatUseCase {
+	uc.experiments = provider
+	uc.experimentRepos = repos
+	return uc
+}
+
+// assignExperiments 按会话 ID 为本轮对话分配实验分组，并写入 context
+func (uc *ChatUseCase) assignExperiments(ctx context.Context, tenantID, sessionID string) (context.Context, []*entity.ExperimentAssignment) {
+	if uc.experiments == nil {
+		return ctx, nil
+	}
+
+	var assignments []*entity.ExperimentAssignment
+	for _, experiment := range uc.experiments(tenantID) {
+		if assignment := experiment.Assign(sessionID); assignment != nil {
+			assignments = append(assignments, assignment)
+		}
+	}
+	if len(assignments) == 0 {
+		return ctx, nil
+	}
+
+	uc.logger.Info(ctx, "experiment variants assigned", map[string]interface{}{
+		"experiments": experimentMetadata(assignments),
+	})
+	return context.WithValue(ctx, experimentsKey{}, assignments), assignments
+}
+
+// experimentScope 返回只对指定组件生效的实验分组 context
+// 各组件的提示词版本和模型只在自己的调用范围内替换
+func experimentScope(ctx context.Context, component entity.ExperimentComponent) context.Context {
+	assignments, _ := ctx.Value(experimentsKey{}).([]*entity.ExperimentAssignment)
+	for _, assignment := range assignments {
+		if assignment.Component == component {
+			return eino.WithExperimentVariant(ctx, assignment)
+		}
+	}
+	return ctx
+}
+
+// experimentMetadata 返回实验名到分组名的映射，写入响应元数据和日志
+func experimentMetadata(assignments []*entity.ExperimentAssignment) map[string]string {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	variants := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		variants[assignment.Experiment] = assignment.Variant
+	}
+	return variants
+}
+
+// recordExperimentOutcomes 记录本轮对话在各实验分组下的结果
+// 只记录参与了本轮处理的组件的实验，保存失败不影响对话
+func (uc *ChatUseCase) recordExperimentOutcomes(ctx context.Context, assignments []*entity.ExperimentAssignment, outcome turnOutcome) {
+	if uc.experimentRepos == nil || len(assignments) == 0 {
+		return
+	}
+
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	sessionID, _ := ctx.Value("session_id").(string)
+	repo := uc.experimentRepos(tenantID)
+
+	for _, assignment := range assignments {
+		if !assignment.Component.AppliesTo(outcome.route) {
+			continue
+		}
+
+		record := entity.NewExperimentOutcome(tenantID, assignment, sessionID, outcome.messageID, outcome.route)
+		record.LatencyMs = outcome.latency.Milliseconds()
+		record.Handoff = outcome.handoff
+		record.RAGMiss = outcome.ragMiss
+
+		if err := repo.SaveOutcome(ctx, record); err != nil {
+			uc.logger.Warn(ctx, "failed to record experiment outcome", map[string]interface{}{
+				"experiment": assignment.Experiment,
+				"variant":    assignment.Variant,
+				"error":      err,
+			})
+		}
+	}
+}
+
+// newTurnOutcome 根据路由结果生成实验统计数据
+func newTurnOutcome(intent *entity.Intent, messageID string, latency time.Duration, routeMetadata map[string]any) turnOutcome {
+	handoff, _ := routeMetadata["handoff"].(bool)
+	ragMiss, _ := routeMetadata["rag_miss"].(bool)
+	return turnOutcome{
+		messageID: messageID,
+		route:     string(intent.Type),
+		latency:   latency,
+		handoff:   handoff || intent.Type == entity.IntentHandoff,
+		ragMiss:   ragMiss,
+	}
+}
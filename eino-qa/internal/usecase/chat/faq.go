@@ -0,0 +1,194 @@
+package chat
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/pkg/utils"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// defaultFAQThreshold 默认的最低问题相似度
+const defaultFAQThreshold = 0.92
+
+// faqCacheTTL 租户标准问答列表（包括问题向量）的缓存时间
+// 标准问答增删改后立即清除本实例的缓存（见 InvalidateFAQs），多实例部署时其他实例最多延迟一个 TTL 生效
+const faqCacheTTL = 30 * time.Second
+
+// FAQ 匹配方式
+const (
+	faqMatchExact    = "exact"
+	faqMatchSemantic = "semantic"
+)
+
+// FAQPolicy 租户的标准问答策略
+type FAQPolicy struct {
+	Enabled   bool
+	Threshold float64 // 向量匹配的最低问题相似度，0 表示使用默认值
+}
+
+// faqCache 按租户缓存的标准问答列表
+type faqCache struct {
+	mu      sync.RWMutex
+	tenants map[string]*cachedFAQs
+}
+
+// cachedFAQs 缓存的租户标准问答列表
+type cachedFAQs struct {
+	faqs      []*entity.FAQ
+	expiresAt time.Time
+}
+
+// faqMatch 命中的标准问答
+type faqMatch struct {
+	faq        *entity.FAQ
+	method     string  // exact 或 semantic
+	question   string  // 命中的问题
+	similarity float64 // 精确匹配时为 1
+}
+
+// WithFAQ 设置标准问答仓储、问题嵌入模型和租户策略（可选）
+func (uc *ChatUseCase) WithFAQ(repos FAQRepositoryProvider, embedder embedding.Embedder, provider FAQPolicyProvider) *ChatUseCase {
+	uc.faqRepos = repos
+	uc.faqEmbedder = embedder
+	uc.faqPolicies = provider
+	uc.faqCache = &faqCache{tenants: make(map[string]*cachedFAQs)}
+	return uc
+}
+
+// InvalidateFAQs 清除租户的标准问答缓存，实现 faq.ChangeListener
+func (uc *ChatUseCase) InvalidateFAQs(tenantID string) {
+	if uc.faqCache == nil {
+		return
+	}
+	uc.faqCache.mu.Lock()
+	delete(uc.faqCache.tenants, tenantID)
+	uc.faqCache.mu.Unlock()
+}
+
+// tenantFAQs 获取租户的标准问答列表（带缓存），查询失败时不缓存
+func (uc *ChatUseCase) tenantFAQs(ctx context.Context, tenantID string) ([]*entity.FAQ, error) {
+	now := time.Now()
+
+	uc.faqCache.mu.RLock()
+	cached, ok := uc.faqCache.tenants[tenantID]
+	uc.faqCache.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.faqs, nil
+	}
+
+	faqs, err := uc.faqRepos(tenantID).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.faqCache.mu.Lock()
+	uc.faqCache.tenants[tenantID] = &cachedFAQs{faqs: faqs, expiresAt: now.Add(faqCacheTTL)}
+	uc.faqCache.mu.Unlock()
+
+	return faqs, nil
+}
+
+// matchFAQ 在语义缓存和意图识别之前匹配租户的标准问答
+// 先按规范化问题精确匹配，再按问题向量相似度匹配；未启用或会话处于订单操作确认、槽位追问状态时返回 nil
+func (uc *ChatUseCase) matchFAQ(ctx context.Context, session *entity.Session, query string) *faqMatch {
+	if uc.faqRepos == nil || uc.faqPolicies == nil {
+		return nil
+	}
+	if session.PendingOrderAction() != nil || session.ExpectedSlot() != nil {
+		return nil
+	}
+
+	policy := uc.faqPolicies(session.TenantID)
+	if !policy.Enabled {
+		return nil
+	}
+
+	faqs, err := uc.tenantFAQs(ctx, session.TenantID)
+	if err != nil {
+		uc.logger.Warn(ctx, "failed to list faqs", map[string]interface{}{"error": err})
+		return nil
+	}
+
+	now := time.Now()
+	var effective []*entity.FAQ
+	for _, faq := range faqs {
+		if faq.IsEffective(now) {
+			effective = append(effective, faq)
+		}
+	}
+	if len(effective) == 0 {
+		return nil
+	}
+
+	for _, faq := range effective {
+		if question, ok := faq.MatchExact(query); ok {
+			return uc.logFAQMatch(ctx, &faqMatch{faq: faq, method: faqMatchExact, question: question, similarity: 1})
+		}
+	}
+
+	if uc.faqEmbedder == nil {
+		return nil
+	}
+	// 与问题向量的生成方式一致（见 faq.FAQUseCase）
+	vectors, err := uc.faqEmbedder.EmbedStrings(ctx, []string{entity.NormalizeCacheQuery(query)})
+	if err != nil || len(vectors) != 1 {
+		uc.logger.Warn(ctx, "failed to embed query for faq", map[string]interface{}{"error": err})
+		return nil
+	}
+
+	threshold := policy.Threshold
+	if threshold <= 0 {
+		threshold = defaultFAQThreshold
+	}
+	var best *faqMatch
+	for _, faq := range effective {
+		for i, vector := range faq.Vectors {
+			if i >= len(faq.Questions) {
+				break
+			}
+			score := utils.CosineSimilarity(vectors[0], vector)
+			if score >= threshold && (best == nil || score > best.similarity) {
+				best = &faqMatch{faq: faq, method: faqMatchSemantic, question: faq.Questions[i], similarity: score}
+			}
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return uc.logFAQMatch(ctx, best)
+}
+
+// logFAQMatch 记录命中的标准问答
+func (uc *ChatUseCase) logFAQMatch(ctx context.Context, match *faqMatch) *faqMatch {
+	uc.logger.Info(ctx, "faq matched", map[string]interface{}{
+		"faq_id":     match.faq.ID,
+		"method":     match.method,
+		"question":   match.question,
+		"similarity": match.similarity,
+	})
+	return match
+}
+
+// turn 命中标准问答时的对话回合，原样返回标准答案
+func (m *faqMatch) turn(query string) *dialogTurn {
+	intent := entity.NewIntent(entity.IntentFAQ, m.similarity)
+	intent.Metadata["faq_id"] = m.faq.ID
+	return &dialogTurn{intent: intent, query: query, answer: m.faq.Answer, answered: true, faq: m}
+}
+
+// metadata 合并到响应元数据的标准问答信息
+func (m *faqMatch) metadata() map[string]any {
+	if m == nil {
+		return nil
+	}
+	return map[string]any{
+		"faq_id":         m.faq.ID,
+		"faq_match":      m.method,
+		"faq_question":   m.question,
+		"faq_similarity": m.similarity,
+	}
+}
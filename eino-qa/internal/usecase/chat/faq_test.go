@@ -0,0 +1,164 @@
+package chat
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/logger"
+	"eino-qa/internal/infrastructure/repository/sqlite"
+	faquc "eino-qa/internal/usecase/faq"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatUseCase_FAQ(t *testing.T) {
+	chatModel := &scriptedChatModel{}
+	client := eino.NewClientWithModels(chatModel, runeEmbedder{}, eino.ClientConfig{})
+
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+	faqRepos := func(tenantID string) repository.FAQRepository { return sqlite.NewFAQRepository(dbManager, tenantID) }
+
+	faqs := faquc.NewFAQUseCase(faqRepos, runeEmbedder{}, nil)
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+	refund, err := faqs.Create(ctx, &faquc.SaveRequest{
+		TenantID:  "tenant1",
+		Questions: []string{"退款政策是什么？", "怎么申请退款"},
+		Answer:    "购买后 7 天内且学习进度低于 10% 可申请全额退款。",
+		Enabled:   true,
+	})
+	require.NoError(t, err)
+	expired := time.Now().Add(-time.Hour)
+	_, err = faqs.Create(ctx, &faquc.SaveRequest{
+		TenantID:    "tenant1",
+		Questions:   []string{"Go 进阶课程多少钱"},
+		Answer:      "限时优惠 999 元。",
+		EffectiveTo: &expired,
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+
+	goCourse := entity.NewDocument("Go 语言进阶课程共 40 课时，价格 1999 元。", "tenant1")
+	policies := map[string]FAQPolicy{"tenant1": {Enabled: true}}
+
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		eino.NewRAGRetriever(client, &memoryVectorRepository{docs: []*entity.Document{goCourse}}, nil),
+		nil,
+		eino.NewResponseGenerator(client),
+		sqlite.NewSessionRepository(dbManager, "tenant1"),
+		time.Hour,
+		log,
+	).WithFAQ(faqRepos, client.GetEmbedModel(), func(tenantID string) FAQPolicy { return policies[tenantID] })
+
+	// 规范化后精确匹配：原样返回标准答案，不调用模型
+	resp, err := uc.Execute(ctx, &ChatRequest{Query: "退款政策 是什么", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, string(entity.IntentFAQ), resp.Route)
+	assert.Equal(t, refund.Answer, resp.Answer)
+	assert.Equal(t, refund.ID, resp.Metadata["faq_id"])
+	assert.Equal(t, "exact", resp.Metadata["faq_match"])
+	assert.Empty(t, resp.Sources)
+	assert.Equal(t, 0, chatModel.calls)
+
+	// 向量匹配：同一会话的后续问题同样命中
+	resp, err = uc.Execute(ctx, &ChatRequest{Query: "退款怎么申请", TenantID: "tenant1", SessionID: resp.SessionID})
+	require.NoError(t, err)
+	assert.Equal(t, refund.Answer, resp.Answer)
+	assert.Equal(t, "semantic", resp.Metadata["faq_match"])
+	assert.Equal(t, "怎么申请退款", resp.Metadata["faq_question"])
+	assert.Equal(t, 0, chatModel.calls)
+
+	// 流式请求直接发送标准答案
+	chunks, err := uc.ExecuteStream(ctx, &ChatRequest{Query: "怎么申请退款", TenantID: "tenant1", Stream: true})
+	require.NoError(t, err)
+	var content string
+	var done *StreamChunk
+	for chunk := range chunks {
+		content += chunk.Content
+		if chunk.Done {
+			done = chunk
+		}
+	}
+	require.NotNil(t, done)
+	assert.Equal(t, refund.Answer, content)
+	assert.Equal(t, entity.IntentFAQ, done.Metadata["intent"])
+	assert.Equal(t, 0, chatModel.calls)
+
+	// 已过期的问答不再匹配，走检索生成
+	resp, err = uc.Execute(ctx, &ChatRequest{Query: "Go 进阶课程多少钱", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Equal(t, string(entity.IntentCourse), resp.Route)
+	assert.Contains(t, resp.Answer, "1999")
+	assert.Nil(t, resp.Metadata["faq_id"])
+
+	// 未启用的租户不匹配
+	ctx2 := context.WithValue(context.Background(), "tenant_id", "tenant2")
+	resp, err = uc.Execute(ctx2, &ChatRequest{Query: "退款政策是什么", TenantID: "tenant2"})
+	require.NoError(t, err)
+	assert.NotEqual(t, string(entity.IntentFAQ), resp.Route)
+}
+
+// countingFAQRepository 记录列表查询次数的标准问答仓储
+type countingFAQRepository struct {
+	repository.FAQRepository
+	lists int
+}
+
+func (r *countingFAQRepository) List(ctx context.Context) ([]*entity.FAQ, error) {
+	r.lists++
+	return r.FAQRepository.List(ctx)
+}
+
+func TestChatUseCase_FAQCache(t *testing.T) {
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+	repo := &countingFAQRepository{FAQRepository: sqlite.NewFAQRepository(dbManager, "tenant1")}
+	faqRepos := func(string) repository.FAQRepository { return repo }
+
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(nil, nil, nil, nil, new(MockSessionRepository), time.Hour, log).
+		WithFAQ(faqRepos, nil, func(string) FAQPolicy { return FAQPolicy{Enabled: true} })
+	faqs := faquc.NewFAQUseCase(faqRepos, nil, nil).WithChangeListener(uc)
+
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+	session := entity.NewSession("tenant1", time.Hour)
+	refund, err := faqs.Create(ctx, &faquc.SaveRequest{
+		TenantID:  "tenant1",
+		Questions: []string{"退款政策是什么？"},
+		Answer:    "购买后 7 天内可申请全额退款。",
+		Enabled:   true,
+	})
+	require.NoError(t, err)
+
+	// 每轮对话不再重新加载租户的全部标准问答
+	for i := 0; i < 3; i++ {
+		match := uc.matchFAQ(ctx, session, "退款政策是什么")
+		require.NotNil(t, match)
+		assert.Equal(t, "退款政策是什么？", match.question)
+	}
+	assert.Equal(t, 1, repo.lists)
+
+	// 更新和删除后立即生效
+	_, err = faqs.Update(ctx, refund.ID, &faquc.SaveRequest{
+		TenantID:  "tenant1",
+		Questions: []string{"退款政策是什么？"},
+		Answer:    "购买后 14 天内可申请全额退款。",
+		Enabled:   true,
+	})
+	require.NoError(t, err)
+	match := uc.matchFAQ(ctx, session, "退款政策是什么")
+	require.NotNil(t, match)
+	assert.Equal(t, "购买后 14 天内可申请全额退款。", match.faq.Answer)
+	assert.Equal(t, 2, repo.lists)
+
+	require.NoError(t, faqs.Delete(ctx, "tenant1", refund.ID))
+	assert.Nil(t, uc.matchFAQ(ctx, session, "退款政策是什么"))
+}
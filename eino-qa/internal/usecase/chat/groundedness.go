@@ -0,0 +1,126 @@
+package chat
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+)
+
+// GroundednessAction 回答依据不足时的处理方式
+type GroundednessAction string
+
+const (
+	// GroundednessActionRegenerate 使用严格提示词重新生成，仍不足时在元数据中标记
+	GroundednessActionRegenerate GroundednessAction = "regenerate"
+	// GroundednessActionHandoff 降级为"无法确认"并转人工
+	GroundednessActionHandoff GroundednessAction = "handoff"
+	// GroundednessActionFlag 原样返回，在元数据中标记依据不足
+	GroundednessActionFlag GroundednessAction = "flag"
+)
+
+const (
+	// defaultGroundednessThreshold 默认的最低依据分数（有依据句子的比例）
+	defaultGroundednessThreshold = 0.8
+	// groundednessHandoffReason 依据不足转人工的原因
+	groundednessHandoffReason = "知识库无法确认答案"
+)
+
+// GroundednessPolicy 租户的回答依据校验策略
+type GroundednessPolicy struct {
+	Enabled   bool
+	Method    eino.GroundednessMethod
+	Threshold float64 // 最低依据分数，0 表示使用默认值
+	Action    GroundednessAction
+}
+
+// courseAnswer 课程咨询的处理结果
+type courseAnswer struct {
+	answer   string
+	sources  []*entity.Document
+	blocks   []*ResponseBlock // 来源引用以外的响应块（如依据不足时的转人工提示）
+	metadata map[string]any   // 合并到响应元数据
+	err      error            // 模型调用并发名额不足等需要返回给客户端的错误，此时没有回答
+}
+
+// WithGroundedness 设置回答依据校验器和租户策略（可选）
+func (uc *ChatUseCase) WithGroundedness(checker *eino.GroundednessChecker, provider GroundednessPolicyProvider) *ChatUseCase {
+	uc.groundednessChecker = checker
+	uc.groundednessPolicies = provider
+	return uc
+}
+
+// verifyGroundedness 校验 RAG 回答是否有检索文档支持，依据不足时按租户策略处理
+func (uc *ChatUseCase) verifyGroundedness(ctx context.Context, query string, result *courseAnswer) {
+	if uc.groundednessChecker == nil || uc.groundednessPolicies == nil || len(result.sources) == 0 {
+		return
+	}
+
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	policy := uc.groundednessPolicies(tenantID)
+	if !policy.Enabled {
+		return
+	}
+	threshold := policy.Threshold
+	if threshold <= 0 {
+		threshold = defaultGroundednessThreshold
+	}
+
+	report := uc.checkGroundedness(ctx, policy.Method, result)
+	if report.Score >= threshold {
+		return
+	}
+
+	uc.logger.Warn(ctx, "answer is not grounded in retrieved documents", map[string]interface{}{
+		"query":       query,
+		"score":       report.Score,
+		"threshold":   threshold,
+		"action":      policy.Action,
+		"unsupported": report.Unsupported(),
+	})
+
+	switch policy.Action {
+	case GroundednessActionRegenerate:
+		answer, sources, err := uc.ragRetriever.RegenerateStrict(experimentScope(ctx, entity.ExperimentRAG), query, result.sources)
+		if err != nil {
+			uc.logger.Error(ctx, "failed to regenerate answer", map[string]interface{}{"error": err})
+			result.metadata["low_groundedness"] = true
+			return
+		}
+
+		result.answer, result.sources = answer, sources
+		result.metadata["groundedness_regenerated"] = true
+		if report = uc.checkGroundedness(ctx, policy.Method, result); report.Score < threshold {
+			result.metadata["low_groundedness"] = true
+		}
+
+	case GroundednessActionHandoff:
+		uc.publishEvent(ctx, entity.EventHandoffCreated, map[string]any{
+			"query":  query,
+			"reason": groundednessHandoffReason,
+			"score":  report.Score,
+		})
+
+		message := "抱歉，我无法根据知识库确认这个问题的答案。" + uc.responseGenerator.GenerateHandoffMessage("")
+		result.answer, result.sources = message, nil
+		result.blocks = append(result.blocks, handoffBlock(groundednessHandoffReason, message))
+		result.metadata["low_groundedness"] = true
+		result.metadata["handoff"] = true
+
+	default:
+		result.metadata["low_groundedness"] = true
+	}
+}
+
+// checkGroundedness 校验回答依据并记录分数，LLM 校验失败时降级为词汇重合校验
+func (uc *ChatUseCase) checkGroundedness(ctx context.Context, method eino.GroundednessMethod, result *courseAnswer) *eino.GroundednessReport {
+	report, err := uc.groundednessChecker.Check(ctx, method, result.answer, result.sources)
+	if err != nil {
+		uc.logger.Warn(ctx, "groundedness check failed, falling back to lexical", map[string]interface{}{"error": err})
+		report, _ = uc.groundednessChecker.Check(ctx, eino.GroundednessMethodLexical, result.answer, result.sources)
+	}
+
+	result.metadata["groundedness_score"] = report.Score
+	result.metadata["groundedness_method"] = string(report.Method)
+	return report
+}
@@ -0,0 +1,142 @@
+package chat
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+)
+
+// InjectionAction 查询疑似提示词注入时的处理方式
+type InjectionAction string
+
+const (
+	// InjectionActionBlock 拒绝回答，路由为 blocked
+	InjectionActionBlock InjectionAction = "block"
+	// InjectionActionSanitize 去掉命中的注入片段后继续处理，去掉后为空时拒绝回答
+	InjectionActionSanitize InjectionAction = "sanitize"
+	// InjectionActionFlag 原样处理，在元数据中标记
+	InjectionActionFlag InjectionAction = "flag"
+	// InjectionActionHandoff 转人工
+	InjectionActionHandoff InjectionAction = "handoff"
+)
+
+const (
+	// defaultInjectionThreshold 默认的最低可疑分数
+	defaultInjectionThreshold = 0.5
+	// injectionHandoffReason 疑似提示词注入转人工的原因
+	injectionHandoffReason = "疑似提示词注入"
+	// injectionBlockedMessage 拒绝回答时的回复
+	injectionBlockedMessage = "抱歉，您的问题中包含我无法执行的指令，请换一种方式描述您的问题。"
+)
+
+// InjectionPolicy 租户的提示词注入检测策略
+type InjectionPolicy struct {
+	Enabled   bool
+	Method    eino.InjectionMethod
+	Threshold float64 // 最低可疑分数，0 表示使用默认值
+	Action    InjectionAction
+}
+
+// injectionVerdict 本轮查询的注入检测结果（只在可疑时产生）
+type injectionVerdict struct {
+	report *eino.InjectionReport
+	action InjectionAction
+}
+
+// WithInjectionGuard 设置提示词注入检测器和租户策略（可选）
+func (uc *ChatUseCase) WithInjectionGuard(guard *eino.InjectionGuard, provider InjectionPolicyProvider) *ChatUseCase {
+	uc.injectionGuard = guard
+	uc.injectionPolicies = provider
+	return uc
+}
+
+// guardQuery 在标准问答、语义缓存和意图识别之前检测查询是否包含提示词注入
+// 可疑时按租户策略处理：sanitize 返回去掉注入片段的请求副本；block、handoff 时本轮不再调用模型，
+// 会话中记录去掉注入片段后的查询，避免后续轮次的对话历史带上注入内容
+func (uc *ChatUseCase) guardQuery(ctx context.Context, req *ChatRequest) (*ChatRequest, *injectionVerdict) {
+	if uc.injectionGuard == nil || uc.injectionPolicies == nil {
+		return req, nil
+	}
+
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	policy := uc.injectionPolicies(tenantID)
+	if !policy.Enabled {
+		return req, nil
+	}
+	threshold := policy.Threshold
+	if threshold <= 0 {
+		threshold = defaultInjectionThreshold
+	}
+
+	report, err := uc.injectionGuard.Check(ctx, policy.Method, req.Query)
+	if err != nil {
+		uc.logger.Warn(ctx, "injection classification failed, falling back to heuristic", map[string]interface{}{"error": err})
+		report, _ = uc.injectionGuard.Check(ctx, eino.InjectionMethodHeuristic, req.Query)
+	}
+	if report.Score < threshold {
+		return req, nil
+	}
+
+	verdict := &injectionVerdict{report: report, action: policy.Action}
+	switch verdict.action {
+	case InjectionActionBlock, InjectionActionSanitize, InjectionActionHandoff:
+	default:
+		verdict.action = InjectionActionFlag
+	}
+	if verdict.action == InjectionActionSanitize && report.Sanitized == "" {
+		verdict.action = InjectionActionBlock
+	}
+
+	uc.logger.Warn(ctx, "prompt injection detected in query", map[string]interface{}{
+		"query":  req.Query,
+		"score":  report.Score,
+		"rules":  report.Rules,
+		"method": report.Method,
+		"reason": report.Reason,
+		"action": verdict.action,
+	})
+
+	if verdict.action == InjectionActionFlag || report.Sanitized == "" {
+		return req, verdict
+	}
+	sanitized := *req
+	sanitized.Query = report.Sanitized
+	return &sanitized, verdict
+}
+
+// stops 本轮是否不再进入标准问答、语义缓存和意图识别
+func (v *injectionVerdict) stops() bool {
+	return v != nil && (v.action == InjectionActionBlock || v.action == InjectionActionHandoff)
+}
+
+// turn 拒绝回答或转人工时的对话回合，其余情况返回 nil
+// 转人工的回合交给 handoff 意图的处理流程（发布转人工事件并返回转人工卡片）
+func (v *injectionVerdict) turn(query string) *dialogTurn {
+	if !v.stops() {
+		return nil
+	}
+
+	if v.action == InjectionActionHandoff {
+		intent := entity.NewIntent(entity.IntentHandoff, v.report.Score)
+		intent.Metadata["reason"] = injectionHandoffReason
+		return &dialogTurn{intent: intent, query: query}
+	}
+
+	intent := entity.NewIntent(entity.IntentBlocked, v.report.Score)
+	intent.Metadata["rules"] = v.report.Rules
+	return &dialogTurn{intent: intent, query: query, answer: injectionBlockedMessage, answered: true}
+}
+
+// metadata 合并到响应元数据的注入检测信息
+func (v *injectionVerdict) metadata() map[string]any {
+	if v == nil {
+		return nil
+	}
+	return map[string]any{
+		"injection_detected": true,
+		"injection_score":    v.report.Score,
+		"injection_rules":    v.report.Rules,
+		"injection_action":   string(v.action),
+	}
+}
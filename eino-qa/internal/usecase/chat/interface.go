@@ -1,9 +1,70 @@
 package chat
 
-import "context"
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
 
 // ChatUseCaseInterface 对话用例接口
 type ChatUseCaseInterface interface {
 	Execute(ctx context.Context, req *ChatRequest) (*ChatResponse, error)
 	ExecuteStream(ctx context.Context, req *ChatRequest) (<-chan *StreamChunk, error)
 }
+
+// EventPublisher 业务事件发布接口（由 Webhook 分发器实现）
+type EventPublisher interface {
+	Publish(ctx context.Context, tenantID string, eventType entity.WebhookEventType, data map[string]any) error
+}
+
+// MissedQueryRepositoryProvider 按租户获取未命中查询仓储
+type MissedQueryRepositoryProvider func(tenantID string) repository.MissedQueryRepository
+
+// SlotDefinitionProvider 按租户获取自定义的对话槽位
+type SlotDefinitionProvider func(tenantID string) []*entity.SlotDefinition
+
+// GroundednessPolicyProvider 按租户获取回答依据校验策略
+type GroundednessPolicyProvider func(tenantID string) GroundednessPolicy
+
+// SuggestionPolicyProvider 按租户获取建议问题策略
+type SuggestionPolicyProvider func(tenantID string) SuggestionPolicy
+
+// ExperimentProvider 按租户获取 A/B 实验配置
+type ExperimentProvider func(tenantID string) []*entity.Experiment
+
+// ExperimentRepositoryProvider 按租户获取实验结果仓储
+type ExperimentRepositoryProvider func(tenantID string) repository.ExperimentRepository
+
+// AnswerCache 语义回答缓存接口（进程内实现见 infrastructure/cache）
+type AnswerCache interface {
+	// Version 获取租户当前的知识库版本
+	Version(tenantID string) uint64
+	// Lookup 查找与问题向量最相似且不低于阈值的缓存回答，未命中时返回 nil
+	Lookup(tenantID string, vector []float32, routes []entity.IntentType, threshold float64) (*entity.CachedAnswer, float64)
+	// Store 缓存回答，知识库版本已变化时丢弃并返回 false
+	Store(tenantID string, answer *entity.CachedAnswer) bool
+}
+
+// AnswerCachePolicyProvider 按租户获取语义回答缓存策略
+type AnswerCachePolicyProvider func(tenantID string) AnswerCachePolicy
+
+// FAQRepositoryProvider 按租户获取标准问答仓储
+type FAQRepositoryProvider func(tenantID string) repository.FAQRepository
+
+// FAQPolicyProvider 按租户获取标准问答策略
+type FAQPolicyProvider func(tenantID string) FAQPolicy
+
+// MetricsRecorder 对话指标记录接口
+type MetricsRecorder interface {
+	RecordCacheLookup(route string, hit bool)
+}
+
+// InjectionPolicyProvider 按租户获取提示词注入检测策略
+type InjectionPolicyProvider func(tenantID string) InjectionPolicy
+
+// ModerationPolicyProvider 按租户获取回答审核策略
+type ModerationPolicyProvider func(tenantID string) ModerationPolicy
+
+// SessionMasker 按租户策略掩码保存到会话中的消息内容（实现见 infrastructure/redact）
+type SessionMasker func(tenantID, text string) string
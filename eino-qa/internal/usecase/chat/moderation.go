@@ -0,0 +1,184 @@
+package chat
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"eino-qa/internal/infrastructure/ai/eino"
+)
+
+const (
+	// defaultModerationFallback 回答未通过审核时的默认回复
+	defaultModerationFallback = "抱歉，这个问题我暂时无法回答，如需帮助请联系人工客服。"
+	// moderationHoldback 流式审核时除最长关键词外额外暂存的字符数，覆盖正则黑名单跨分片的匹配
+	moderationHoldback = 16
+)
+
+// ModerationPolicy 租户的回答审核策略
+type ModerationPolicy struct {
+	Enabled         bool
+	Method          eino.ModerationMethod
+	Rules           *eino.ModerationRules // 禁止话题、竞品和正则黑名单
+	FallbackMessage string                // 未通过审核时的回复，为空时使用默认回复
+}
+
+// moderationVerdict 回答未通过审核的结果
+type moderationVerdict struct {
+	report   *eino.ModerationReport
+	fallback string
+}
+
+// WithModeration 设置回答审核器和租户策略（可选）
+func (uc *ChatUseCase) WithModeration(moderator *eino.Moderator, provider ModerationPolicyProvider) *ChatUseCase {
+	uc.moderator = moderator
+	uc.moderationPolicies = provider
+	return uc
+}
+
+// moderationPolicy 获取当前租户启用的审核策略，未启用时返回 false
+func (uc *ChatUseCase) moderationPolicy(ctx context.Context) (ModerationPolicy, bool) {
+	if uc.moderator == nil || uc.moderationPolicies == nil {
+		return ModerationPolicy{}, false
+	}
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	policy := uc.moderationPolicies(tenantID)
+	if policy.FallbackMessage == "" {
+		policy.FallbackMessage = defaultModerationFallback
+	}
+	return policy, policy.Enabled
+}
+
+// moderateAnswer 在回答发送给用户之前审核，未通过时返回审核结果，调用方改用兜底回复
+// 审核模型调用失败时降级为规则审核
+func (uc *ChatUseCase) moderateAnswer(ctx context.Context, answer string) *moderationVerdict {
+	policy, ok := uc.moderationPolicy(ctx)
+	if !ok {
+		return nil
+	}
+	return uc.checkModeration(ctx, policy, policy.Method, answer)
+}
+
+// checkModeration 按指定方式审核文本并记录告警日志
+func (uc *ChatUseCase) checkModeration(ctx context.Context, policy ModerationPolicy, method eino.ModerationMethod, text string) *moderationVerdict {
+	report, err := uc.moderator.Check(ctx, method, policy.Rules, text)
+	if err != nil {
+		uc.logger.Warn(ctx, "answer moderation failed, falling back to rules", map[string]interface{}{"error": err})
+		report, _ = uc.moderator.Check(ctx, eino.ModerationMethodRules, policy.Rules, text)
+	}
+	if !report.Flagged {
+		return nil
+	}
+
+	uc.logger.Warn(ctx, "answer flagged by moderation", map[string]interface{}{
+		"category": report.Category,
+		"match":    report.Match,
+		"method":   report.Method,
+		"reason":   report.Reason,
+	})
+	return &moderationVerdict{report: report, fallback: policy.FallbackMessage}
+}
+
+// metadata 合并到响应元数据的审核信息
+func (v *moderationVerdict) metadata() map[string]any {
+	if v == nil {
+		return nil
+	}
+	return map[string]any{
+		"moderation_flagged":  true,
+		"moderation_category": string(v.report.Category),
+		"moderation_method":   string(v.report.Method),
+	}
+}
+
+// moderatedStream 流式回答的审核器
+// 路由处理器把内容写入 in，审核通过的内容转发到输出通道；回答末尾暂存一段尚未确认的内容，
+// 避免关键词被拆在两个分片时前半段已经发出。命中规则时停止生成，发送替换消息并丢弃后续内容
+type moderatedStream struct {
+	in   chan<- *StreamChunk
+	done chan struct{}
+
+	verdict *moderationVerdict
+}
+
+// moderateStream 创建流式回答审核器，未启用审核时直接写入 out
+// stop 用于命中规则后停止生成
+func (uc *ChatUseCase) moderateStream(ctx context.Context, stop context.CancelFunc, out chan<- *StreamChunk) *moderatedStream {
+	policy, ok := uc.moderationPolicy(ctx)
+	if !ok {
+		return &moderatedStream{in: out}
+	}
+
+	in := make(chan *StreamChunk, 10)
+	s := &moderatedStream{in: in, done: make(chan struct{})}
+	holdback := policy.Rules.MaxKeywordLen() + moderationHoldback
+
+	go func() {
+		defer close(s.done)
+
+		var text strings.Builder
+		sent := 0
+		for chunk := range in {
+			if s.verdict != nil {
+				continue // 已替换，丢弃剩余内容
+			}
+			if chunk.Content == "" {
+				out <- chunk
+				continue
+			}
+
+			text.WriteString(chunk.Content)
+			if policy.Rules.Match(text.String()) != nil {
+				s.verdict = uc.checkModeration(ctx, policy, eino.ModerationMethodRules, text.String())
+				out <- &StreamChunk{Content: s.verdict.fallback, Replace: true}
+				stop()
+				continue
+			}
+
+			// 只发送暂存区之前的内容
+			if end := safeCut(text.String(), holdback); end > sent {
+				out <- &StreamChunk{Content: text.String()[sent:end]}
+				sent = end
+			}
+		}
+		if s.verdict != nil {
+			return
+		}
+
+		// 完整回答按租户审核方式（可能调用审核模型）再审核一次
+		full := text.String()
+		if s.verdict = uc.checkModeration(ctx, policy, policy.Method, full); s.verdict != nil {
+			out <- &StreamChunk{Content: s.verdict.fallback, Replace: true}
+			return
+		}
+		if sent < len(full) {
+			out <- &StreamChunk{Content: full[sent:]}
+		}
+	}()
+
+	return s
+}
+
+// close 结束写入并等待审核完成，返回未通过审核的结果
+func (s *moderatedStream) close() *moderationVerdict {
+	if s.done == nil {
+		return nil
+	}
+	close(s.in)
+	<-s.done
+	return s.verdict
+}
+
+// safeCut 返回保留末尾 holdback 个字符后可以发送的字节位置
+func safeCut(text string, holdback int) int {
+	n := utf8.RuneCountInString(text) - holdback
+	if n <= 0 {
+		return 0
+	}
+	end := 0
+	for i := 0; i < n; i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+	return end
+}
@@ -0,0 +1,144 @@
+package chat
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/logger"
+	"eino-qa/internal/infrastructure/repository/sqlite"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// directChatModel 意图总是 direct，回答按分片流式返回的聊天模型
+type directChatModel struct {
+	chunks []string
+}
+
+func (m *directChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if strings.Contains(input[len(input)-1].Content, "请分析用户意图") {
+		return schema.AssistantMessage(`{"intent":"direct","confidence":0.95,"reason":"闲聊"}`, nil), nil
+	}
+	return schema.AssistantMessage(strings.Join(m.chunks, ""), nil), nil
+}
+
+func (m *directChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	reader, writer := schema.Pipe[*schema.Message](0)
+	go func() {
+		defer writer.Close()
+		for _, chunk := range m.chunks {
+			if writer.Send(schema.AssistantMessage(chunk, nil), nil) {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+func (m *directChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+func TestChatUseCase_Moderation(t *testing.T) {
+	chatModel := &directChatModel{}
+	client := eino.NewClientWithModels(chatModel, runeEmbedder{}, eino.ClientConfig{})
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+	sessions := sqlite.NewSessionRepository(dbManager, "tenant1")
+
+	rules, err := eino.NewModerationRules([]string{"股票"}, []string{"XX学堂"}, nil)
+	require.NoError(t, err)
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		nil,
+		nil,
+		eino.NewResponseGenerator(client),
+		sessions,
+		time.Hour,
+		log,
+	).WithModeration(eino.NewModerator(client), func(tenantID string) ModerationPolicy {
+		return ModerationPolicy{Enabled: tenantID == "tenant1", Rules: rules, FallbackMessage: "这个问题请咨询人工客服。"}
+	})
+
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+	flagged := []string{"我们的课程", "比 XX", "学堂便宜一半，", "欢迎报名。"}
+
+	t.Run("未通过审核时改用兜底回复", func(t *testing.T) {
+		chatModel.chunks = flagged
+
+		resp, err := uc.Execute(ctx, &ChatRequest{Query: "你们和别家比怎么样", TenantID: "tenant1"})
+		require.NoError(t, err)
+		assert.Equal(t, "这个问题请咨询人工客服。", resp.Answer)
+		assert.Equal(t, true, resp.Metadata["moderation_flagged"])
+		assert.Equal(t, "competitor", resp.Metadata["moderation_category"])
+		assert.Empty(t, resp.Suggestions)
+
+		// 会话中保存兜底回复
+		session, err := sessions.Load(ctx, resp.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, "这个问题请咨询人工客服。", session.Messages[1].Content)
+	})
+
+	t.Run("流式回答命中规则时截断并替换", func(t *testing.T) {
+		chatModel.chunks = append([]string{"您好，我们的 Go 语言进阶课程共 40 课时，包含并发编程和微服务实战，"}, flagged...)
+
+		chunks, err := uc.ExecuteStream(ctx, &ChatRequest{Query: "你们和别家比怎么样", TenantID: "tenant1", Stream: true})
+		require.NoError(t, err)
+		var sent string
+		var replaced *StreamChunk
+		var done *StreamChunk
+		for chunk := range chunks {
+			switch {
+			case chunk.Done:
+				done = chunk
+			case chunk.Replace:
+				replaced = chunk
+			case replaced == nil:
+				sent += chunk.Content
+			default:
+				t.Fatalf("content after replace: %q", chunk.Content)
+			}
+		}
+
+		// 替换前已发送的内容不包含竞品名称的任何部分
+		assert.NotEmpty(t, sent)
+		assert.NotContains(t, sent, "XX")
+		require.NotNil(t, replaced)
+		assert.Equal(t, "这个问题请咨询人工客服。", replaced.Content)
+		require.NotNil(t, done)
+		assert.Equal(t, true, done.Metadata["moderation_flagged"])
+	})
+
+	t.Run("通过审核的流式回答完整发送", func(t *testing.T) {
+		chatModel.chunks = []string{"您好，我们的 Go 语言进阶课程共 40 课时，", "包含并发编程和微服务实战，", "欢迎报名。"}
+
+		chunks, err := uc.ExecuteStream(ctx, &ChatRequest{Query: "介绍一下课程", TenantID: "tenant1", Stream: true})
+		require.NoError(t, err)
+		var contents []string
+		for chunk := range chunks {
+			assert.False(t, chunk.Replace)
+			if chunk.Content != "" {
+				contents = append(contents, chunk.Content)
+			}
+		}
+		assert.Equal(t, strings.Join(chatModel.chunks, ""), strings.Join(contents, ""))
+		assert.Greater(t, len(contents), 1)
+	})
+
+	t.Run("未启用的租户不审核", func(t *testing.T) {
+		chatModel.chunks = flagged
+
+		resp, err := uc.Execute(context.WithValue(context.Background(), "tenant_id", "tenant2"), &ChatRequest{Query: "你们和别家比怎么样", TenantID: "tenant2"})
+		require.NoError(t, err)
+		assert.Equal(t, strings.Join(flagged, ""), resp.Answer)
+		assert.NotContains(t, resp.Metadata, "moderation_flagged")
+	})
+}
@@ -0,0 +1,131 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+)
+
+// confirmReplies 确认执行订单操作的回复
+var confirmReplies = map[string]bool{
+	"确认": true, "确定": true, "确认执行": true, "是": true, "是的": true, "好": true, "好的": true,
+	"yes": true, "y": true, "confirm": true, "ok": true,
+}
+
+// rejectReplies 放弃订单操作的回复
+var rejectReplies = map[string]bool{
+	"算了": true, "不": true, "不用": true, "不用了": true, "不要": true, "否": true, "放弃": true,
+	"no": true, "n": true,
+}
+
+// normalizeReply 去除首尾空白和标点，统一小写
+func normalizeReply(query string) string {
+	return strings.ToLower(strings.Trim(query, " \t\r\n。！!，,.~～"))
+}
+
+// resolvePendingOrderAction 处理会话中待确认的订单操作
+// 用户确认时执行，拒绝时放弃；其他回复视为放弃本次操作，返回 false 由调用方按正常流程处理
+// 返回 true 表示本轮已作为确认回合处理
+func (uc *ChatUseCase) resolvePendingOrderAction(ctx context.Context, session *entity.Session, userID, query string) (string, bool) {
+	action := session.PendingOrderAction()
+	if action == nil {
+		return "", false
+	}
+
+	// 无论结果如何，待确认操作只对紧接着的一轮有效
+	session.ClearPendingOrderAction()
+
+	reply := normalizeReply(query)
+	confirmed, rejected := confirmReplies[reply], rejectReplies[reply]
+
+	fields := map[string]interface{}{
+		"action_id":   action.ID,
+		"action_type": action.Type,
+		"order_id":    action.OrderID,
+	}
+
+	// 身份变化（如切换账号）时不执行之前用户发起的操作
+	if action.UserID != userID {
+		uc.logger.Warn(ctx, "pending order action discarded: user mismatch", fields)
+		return "", false
+	}
+
+	if !confirmed && !rejected {
+		uc.logger.Info(ctx, "pending order action abandoned", fields)
+		return "", false
+	}
+
+	if rejected {
+		uc.logger.Info(ctx, "pending order action rejected", fields)
+		return fmt.Sprintf("好的，已放弃对订单 %s 的%s操作。", action.OrderID, action.Type.DisplayName()), true
+	}
+
+	if action.IsExpired() {
+		uc.logger.Info(ctx, "pending order action expired", fields)
+		return fmt.Sprintf("%s的确认已超时，请重新发起。", action.Type.DisplayName()), true
+	}
+
+	answer, event, err := uc.orderQuerier.ExecuteAction(ctx, action)
+	if err != nil {
+		fields["error"] = err
+		uc.logger.Error(ctx, "order action failed", fields)
+		return uc.responseGenerator.GenerateErrorMessage(err), true
+	}
+
+	// 审计：订单事件已记录操作者和原因，这里记录日志并发布业务事件
+	if event == nil {
+		uc.logger.Info(ctx, "order action already applied", fields)
+		return answer, true
+	}
+
+	fields["event_id"] = event.ID
+	fields["from_status"] = event.FromStatus
+	fields["to_status"] = event.ToStatus
+	fields["actor"] = event.Actor
+	uc.logger.Info(ctx, "order action executed", fields)
+
+	uc.publishEvent(ctx, entity.EventOrderActionExecuted, map[string]any{
+		"action_id":   action.ID,
+		"action_type": string(action.Type),
+		"order_id":    action.OrderID,
+		"user_id":     action.UserID,
+		"event_id":    event.ID,
+		"from_status": string(event.FromStatus),
+		"to_status":   string(event.ToStatus),
+		"reason":      event.Reason,
+	})
+
+	return answer, true
+}
+
+// handleOrderActionRequest 识别订单操作请求，满足条件时在会话中记录待确认操作并返回确认卡片
+// 返回 false 表示不是操作请求（或识别失败），由调用方按订单查询处理
+func (uc *ChatUseCase) handleOrderActionRequest(ctx context.Context, session *entity.Session, userID, query string) (string, []*ResponseBlock, bool) {
+	action, reply, err := uc.orderQuerier.PlanAction(ctx, userID, query)
+	if err != nil {
+		var missing *eino.MissingSlotError
+		if errors.As(err, &missing) {
+			return uc.askForSlot(ctx, session, entity.IntentOrder, query, missing.Slot, missing.Prompt), nil, true
+		}
+		uc.logger.Warn(ctx, "order action recognition failed", map[string]interface{}{"error": err})
+		return "", nil, false
+	}
+
+	var blocks []*ResponseBlock
+	if action != nil {
+		blocks = actionConfirmationBlocks(action)
+		session.SetPendingOrderAction(action)
+		uc.logger.Info(ctx, "order action pending confirmation", map[string]interface{}{
+			"action_id":   action.ID,
+			"action_type": action.Type,
+			"order_id":    action.OrderID,
+			"expires_at":  action.ExpiresAt,
+		})
+	}
+
+	return reply, blocks, reply != ""
+}
@@ -59,7 +59,7 @@ func (uc *ChatUseCase) ExecuteParallel(ctx context.Context, req *ChatRequest) (*
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		answer, err := uc.orderQuerier.Query(ctx, req.Query)
+		answer, err := uc.orderQuerier.Query(ctx, req.UserID, req.Query)
 		result.OrderAnswer = answer
 		result.OrderError = err
 
@@ -164,115 +164,3 @@ func (uc *ChatUseCase) mergeAnswersWithLLM(ctx context.Context, query string, re
 
 	return answer, nil
 }
-
-// ExecuteWithParallelRetrieval 执行带并行检索的对话
-// 这个方法会在识别意图后，根据需要并行查询多个数据源
-func (uc *ChatUseCase) ExecuteWithParallelRetrieval(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
-	// 验证请求
-	if err := req.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
-	}
-
-	startTime := time.Now()
-	uc.logger.Info(ctx, "chat with parallel retrieval started", map[string]interface{}{
-		"tenant_id":  req.TenantID,
-		"session_id": req.SessionID,
-		"query":      req.Query,
-	})
-
-	// 1. 加载或创建会话
-	session, err := uc.loadOrCreateSession(ctx, req.TenantID, req.SessionID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load session: %w", err)
-	}
-
-	// 2. 添加用户消息
-	userMessage := entity.NewMessage(req.Query, "user")
-	if err := session.AddMessage(userMessage); err != nil {
-		return nil, fmt.Errorf("failed to add user message: %w", err)
-	}
-
-	// 3. 识别意图
-	intent, err := uc.intentRecognizer.Recognize(ctx, req.Query, session.GetMessages())
-	if err != nil {
-		return nil, fmt.Errorf("failed to recognize intent: %w", err)
-	}
-
-	uc.logger.Info(ctx, "intent recognized", map[string]interface{}{
-		"intent":     intent.Type,
-		"confidence": intent.Confidence,
-	})
-
-	var answer string
-	var sources []*entity.Document
-
-	// 4. 根据意图决定是否使用并行检索
-	switch intent.Type {
-	case entity.IntentCourse:
-		// 单一数据源，不需要并行
-		answer, sources, err = uc.ragRetriever.Retrieve(ctx, req.Query)
-		if err != nil {
-			answer = uc.responseGenerator.GenerateFallbackMessage()
-		}
-
-	case entity.IntentOrder:
-		// 单一数据源，不需要并行
-		answer, err = uc.orderQuerier.Query(ctx, req.Query)
-		if err != nil {
-			answer = uc.responseGenerator.GenerateErrorMessage(err)
-		}
-
-	case entity.IntentDirect:
-		// 可能需要多个数据源，使用并行检索
-		parallelResult, err := uc.ExecuteParallelWithTimeout(ctx, req, 5*time.Second)
-		if err != nil {
-			uc.logger.Error(ctx, "parallel retrieval failed", map[string]interface{}{"error": err})
-			// 降级到普通响应生成
-			answer, _ = uc.responseGenerator.Generate(ctx, req.Query, session.GetMessages())
-		} else {
-			// 合并并行查询结果
-			answer, sources, err = uc.MergeParallelResults(ctx, parallelResult, req.Query)
-			if err != nil {
-				answer = uc.responseGenerator.GenerateFallbackMessage()
-			}
-		}
-
-	case entity.IntentHandoff:
-		answer = uc.handleHandoffIntent(ctx, intent)
-
-	default:
-		answer = uc.responseGenerator.GenerateFallbackMessage()
-	}
-
-	// 5. 添加助手消息
-	assistantMessage := entity.NewMessage(answer, "assistant")
-	if err := session.AddMessage(assistantMessage); err != nil {
-		uc.logger.Error(ctx, "failed to add assistant message", map[string]interface{}{"error": err})
-	}
-
-	// 6. 保存会话
-	if err := uc.sessionRepo.Save(ctx, session); err != nil {
-		uc.logger.Error(ctx, "failed to save session", map[string]interface{}{"error": err})
-	}
-
-	duration := time.Since(startTime)
-	uc.logger.Info(ctx, "chat with parallel retrieval completed", map[string]interface{}{
-		"duration_ms": duration.Milliseconds(),
-		"intent":      intent.Type,
-	})
-
-	// 7. 构建响应
-	response := &ChatResponse{
-		Answer:    answer,
-		Route:     string(intent.Type),
-		Sources:   sources,
-		SessionID: session.ID,
-		Metadata: map[string]any{
-			"intent":      intent.Type,
-			"confidence":  intent.Confidence,
-			"duration_ms": duration.Milliseconds(),
-		},
-	}
-
-	return response, nil
-}
@@ -0,0 +1,36 @@
+package chat
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// WithSessionMasker 设置会话消息的个人信息掩码（可选），保存会话前按租户策略掩码消息内容
+func (uc *ChatUseCase) WithSessionMasker(masker SessionMasker) *ChatUseCase {
+	uc.sessionMasker = masker
+	return uc
+}
+
+// saveSession 掩码消息内容和元数据中保存的用户原文后保存会话
+// 掩码是幂等的，之前轮次已掩码的内容不会变化；后续轮次的对话历史使用掩码后的内容
+// 已填写的槽位值（如订单号）是后续轮次要使用的结构化数据，不掩码
+func (uc *ChatUseCase) saveSession(ctx context.Context, session *entity.Session) error {
+	if uc.sessionMasker != nil {
+		mask := func(text string) string {
+			return uc.sessionMasker(session.TenantID, text)
+		}
+		for _, msg := range session.Messages {
+			msg.Content = mask(msg.Content)
+		}
+		if slot := session.ExpectedSlot(); slot != nil {
+			slot.Query = mask(slot.Query)
+			session.SetExpectedSlot(slot)
+		}
+		if action := session.PendingOrderAction(); action != nil {
+			action.Reason = mask(action.Reason)
+			session.SetPendingOrderAction(action)
+		}
+	}
+	return uc.sessionRepo.Save(ctx, session)
+}
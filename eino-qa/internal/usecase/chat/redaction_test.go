@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/logger"
+	"eino-qa/internal/infrastructure/redact"
+	"eino-qa/internal/infrastructure/repository/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatUseCase_SessionMasker(t *testing.T) {
+	client := eino.NewClientWithModels(&scriptedChatModel{}, runeEmbedder{}, eino.ClientConfig{})
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+	sessions := sqlite.NewSessionRepository(dbManager, "tenant1")
+
+	goCourse := entity.NewDocument("Go 语言进阶课程共 40 课时，价格 1999 元。", "tenant1")
+	redactor := redact.New(func(tenantID string) redact.Policy {
+		return redact.Policy{Enabled: true, MaskSessions: true}
+	})
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		eino.NewRAGRetriever(client, &memoryVectorRepository{docs: []*entity.Document{goCourse}}, nil),
+		nil,
+		eino.NewResponseGenerator(client),
+		sessions,
+		time.Hour,
+		log,
+	).WithSessionMasker(redactor.MaskSession)
+
+	ctx := context.WithValue(context.Background(), "tenant_id", "tenant1")
+	resp, err := uc.Execute(ctx, &ChatRequest{Query: "我的手机号是13812345678，Go 进阶课程多少钱", TenantID: "tenant1"})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Answer, "1999")
+
+	// 保存的会话中手机号已掩码
+	session, err := sessions.Load(ctx, resp.SessionID)
+	require.NoError(t, err)
+	require.NotEmpty(t, session.Messages)
+	assert.Equal(t, "我的手机号是138****5678，Go 进阶课程多少钱", session.Messages[0].Content)
+}
+
+func TestChatUseCase_saveSessionMasksMetadata(t *testing.T) {
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+	sessions := sqlite.NewSessionRepository(dbManager, "tenant1")
+
+	redactor := redact.New(func(tenantID string) redact.Policy {
+		return redact.Policy{Enabled: true, MaskSessions: true}
+	})
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := NewChatUseCase(nil, nil, nil, nil, sessions, time.Hour, log).WithSessionMasker(redactor.MaskSession)
+
+	session := entity.NewSession("tenant1", time.Hour)
+	session.SetExpectedSlot(&entity.ExpectedSlot{
+		Name:      entity.SlotOrderID,
+		Intent:    entity.IntentOrder,
+		Query:     "手机号 13812345678 的订单到哪了",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	session.SetPendingOrderAction(entity.NewPendingOrderAction(entity.OrderActionRefund, "#20251114001", "alice", "退款请联系 13812345678", 0))
+	session.SetSlotValue(entity.SlotOrderID, "#20251114001")
+
+	ctx := context.Background()
+	require.NoError(t, uc.saveSession(ctx, session))
+
+	// 元数据中保存的用户原文同样掩码，槽位值原样保存
+	loaded, err := sessions.Load(ctx, session.ID)
+	require.NoError(t, err)
+	require.NotNil(t, loaded.ExpectedSlot())
+	assert.Equal(t, "手机号 138****5678 的订单到哪了", loaded.ExpectedSlot().Query)
+	require.NotNil(t, loaded.PendingOrderAction())
+	assert.Equal(t, "退款请联系 138****5678", loaded.PendingOrderAction().Reason)
+	assert.Equal(t, "#20251114001", loaded.SlotValue(entity.SlotOrderID))
+}
@@ -0,0 +1,197 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// BlockType 结构化响应块类型
+type BlockType string
+
+const (
+	// BlockOrderCard 订单卡片
+	BlockOrderCard BlockType = "order_card"
+	// BlockSourceCitation 知识库来源引用
+	BlockSourceCitation BlockType = "source_citation"
+	// BlockQuickReplies 快捷回复
+	BlockQuickReplies BlockType = "quick_replies"
+	// BlockHandoffNotice 转人工提示
+	BlockHandoffNotice BlockType = "handoff_notice"
+	// BlockActionConfirmation 订单操作确认
+	BlockActionConfirmation BlockType = "action_confirmation"
+)
+
+// maxCitationSnippet 来源引用摘要的最大字符数
+const maxCitationSnippet = 120
+
+// ResponseBlock 结构化响应块，与文本回答一起返回，供前端渲染为卡片、链接和按钮
+// 按 Type 只设置对应的一个字段
+type ResponseBlock struct {
+	Type               BlockType
+	OrderCard          *OrderCard
+	SourceCitation     *SourceCitation
+	QuickReplies       *QuickReplies
+	HandoffNotice      *HandoffNotice
+	ActionConfirmation *ActionConfirmation
+}
+
+// OrderCard 订单卡片
+type OrderCard struct {
+	OrderID     string
+	CourseName  string
+	Amount      float64
+	Status      entity.OrderStatus
+	StatusLabel string // 状态的中文名称，用于状态徽标
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SourceCitation 知识库来源引用
+type SourceCitation struct {
+	Index      int // 从 1 开始的序号，与回答中的 [n] 引用标记对应
+	DocumentID string
+	Title      string // 元数据 title/question，没有时截取内容开头
+	URL        string // 元数据 url（如课程详情页），可为空
+	Snippet    string
+	Score      float64
+	Cited      bool // 回答中是否引用了该文档
+}
+
+// QuickReplies 快捷回复，用户点击后作为下一轮的 query 发送
+type QuickReplies struct {
+	Options []string
+}
+
+// HandoffNotice 转人工提示
+type HandoffNotice struct {
+	Reason  string
+	Message string
+}
+
+// ActionConfirmation 等待用户确认的订单操作
+type ActionConfirmation struct {
+	ActionID     string
+	Action       entity.OrderActionType
+	ActionLabel  string
+	OrderID      string
+	Reason       string
+	ExpiresAt    time.Time
+	ConfirmReply string // 确认时发送的回复
+	RejectReply  string // 放弃时发送的回复
+}
+
+// orderCardBlocks 为订单生成订单卡片
+// 只有一个订单时，按状态机附加可执行操作的快捷回复
+func orderCardBlocks(orders []*entity.Order) []*ResponseBlock {
+	blocks := make([]*ResponseBlock, 0, len(orders)+1)
+	for _, order := range orders {
+		blocks = append(blocks, &ResponseBlock{
+			Type: BlockOrderCard,
+			OrderCard: &OrderCard{
+				OrderID:     order.ID,
+				CourseName:  order.CourseName,
+				Amount:      order.Amount,
+				Status:      order.Status,
+				StatusLabel: order.Status.DisplayName(),
+				CreatedAt:   order.CreatedAt,
+				UpdatedAt:   order.UpdatedAt,
+			},
+		})
+	}
+
+	if len(orders) == 1 {
+		var options []string
+		for _, action := range []entity.OrderActionType{entity.OrderActionCancel, entity.OrderActionRefund} {
+			if orders[0].Status.CanTransitionTo(action.TargetStatus()) {
+				options = append(options, fmt.Sprintf("%s %s", action.DisplayName(), orders[0].ID))
+			}
+		}
+		if block := quickRepliesBlock(options...); block != nil {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks
+}
+
+// citationBlocks 为 RAG 来源文档生成来源引用
+func citationBlocks(sources []*entity.Document) []*ResponseBlock {
+	blocks := make([]*ResponseBlock, 0, len(sources))
+	for i, doc := range sources {
+		cited, _ := doc.Cited()
+		blocks = append(blocks, &ResponseBlock{
+			Type: BlockSourceCitation,
+			SourceCitation: &SourceCitation{
+				Index:      i + 1,
+				DocumentID: doc.ID,
+				Title:      doc.Title(),
+				URL:        metadataString(doc, "url"),
+				Snippet:    truncateRunes(doc.Content, maxCitationSnippet),
+				Score:      doc.Score,
+				Cited:      cited,
+			},
+		})
+	}
+	return blocks
+}
+
+// quickRepliesBlock 生成快捷回复，没有选项时返回 nil
+func quickRepliesBlock(options ...string) *ResponseBlock {
+	if len(options) == 0 {
+		return nil
+	}
+	return &ResponseBlock{
+		Type:         BlockQuickReplies,
+		QuickReplies: &QuickReplies{Options: options},
+	}
+}
+
+// handoffBlock 生成转人工提示
+func handoffBlock(reason, message string) *ResponseBlock {
+	return &ResponseBlock{
+		Type:          BlockHandoffNotice,
+		HandoffNotice: &HandoffNotice{Reason: reason, Message: message},
+	}
+}
+
+// actionConfirmationBlocks 生成订单操作确认卡片和确认/放弃快捷回复
+func actionConfirmationBlocks(action *entity.PendingOrderAction) []*ResponseBlock {
+	const confirmReply, rejectReply = "确认", "算了"
+
+	return []*ResponseBlock{
+		{
+			Type: BlockActionConfirmation,
+			ActionConfirmation: &ActionConfirmation{
+				ActionID:     action.ID,
+				Action:       action.Type,
+				ActionLabel:  action.Type.DisplayName(),
+				OrderID:      action.OrderID,
+				Reason:       action.Reason,
+				ExpiresAt:    action.ExpiresAt,
+				ConfirmReply: confirmReply,
+				RejectReply:  rejectReply,
+			},
+		},
+		quickRepliesBlock(confirmReply, rejectReply),
+	}
+}
+
+// metadataString 读取文档的字符串元数据
+func metadataString(doc *entity.Document, key string) string {
+	value, _ := doc.GetMetadata(key)
+	s, _ := value.(string)
+	return strings.TrimSpace(s)
+}
+
+// truncateRunes 按字符截取文本，超出时追加省略号
+func truncateRunes(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
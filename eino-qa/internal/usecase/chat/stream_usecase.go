@@ -2,6 +2,7 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -40,6 +41,17 @@ func (uc *ChatUseCase) ExecuteStream(ctx context.Context, req *ChatRequest) (<-c
 			}
 			return
 		}
+		ctx = withSessionContext(ctx, req.TenantID, session.ID)
+		ctx, assignments := uc.assignExperiments(ctx, req.TenantID, session.ID)
+		req, injection := uc.guardQuery(ctx, req)
+		var faq *faqMatch
+		var cacheLookup *answerCacheLookup
+		if !injection.stops() {
+			faq = uc.matchFAQ(ctx, session, req.Query)
+			if faq == nil {
+				cacheLookup = uc.lookupAnswerCache(ctx, session, req.Query, assignments)
+			}
+		}
 
 		// 2. 添加用户消息到会话
 		userMessage := entity.NewMessage(req.Query, "user")
@@ -52,8 +64,8 @@ func (uc *ChatUseCase) ExecuteStream(ctx context.Context, req *ChatRequest) (<-c
 			return
 		}
 
-		// 3. 识别意图
-		intent, err := uc.intentRecognizer.Recognize(ctx, req.Query, session.GetMessages())
+		// 3. 识别意图（查询被拦截、命中标准问答、语义缓存、订单操作确认回合、槽位追问时直接得到回答）
+		turn, err := uc.routeTurn(ctx, session, req, injection, faq, cacheLookup)
 		if err != nil {
 			uc.logger.Error(ctx, "failed to recognize intent", map[string]interface{}{"error": err})
 			chunkChan <- &StreamChunk{
@@ -63,38 +75,94 @@ func (uc *ChatUseCase) ExecuteStream(ctx context.Context, req *ChatRequest) (<-c
 			return
 		}
 
+		intent, fullAnswer := turn.intent, turn.answer
 		uc.logger.Info(ctx, "intent recognized", map[string]interface{}{
 			"intent":     intent.Type,
 			"confidence": intent.Confidence,
 		})
 
 		// 4. 根据意图路由到不同的处理流程
-		var fullAnswer string
 		var sources []*entity.Document
+		var blocks []*ResponseBlock
+		var routeMetadata map[string]any
+		var routeErr error
+		cacheable := false
+
+		// 生成的回答经过审核后再发送（已生成回答的回合和命中缓存的回答不审核），
+		// 命中审核规则时停止生成
+		routeCtx, stopRoute := context.WithCancel(ctx)
+		defer stopRoute()
+		stream := &moderatedStream{in: chunkChan}
+		if !turn.answered && turn.cached == nil {
+			stream = uc.moderateStream(ctx, stopRoute, chunkChan)
+		}
 
-		switch intent.Type {
-		case entity.IntentCourse:
-			fullAnswer, sources = uc.handleCourseIntentStream(ctx, req.Query, chunkChan)
-		case entity.IntentOrder:
-			fullAnswer = uc.handleOrderIntentStream(ctx, req.Query, chunkChan)
-		case entity.IntentDirect:
-			fullAnswer = uc.handleDirectIntentStream(ctx, req.Query, session.GetMessages(), chunkChan)
-		case entity.IntentHandoff:
-			fullAnswer = uc.handleHandoffIntent(ctx, intent)
+		switch {
+		case turn.cached != nil:
+			fullAnswer, sources = turn.cached.Answer, turn.cached.Sources
+			blocks = citationBlocks(sources)
 			chunkChan <- &StreamChunk{Content: fullAnswer}
+		case turn.answered:
+			chunkChan <- &StreamChunk{Content: fullAnswer}
+		case intent.Type == entity.IntentCourse:
+			course := uc.handleCourseIntentStream(routeCtx, req.Query, turn.query, stream.in)
+			fullAnswer, sources, routeMetadata, routeErr = course.answer, course.sources, course.metadata, course.err
+			blocks = append(citationBlocks(sources), course.blocks...)
+			cacheable = course.cacheable()
+		case intent.Type == entity.IntentOrder:
+			fullAnswer, blocks, routeErr = uc.handleOrderIntentStream(routeCtx, session, req.UserID, turn.query, stream.in)
+		case intent.Type == entity.IntentDirect:
+			fullAnswer, routeErr = uc.handleDirectIntentStream(routeCtx, turn.query, session.GetMessages(), stream.in)
+			cacheable = routeErr == nil
+		case intent.Type == entity.IntentHandoff:
+			fullAnswer, blocks = uc.handleHandoffIntent(routeCtx, turn.query, intent)
+			stream.in <- &StreamChunk{Content: fullAnswer}
 		default:
 			fullAnswer = uc.responseGenerator.GenerateFallbackMessage()
-			chunkChan <- &StreamChunk{Content: fullAnswer}
+			stream.in <- &StreamChunk{Content: fullAnswer}
+		}
+
+		// 未通过审核时已发送替换消息，不再发送来源和结构化响应块
+		moderation := stream.close()
+
+		// 模型调用并发名额不足时不发送错误回答，发送错误标记由接口层告知客户端稍后重试
+		if errors.Is(routeErr, entity.ErrLLMConcurrencyLimit) {
+			uc.logger.Warn(ctx, "llm concurrency limit reached", map[string]interface{}{"intent": intent.Type})
+			chunkChan <- &StreamChunk{
+				Error: fmt.Errorf("failed to handle %s intent: %w", intent.Type, routeErr),
+				Done:  true,
+			}
+			return
+		}
+		if moderation != nil {
+			fullAnswer, sources, blocks, cacheable = moderation.fallback, nil, nil, false
+		}
+
+		// 结构化响应块在文本之后逐个发送
+		for _, block := range blocks {
+			chunkChan <- &StreamChunk{Block: block}
+		}
+
+		// 建议问题随完成标记发送（订单操作确认、槽位追问、出错和未通过审核的回合不推荐，命中缓存时沿用缓存的建议）
+		var suggestions []string
+		switch {
+		case turn.cached != nil:
+			suggestions = turn.cached.Suggestions
+		case !turn.answered && routeErr == nil && moderation == nil:
+			suggestions = uc.suggestFollowUps(ctx, intent.Type, turn.query, fullAnswer, sources, blocks)
+		}
+		if cacheable {
+			uc.storeAnswer(ctx, cacheLookup, intent, fullAnswer, sources, suggestions)
 		}
 
 		// 5. 添加助手消息到会话
-		assistantMessage := entity.NewMessage(fullAnswer, "assistant")
+		assistantMessage := newAssistantMessage(fullAnswer, string(intent.Type), intent)
 		if err := session.AddMessage(assistantMessage); err != nil {
 			uc.logger.Error(ctx, "failed to add assistant message", map[string]interface{}{"error": err})
 		}
 
 		// 6. 保存会话
-		if err := uc.sessionRepo.Save(ctx, session); err != nil {
+		if err := uc.saveSession(ctx, session); err != nil {
 			uc.logger.Error(ctx, "failed to save session", map[string]interface{}{"error": err})
 		}
 
@@ -103,18 +171,31 @@ func (uc *ChatUseCase) ExecuteStream(ctx context.Context, req *ChatRequest) (<-c
 		uc.logger.Info(ctx, "stream chat request completed", map[string]interface{}{
 			"duration_ms": duration.Milliseconds(),
 			"intent":      intent.Type,
+			"experiments": experimentMetadata(assignments),
 		})
+		uc.recordExperimentOutcomes(ctx, assignments, newTurnOutcome(intent, assistantMessage.ID, duration, routeMetadata))
+
+		metadata := mergeMetadata(map[string]any{
+			"intent":      intent.Type,
+			"confidence":  intent.Confidence,
+			"duration_ms": duration.Milliseconds(),
+			"session_id":  session.ID,
+			"message_id":  assistantMessage.ID,
+			"sources":     sources,
+		}, routeMetadata)
+		mergeMetadata(metadata, cacheLookup.metadata())
+		mergeMetadata(metadata, faq.metadata())
+		mergeMetadata(metadata, injection.metadata())
+		mergeMetadata(metadata, moderation.metadata())
+		if variants := experimentMetadata(assignments); variants != nil {
+			metadata["experiments"] = variants
+		}
 
 		// 7. 发送完成标记
 		chunkChan <- &StreamChunk{
-			Done: true,
-			Metadata: map[string]any{
-				"intent":      intent.Type,
-				"confidence":  intent.Confidence,
-				"duration_ms": duration.Milliseconds(),
-				"session_id":  session.ID,
-				"sources":     sources,
-			},
+			Done:        true,
+			Suggestions: suggestions,
+			Metadata:    metadata,
 		}
 	}()
 
@@ -122,46 +203,46 @@ func (uc *ChatUseCase) ExecuteStream(ctx context.Context, req *ChatRequest) (<-c
 }
 
 // handleCourseIntentStream 处理课程咨询意图（流式）
-func (uc *ChatUseCase) handleCourseIntentStream(ctx context.Context, query string, chunkChan chan<- *StreamChunk) (string, []*entity.Document) {
-	uc.logger.Info(ctx, "handling course intent (stream)", map[string]interface{}{"query": query})
-
-	// RAG 检索不支持流式，直接返回完整结果
-	answer, sources, err := uc.ragRetriever.Retrieve(ctx, query)
-	if err != nil {
-		uc.logger.Error(ctx, "RAG retrieval failed", map[string]interface{}{"error": err})
-		answer = uc.responseGenerator.GenerateFallbackMessage()
-		sources = nil
+// RAG 检索和依据校验不支持流式，校验通过后发送完整答案
+func (uc *ChatUseCase) handleCourseIntentStream(ctx context.Context, userQuery, query string, chunkChan chan<- *StreamChunk) *courseAnswer {
+	course := uc.handleCourseIntent(ctx, userQuery, query)
+	if course.err != nil {
+		return course
 	}
 
 	// 发送完整答案
-	chunkChan <- &StreamChunk{Content: answer}
+	chunkChan <- &StreamChunk{Content: course.answer}
 
-	return answer, sources
+	return course
 }
 
-// handleOrderIntentStream 处理订单查询意图（流式）
-func (uc *ChatUseCase) handleOrderIntentStream(ctx context.Context, query string, chunkChan chan<- *StreamChunk) string {
+// handleOrderIntentStream 处理订单查询意图（流式），返回完整答案和订单卡片
+// 查询失败时返回错误，回答中已包含发送给用户的错误消息（模型调用并发名额不足时不发送）
+func (uc *ChatUseCase) handleOrderIntentStream(ctx context.Context, session *entity.Session, userID, query string, chunkChan chan<- *StreamChunk) (string, []*ResponseBlock, error) {
 	uc.logger.Info(ctx, "handling order intent (stream)", map[string]interface{}{"query": query})
 
 	// 订单查询不支持流式，直接返回完整结果
-	answer, err := uc.orderQuerier.Query(ctx, query)
+	answer, blocks, err := uc.handleOrderIntent(ctx, session, userID, query)
+	if errors.Is(err, entity.ErrLLMConcurrencyLimit) {
+		return "", nil, err
+	}
 	if err != nil {
-		uc.logger.Error(ctx, "order query failed", map[string]interface{}{"error": err})
 		answer = uc.responseGenerator.GenerateErrorMessage(err)
 	}
 
 	// 发送完整答案
 	chunkChan <- &StreamChunk{Content: answer}
 
-	return answer
+	return answer, blocks, err
 }
 
 // handleDirectIntentStream 处理直接回答意图（流式）
-func (uc *ChatUseCase) handleDirectIntentStream(ctx context.Context, query string, history []*entity.Message, chunkChan chan<- *StreamChunk) string {
+// 生成失败或被取消时返回错误，回答中已包含发送给用户的错误消息（模型调用并发名额不足时不发送）
+func (uc *ChatUseCase) handleDirectIntentStream(ctx context.Context, query string, history []*entity.Message, chunkChan chan<- *StreamChunk) (string, error) {
 	uc.logger.Info(ctx, "handling direct intent (stream)", map[string]interface{}{"query": query})
 
 	// 使用响应生成器的流式接口
-	contentChan, errorChan := uc.responseGenerator.GenerateStream(ctx, query, history)
+	contentChan, errorChan := uc.responseGenerator.GenerateStream(experimentScope(ctx, entity.ExperimentDirect), query, history)
 
 	var fullAnswer string
 
@@ -171,7 +252,7 @@ func (uc *ChatUseCase) handleDirectIntentStream(ctx context.Context, query strin
 		case content, ok := <-contentChan:
 			if !ok {
 				// 通道关闭，流式响应结束
-				return fullAnswer
+				return fullAnswer, nil
 			}
 			fullAnswer += content
 			chunkChan <- &StreamChunk{Content: content}
@@ -179,14 +260,17 @@ func (uc *ChatUseCase) handleDirectIntentStream(ctx context.Context, query strin
 		case err, ok := <-errorChan:
 			if ok && err != nil {
 				uc.logger.Error(ctx, "stream generation failed", map[string]interface{}{"error": err})
+				if errors.Is(err, entity.ErrLLMConcurrencyLimit) {
+					return fullAnswer, err
+				}
 				errorMsg := uc.responseGenerator.GenerateErrorMessage(err)
 				chunkChan <- &StreamChunk{Content: errorMsg}
-				return fullAnswer + errorMsg
+				return fullAnswer + errorMsg, err
 			}
 
 		case <-ctx.Done():
 			uc.logger.Warn(ctx, "stream context cancelled", map[string]interface{}{})
-			return fullAnswer
+			return fullAnswer, ctx.Err()
 		}
 	}
 }
@@ -0,0 +1,359 @@
+package chat
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/redact"
+)
+
+const (
+	// minSuggestions 建议问题的最少数量，不足时不返回
+	minSuggestions = 2
+	// maxSuggestions 建议问题的最多数量
+	maxSuggestions = 4
+	// maxNeighbourChunks 生成建议时最多读取的相邻分块数
+	maxNeighbourChunks = 4
+	// popularQueryMinOverlap 热门问题与本轮来源文档的最低词汇重合度，避免推荐无关问题
+	popularQueryMinOverlap = 0.3
+	// maxPopularQueries 每个租户保留的热门问题数量
+	maxPopularQueries = 200
+	// popularQueryMinSessions 热门问题至少来自多少个不同会话才推荐给其他用户
+	popularQueryMinSessions = 3
+	// maxPopularQuerySessions 每个热门问题记录的会话数上限，超过后不再去重
+	maxPopularQuerySessions = 100
+	// suggestionBudgetWindow LLM 生成建议的调用次数统计窗口
+	suggestionBudgetWindow = time.Hour
+)
+
+// orderStatusSuggestions 按订单状态推荐的后续问题
+var orderStatusSuggestions = map[entity.OrderStatus][]string{
+	entity.OrderStatusPending:         {"如何支付这个订单？", "未支付的订单会保留多久？", "怎么取消订单？"},
+	entity.OrderStatusPaid:            {"课程什么时候开通？", "如何申请退款？", "可以开发票吗？"},
+	entity.OrderStatusRefundRequested: {"退款审核需要多久？", "退款多久到账？", "可以撤销退款申请吗？"},
+	entity.OrderStatusRefunded:        {"退款多久到账？", "如何重新购买课程？"},
+	entity.OrderStatusCancelled:       {"如何重新购买课程？", "订单取消后还能恢复吗？"},
+}
+
+// defaultOrderSuggestions 没有订单卡片（未找到订单、订单列表）时推荐的问题
+var defaultOrderSuggestions = []string{"我有哪些未支付的订单？", "我一共花了多少钱？", "如何申请退款？"}
+
+// SuggestionPolicy 租户的建议问题策略
+type SuggestionPolicy struct {
+	Enabled bool
+	// MaxLLMCallsPerHour 每小时最多调用 LLM 生成建议的次数，0 表示只使用相邻分块和热门问题
+	MaxLLMCallsPerHour int
+}
+
+// WithSuggestions 设置建议问题生成器和租户策略（可选）
+func (uc *ChatUseCase) WithSuggestions(generator *eino.SuggestionGenerator, provider SuggestionPolicyProvider) *ChatUseCase {
+	uc.suggestionGenerator = generator
+	uc.suggestionPolicies = provider
+	uc.popularQueries = newPopularQueries(maxPopularQueries)
+	uc.suggestionBudget = newLLMBudget(suggestionBudgetWindow)
+	return uc
+}
+
+// suggestFollowUps 生成本轮回答后的建议问题
+// 课程咨询来自相邻分块、热门问题和 LLM（受调用次数上限约束），订单查询按订单状态推荐
+func (uc *ChatUseCase) suggestFollowUps(ctx context.Context, intentType entity.IntentType, query, answer string, sources []*entity.Document, blocks []*ResponseBlock) []string {
+	if uc.suggestionPolicies == nil {
+		return nil
+	}
+
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	policy := uc.suggestionPolicies(tenantID)
+	if !policy.Enabled {
+		return nil
+	}
+
+	suggestions := newSuggestionList(query)
+	switch intentType {
+	case entity.IntentCourse:
+		uc.suggestCourseFollowUps(ctx, tenantID, policy, query, answer, sources, suggestions)
+	case entity.IntentOrder:
+		suggestOrderFollowUps(blocks, suggestions)
+	}
+
+	if len(suggestions.items) < minSuggestions {
+		return nil
+	}
+	return uc.moderateSuggestions(ctx, suggestions.items)
+}
+
+// moderateSuggestions 审核建议问题，流式和非流式回答都在返回前经过这里
+// 启用审核的租户丢弃命中审核规则的问题，其余问题再按租户审核方式整体审核一次，未通过时不返回建议
+func (uc *ChatUseCase) moderateSuggestions(ctx context.Context, items []string) []string {
+	policy, ok := uc.moderationPolicy(ctx)
+	if !ok {
+		return items
+	}
+
+	kept := make([]string, 0, len(items))
+	for _, item := range items {
+		if policy.Rules.Match(item) == nil {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) < minSuggestions {
+		return nil
+	}
+	if uc.checkModeration(ctx, policy, policy.Method, strings.Join(kept, "\n")) != nil {
+		return nil
+	}
+	return kept
+}
+
+// suggestCourseFollowUps 为课程咨询生成建议问题
+func (uc *ChatUseCase) suggestCourseFollowUps(ctx context.Context, tenantID string, policy SuggestionPolicy, query, answer string, sources []*entity.Document, suggestions *suggestionList) {
+	// 检索失败的降级回答不推荐追问
+	if len(sources) == 0 {
+		return
+	}
+
+	// 1. 相邻分块中的问答对问题
+	var neighbours []*entity.Document
+	if uc.ragRetriever != nil {
+		neighbours = uc.ragRetriever.NeighbourChunks(ctx, sources, maxNeighbourChunks)
+	}
+	for _, doc := range neighbours {
+		if question := metadataString(doc, "question"); question != "" {
+			suggestions.add(question)
+		}
+	}
+
+	// 2. 与本轮来源文档相关的热门问题
+	// 热门问题是其他用户的原话，只推荐多个会话都问过、不含个人信息且通过审核规则的问题
+	var reference strings.Builder
+	for _, doc := range sources {
+		reference.WriteString(doc.Content)
+		reference.WriteString("\n")
+	}
+	moderation, moderated := uc.moderationPolicy(ctx)
+	for _, popular := range uc.popularQueries.top(tenantID, maxPopularQueries, popularQueryMinSessions) {
+		if suggestions.full() {
+			break
+		}
+		if eino.LexicalOverlap(popular, reference.String()) < popularQueryMinOverlap {
+			continue
+		}
+		if moderated && moderation.Rules.Match(popular) != nil {
+			continue
+		}
+		suggestions.add(popular)
+	}
+	if len(redact.Find(query)) == 0 {
+		sessionID, _ := ctx.Value("session_id").(string)
+		uc.popularQueries.record(tenantID, sessionID, query)
+	}
+
+	// 3. 不足时由 LLM 基于相邻分块生成，受租户每小时调用次数限制
+	if suggestions.full() || uc.suggestionGenerator == nil {
+		return
+	}
+	if !uc.suggestionBudget.allow(tenantID, policy.MaxLLMCallsPerHour, time.Now()) {
+		uc.logger.Debug(ctx, "suggestion LLM budget exhausted", map[string]interface{}{
+			"tenant_id": tenantID,
+		})
+		return
+	}
+
+	contextDocs := neighbours
+	if len(contextDocs) == 0 {
+		contextDocs = sources
+	}
+	contexts := make([]string, len(contextDocs))
+	for i, doc := range contextDocs {
+		contexts[i] = doc.Content
+	}
+
+	generated, err := uc.suggestionGenerator.Generate(ctx, query, answer, contexts, maxSuggestions-len(suggestions.items))
+	if err != nil {
+		uc.logger.Warn(ctx, "failed to generate suggestions", map[string]interface{}{"error": err})
+		return
+	}
+	for _, question := range generated {
+		suggestions.add(question)
+	}
+}
+
+// suggestOrderFollowUps 按订单卡片中的订单状态推荐问题
+func suggestOrderFollowUps(blocks []*ResponseBlock, suggestions *suggestionList) {
+	var cards []*OrderCard
+	for _, block := range blocks {
+		if block.OrderCard != nil {
+			cards = append(cards, block.OrderCard)
+		}
+	}
+
+	if len(cards) == 1 {
+		for _, question := range orderStatusSuggestions[cards[0].Status] {
+			suggestions.add(question)
+		}
+		return
+	}
+
+	for _, question := range defaultOrderSuggestions {
+		suggestions.add(question)
+	}
+}
+
+// suggestionList 去重的建议问题列表，排除用户本轮的问题
+type suggestionList struct {
+	items []string
+	seen  map[string]bool
+}
+
+// newSuggestionList 创建建议问题列表
+func newSuggestionList(query string) *suggestionList {
+	return &suggestionList{seen: map[string]bool{normalizeQuestion(query): true}}
+}
+
+// add 添加建议问题，重复或已满时忽略
+func (l *suggestionList) add(question string) {
+	key := normalizeQuestion(question)
+	if key == "" || l.seen[key] || l.full() {
+		return
+	}
+	l.seen[key] = true
+	l.items = append(l.items, strings.TrimSpace(question))
+}
+
+// full 是否已达到最多数量
+func (l *suggestionList) full() bool {
+	return len(l.items) >= maxSuggestions
+}
+
+// normalizeQuestion 规范化问题用于去重：去掉首尾空白和句末标点
+func normalizeQuestion(question string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(question), "？?。.！! "))
+}
+
+// popularQueries 按租户统计的热门课程问题（进程内）
+type popularQueries struct {
+	mu     sync.Mutex
+	limit  int
+	counts map[string]map[string]*popularQuery
+}
+
+// popularQuery 热门问题及提问的会话数
+type popularQuery struct {
+	text     string
+	count    int // 不同会话数
+	sessions map[string]bool
+}
+
+// newPopularQueries 创建热门问题统计
+func newPopularQueries(limit int) *popularQueries {
+	return &popularQueries{limit: limit, counts: make(map[string]map[string]*popularQuery)}
+}
+
+// record 记录一次提问，同一会话重复提问只计一次，超出容量时淘汰会话数最少的问题
+func (p *popularQueries) record(tenantID, sessionID, query string) {
+	key := normalizeQuestion(query)
+	if key == "" || sessionID == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	queries, ok := p.counts[tenantID]
+	if !ok {
+		queries = make(map[string]*popularQuery)
+		p.counts[tenantID] = queries
+	}
+	if q, ok := queries[key]; ok {
+		if q.sessions[sessionID] {
+			return
+		}
+		if len(q.sessions) < maxPopularQuerySessions {
+			q.sessions[sessionID] = true
+		}
+		q.count++
+		return
+	}
+
+	if len(queries) >= p.limit {
+		var evict string
+		for k, q := range queries {
+			if evict == "" || q.count < queries[evict].count {
+				evict = k
+			}
+		}
+		delete(queries, evict)
+	}
+	queries[key] = &popularQuery{text: strings.TrimSpace(query), count: 1, sessions: map[string]bool{sessionID: true}}
+}
+
+// top 返回至少来自 minSessions 个会话的问题中会话数最多的 n 个
+func (p *popularQueries) top(tenantID string, n, minSessions int) []string {
+	p.mu.Lock()
+	list := make([]*popularQuery, 0, len(p.counts[tenantID]))
+	for _, q := range p.counts[tenantID] {
+		if q.count >= minSessions {
+			list = append(list, &popularQuery{text: q.text, count: q.count})
+		}
+	}
+	p.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].count != list[j].count {
+			return list[i].count > list[j].count
+		}
+		return list[i].text < list[j].text
+	})
+
+	if len(list) > n {
+		list = list[:n]
+	}
+	texts := make([]string, len(list))
+	for i, q := range list {
+		texts[i] = q.text
+	}
+	return texts
+}
+
+// llmBudget 按租户限制固定窗口内的 LLM 调用次数
+type llmBudget struct {
+	mu      sync.Mutex
+	window  time.Duration
+	windows map[string]*budgetWindow
+}
+
+// budgetWindow 租户当前窗口的调用统计
+type budgetWindow struct {
+	start time.Time
+	calls int
+}
+
+// newLLMBudget 创建 LLM 调用预算
+func newLLMBudget(window time.Duration) *llmBudget {
+	return &llmBudget{window: window, windows: make(map[string]*budgetWindow)}
+}
+
+// allow 判断租户本窗口是否还能调用 LLM，允许时计入一次调用
+func (b *llmBudget) allow(tenantID string, limit int, now time.Time) bool {
+	if limit <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.windows[tenantID]
+	if !ok || now.Sub(w.start) >= b.window {
+		w = &budgetWindow{start: now}
+		b.windows[tenantID] = w
+	}
+	if w.calls >= limit {
+		return false
+	}
+	w.calls++
+	return true
+}
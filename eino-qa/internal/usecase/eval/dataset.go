@@ -0,0 +1,85 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"eino-qa/internal/domain/entity"
+)
+
+var (
+	// ErrEmptyDataset 数据集为空
+	ErrEmptyDataset = errors.New("dataset is empty")
+	// ErrInvalidSample 数据集中的样本无效
+	ErrInvalidSample = errors.New("invalid sample")
+)
+
+// maxSampleLineSize 数据集单行的最大长度
+const maxSampleLineSize = 1 << 20
+
+// Sample 标注样本（数据集 JSONL 的一行）
+// 未标注的字段不参与对应指标的计算
+type Sample struct {
+	ID              string   `json:"id,omitempty"`
+	Query           string   `json:"query"`
+	Intent          string   `json:"intent,omitempty"`           // 期望意图：course、order、direct、handoff
+	SourceIDs       []string `json:"source_ids,omitempty"`       // 期望检索到的文档 ID
+	ReferenceAnswer string   `json:"reference_answer,omitempty"` // 参考答案
+}
+
+// LoadDataset 读取 JSONL 格式的标注数据集，空行和以 # 开头的行忽略
+// 没有 id 的样本按行号编号，便于在报告中定位
+func LoadDataset(r io.Reader) ([]*Sample, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSampleLineSize)
+
+	var samples []*Sample
+	seen := make(map[string]int)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var sample Sample
+		if err := json.Unmarshal([]byte(line), &sample); err != nil {
+			return nil, fmt.Errorf("line %d: %w: %v", lineNo, ErrInvalidSample, err)
+		}
+		if err := sample.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if sample.ID == "" {
+			sample.ID = fmt.Sprintf("line-%d", lineNo)
+		}
+		if prev, ok := seen[sample.ID]; ok {
+			return nil, fmt.Errorf("line %d: %w: duplicate id %q (first seen on line %d)", lineNo, ErrInvalidSample, sample.ID, prev)
+		}
+		seen[sample.ID] = lineNo
+		samples = append(samples, &sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	if len(samples) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	return samples, nil
+}
+
+// validate 验证样本
+func (s *Sample) validate() error {
+	s.Query = strings.TrimSpace(s.Query)
+	if s.Query == "" {
+		return fmt.Errorf("%w: query is required", ErrInvalidSample)
+	}
+	if s.Intent != "" && entity.NewIntent(entity.IntentType(s.Intent), 1).Validate() != nil {
+		return fmt.Errorf("%w: unknown intent %q", ErrInvalidSample, s.Intent)
+	}
+	return nil
+}
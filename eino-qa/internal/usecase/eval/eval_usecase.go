@@ -0,0 +1,236 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"eino-qa/internal/usecase/chat"
+	"eino-qa/pkg/utils"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultK 默认的召回率截断位置
+	defaultK = 5
+	// errorLabel 意图识别失败时在混淆矩阵中的预测标签
+	errorLabel = "error"
+)
+
+// Options 评估选项
+type Options struct {
+	TenantID string // 评估使用的租户（知识库、提示词模板和租户配置）
+	K        int    // recall@k 的 k，0 表示使用默认值 5
+	Dataset  string // 数据集名称，写入报告
+}
+
+// EvalUseCase 离线评估用例
+// 用标注数据集依次评估意图识别、向量检索和端到端回答，未配置的组件跳过对应指标
+type EvalUseCase struct {
+	intents   IntentRecognizer
+	retriever Retriever
+	chat      ChatExecutor
+	embedder  embedding.Embedder
+	logger    *logrus.Logger
+}
+
+// NewEvalUseCase 创建离线评估用例
+func NewEvalUseCase(intents IntentRecognizer, retriever Retriever, chatExecutor ChatExecutor, logger *logrus.Logger) *EvalUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &EvalUseCase{
+		intents:   intents,
+		retriever: retriever,
+		chat:      chatExecutor,
+		logger:    logger,
+	}
+}
+
+// WithEmbedder 设置嵌入模型（可选），用于计算回答与参考答案的语义相似度
+func (uc *EvalUseCase) WithEmbedder(embedder embedding.Embedder) *EvalUseCase {
+	uc.embedder = embedder
+	return uc
+}
+
+// Run 评估数据集，返回汇总报告
+// 单个样本的组件调用失败记录在样本结果中并按最差结果计分，不中断评估
+func (uc *EvalUseCase) Run(ctx context.Context, samples []*Sample, opts Options) (*Report, error) {
+	if len(samples) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	if opts.TenantID == "" {
+		opts.TenantID = "default"
+	}
+	if opts.K <= 0 {
+		opts.K = defaultK
+	}
+	ctx = context.WithValue(ctx, "tenant_id", opts.TenantID)
+
+	report := &Report{
+		Dataset:  opts.Dataset,
+		TenantID: opts.TenantID,
+		K:        opts.K,
+		Samples:  len(samples),
+		Intent:   IntentMetrics{Confusion: make(map[string]map[string]int)},
+		Results:  make([]*SampleResult, 0, len(samples)),
+	}
+
+	for i, sample := range samples {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result := &SampleResult{ID: sample.ID, Query: sample.Query}
+		uc.evaluateIntent(ctx, sample, result, report)
+		uc.evaluateRetrieval(ctx, sample, opts.K, result, report)
+		uc.evaluateAnswer(ctx, sample, opts.TenantID, result, report)
+		report.Results = append(report.Results, result)
+
+		if (i+1)%10 == 0 || i+1 == len(samples) {
+			uc.logger.WithFields(logrus.Fields{
+				"done":  i + 1,
+				"total": len(samples),
+			}).Info("evaluation progress")
+		}
+	}
+
+	report.summarize()
+	return report, nil
+}
+
+// evaluateIntent 评估意图识别（单轮，无历史消息）
+func (uc *EvalUseCase) evaluateIntent(ctx context.Context, sample *Sample, result *SampleResult, report *Report) {
+	if uc.intents == nil || sample.Intent == "" {
+		return
+	}
+
+	result.ExpectedIntent = sample.Intent
+	predicted := errorLabel
+	intent, err := uc.intents.Recognize(ctx, sample.Query, nil)
+	if err != nil {
+		result.addError("intent", err)
+		report.Intent.Errors++
+	} else {
+		predicted = string(intent.Type)
+	}
+	result.PredictedIntent = predicted
+
+	row := report.Intent.Confusion[sample.Intent]
+	if row == nil {
+		row = make(map[string]int)
+		report.Intent.Confusion[sample.Intent] = row
+	}
+	row[predicted]++
+
+	report.Intent.Evaluated++
+	if predicted == sample.Intent {
+		report.Intent.Correct++
+	}
+}
+
+// evaluateRetrieval 评估向量检索的 recall@k 和倒数排名
+func (uc *EvalUseCase) evaluateRetrieval(ctx context.Context, sample *Sample, k int, result *SampleResult, report *Report) {
+	if uc.retriever == nil || len(sample.SourceIDs) == 0 {
+		return
+	}
+
+	var retrieved []string
+	docs, err := uc.retriever.Search(ctx, sample.Query, k)
+	if err != nil {
+		result.addError("retrieval", err)
+		report.Retrieval.Errors++
+	}
+	for _, doc := range docs {
+		retrieved = append(retrieved, doc.ID)
+	}
+
+	recall := recallAtK(retrieved, sample.SourceIDs, k)
+	rr := reciprocalRank(retrieved, sample.SourceIDs)
+	result.RetrievedIDs = retrieved
+	result.Recall = &recall
+	result.ReciprocalRank = &rr
+
+	report.Retrieval.Evaluated++
+	report.Retrieval.RecallAtK += recall
+	report.Retrieval.MRR += rr
+}
+
+// evaluateAnswer 通过完整对话流程生成回答，并与参考答案比较
+func (uc *EvalUseCase) evaluateAnswer(ctx context.Context, sample *Sample, tenantID string, result *SampleResult, report *Report) {
+	if uc.chat == nil || sample.ReferenceAnswer == "" {
+		return
+	}
+
+	report.Answer.Evaluated++
+	zero := 0.0
+
+	// 每个样本使用新会话，避免样本之间互相影响
+	resp, err := uc.chat.Execute(ctx, &chat.ChatRequest{Query: sample.Query, TenantID: tenantID})
+	if err != nil {
+		result.addError("answer", err)
+		report.Answer.Errors++
+		result.LexicalSimilarity = &zero
+		return
+	}
+
+	lexical := lexicalSimilarity(resp.Answer, sample.ReferenceAnswer)
+	result.Answer = resp.Answer
+	result.Route = resp.Route
+	result.LexicalSimilarity = &lexical
+	report.Answer.LexicalSimilarity += lexical
+
+	if uc.embedder == nil {
+		return
+	}
+	vectors, err := uc.embedder.EmbedStrings(ctx, []string{resp.Answer, sample.ReferenceAnswer})
+	if err != nil || len(vectors) != 2 {
+		if err == nil {
+			err = fmt.Errorf("expected 2 embeddings, got %d", len(vectors))
+		}
+		result.addError("embedding", err)
+		result.SemanticSimilarity = &zero
+	} else {
+		semantic := utils.CosineSimilarity(vectors[0], vectors[1])
+		result.SemanticSimilarity = &semantic
+		report.Answer.SemanticSimilarity += semantic
+	}
+	report.Answer.SemanticEvaluated++
+}
+
+// summarize 将累加值换算为平均值，并整理混淆矩阵的标签
+func (r *Report) summarize() {
+	if r.Intent.Evaluated > 0 {
+		r.Intent.Accuracy = round4(float64(r.Intent.Correct) / float64(r.Intent.Evaluated))
+	}
+	if r.Retrieval.Evaluated > 0 {
+		r.Retrieval.RecallAtK = round4(r.Retrieval.RecallAtK / float64(r.Retrieval.Evaluated))
+		r.Retrieval.MRR = round4(r.Retrieval.MRR / float64(r.Retrieval.Evaluated))
+	}
+	if r.Answer.Evaluated > 0 {
+		r.Answer.LexicalSimilarity = round4(r.Answer.LexicalSimilarity / float64(r.Answer.Evaluated))
+	}
+	if r.Answer.SemanticEvaluated > 0 {
+		r.Answer.SemanticSimilarity = round4(r.Answer.SemanticSimilarity / float64(r.Answer.SemanticEvaluated))
+	}
+
+	labels := make(map[string]bool)
+	for expected, row := range r.Intent.Confusion {
+		labels[expected] = true
+		for predicted := range row {
+			labels[predicted] = true
+		}
+	}
+	r.Intent.Labels = make([]string, 0, len(labels))
+	for label := range labels {
+		r.Intent.Labels = append(r.Intent.Labels, label)
+	}
+	sort.Strings(r.Intent.Labels)
+
+	for _, result := range r.Results {
+		result.round()
+	}
+}
@@ -0,0 +1,220 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/chat"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIntentRecognizer 按问题返回固定意图
+type fakeIntentRecognizer map[string]entity.IntentType
+
+func (f fakeIntentRecognizer) Recognize(ctx context.Context, query string, history []*entity.Message) (*entity.Intent, error) {
+	intentType, ok := f[query]
+	if !ok {
+		return nil, errors.New("llm unavailable")
+	}
+	return entity.NewIntent(intentType, 0.9), nil
+}
+
+// fakeRetriever 按问题返回固定的检索结果，并记录调用时的租户
+type fakeRetriever struct {
+	docs   map[string][]string
+	tenant string
+}
+
+func (f *fakeRetriever) Search(ctx context.Context, query string, topK int) ([]*entity.Document, error) {
+	f.tenant, _ = ctx.Value("tenant_id").(string)
+	var docs []*entity.Document
+	for _, id := range f.docs[query] {
+		if len(docs) < topK {
+			docs = append(docs, &entity.Document{ID: id})
+		}
+	}
+	return docs, nil
+}
+
+// fakeChatExecutor 按问题返回固定回答
+type fakeChatExecutor map[string]string
+
+func (f fakeChatExecutor) Execute(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error) {
+	answer, ok := f[req.Query]
+	if !ok {
+		return nil, errors.New("chat failed")
+	}
+	return &chat.ChatResponse{Answer: answer, Route: string(entity.IntentCourse)}, nil
+}
+
+// fakeEmbedder 相同文本返回相同向量，不同文本返回正交向量
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		if text == texts[0] {
+			vectors[i] = []float64{1, 0}
+		} else {
+			vectors[i] = []float64{0, 1}
+		}
+	}
+	return vectors, nil
+}
+
+const testDataset = `
+# 课程咨询
+{"id": "q1", "query": "Go 课程多少钱", "intent": "course", "source_ids": ["doc_go"], "reference_answer": "Go 语言进阶课程售价 299 元。"}
+{"id": "q2", "query": "Python 课程讲什么", "intent": "course", "source_ids": ["doc_py", "doc_py2"], "reference_answer": "Python 课程讲解数据分析。"}
+{"query": "我的订单到哪了", "intent": "order"}
+{"id": "q4", "query": "你好", "intent": "direct"}
+`
+
+func TestLoadDataset(t *testing.T) {
+	samples, err := LoadDataset(strings.NewReader(testDataset))
+	require.NoError(t, err)
+	require.Len(t, samples, 4)
+	assert.Equal(t, "line-5", samples[2].ID)
+	assert.Equal(t, []string{"doc_py", "doc_py2"}, samples[1].SourceIDs)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{"empty", "\n# only comments\n", ErrEmptyDataset},
+		{"missing query", `{"intent": "course"}`, ErrInvalidSample},
+		{"unknown intent", `{"query": "你好", "intent": "greeting"}`, ErrInvalidSample},
+		{"duplicate id", "{\"id\": \"a\", \"query\": \"1\"}\n{\"id\": \"a\", \"query\": \"2\"}", ErrInvalidSample},
+		{"malformed", `{"query": `, ErrInvalidSample},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadDataset(strings.NewReader(tt.data))
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	retrieved := []string{"a", "b", "c", "b"}
+
+	assert.InDelta(t, 0.5, recallAtK(retrieved, []string{"b", "x"}, 2), 1e-9)
+	assert.InDelta(t, 0.0, recallAtK(retrieved, []string{"c"}, 2), 1e-9)
+	assert.InDelta(t, 1.0, recallAtK(retrieved, []string{"b"}, 10), 1e-9)
+
+	assert.InDelta(t, 1.0/3, reciprocalRank(retrieved, []string{"c", "x"}), 1e-9)
+	assert.InDelta(t, 0.0, reciprocalRank(retrieved, []string{"x"}), 1e-9)
+
+	assert.InDelta(t, 1.0, lexicalSimilarity("课程售价 299 元", "课程售价 299 元"), 1e-9)
+	assert.InDelta(t, 0.0, lexicalSimilarity("你好", "订单"), 1e-9)
+}
+
+func TestEvalUseCase_Run(t *testing.T) {
+	samples, err := LoadDataset(strings.NewReader(testDataset))
+	require.NoError(t, err)
+
+	retriever := &fakeRetriever{docs: map[string][]string{
+		"Go 课程多少钱":     {"doc_x", "doc_go"},
+		"Python 课程讲什么": {"doc_py", "doc_y", "doc_z", "doc_py2"},
+	}}
+	uc := NewEvalUseCase(
+		fakeIntentRecognizer{
+			"Go 课程多少钱":     entity.IntentCourse,
+			"Python 课程讲什么": entity.IntentDirect,
+			"我的订单到哪了":      entity.IntentOrder,
+		},
+		retriever,
+		fakeChatExecutor{"Go 课程多少钱": "Go 语言进阶课程售价 299 元。"},
+		nil,
+	).WithEmbedder(fakeEmbedder{})
+
+	report, err := uc.Run(context.Background(), samples, Options{TenantID: "tenant1", K: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "tenant1", retriever.tenant)
+
+	// 意图：q1、q3 正确，q2 识别为 direct，q4 调用失败
+	assert.Equal(t, 4, report.Intent.Evaluated)
+	assert.Equal(t, 2, report.Intent.Correct)
+	assert.Equal(t, 1, report.Intent.Errors)
+	assert.Equal(t, 0.5, report.Intent.Accuracy)
+	assert.Equal(t, []string{"course", "direct", "error", "order"}, report.Intent.Labels)
+	assert.Equal(t, 1, report.Intent.Confusion["course"]["direct"])
+	assert.Equal(t, 1, report.Intent.Confusion["direct"]["error"])
+
+	// 检索：q1 recall 1、RR 0.5；q2 前 3 个只命中 doc_py，recall 0.5、RR 1
+	assert.Equal(t, 2, report.Retrieval.Evaluated)
+	assert.Equal(t, 0.75, report.Retrieval.RecallAtK)
+	assert.Equal(t, 0.75, report.Retrieval.MRR)
+
+	// 回答：q1 与参考答案一致，q2 对话失败按 0 计
+	assert.Equal(t, 2, report.Answer.Evaluated)
+	assert.Equal(t, 1, report.Answer.Errors)
+	assert.Equal(t, 0.5, report.Answer.LexicalSimilarity)
+	assert.Equal(t, 1, report.Answer.SemanticEvaluated)
+	assert.Equal(t, 1.0, report.Answer.SemanticSimilarity)
+
+	// 未配置的组件不参与评估
+	partial, err := NewEvalUseCase(nil, retriever, nil, nil).Run(context.Background(), samples, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "default", partial.TenantID)
+	assert.Equal(t, 5, partial.K)
+	assert.Zero(t, partial.Intent.Evaluated)
+	assert.Zero(t, partial.Answer.Evaluated)
+	assert.Equal(t, 1.0, partial.Retrieval.RecallAtK)
+}
+
+func TestReport_CompareAndWrite(t *testing.T) {
+	baseline := &Report{
+		TenantID:  "tenant1",
+		K:         5,
+		Samples:   2,
+		Intent:    IntentMetrics{Evaluated: 2, Accuracy: 0.9},
+		Retrieval: RetrievalMetrics{Evaluated: 2, RecallAtK: 0.8, MRR: 0.7},
+		Answer:    AnswerMetrics{Evaluated: 2, LexicalSimilarity: 0.6},
+	}
+	current := &Report{
+		TenantID:  "tenant1",
+		K:         5,
+		Samples:   2,
+		Intent:    IntentMetrics{Evaluated: 2, Correct: 1, Accuracy: 0.5, Labels: []string{"course", "direct"}, Confusion: map[string]map[string]int{"course": {"course": 1, "direct": 1}}},
+		Retrieval: RetrievalMetrics{Evaluated: 2, RecallAtK: 0.795, MRR: 0.75},
+		Answer:    AnswerMetrics{Evaluated: 2, LexicalSimilarity: 0.6, SemanticEvaluated: 2, SemanticSimilarity: 0.8},
+		Results: []*SampleResult{
+			{ID: "q1", Query: "Go 课程|价格", ExpectedIntent: "course", PredictedIntent: "direct"},
+		},
+	}
+
+	// 容差内的下降和基线没有的指标不算回退
+	regressions := Compare(baseline, current, 0.01)
+	require.Len(t, regressions, 1)
+	assert.Equal(t, "intent.accuracy", regressions[0].Metric)
+	assert.Equal(t, "intent.accuracy: 0.9000 -> 0.5000 (-0.4000)", regressions[0].String())
+
+	// k 不同时不比较 recall@k
+	current.K = 3
+	current.Retrieval.RecallAtK = 0.1
+	assert.Len(t, Compare(baseline, current, 0.01), 1)
+
+	// JSON 报告可以读回作为基线
+	var buf bytes.Buffer
+	require.NoError(t, current.WriteJSON(&buf))
+	loaded, err := LoadReport(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, current.Intent, loaded.Intent)
+
+	buf.Reset()
+	require.NoError(t, current.WriteMarkdown(&buf, baseline))
+	md := buf.String()
+	assert.Contains(t, md, "| intent.accuracy | 0.5000 | 0.9000 | -0.4000 |")
+	assert.Contains(t, md, "| answer.semantic_similarity | 0.8000 | - | - |")
+	assert.Contains(t, md, "| course | 1 | 1 |")
+	assert.Contains(t, md, `| q1 | Go 课程\|价格 | 意图 course → direct |`)
+}
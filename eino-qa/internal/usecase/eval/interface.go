@@ -0,0 +1,23 @@
+package eval
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/usecase/chat"
+)
+
+// IntentRecognizer 意图识别接口（由 eino.IntentRecognizer 实现）
+type IntentRecognizer interface {
+	Recognize(ctx context.Context, query string, history []*entity.Message) (*entity.Intent, error)
+}
+
+// Retriever 向量检索接口（由 eino.RAGRetriever 实现）
+type Retriever interface {
+	Search(ctx context.Context, query string, topK int) ([]*entity.Document, error)
+}
+
+// ChatExecutor 端到端对话接口（由 chat.ChatUseCase 实现）
+type ChatExecutor interface {
+	Execute(ctx context.Context, req *chat.ChatRequest) (*chat.ChatResponse, error)
+}
@@ -0,0 +1,62 @@
+package eval
+
+import (
+	"math"
+
+	"eino-qa/internal/infrastructure/ai/eino"
+)
+
+// recallAtK 前 k 个检索结果中命中的期望文档比例
+func recallAtK(retrieved, expected []string, k int) float64 {
+	if len(expected) == 0 {
+		return 0
+	}
+	if k > len(retrieved) {
+		k = len(retrieved)
+	}
+
+	want := make(map[string]bool, len(expected))
+	for _, id := range expected {
+		want[id] = true
+	}
+
+	hits := 0
+	for _, id := range retrieved[:k] {
+		if want[id] {
+			hits++
+			delete(want, id) // 同一文档重复出现只计一次
+		}
+	}
+	return float64(hits) / float64(len(expected))
+}
+
+// reciprocalRank 第一个命中的期望文档排名的倒数，未命中时为 0
+func reciprocalRank(retrieved, expected []string) float64 {
+	want := make(map[string]bool, len(expected))
+	for _, id := range expected {
+		want[id] = true
+	}
+
+	for i, id := range retrieved {
+		if want[id] {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// lexicalSimilarity 回答与参考答案的字符二元组 F1，0~1
+// 精确率为回答在参考答案中的重合比例，召回率为参考答案在回答中的重合比例
+func lexicalSimilarity(answer, reference string) float64 {
+	precision := eino.LexicalOverlap(answer, reference)
+	recall := eino.LexicalOverlap(reference, answer)
+	if precision+recall == 0 {
+		return 0
+	}
+	return 2 * precision * recall / (precision + recall)
+}
+
+// round4 保留 4 位小数，保证报告在重复运行时可以稳定比对
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,230 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Report 离线评估报告
+// 报告不含时间戳等易变字段，浮点数保留 4 位小数，便于提交到仓库后逐行比对
+type Report struct {
+	Dataset   string           `json:"dataset,omitempty"`
+	TenantID  string           `json:"tenant_id"`
+	K         int              `json:"k"`
+	Samples   int              `json:"samples"`
+	Intent    IntentMetrics    `json:"intent"`
+	Retrieval RetrievalMetrics `json:"retrieval"`
+	Answer    AnswerMetrics    `json:"answer"`
+	Results   []*SampleResult  `json:"results"`
+}
+
+// IntentMetrics 意图识别指标
+type IntentMetrics struct {
+	Evaluated int      `json:"evaluated"`
+	Correct   int      `json:"correct"`
+	Errors    int      `json:"errors"`
+	Accuracy  float64  `json:"accuracy"`
+	Labels    []string `json:"labels"`
+	// Confusion 混淆矩阵：期望意图 -> 识别结果 -> 样本数
+	Confusion map[string]map[string]int `json:"confusion"`
+}
+
+// RetrievalMetrics 向量检索指标
+type RetrievalMetrics struct {
+	Evaluated int     `json:"evaluated"`
+	Errors    int     `json:"errors"`
+	RecallAtK float64 `json:"recall_at_k"`
+	MRR       float64 `json:"mrr"`
+}
+
+// AnswerMetrics 回答质量指标
+type AnswerMetrics struct {
+	Evaluated          int     `json:"evaluated"`
+	Errors             int     `json:"errors"`
+	LexicalSimilarity  float64 `json:"lexical_similarity"`
+	SemanticEvaluated  int     `json:"semantic_evaluated,omitempty"`
+	SemanticSimilarity float64 `json:"semantic_similarity,omitempty"`
+}
+
+// SampleResult 单个样本的评估结果，未评估的指标为空
+type SampleResult struct {
+	ID                 string   `json:"id"`
+	Query              string   `json:"query"`
+	ExpectedIntent     string   `json:"expected_intent,omitempty"`
+	PredictedIntent    string   `json:"predicted_intent,omitempty"`
+	RetrievedIDs       []string `json:"retrieved_ids,omitempty"`
+	Recall             *float64 `json:"recall,omitempty"`
+	ReciprocalRank     *float64 `json:"reciprocal_rank,omitempty"`
+	Route              string   `json:"route,omitempty"`
+	Answer             string   `json:"answer,omitempty"`
+	LexicalSimilarity  *float64 `json:"lexical_similarity,omitempty"`
+	SemanticSimilarity *float64 `json:"semantic_similarity,omitempty"`
+	Errors             []string `json:"errors,omitempty"`
+}
+
+// addError 记录组件调用失败
+func (r *SampleResult) addError(stage string, err error) {
+	r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", stage, err))
+}
+
+// round 样本指标保留 4 位小数
+func (r *SampleResult) round() {
+	for _, v := range []*float64{r.Recall, r.ReciprocalRank, r.LexicalSimilarity, r.SemanticSimilarity} {
+		if v != nil {
+			*v = round4(*v)
+		}
+	}
+}
+
+// WriteJSON 输出 JSON 格式的报告
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(r)
+}
+
+// LoadReport 读取 JSON 格式的报告（作为比较基线）
+func LoadReport(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+	return &report, nil
+}
+
+// Regression 相对基线下降的指标
+type Regression struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+}
+
+// String 返回可读的回退描述
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: %.4f -> %.4f (%+.4f)", r.Metric, r.Baseline, r.Current, r.Current-r.Baseline)
+}
+
+// metric 报告中参与基线比较的指标
+type metric struct {
+	name      string
+	value     float64
+	evaluated bool
+}
+
+// metrics 返回参与基线比较的汇总指标
+func (r *Report) metrics() []metric {
+	return []metric{
+		{"intent.accuracy", r.Intent.Accuracy, r.Intent.Evaluated > 0},
+		{fmt.Sprintf("retrieval.recall_at_%d", r.K), r.Retrieval.RecallAtK, r.Retrieval.Evaluated > 0},
+		{"retrieval.mrr", r.Retrieval.MRR, r.Retrieval.Evaluated > 0},
+		{"answer.lexical_similarity", r.Answer.LexicalSimilarity, r.Answer.Evaluated > 0},
+		{"answer.semantic_similarity", r.Answer.SemanticSimilarity, r.Answer.SemanticEvaluated > 0},
+	}
+}
+
+// Compare 与基线报告比较，返回下降超过 tolerance 的指标
+// 只比较两份报告都评估过的指标；k 不同时 recall@k 视为不同指标，不做比较
+func Compare(baseline, current *Report, tolerance float64) []Regression {
+	base := make(map[string]metric)
+	for _, m := range baseline.metrics() {
+		base[m.name] = m
+	}
+
+	var regressions []Regression
+	for _, m := range current.metrics() {
+		b, ok := base[m.name]
+		if !ok || !b.evaluated || !m.evaluated {
+			continue
+		}
+		if m.value < b.value-tolerance {
+			regressions = append(regressions, Regression{Metric: m.name, Baseline: b.value, Current: m.value})
+		}
+	}
+	return regressions
+}
+
+// WriteMarkdown 输出 Markdown 格式的报告，baseline 不为空时附带与基线的对比
+func (r *Report) WriteMarkdown(w io.Writer, baseline *Report) error {
+	var b strings.Builder
+
+	b.WriteString("# 离线评估报告\n\n")
+	if r.Dataset != "" {
+		fmt.Fprintf(&b, "- 数据集：`%s`\n", r.Dataset)
+	}
+	fmt.Fprintf(&b, "- 租户：`%s`\n- 样本数：%d\n\n", r.TenantID, r.Samples)
+
+	// 汇总指标
+	base := make(map[string]metric)
+	if baseline != nil {
+		for _, m := range baseline.metrics() {
+			base[m.name] = m
+		}
+		b.WriteString("| 指标 | 当前 | 基线 | 变化 |\n|------|------|------|------|\n")
+	} else {
+		b.WriteString("| 指标 | 当前 |\n|------|------|\n")
+	}
+	for _, m := range r.metrics() {
+		if !m.evaluated {
+			continue
+		}
+		if baseline == nil {
+			fmt.Fprintf(&b, "| %s | %.4f |\n", m.name, m.value)
+			continue
+		}
+		if bm, ok := base[m.name]; ok && bm.evaluated {
+			fmt.Fprintf(&b, "| %s | %.4f | %.4f | %+.4f |\n", m.name, m.value, bm.value, m.value-bm.value)
+		} else {
+			fmt.Fprintf(&b, "| %s | %.4f | - | - |\n", m.name, m.value)
+		}
+	}
+
+	// 混淆矩阵
+	if r.Intent.Evaluated > 0 {
+		b.WriteString("\n## 意图混淆矩阵\n\n行为期望意图，列为识别结果。\n\n")
+		b.WriteString("| 期望 \\ 识别 | " + strings.Join(r.Intent.Labels, " | ") + " |\n")
+		b.WriteString("|---" + strings.Repeat("|---", len(r.Intent.Labels)) + "|\n")
+		for _, expected := range r.Intent.Labels {
+			row, ok := r.Intent.Confusion[expected]
+			if !ok {
+				continue
+			}
+			cells := make([]string, len(r.Intent.Labels))
+			for i, predicted := range r.Intent.Labels {
+				cells[i] = fmt.Sprint(row[predicted])
+			}
+			fmt.Fprintf(&b, "| %s | %s |\n", expected, strings.Join(cells, " | "))
+		}
+	}
+
+	// 未通过的样本
+	var failures []string
+	for _, result := range r.Results {
+		var reasons []string
+		if result.ExpectedIntent != "" && result.PredictedIntent != result.ExpectedIntent {
+			reasons = append(reasons, fmt.Sprintf("意图 %s → %s", result.ExpectedIntent, result.PredictedIntent))
+		}
+		if result.Recall != nil && *result.Recall == 0 {
+			reasons = append(reasons, fmt.Sprintf("前 %d 个检索结果未命中", r.K))
+		}
+		reasons = append(reasons, result.Errors...)
+		if len(reasons) > 0 {
+			failures = append(failures, fmt.Sprintf("| %s | %s | %s |", result.ID, escapeMarkdownCell(result.Query), escapeMarkdownCell(strings.Join(reasons, "；"))))
+		}
+	}
+	if len(failures) > 0 {
+		b.WriteString("\n## 未通过的样本\n\n| ID | 问题 | 原因 |\n|----|------|------|\n")
+		b.WriteString(strings.Join(failures, "\n") + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeMarkdownCell 转义表格单元格中的竖线和换行
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,94 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExperimentUseCase A/B 实验用例
+// 汇总租户各实验分组的延迟、转人工率、知识库未命中率和用户反馈
+type ExperimentUseCase struct {
+	experiments ExperimentProvider
+	repos       RepositoryProvider
+	logger      *logrus.Logger
+}
+
+// NewExperimentUseCase 创建 A/B 实验用例
+func NewExperimentUseCase(experiments ExperimentProvider, repos RepositoryProvider, logger *logrus.Logger) *ExperimentUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &ExperimentUseCase{
+		experiments: experiments,
+		repos:       repos,
+		logger:      logger,
+	}
+}
+
+// ExperimentReport 实验结果报告
+type ExperimentReport struct {
+	Name      string
+	Component entity.ExperimentComponent
+	Variants  []*VariantReport
+}
+
+// VariantReport 实验分组的配置和汇总结果
+type VariantReport struct {
+	*entity.VariantResult
+	Weight        int
+	PromptVersion int
+	Model         string
+}
+
+// Results 返回租户当前配置的实验的结果
+// 没有流量的分组也会返回（各项指标为 0），已从配置中移除的分组不再返回
+func (uc *ExperimentUseCase) Results(ctx context.Context, tenantID string, since time.Time) ([]*ExperimentReport, error) {
+	tenantID = entity.NormalizeTenantID(tenantID)
+	repo := uc.repos(tenantID)
+
+	experiments := uc.experiments(tenantID)
+	reports := make([]*ExperimentReport, 0, len(experiments))
+	for _, experiment := range experiments {
+		results, err := repo.Results(ctx, experiment.Name, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load results of experiment %s: %w", experiment.Name, err)
+		}
+
+		byVariant := make(map[string]*entity.VariantResult, len(results))
+		for _, result := range results {
+			byVariant[result.Variant] = result
+		}
+
+		report := &ExperimentReport{
+			Name:      experiment.Name,
+			Component: experiment.Component,
+			Variants:  make([]*VariantReport, 0, len(experiment.Variants)),
+		}
+		for _, variant := range experiment.Variants {
+			result, ok := byVariant[variant.Name]
+			if !ok {
+				result = &entity.VariantResult{Variant: variant.Name}
+			}
+			report.Variants = append(report.Variants, &VariantReport{
+				VariantResult: result,
+				Weight:        variant.Weight,
+				PromptVersion: variant.PromptVersion,
+				Model:         variant.Model,
+			})
+		}
+		reports = append(reports, report)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":   tenantID,
+		"experiments": len(reports),
+	}).Debug("experiment results aggregated")
+
+	return reports, nil
+}
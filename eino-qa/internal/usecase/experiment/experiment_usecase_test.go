@@ -0,0 +1,67 @@
+package experiment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExperimentRepository 返回固定汇总结果的实验仓储
+type fakeExperimentRepository struct {
+	results map[string][]*entity.VariantResult
+}
+
+func (r *fakeExperimentRepository) SaveOutcome(ctx context.Context, outcome *entity.ExperimentOutcome) error {
+	return nil
+}
+
+func (r *fakeExperimentRepository) Results(ctx context.Context, experiment string, since time.Time) ([]*entity.VariantResult, error) {
+	return r.results[experiment], nil
+}
+
+func TestExperimentUseCase_Results(t *testing.T) {
+	repo := &fakeExperimentRepository{results: map[string][]*entity.VariantResult{
+		"rag_prompt": {
+			{Variant: "control", Requests: 10, Handoffs: 2},
+			{Variant: "removed", Requests: 3},
+		},
+	}}
+
+	var gotTenant string
+	uc := NewExperimentUseCase(
+		func(tenantID string) []*entity.Experiment {
+			gotTenant = tenantID
+			return []*entity.Experiment{{
+				Name:      "rag_prompt",
+				Component: entity.ExperimentRAG,
+				Variants: []*entity.ExperimentVariant{
+					{Name: "control", Weight: 50},
+					{Name: "treatment", Weight: 50, PromptVersion: 3, Model: "doubao-pro"},
+				},
+			}}
+		},
+		func(string) repository.ExperimentRepository { return repo },
+		nil,
+	)
+
+	reports, err := uc.Results(context.Background(), "", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "default", gotTenant)
+	require.Len(t, reports, 1)
+
+	// 按配置顺序返回分组，没有流量的分组指标为 0，已移除的分组不返回
+	variants := reports[0].Variants
+	require.Len(t, variants, 2)
+	assert.Equal(t, "control", variants[0].Variant)
+	assert.InDelta(t, 0.2, variants[0].HandoffRate(), 0.001)
+	assert.Equal(t, "treatment", variants[1].Variant)
+	assert.Equal(t, int64(0), variants[1].Requests)
+	assert.Equal(t, 3, variants[1].PromptVersion)
+	assert.Equal(t, "doubao-pro", variants[1].Model)
+}
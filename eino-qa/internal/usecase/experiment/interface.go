@@ -0,0 +1,20 @@
+package experiment
+
+import (
+	"context"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// ExperimentUseCaseInterface A/B 实验用例接口
+type ExperimentUseCaseInterface interface {
+	Results(ctx context.Context, tenantID string, since time.Time) ([]*ExperimentReport, error)
+}
+
+// ExperimentProvider 按租户获取 A/B 实验配置
+type ExperimentProvider func(tenantID string) []*entity.Experiment
+
+// RepositoryProvider 按租户获取实验结果仓储
+type RepositoryProvider func(tenantID string) repository.ExperimentRepository
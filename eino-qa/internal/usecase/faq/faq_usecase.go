@@ -0,0 +1,208 @@
+package faq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/sirupsen/logrus"
+)
+
+// SaveRequest 创建或更新标准问答请求（更新时整体替换）
+type SaveRequest struct {
+	TenantID      string
+	Questions     []string
+	Answer        string
+	EffectiveFrom *time.Time
+	EffectiveTo   *time.Time
+	Enabled       bool
+}
+
+// FAQUseCase 标准问答管理用例
+// 保存时为每个问题生成嵌入向量，对话时先按规范化问题精确匹配，再按向量相似度匹配
+type FAQUseCase struct {
+	repos     RepositoryProvider
+	embedder  embedding.Embedder
+	logger    *logrus.Logger
+	listeners []ChangeListener
+}
+
+// NewFAQUseCase 创建标准问答管理用例，embedder 为 nil 时只支持精确匹配
+func NewFAQUseCase(repos RepositoryProvider, embedder embedding.Embedder, logger *logrus.Logger) *FAQUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &FAQUseCase{
+		repos:    repos,
+		embedder: embedder,
+		logger:   logger,
+	}
+}
+
+// WithChangeListener 添加标准问答变更监听（可选），创建、更新或删除成功后按租户通知
+func (uc *FAQUseCase) WithChangeListener(listener ChangeListener) *FAQUseCase {
+	uc.listeners = append(uc.listeners, listener)
+	return uc
+}
+
+// Create 创建标准问答
+func (uc *FAQUseCase) Create(ctx context.Context, req *SaveRequest) (*entity.FAQ, error) {
+	tenantID := entity.NormalizeTenantID(req.TenantID)
+
+	faq := entity.NewFAQ(tenantID, dedupeQuestions(req.Questions), strings.TrimSpace(req.Answer))
+	faq.EffectiveFrom = req.EffectiveFrom
+	faq.EffectiveTo = req.EffectiveTo
+	faq.Enabled = req.Enabled
+	if err := uc.prepare(ctx, faq); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repos(tenantID).Create(ctx, faq); err != nil {
+		uc.logger.WithError(err).Error("failed to create faq")
+		return nil, fmt.Errorf("failed to create faq: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"faq_id":    faq.ID,
+		"questions": len(faq.Questions),
+	}).Info("faq created")
+	uc.notify(tenantID)
+
+	return faq, nil
+}
+
+// Get 获取标准问答
+func (uc *FAQUseCase) Get(ctx context.Context, tenantID, id string) (*entity.FAQ, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id cannot be empty")
+	}
+	return uc.repos(entity.NormalizeTenantID(tenantID)).FindByID(ctx, id)
+}
+
+// List 列出租户的标准问答
+func (uc *FAQUseCase) List(ctx context.Context, tenantID string) ([]*entity.FAQ, error) {
+	faqs, err := uc.repos(entity.NormalizeTenantID(tenantID)).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list faqs: %w", err)
+	}
+	return faqs, nil
+}
+
+// Update 更新标准问答，问题和答案、生效时间、启用状态整体替换
+func (uc *FAQUseCase) Update(ctx context.Context, id string, req *SaveRequest) (*entity.FAQ, error) {
+	tenantID := entity.NormalizeTenantID(req.TenantID)
+	repo := uc.repos(tenantID)
+
+	faq, err := repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	faq.Questions = dedupeQuestions(req.Questions)
+	faq.Answer = strings.TrimSpace(req.Answer)
+	faq.EffectiveFrom = req.EffectiveFrom
+	faq.EffectiveTo = req.EffectiveTo
+	faq.Enabled = req.Enabled
+	faq.UpdatedAt = time.Now()
+	if err := uc.prepare(ctx, faq); err != nil {
+		return nil, err
+	}
+
+	if err := repo.Update(ctx, faq); err != nil {
+		uc.logger.WithError(err).Error("failed to update faq")
+		return nil, fmt.Errorf("failed to update faq: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"faq_id":    faq.ID,
+		"questions": len(faq.Questions),
+		"enabled":   faq.Enabled,
+	}).Info("faq updated")
+	uc.notify(tenantID)
+
+	return faq, nil
+}
+
+// Delete 删除标准问答
+func (uc *FAQUseCase) Delete(ctx context.Context, tenantID, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	tenantID = entity.NormalizeTenantID(tenantID)
+	if err := uc.repos(tenantID).Delete(ctx, id); err != nil {
+		return err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"faq_id":    id,
+	}).Info("faq deleted")
+	uc.notify(tenantID)
+
+	return nil
+}
+
+// notify 通知租户的标准问答已变更
+func (uc *FAQUseCase) notify(tenantID string) {
+	for _, listener := range uc.listeners {
+		listener.InvalidateFAQs(tenantID)
+	}
+}
+
+// prepare 校验标准问答并为问题生成嵌入向量
+// 问题按语义缓存的规范化方式嵌入，与对话中查询向量的生成方式一致
+func (uc *FAQUseCase) prepare(ctx context.Context, faq *entity.FAQ) error {
+	if err := faq.Validate(); err != nil {
+		return err
+	}
+
+	faq.Vectors = nil
+	if uc.embedder == nil {
+		return nil
+	}
+
+	texts := make([]string, len(faq.Questions))
+	for i, q := range faq.Questions {
+		texts[i] = entity.NormalizeCacheQuery(q)
+	}
+	vectors, err := uc.embedder.EmbedStrings(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed faq questions: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("failed to embed faq questions: expected %d vectors, got %d", len(texts), len(vectors))
+	}
+
+	faq.Vectors = make([][]float32, len(vectors))
+	for i, vector := range vectors {
+		faq.Vectors[i] = make([]float32, len(vector))
+		for j, v := range vector {
+			faq.Vectors[i][j] = float32(v)
+		}
+	}
+	return nil
+}
+
+// dedupeQuestions 去掉问题首尾空白，并移除规范化后重复的问题
+func dedupeQuestions(questions []string) []string {
+	seen := make(map[string]bool, len(questions))
+	result := make([]string, 0, len(questions))
+	for _, q := range questions {
+		q = strings.TrimSpace(q)
+		key := entity.NormalizeFAQQuestion(q)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, q)
+	}
+	return result
+}
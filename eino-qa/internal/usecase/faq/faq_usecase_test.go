@@ -0,0 +1,139 @@
+package faq
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFAQRepository 内存标准问答仓储
+type fakeFAQRepository struct {
+	items []*entity.FAQ
+}
+
+func (r *fakeFAQRepository) Create(ctx context.Context, faq *entity.FAQ) error {
+	copied := *faq
+	r.items = append(r.items, &copied)
+	return nil
+}
+
+func (r *fakeFAQRepository) Update(ctx context.Context, faq *entity.FAQ) error {
+	for i, item := range r.items {
+		if item.ID == faq.ID {
+			copied := *faq
+			r.items[i] = &copied
+			return nil
+		}
+	}
+	return entity.ErrFAQNotFound
+}
+
+func (r *fakeFAQRepository) FindByID(ctx context.Context, id string) (*entity.FAQ, error) {
+	for _, item := range r.items {
+		if item.ID == id {
+			copied := *item
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", entity.ErrFAQNotFound, id)
+}
+
+func (r *fakeFAQRepository) List(ctx context.Context) ([]*entity.FAQ, error) {
+	return r.items, nil
+}
+
+func (r *fakeFAQRepository) Delete(ctx context.Context, id string) error {
+	for i, item := range r.items {
+		if item.ID == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+	return entity.ErrFAQNotFound
+}
+
+// lengthEmbedder 按文本长度生成向量，记录嵌入的文本
+type lengthEmbedder struct {
+	texts []string
+}
+
+func (e *lengthEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	e.texts = append(e.texts, texts...)
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len([]rune(text))), 1}
+	}
+	return vectors, nil
+}
+
+func TestFAQUseCase_CRUD(t *testing.T) {
+	repo := &fakeFAQRepository{}
+	embedder := &lengthEmbedder{}
+	uc := NewFAQUseCase(func(tenantID string) repository.FAQRepository {
+		require.Equal(t, "tenant1", tenantID)
+		return repo
+	}, embedder, nil)
+	ctx := context.Background()
+
+	// 问题去重（忽略标点和空白），每个问题生成一个向量
+	faq, err := uc.Create(ctx, &SaveRequest{
+		TenantID:  "tenant1",
+		Questions: []string{" 退款政策是什么？", "退款政策是什么", "How to get a REFUND?"},
+		Answer:    "购买 7 天内未学习可全额退款。\n",
+		Enabled:   true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"退款政策是什么？", "How to get a REFUND?"}, faq.Questions)
+	assert.Equal(t, "购买 7 天内未学习可全额退款。", faq.Answer)
+	require.Len(t, faq.Vectors, 2)
+	assert.Equal(t, []string{"退款政策是什么", "how to get a refund"}, embedder.texts)
+
+	_, err = uc.Create(ctx, &SaveRequest{TenantID: "tenant1", Questions: []string{"问题"}})
+	assert.ErrorIs(t, err, entity.ErrEmptyFAQAnswer)
+
+	// 更新整体替换，并重新生成向量
+	from := time.Now().Add(24 * time.Hour)
+	updated, err := uc.Update(ctx, faq.ID, &SaveRequest{
+		TenantID:      "tenant1",
+		Questions:     []string{"退款政策"},
+		Answer:        "购买 14 天内未学习可全额退款。",
+		EffectiveFrom: &from,
+	})
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, []string{"退款政策"}, updated.Questions)
+	require.Len(t, updated.Vectors, 1)
+
+	got, err := uc.Get(ctx, "tenant1", faq.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "购买 14 天内未学习可全额退款。", got.Answer)
+	assert.Equal(t, faq.CreatedAt, got.CreatedAt)
+
+	_, err = uc.Update(ctx, "faq_missing", &SaveRequest{TenantID: "tenant1", Questions: []string{"q"}, Answer: "a"})
+	assert.ErrorIs(t, err, entity.ErrFAQNotFound)
+
+	list, err := uc.List(ctx, "tenant1")
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	require.NoError(t, uc.Delete(ctx, "tenant1", faq.ID))
+	assert.ErrorIs(t, uc.Delete(ctx, "tenant1", faq.ID), entity.ErrFAQNotFound)
+}
+
+func TestFAQUseCase_WithoutEmbedder(t *testing.T) {
+	repo := &fakeFAQRepository{}
+	uc := NewFAQUseCase(func(string) repository.FAQRepository { return repo }, nil, nil)
+
+	faq, err := uc.Create(context.Background(), &SaveRequest{Questions: []string{"会员多少钱"}, Answer: "699 元", Enabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "default", faq.TenantID)
+	assert.Empty(t, faq.Vectors)
+}
@@ -0,0 +1,25 @@
+package faq
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// FAQUseCaseInterface 标准问答管理用例接口
+type FAQUseCaseInterface interface {
+	Create(ctx context.Context, req *SaveRequest) (*entity.FAQ, error)
+	Get(ctx context.Context, tenantID, id string) (*entity.FAQ, error)
+	List(ctx context.Context, tenantID string) ([]*entity.FAQ, error)
+	Update(ctx context.Context, id string, req *SaveRequest) (*entity.FAQ, error)
+	Delete(ctx context.Context, tenantID, id string) error
+}
+
+// ChangeListener 标准问答变更监听接口（对话用例按租户清除标准问答缓存）
+type ChangeListener interface {
+	InvalidateFAQs(tenantID string)
+}
+
+// RepositoryProvider 按租户获取标准问答仓储
+type RepositoryProvider func(tenantID string) repository.FAQRepository
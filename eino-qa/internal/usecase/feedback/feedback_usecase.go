@@ -0,0 +1,252 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/usecase/vector"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FeedbackUseCase 用户反馈用例
+// 记录用户对回答的评价，统计满意度，并支持将纠正答案加入知识库
+type FeedbackUseCase struct {
+	sessionRepo   repository.SessionRepository
+	repos         RepositoryProvider
+	vectorUseCase vector.VectorUseCaseInterface
+	metrics       MetricsRecorder
+	publisher     EventPublisher
+	logger        *logrus.Logger
+}
+
+// NewFeedbackUseCase 创建用户反馈用例
+func NewFeedbackUseCase(
+	sessionRepo repository.SessionRepository,
+	repos RepositoryProvider,
+	vectorUseCase vector.VectorUseCaseInterface,
+	logger *logrus.Logger,
+) *FeedbackUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &FeedbackUseCase{
+		sessionRepo:   sessionRepo,
+		repos:         repos,
+		vectorUseCase: vectorUseCase,
+		logger:        logger,
+	}
+}
+
+// WithMetrics 设置反馈指标记录器（可选）
+func (uc *FeedbackUseCase) WithMetrics(metrics MetricsRecorder) *FeedbackUseCase {
+	uc.metrics = metrics
+	return uc
+}
+
+// WithEventPublisher 设置业务事件发布器（可选）
+func (uc *FeedbackUseCase) WithEventPublisher(publisher EventPublisher) *FeedbackUseCase {
+	uc.publisher = publisher
+	return uc
+}
+
+// SubmitRequest 提交反馈请求
+type SubmitRequest struct {
+	TenantID   string
+	SessionID  string
+	MessageID  string
+	Rating     string
+	Reason     string
+	Comment    string
+	Correction string
+}
+
+// Submit 提交对助手回答的反馈
+// 同一条消息重复提交时覆盖之前的反馈
+func (uc *FeedbackUseCase) Submit(ctx context.Context, req *SubmitRequest) (*entity.Feedback, error) {
+	tenantID := entity.NormalizeTenantID(req.TenantID)
+
+	if req.SessionID == "" {
+		return nil, entity.ErrEmptySessionID
+	}
+	if req.MessageID == "" {
+		return nil, entity.ErrEmptyMessageID
+	}
+
+	rating := entity.FeedbackRating(req.Rating)
+	if !rating.IsValid() {
+		return nil, entity.ErrInvalidFeedbackRating
+	}
+
+	// 1. 定位被评价的助手消息
+	session, err := uc.sessionRepo.Load(ctx, req.SessionID)
+	if err != nil || session.TenantID != tenantID {
+		return nil, fmt.Errorf("%w: session %s", entity.ErrMessageNotFound, req.SessionID)
+	}
+
+	message := session.FindMessage(req.MessageID)
+	if message == nil || !message.IsAssistant() {
+		return nil, fmt.Errorf("%w: %s", entity.ErrMessageNotFound, req.MessageID)
+	}
+
+	// 2. 加载已有反馈或创建新反馈
+	repo := uc.repos(tenantID)
+
+	feedback, err := repo.FindByMessageID(ctx, req.MessageID)
+	if err != nil && !errors.Is(err, entity.ErrFeedbackNotFound) {
+		return nil, fmt.Errorf("failed to load feedback: %w", err)
+	}
+
+	isNew := feedback == nil
+	if isNew {
+		feedback = entity.NewFeedback(tenantID, session.ID, message.ID, rating)
+		feedback.Answer = message.Content
+		feedback.Route, _ = message.Metadata["route"].(string)
+		feedback.Intent, _ = message.Metadata["intent"].(string)
+		if query := session.PrecedingUserMessage(message.ID); query != nil {
+			feedback.Query = query.Content
+		}
+	}
+
+	ratingChanged := feedback.Rating != rating
+	feedback.Rating = rating
+	feedback.Reason = entity.FeedbackReason(req.Reason)
+	feedback.Comment = req.Comment
+	if req.Correction != feedback.Correction {
+		// 纠正答案变化后需要重新入库
+		feedback.Correction = req.Correction
+		feedback.Status = entity.FeedbackStatusOpen
+		feedback.DocumentID = ""
+	}
+	feedback.UpdatedAt = time.Now()
+
+	if err := feedback.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := repo.Save(ctx, feedback); err != nil {
+		uc.logger.WithError(err).Error("failed to save feedback")
+		return nil, fmt.Errorf("failed to save feedback: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":   tenantID,
+		"feedback_id": feedback.ID,
+		"message_id":  feedback.MessageID,
+		"rating":      feedback.Rating,
+		"route":       feedback.Route,
+	}).Info("feedback submitted")
+
+	// 3. 统计指标（仅在首次提交或评分变化时计数）
+	if uc.metrics != nil && (isNew || ratingChanged) {
+		uc.metrics.RecordFeedback(feedback.Route, feedback.Intent, feedback.IsPositive())
+	}
+
+	// 4. 负面反馈通知
+	if !feedback.IsPositive() && (isNew || ratingChanged) {
+		uc.publishNegative(ctx, feedback)
+	}
+
+	return feedback, nil
+}
+
+// List 列出反馈
+func (uc *FeedbackUseCase) List(ctx context.Context, tenantID string, filter repository.FeedbackFilter, offset, limit int) ([]*entity.Feedback, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	feedbacks, err := uc.repos(entity.NormalizeTenantID(tenantID)).List(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	return feedbacks, nil
+}
+
+// Promote 将反馈中的纠正答案加入知识库
+// 已入库的反馈直接返回，保证重复调用幂等
+func (uc *FeedbackUseCase) Promote(ctx context.Context, tenantID, feedbackID string) (*entity.Feedback, error) {
+	tenantID = entity.NormalizeTenantID(tenantID)
+	repo := uc.repos(tenantID)
+
+	feedback, err := repo.FindByID(ctx, feedbackID)
+	if err != nil {
+		return nil, err
+	}
+
+	if feedback.IsPromoted() {
+		return feedback, nil
+	}
+
+	if !feedback.HasCorrection() {
+		return nil, entity.ErrEmptyCorrection
+	}
+
+	resp, err := uc.vectorUseCase.AddVectors(ctx, &vector.AddVectorRequest{
+		Texts:    []string{buildKnowledgeText(feedback)},
+		TenantID: tenantID,
+		Metadata: map[string]any{
+			"source":      "feedback",
+			"feedback_id": feedback.ID,
+			"question":    feedback.Query,
+			"route":       feedback.Route,
+		},
+	})
+	if err != nil {
+		uc.logger.WithError(err).WithField("feedback_id", feedback.ID).Error("failed to promote feedback")
+		return nil, fmt.Errorf("failed to add correction to knowledge base: %w", err)
+	}
+	if len(resp.DocumentIDs) == 0 {
+		return nil, fmt.Errorf("failed to add correction to knowledge base: no document created")
+	}
+
+	feedback.MarkPromoted(resp.DocumentIDs[0])
+	if err := repo.Save(ctx, feedback); err != nil {
+		return nil, fmt.Errorf("failed to update feedback: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":   tenantID,
+		"feedback_id": feedback.ID,
+		"document_id": feedback.DocumentID,
+	}).Info("feedback correction promoted to knowledge base")
+
+	return feedback, nil
+}
+
+// publishNegative 发布负面反馈事件
+func (uc *FeedbackUseCase) publishNegative(ctx context.Context, feedback *entity.Feedback) {
+	if uc.publisher == nil {
+		return
+	}
+
+	err := uc.publisher.Publish(ctx, feedback.TenantID, entity.EventFeedbackNegative, map[string]any{
+		"feedback_id":    feedback.ID,
+		"session_id":     feedback.SessionID,
+		"message_id":     feedback.MessageID,
+		"reason":         string(feedback.Reason),
+		"route":          feedback.Route,
+		"intent":         feedback.Intent,
+		"query":          feedback.Query,
+		"has_correction": feedback.HasCorrection(),
+	})
+	if err != nil {
+		uc.logger.WithError(err).WithField("feedback_id", feedback.ID).Warn("failed to publish negative feedback event")
+	}
+}
+
+// buildKnowledgeText 构建入库的问答文本
+func buildKnowledgeText(feedback *entity.Feedback) string {
+	if feedback.Query == "" {
+		return feedback.Correction
+	}
+	return fmt.Sprintf("问题：%s\n答案：%s", feedback.Query, feedback.Correction)
+}
@@ -0,0 +1,274 @@
+package feedback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/usecase/vector"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSessionRepository 内存会话仓储（仅实现反馈用到的方法）
+type fakeSessionRepository struct {
+	repository.SessionRepository
+	sessions map[string]*entity.Session
+}
+
+func (r *fakeSessionRepository) Load(ctx context.Context, sessionID string) (*entity.Session, error) {
+	if s, ok := r.sessions[sessionID]; ok {
+		return s, nil
+	}
+	return nil, entity.ErrSessionExpired
+}
+
+// fakeFeedbackRepository 内存反馈仓储
+type fakeFeedbackRepository struct {
+	items map[string]*entity.Feedback
+}
+
+func (r *fakeFeedbackRepository) Save(ctx context.Context, feedback *entity.Feedback) error {
+	copied := *feedback
+	r.items[feedback.ID] = &copied
+	return nil
+}
+
+func (r *fakeFeedbackRepository) FindByID(ctx context.Context, id string) (*entity.Feedback, error) {
+	if f, ok := r.items[id]; ok {
+		copied := *f
+		return &copied, nil
+	}
+	return nil, entity.ErrFeedbackNotFound
+}
+
+func (r *fakeFeedbackRepository) FindByMessageID(ctx context.Context, messageID string) (*entity.Feedback, error) {
+	for _, f := range r.items {
+		if f.MessageID == messageID {
+			copied := *f
+			return &copied, nil
+		}
+	}
+	return nil, entity.ErrFeedbackNotFound
+}
+
+func (r *fakeFeedbackRepository) List(ctx context.Context, filter repository.FeedbackFilter, offset, limit int) ([]*entity.Feedback, error) {
+	var result []*entity.Feedback
+	for _, f := range r.items {
+		if filter.Rating == "" || f.Rating == filter.Rating {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// MockVectorUseCase 模拟向量管理用例
+type MockVectorUseCase struct {
+	mock.Mock
+}
+
+func (m *MockVectorUseCase) AddVectors(ctx context.Context, req *vector.AddVectorRequest) (*vector.AddVectorResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*vector.AddVectorResponse), args.Error(1)
+}
+
+func (m *MockVectorUseCase) DeleteVectors(ctx context.Context, req *vector.DeleteVectorRequest) (*vector.DeleteVectorResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*vector.DeleteVectorResponse), args.Error(1)
+}
+
+func (m *MockVectorUseCase) GetVectorCount(ctx context.Context, tenantID string) (int64, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockVectorUseCase) GetVectorByID(ctx context.Context, id string, tenantID string) (*entity.Document, error) {
+	args := m.Called(ctx, id, tenantID)
+	return args.Get(0).(*entity.Document), args.Error(1)
+}
+
+// MockMetrics 模拟指标记录器
+type MockMetrics struct {
+	mock.Mock
+}
+
+func (m *MockMetrics) RecordFeedback(route string, intent string, positive bool) {
+	m.Called(route, intent, positive)
+}
+
+// MockPublisher 模拟事件发布器
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, tenantID string, eventType entity.WebhookEventType, data map[string]any) error {
+	args := m.Called(ctx, tenantID, eventType, data)
+	return args.Error(0)
+}
+
+type testFixture struct {
+	uc        *FeedbackUseCase
+	repo      *fakeFeedbackRepository
+	vectorUC  *MockVectorUseCase
+	metrics   *MockMetrics
+	publisher *MockPublisher
+	session   *entity.Session
+	answer    *entity.Message
+}
+
+func newFixture(t *testing.T) *testFixture {
+	session := entity.NewSession("tenant1", time.Hour)
+	require.NoError(t, session.AddMessage(entity.NewMessage("Python 课程多少钱", "user")))
+	answer := entity.NewMessage("Python 课程 199 元", "assistant")
+	answer.Metadata["route"] = "course"
+	answer.Metadata["intent"] = "course"
+	require.NoError(t, session.AddMessage(answer))
+
+	repo := &fakeFeedbackRepository{items: make(map[string]*entity.Feedback)}
+	vectorUC := new(MockVectorUseCase)
+	metrics := new(MockMetrics)
+	publisher := new(MockPublisher)
+
+	uc := NewFeedbackUseCase(
+		&fakeSessionRepository{sessions: map[string]*entity.Session{session.ID: session}},
+		func(string) repository.FeedbackRepository { return repo },
+		vectorUC,
+		nil,
+	).WithMetrics(metrics).WithEventPublisher(publisher)
+
+	return &testFixture{
+		uc:        uc,
+		repo:      repo,
+		vectorUC:  vectorUC,
+		metrics:   metrics,
+		publisher: publisher,
+		session:   session,
+		answer:    answer,
+	}
+}
+
+func TestSubmit_Positive(t *testing.T) {
+	f := newFixture(t)
+	f.metrics.On("RecordFeedback", "course", "course", true).Once()
+
+	fb, err := f.uc.Submit(context.Background(), &SubmitRequest{
+		TenantID:  "tenant1",
+		SessionID: f.session.ID,
+		MessageID: f.answer.ID,
+		Rating:    "up",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, entity.FeedbackUp, fb.Rating)
+	assert.Equal(t, "Python 课程多少钱", fb.Query)
+	assert.Equal(t, "Python 课程 199 元", fb.Answer)
+	assert.Equal(t, "course", fb.Route)
+	f.metrics.AssertExpectations(t)
+	f.publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubmit_NegativeWithCorrectionAndResubmit(t *testing.T) {
+	f := newFixture(t)
+	f.metrics.On("RecordFeedback", "course", "course", false).Once()
+	f.publisher.On("Publish", mock.Anything, "tenant1", entity.EventFeedbackNegative, mock.Anything).Return(nil).Once()
+
+	req := &SubmitRequest{
+		TenantID:   "tenant1",
+		SessionID:  f.session.ID,
+		MessageID:  f.answer.ID,
+		Rating:     "down",
+		Reason:     "outdated",
+		Correction: "Python 课程现价 299 元",
+	}
+	first, err := f.uc.Submit(context.Background(), req)
+	require.NoError(t, err)
+
+	// 重复提交覆盖同一条反馈，不重复计数
+	req.Comment = "价格已调整"
+	second, err := f.uc.Submit(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, "价格已调整", second.Comment)
+	assert.Len(t, f.repo.items, 1)
+	f.metrics.AssertExpectations(t)
+	f.publisher.AssertExpectations(t)
+}
+
+func TestSubmit_Errors(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name    string
+		req     *SubmitRequest
+		wantErr error
+	}{
+		{"invalid rating", &SubmitRequest{SessionID: f.session.ID, MessageID: f.answer.ID, Rating: "meh"}, entity.ErrInvalidFeedbackRating},
+		{"invalid reason", &SubmitRequest{TenantID: "tenant1", SessionID: f.session.ID, MessageID: f.answer.ID, Rating: "down", Reason: "boring"}, entity.ErrInvalidFeedbackReason},
+		{"unknown message", &SubmitRequest{TenantID: "tenant1", SessionID: f.session.ID, MessageID: "msg_unknown", Rating: "up"}, entity.ErrMessageNotFound},
+		{"user message", &SubmitRequest{TenantID: "tenant1", SessionID: f.session.ID, MessageID: f.session.Messages[0].ID, Rating: "up"}, entity.ErrMessageNotFound},
+		{"other tenant", &SubmitRequest{TenantID: "tenant2", SessionID: f.session.ID, MessageID: f.answer.ID, Rating: "up"}, entity.ErrMessageNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.uc.Submit(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPromote(t *testing.T) {
+	f := newFixture(t)
+	f.metrics.On("RecordFeedback", mock.Anything, mock.Anything, mock.Anything)
+	f.publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	fb, err := f.uc.Submit(context.Background(), &SubmitRequest{
+		TenantID:   "tenant1",
+		SessionID:  f.session.ID,
+		MessageID:  f.answer.ID,
+		Rating:     "down",
+		Correction: "Python 课程现价 299 元",
+	})
+	require.NoError(t, err)
+
+	f.vectorUC.On("AddVectors", mock.Anything, mock.MatchedBy(func(req *vector.AddVectorRequest) bool {
+		return req.TenantID == "tenant1" &&
+			req.Texts[0] == "问题：Python 课程多少钱\n答案：Python 课程现价 299 元" &&
+			req.Metadata["feedback_id"] == fb.ID
+	})).Return(&vector.AddVectorResponse{Success: true, DocumentIDs: []string{"doc_1"}, Count: 1}, nil).Once()
+
+	promoted, err := f.uc.Promote(context.Background(), "tenant1", fb.ID)
+	require.NoError(t, err)
+	assert.True(t, promoted.IsPromoted())
+	assert.Equal(t, "doc_1", promoted.DocumentID)
+
+	// 再次提升保持幂等
+	again, err := f.uc.Promote(context.Background(), "tenant1", fb.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "doc_1", again.DocumentID)
+	f.vectorUC.AssertExpectations(t)
+}
+
+func TestPromote_WithoutCorrection(t *testing.T) {
+	f := newFixture(t)
+	f.metrics.On("RecordFeedback", mock.Anything, mock.Anything, mock.Anything)
+
+	fb, err := f.uc.Submit(context.Background(), &SubmitRequest{
+		TenantID:  "tenant1",
+		SessionID: f.session.ID,
+		MessageID: f.answer.ID,
+		Rating:    "up",
+	})
+	require.NoError(t, err)
+
+	_, err = f.uc.Promote(context.Background(), "tenant1", fb.ID)
+	assert.ErrorIs(t, err, entity.ErrEmptyCorrection)
+}
@@ -0,0 +1,28 @@
+package feedback
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// FeedbackUseCaseInterface 用户反馈用例接口
+type FeedbackUseCaseInterface interface {
+	Submit(ctx context.Context, req *SubmitRequest) (*entity.Feedback, error)
+	List(ctx context.Context, tenantID string, filter repository.FeedbackFilter, offset, limit int) ([]*entity.Feedback, error)
+	Promote(ctx context.Context, tenantID, feedbackID string) (*entity.Feedback, error)
+}
+
+// RepositoryProvider 按租户获取反馈仓储
+type RepositoryProvider func(tenantID string) repository.FeedbackRepository
+
+// MetricsRecorder 反馈指标记录接口
+type MetricsRecorder interface {
+	RecordFeedback(route string, intent string, positive bool)
+}
+
+// EventPublisher 业务事件发布接口
+type EventPublisher interface {
+	Publish(ctx context.Context, tenantID string, eventType entity.WebhookEventType, data map[string]any) error
+}
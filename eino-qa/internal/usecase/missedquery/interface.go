@@ -0,0 +1,18 @@
+package missedquery
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// MissedQueryUseCaseInterface 未命中查询分析用例接口
+type MissedQueryUseCaseInterface interface {
+	List(ctx context.Context, tenantID string, offset, limit int) ([]*entity.MissedQuery, int64, error)
+	Cluster(ctx context.Context, req *ClusterRequest) ([]*Cluster, error)
+	DraftFAQ(ctx context.Context, tenantID string, queryIDs []uint) (*FAQDraft, error)
+}
+
+// RepositoryProvider 按租户获取未命中查询仓储
+type RepositoryProvider func(tenantID string) repository.MissedQueryRepository
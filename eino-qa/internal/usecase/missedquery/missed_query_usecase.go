@@ -0,0 +1,345 @@
+package missedquery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/pkg/utils"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultScanLimit 聚类时默认扫描的最近未命中记录数
+	defaultScanLimit = 500
+	// maxScanLimit 聚类时最多扫描的记录数
+	maxScanLimit = 2000
+	// defaultSimilarityThreshold 默认聚类相似度阈值（余弦相似度）
+	defaultSimilarityThreshold = 0.85
+	// maxDraftVariants 草稿中最多列出的相似问法数量
+	maxDraftVariants = 10
+)
+
+// MissedQueryUseCase 未命中查询分析用例
+// 用于发现知识库缺口：列出未命中查询、按语义聚类为话题、生成 FAQ 草稿
+type MissedQueryUseCase struct {
+	embedder embedding.Embedder
+	repos    RepositoryProvider
+	logger   *logrus.Logger
+}
+
+// NewMissedQueryUseCase 创建未命中查询分析用例
+func NewMissedQueryUseCase(
+	embedder embedding.Embedder,
+	repos RepositoryProvider,
+	logger *logrus.Logger,
+) *MissedQueryUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &MissedQueryUseCase{
+		embedder: embedder,
+		repos:    repos,
+		logger:   logger,
+	}
+}
+
+// ClusterRequest 聚类请求
+type ClusterRequest struct {
+	TenantID  string
+	Limit     int       // 扫描最近的记录数，默认 500
+	Threshold float64   // 余弦相似度阈值，默认 0.85
+	Since     time.Time // 仅统计该时间之后的记录（可选）
+}
+
+// Cluster 未命中查询话题
+type Cluster struct {
+	Representative string    // 代表性查询（出现次数最多的问法）
+	Count          int       // 话题内未命中总次数
+	QueryIDs       []uint    // 话题内所有记录 ID
+	Variants       []Variant // 去重后的问法（按次数降序）
+	AvgTopScore    float64   // 平均最高相似度
+	LastSeenAt     time.Time // 最近一次未命中时间
+}
+
+// Variant 话题内的一种问法
+type Variant struct {
+	Query string
+	Count int
+}
+
+// FAQDraft FAQ 文档草稿
+// 内容和元数据可直接提交到向量管理接口入库
+type FAQDraft struct {
+	Question string
+	Variants []string
+	Content  string
+	Metadata map[string]any
+}
+
+// List 分页列出未命中查询
+func (uc *MissedQueryUseCase) List(ctx context.Context, tenantID string, offset, limit int) ([]*entity.MissedQuery, int64, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	repo := uc.repos(entity.NormalizeTenantID(tenantID))
+
+	queries, err := repo.List(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list missed queries: %w", err)
+	}
+
+	total, err := repo.Count(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count missed queries: %w", err)
+	}
+
+	return queries, total, nil
+}
+
+// Cluster 将最近的未命中查询按 embedding 相似度聚类，按频次降序返回话题
+func (uc *MissedQueryUseCase) Cluster(ctx context.Context, req *ClusterRequest) ([]*Cluster, error) {
+	tenantID := entity.NormalizeTenantID(req.TenantID)
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultScanLimit
+	}
+	if limit > maxScanLimit {
+		limit = maxScanLimit
+	}
+
+	threshold := req.Threshold
+	if threshold <= 0 || threshold > 1 {
+		threshold = defaultSimilarityThreshold
+	}
+
+	queries, err := uc.repos(tenantID).List(ctx, 0, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list missed queries: %w", err)
+	}
+
+	if !req.Since.IsZero() {
+		filtered := queries[:0]
+		for _, q := range queries {
+			if !q.CreatedAt.Before(req.Since) {
+				filtered = append(filtered, q)
+			}
+		}
+		queries = filtered
+	}
+
+	if len(queries) == 0 {
+		return []*Cluster{}, nil
+	}
+
+	// 1. 相同问法先合并，减少 embedding 调用
+	groups := groupByNormalizedQuery(queries)
+
+	// 2. 生成每种问法的向量
+	texts := make([]string, len(groups))
+	for i, g := range groups {
+		texts[i] = g.text
+	}
+
+	vectors, err := uc.embedder.EmbedStrings(ctx, texts)
+	if err != nil {
+		uc.logger.WithError(err).Error("failed to embed missed queries")
+		return nil, fmt.Errorf("failed to embed missed queries: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("embedding result count mismatch: expected %d, got %d", len(texts), len(vectors))
+	}
+
+	// 3. 按频次从高到低贪心聚类，高频问法作为话题中心
+	clusters := clusterGroups(groups, vectors, threshold)
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"queries":   len(queries),
+		"clusters":  len(clusters),
+	}).Info("missed queries clustered")
+
+	return clusters, nil
+}
+
+// DraftFAQ 将一组未命中查询（通常是一个话题）转换为 FAQ 文档草稿
+func (uc *MissedQueryUseCase) DraftFAQ(ctx context.Context, tenantID string, queryIDs []uint) (*FAQDraft, error) {
+	if len(queryIDs) == 0 {
+		return nil, fmt.Errorf("query_ids cannot be empty")
+	}
+
+	tenantID = entity.NormalizeTenantID(tenantID)
+
+	queries, err := uc.repos(tenantID).FindByIDs(ctx, queryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load missed queries: %w", err)
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no missed queries found for given ids")
+	}
+
+	groups := groupByNormalizedQuery(queries)
+	sortGroups(groups)
+
+	question := groups[0].text
+	variants := make([]string, 0, len(groups)-1)
+	for _, g := range groups[1:] {
+		if len(variants) >= maxDraftVariants {
+			break
+		}
+		variants = append(variants, g.text)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("问题：%s\n", question))
+	if len(variants) > 0 {
+		sb.WriteString("相似问法：\n")
+		for _, v := range variants {
+			sb.WriteString(fmt.Sprintf("- %s\n", v))
+		}
+	}
+	sb.WriteString("答案：（待补充）\n")
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"queries":   len(queries),
+	}).Info("faq draft generated from missed queries")
+
+	return &FAQDraft{
+		Question: question,
+		Variants: variants,
+		Content:  sb.String(),
+		Metadata: map[string]any{
+			"type":        "faq",
+			"status":      "draft",
+			"source":      "missed_queries",
+			"question":    question,
+			"query_count": len(queries),
+		},
+	}, nil
+}
+
+// queryGroup 相同问法的未命中记录
+type queryGroup struct {
+	text       string
+	ids        []uint
+	scoreSum   float64
+	lastSeenAt time.Time
+}
+
+// groupByNormalizedQuery 按标准化后的查询文本合并记录（保持首次出现顺序）
+func groupByNormalizedQuery(queries []*entity.MissedQuery) []*queryGroup {
+	index := make(map[string]*queryGroup)
+	groups := make([]*queryGroup, 0, len(queries))
+
+	for _, q := range queries {
+		key := normalizeQuery(q.Query)
+		if key == "" {
+			continue
+		}
+
+		g, ok := index[key]
+		if !ok {
+			g = &queryGroup{text: strings.TrimSpace(q.Query)}
+			index[key] = g
+			groups = append(groups, g)
+		}
+
+		g.ids = append(g.ids, q.ID)
+		g.scoreSum += q.TopScore
+		if q.CreatedAt.After(g.lastSeenAt) {
+			g.lastSeenAt = q.CreatedAt
+		}
+	}
+
+	return groups
+}
+
+// sortGroups 按出现次数降序排序，次数相同按最近出现时间
+func sortGroups(groups []*queryGroup) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].ids) != len(groups[j].ids) {
+			return len(groups[i].ids) > len(groups[j].ids)
+		}
+		return groups[i].lastSeenAt.After(groups[j].lastSeenAt)
+	})
+}
+
+// clusterGroups 贪心聚类：依次将问法归入与中心相似度最高且超过阈值的话题
+func clusterGroups(groups []*queryGroup, vectors [][]float64, threshold float64) []*Cluster {
+	order := make([]int, len(groups))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(groups[order[a]].ids) > len(groups[order[b]].ids)
+	})
+
+	type centroid struct {
+		vector  []float64
+		cluster *Cluster
+	}
+	centroids := make([]*centroid, 0)
+	scoreSums := make(map[*Cluster]float64)
+
+	for _, idx := range order {
+		g := groups[idx]
+
+		var best *centroid
+		bestSim := threshold
+		for _, c := range centroids {
+			if sim := utils.CosineSimilarity(c.vector, vectors[idx]); sim >= bestSim {
+				best, bestSim = c, sim
+			}
+		}
+
+		if best == nil {
+			best = &centroid{
+				vector:  vectors[idx],
+				cluster: &Cluster{Representative: g.text},
+			}
+			centroids = append(centroids, best)
+		}
+
+		cl := best.cluster
+		cl.Count += len(g.ids)
+		cl.QueryIDs = append(cl.QueryIDs, g.ids...)
+		cl.Variants = append(cl.Variants, Variant{Query: g.text, Count: len(g.ids)})
+		if g.lastSeenAt.After(cl.LastSeenAt) {
+			cl.LastSeenAt = g.lastSeenAt
+		}
+		scoreSums[cl] += g.scoreSum
+	}
+
+	clusters := make([]*Cluster, len(centroids))
+	for i, c := range centroids {
+		c.cluster.AvgTopScore = scoreSums[c.cluster] / float64(c.cluster.Count)
+		clusters[i] = c.cluster
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].LastSeenAt.After(clusters[j].LastSeenAt)
+	})
+
+	return clusters
+}
+
+// normalizeQuery 标准化查询文本（忽略大小写、首尾空白和末尾标点）
+func normalizeQuery(query string) string {
+	q := strings.ToLower(strings.TrimSpace(query))
+	return strings.TrimRight(q, "?？!！。.,， ")
+}
@@ -0,0 +1,144 @@
+package missedquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEmbedder 模拟嵌入模型
+type MockEmbedder struct {
+	mock.Mock
+}
+
+func (m *MockEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	args := m.Called(ctx, texts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]float64), args.Error(1)
+}
+
+// fakeRepository 内存未命中查询仓储
+type fakeRepository struct {
+	queries []*entity.MissedQuery
+}
+
+func (r *fakeRepository) Create(ctx context.Context, query *entity.MissedQuery) error {
+	query.ID = uint(len(r.queries) + 1)
+	r.queries = append(r.queries, query)
+	return nil
+}
+
+func (r *fakeRepository) List(ctx context.Context, offset, limit int) ([]*entity.MissedQuery, error) {
+	if offset >= len(r.queries) {
+		return []*entity.MissedQuery{}, nil
+	}
+	end := offset + limit
+	if end > len(r.queries) {
+		end = len(r.queries)
+	}
+	return append([]*entity.MissedQuery(nil), r.queries[offset:end]...), nil
+}
+
+func (r *fakeRepository) FindByIDs(ctx context.Context, ids []uint) ([]*entity.MissedQuery, error) {
+	wanted := make(map[uint]bool)
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var result []*entity.MissedQuery
+	for _, q := range r.queries {
+		if wanted[q.ID] {
+			result = append(result, q)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.queries)), nil
+}
+
+func (r *fakeRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func newTestUseCase(embedder embedding.Embedder, repo *fakeRepository) *MissedQueryUseCase {
+	return NewMissedQueryUseCase(embedder, func(string) repository.MissedQueryRepository { return repo }, nil)
+}
+
+func seedRepository(queries ...string) *fakeRepository {
+	repo := &fakeRepository{}
+	for _, q := range queries {
+		miss := entity.NewMissedQuery("default", q, "course")
+		miss.TopScore = 0.5
+		repo.Create(context.Background(), miss)
+	}
+	return repo
+}
+
+func TestCluster_GroupsSimilarQueriesRankedByFrequency(t *testing.T) {
+	repo := seedRepository(
+		"退款需要多久",
+		"退款需要多久？",
+		"退款多久到账",
+		"有没有发票",
+		"退款需要多久",
+	)
+
+	embedder := new(MockEmbedder)
+	// 相同问法合并后只需 embedding 三种问法
+	embedder.On("EmbedStrings", mock.Anything, []string{"退款需要多久", "退款多久到账", "有没有发票"}).
+		Return([][]float64{{1, 0}, {0.95, 0.1}, {0, 1}}, nil)
+
+	clusters, err := newTestUseCase(embedder, repo).Cluster(context.Background(), &ClusterRequest{TenantID: "default"})
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+
+	assert.Equal(t, "退款需要多久", clusters[0].Representative)
+	assert.Equal(t, 4, clusters[0].Count)
+	assert.Len(t, clusters[0].QueryIDs, 4)
+	assert.Equal(t, []Variant{{Query: "退款需要多久", Count: 3}, {Query: "退款多久到账", Count: 1}}, clusters[0].Variants)
+	assert.InDelta(t, 0.5, clusters[0].AvgTopScore, 1e-9)
+
+	assert.Equal(t, "有没有发票", clusters[1].Representative)
+	assert.Equal(t, 1, clusters[1].Count)
+
+	embedder.AssertExpectations(t)
+}
+
+func TestCluster_Empty(t *testing.T) {
+	embedder := new(MockEmbedder)
+
+	clusters, err := newTestUseCase(embedder, &fakeRepository{}).Cluster(context.Background(), &ClusterRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, clusters)
+	embedder.AssertNotCalled(t, "EmbedStrings", mock.Anything, mock.Anything)
+}
+
+func TestDraftFAQ(t *testing.T) {
+	repo := seedRepository("退款多久到账", "退款需要多久", "退款需要多久")
+
+	draft, err := newTestUseCase(new(MockEmbedder), repo).DraftFAQ(context.Background(), "default", []uint{1, 2, 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, "退款需要多久", draft.Question)
+	assert.Equal(t, []string{"退款多久到账"}, draft.Variants)
+	assert.Contains(t, draft.Content, "问题：退款需要多久")
+	assert.Contains(t, draft.Content, "- 退款多久到账")
+	assert.Equal(t, "draft", draft.Metadata["status"])
+	assert.Equal(t, 3, draft.Metadata["query_count"])
+}
+
+func TestDraftFAQ_NoIDs(t *testing.T) {
+	_, err := newTestUseCase(new(MockEmbedder), &fakeRepository{}).DraftFAQ(context.Background(), "default", nil)
+	assert.Error(t, err)
+}
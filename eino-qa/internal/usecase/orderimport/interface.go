@@ -0,0 +1,46 @@
+package orderimport
+
+import (
+	"context"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// OrderImportUseCaseInterface 订单导入用例接口
+type OrderImportUseCaseInterface interface {
+	Import(ctx context.Context, req *ImportRequest) (*ImportReport, error)
+	Sync(ctx context.Context, tenantID string) (*ImportReport, error)
+	SyncStatus(ctx context.Context, tenantID string) (*entity.OrderSyncState, error)
+}
+
+// EventPublisher 业务事件发布接口（由 Webhook 分发器实现）
+type EventPublisher interface {
+	Publish(ctx context.Context, tenantID string, eventType entity.WebhookEventType, data map[string]any) error
+}
+
+// RepositoryProvider 按租户获取订单仓储
+type RepositoryProvider func(tenantID string) repository.OrderRepository
+
+// SyncRepositoryProvider 按租户获取订单同步状态仓储
+type SyncRepositoryProvider func(tenantID string) repository.OrderSyncRepository
+
+// OrderSource 外部电商系统订单数据源
+type OrderSource interface {
+	// Name 数据源名称，用于区分同步状态和订单事件的操作者
+	Name() string
+
+	// Fetch 拉取 since 之后更新的订单（since 为零值时全量拉取），page 从 1 开始
+	// 返回: 原始记录（按 FieldMapping 转换为订单）、是否还有下一页和错误
+	Fetch(ctx context.Context, since time.Time, page int) ([]map[string]any, bool, error)
+}
+
+// SyncSource 租户的同步数据源及其字段映射
+type SyncSource struct {
+	Source  OrderSource
+	Mapping *FieldMapping
+}
+
+// SourceProvider 按租户获取同步数据源，租户未配置同步时返回 nil
+type SourceProvider func(tenantID string) *SyncSource
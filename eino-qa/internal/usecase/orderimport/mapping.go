@@ -0,0 +1,290 @@
+package orderimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+)
+
+// 字段映射相关错误
+var (
+	ErrMissingField = errors.New("required field is missing")
+	ErrInvalidField = errors.New("invalid field value")
+)
+
+// timeLayouts 未指定 TimeLayout 时依次尝试的时间格式（另支持 Unix 秒级时间戳）
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+}
+
+// FieldMapping 外部订单字段到订单实体的映射
+// 字段名支持用 "." 访问嵌套对象，如 "buyer.id"
+type FieldMapping struct {
+	ID           string            `json:"id"`
+	UserID       string            `json:"user_id"`
+	CourseName   string            `json:"course_name"`
+	Amount       string            `json:"amount"`
+	Status       string            `json:"status"`
+	CreatedAt    string            `json:"created_at"`
+	UpdatedAt    string            `json:"updated_at"`
+	StatusValues map[string]string `json:"status_values"` // 外部状态值到订单状态的映射，如 "TRADE_SUCCESS": "paid"
+	TimeLayout   string            `json:"time_layout"`   // 时间格式，为空时自动识别
+}
+
+// DefaultFieldMapping 默认字段映射：外部字段名与订单字段名一致
+func DefaultFieldMapping() *FieldMapping {
+	return &FieldMapping{
+		ID:         "id",
+		UserID:     "user_id",
+		CourseName: "course_name",
+		Amount:     "amount",
+		Status:     "status",
+		CreatedAt:  "created_at",
+		UpdatedAt:  "updated_at",
+	}
+}
+
+// withDefaults 未配置的字段使用默认字段名
+func (m *FieldMapping) withDefaults() *FieldMapping {
+	merged := DefaultFieldMapping()
+	if m == nil {
+		return merged
+	}
+
+	pick := func(value, fallback string) string {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+		return fallback
+	}
+
+	merged.ID = pick(m.ID, merged.ID)
+	merged.UserID = pick(m.UserID, merged.UserID)
+	merged.CourseName = pick(m.CourseName, merged.CourseName)
+	merged.Amount = pick(m.Amount, merged.Amount)
+	merged.Status = pick(m.Status, merged.Status)
+	merged.CreatedAt = pick(m.CreatedAt, merged.CreatedAt)
+	merged.UpdatedAt = pick(m.UpdatedAt, merged.UpdatedAt)
+	merged.StatusValues = m.StatusValues
+	merged.TimeLayout = m.TimeLayout
+	return merged
+}
+
+// ToOrder 按映射将一条外部记录转换为订单并校验
+// 订单号按租户的订单号方案规范化；创建/更新时间缺失时互相补齐，都缺失时使用当前时间
+func (m *FieldMapping) ToOrder(tenantID string, record map[string]any) (*entity.Order, error) {
+	rawID, err := m.requiredString(record, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderID, ok := entity.OrderIDSchemeFor(tenantID).Normalize(rawID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidOrderID, rawID)
+	}
+
+	userID, err := m.requiredString(record, m.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	courseName, err := m.requiredString(record, m.CourseName)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := m.amount(record)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := m.status(record)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt, err := m.time(record, m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedAt, err := m.time(record, m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case createdAt.IsZero() && updatedAt.IsZero():
+		createdAt = time.Now()
+		updatedAt = createdAt
+	case createdAt.IsZero():
+		createdAt = updatedAt
+	case updatedAt.IsZero():
+		updatedAt = createdAt
+	}
+
+	order := &entity.Order{
+		ID:         orderID,
+		UserID:     userID,
+		CourseName: courseName,
+		Amount:     amount,
+		Status:     status,
+		TenantID:   tenantID,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+		Metadata:   make(map[string]any),
+	}
+
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// requiredString 读取必填的字符串字段
+func (m *FieldMapping) requiredString(record map[string]any, field string) (string, error) {
+	value := stringValue(lookup(record, field))
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingField, field)
+	}
+	return value, nil
+}
+
+// amount 读取金额字段
+func (m *FieldMapping) amount(record map[string]any) (float64, error) {
+	value := lookup(record, m.Amount)
+
+	var amount float64
+	switch v := value.(type) {
+	case nil:
+		return 0, fmt.Errorf("%w: %s", ErrMissingField, m.Amount)
+	case float64:
+		amount = v
+	case int:
+		amount = float64(v)
+	case int64:
+		amount = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s=%v", ErrInvalidField, m.Amount, v)
+		}
+		amount = f
+	default:
+		s := strings.TrimSpace(stringValue(v))
+		if s == "" {
+			return 0, fmt.Errorf("%w: %s", ErrMissingField, m.Amount)
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s=%v", ErrInvalidField, m.Amount, v)
+		}
+		amount = f
+	}
+
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("%w: %s=%v", ErrInvalidField, m.Amount, value)
+	}
+	return amount, nil
+}
+
+// status 读取状态字段，先按 StatusValues 映射，再按订单状态值匹配
+func (m *FieldMapping) status(record map[string]any) (entity.OrderStatus, error) {
+	raw, err := m.requiredString(record, m.Status)
+	if err != nil {
+		return "", err
+	}
+
+	if mapped, ok := m.StatusValues[raw]; ok {
+		raw = mapped
+	}
+
+	status := entity.OrderStatus(strings.ToLower(raw))
+	if !status.IsValid() {
+		return "", fmt.Errorf("%w: %s", entity.ErrInvalidOrderStatus, raw)
+	}
+	return status, nil
+}
+
+// time 读取时间字段，字段缺失时返回零值
+func (m *FieldMapping) time(record map[string]any, field string) (time.Time, error) {
+	value := lookup(record, field)
+
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	case json.Number:
+		if seconds, err := v.Int64(); err == nil {
+			return time.Unix(seconds, 0), nil
+		}
+	}
+
+	s := stringValue(value)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if m.TimeLayout != "" {
+		t, err := time.ParseInLocation(m.TimeLayout, s, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %s=%s", ErrInvalidField, field, s)
+		}
+		return t, nil
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %s=%s", ErrInvalidField, field, s)
+}
+
+// lookup 按字段名读取记录中的值，支持 "." 分隔的嵌套字段
+func lookup(record map[string]any, field string) any {
+	if value, ok := record[field]; ok {
+		return value
+	}
+
+	var current any = record
+	for _, part := range strings.Split(field, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// stringValue 将字段值转换为去除首尾空白的字符串
+func stringValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,489 @@
+package orderimport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 支持的导入格式
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+const (
+	// maxReportedErrors 导入报告中最多列出的错误行数
+	maxReportedErrors = 100
+	// maxJSONLLineSize JSONL 单行最大字节数
+	maxJSONLLineSize = 1 << 20
+	// maxSyncPages 单次同步最多拉取的页数，防止数据源分页异常导致死循环
+	maxSyncPages = 10000
+)
+
+// 订单导入相关错误
+var (
+	ErrUnsupportedFormat    = errors.New("unsupported import format")
+	ErrOrderSyncNotEnabled  = errors.New("order sync is not configured for this tenant")
+	ErrOrderSyncInProgress  = errors.New("order sync is already running")
+	ErrOrderSyncPageLimited = errors.New("order sync stopped at page limit")
+)
+
+// ImportRequest 批量导入请求
+type ImportRequest struct {
+	TenantID string
+	Format   string        // csv 或 jsonl
+	Reader   io.Reader     // 文件内容，CSV 第一行为表头
+	Mapping  *FieldMapping // 字段映射，为空时使用默认映射
+	DryRun   bool          // 只校验不写入
+}
+
+// RowError 导入失败的记录
+type RowError struct {
+	Line    int    `json:"line"` // 文件中的行号；同步时为记录序号
+	OrderID string `json:"order_id,omitempty"`
+	Error   string `json:"error"`
+}
+
+// ImportReport 导入/同步结果报告
+type ImportReport struct {
+	Total     int        `json:"total"`
+	Valid     int        `json:"valid"`
+	Created   int        `json:"created"`
+	Updated   int        `json:"updated"`
+	Unchanged int        `json:"unchanged"`
+	Failed    int        `json:"failed"`
+	Errors    []RowError `json:"errors"`
+	DryRun    bool       `json:"dry_run"`
+}
+
+// Stats 转换为导入统计
+func (r *ImportReport) Stats() entity.OrderImportStats {
+	return entity.OrderImportStats{
+		Total:     r.Total,
+		Created:   r.Created,
+		Updated:   r.Updated,
+		Unchanged: r.Unchanged,
+		Failed:    r.Failed,
+	}
+}
+
+// addError 记录失败的记录，超过上限时只计数
+func (r *ImportReport) addError(line int, orderID string, err error) {
+	r.Failed++
+	if len(r.Errors) < maxReportedErrors {
+		r.Errors = append(r.Errors, RowError{Line: line, OrderID: orderID, Error: err.Error()})
+	}
+}
+
+// count 按写入结果计数
+func (r *ImportReport) count(result repository.OrderUpsertResult) {
+	switch result {
+	case repository.OrderUpsertCreated:
+		r.Created++
+	case repository.OrderUpsertUpdated:
+		r.Updated++
+	default:
+		r.Unchanged++
+	}
+}
+
+// OrderImportUseCase 订单导入用例
+// 支持 CSV/JSONL 文件批量导入，以及从外部电商系统增量拉取同步，均按订单 ID 写入租户的订单表
+type OrderImportUseCase struct {
+	repos     RepositoryProvider
+	syncRepos SyncRepositoryProvider
+	sources   SourceProvider
+	publisher EventPublisher
+	logger    *logrus.Logger
+
+	mu      sync.Mutex
+	running map[string]bool // 正在同步的租户
+}
+
+// NewOrderImportUseCase 创建订单导入用例
+func NewOrderImportUseCase(
+	repos RepositoryProvider,
+	syncRepos SyncRepositoryProvider,
+	sources SourceProvider,
+	logger *logrus.Logger,
+) *OrderImportUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &OrderImportUseCase{
+		repos:     repos,
+		syncRepos: syncRepos,
+		sources:   sources,
+		logger:    logger,
+		running:   make(map[string]bool),
+	}
+}
+
+// WithEventPublisher 设置业务事件发布器（可选），导入和同步结束时发布 job.finished 事件
+func (uc *OrderImportUseCase) WithEventPublisher(publisher EventPublisher) *OrderImportUseCase {
+	uc.publisher = publisher
+	return uc
+}
+
+// Import 批量导入订单文件
+// 单条记录校验或写入失败不影响其他记录，失败原因记录在报告中
+func (uc *OrderImportUseCase) Import(ctx context.Context, req *ImportRequest) (*ImportReport, error) {
+	tenantID := entity.NormalizeTenantID(req.TenantID)
+	mapping := req.Mapping.withDefaults()
+	repo := uc.repos(tenantID)
+	actor := "import:" + req.Format
+
+	report := &ImportReport{Errors: []RowError{}, DryRun: req.DryRun}
+
+	err := forEachRecord(req.Format, req.Reader, func(line int, record map[string]any, parseErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		report.Total++
+		if parseErr != nil {
+			report.addError(line, "", parseErr)
+			return nil
+		}
+
+		order, err := mapping.ToOrder(tenantID, record)
+		if err != nil {
+			report.addError(line, stringValue(lookup(record, mapping.ID)), err)
+			return nil
+		}
+		report.Valid++
+
+		if req.DryRun {
+			return nil
+		}
+
+		result, err := repo.Upsert(ctx, order, actor)
+		if err != nil {
+			report.addError(line, order.ID, err)
+			return nil
+		}
+		report.count(result)
+		return nil
+	})
+	if err != nil {
+		if !req.DryRun {
+			uc.publishJobFinished(ctx, tenantID, "order_import", actor, report, err)
+		}
+		return nil, err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"format":    req.Format,
+		"total":     report.Total,
+		"created":   report.Created,
+		"updated":   report.Updated,
+		"unchanged": report.Unchanged,
+		"failed":    report.Failed,
+		"dry_run":   req.DryRun,
+	}).Info("orders imported")
+
+	if !req.DryRun {
+		uc.publishJobFinished(ctx, tenantID, "order_import", actor, report, nil)
+	}
+	return report, nil
+}
+
+// Sync 从租户配置的外部系统增量拉取订单
+// 以上次成功同步的游标作为 updated_since，全部页拉取成功后将游标推进到本次记录的最大更新时间；
+// 中途失败时游标不变，下次从同一位置重新拉取（写入按订单 ID 幂等）
+func (uc *OrderImportUseCase) Sync(ctx context.Context, tenantID string) (*ImportReport, error) {
+	tenantID = entity.NormalizeTenantID(tenantID)
+
+	source := uc.source(tenantID)
+	if source == nil {
+		return nil, ErrOrderSyncNotEnabled
+	}
+
+	if !uc.acquire(tenantID) {
+		return nil, ErrOrderSyncInProgress
+	}
+	defer uc.release(tenantID)
+
+	syncRepo := uc.syncRepos(tenantID)
+	name := source.Source.Name()
+
+	state, err := syncRepo.GetState(ctx, name)
+	if errors.Is(err, entity.ErrOrderSyncStateNotFound) {
+		state = entity.NewOrderSyncState(tenantID, name)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load order sync state: %w", err)
+	}
+
+	state.Start()
+	if err := syncRepo.SaveState(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save order sync state: %w", err)
+	}
+
+	report, cursor, syncErr := uc.pull(ctx, tenantID, source, state.Cursor)
+
+	// 使用独立的 context 保存结果，请求取消时同样记录失败状态
+	state.Finish(report.Stats(), cursor, syncErr)
+	if err := syncRepo.SaveState(context.WithoutCancel(ctx), state); err != nil {
+		uc.logger.WithError(err).WithField("tenant_id", tenantID).Error("failed to save order sync state")
+	}
+
+	fields := logrus.Fields{
+		"tenant_id": tenantID,
+		"source":    name,
+		"total":     report.Total,
+		"created":   report.Created,
+		"updated":   report.Updated,
+		"unchanged": report.Unchanged,
+		"failed":    report.Failed,
+		"cursor":    state.Cursor,
+	}
+	uc.publishJobFinished(ctx, tenantID, "order_sync", "sync:"+name, report, syncErr)
+	if syncErr != nil {
+		uc.logger.WithFields(fields).WithError(syncErr).Error("order sync failed")
+		return report, syncErr
+	}
+
+	uc.logger.WithFields(fields).Info("order sync completed")
+	return report, nil
+}
+
+// publishJobFinished 发布导入/同步任务完成事件，请求取消时同样发布失败结果
+func (uc *OrderImportUseCase) publishJobFinished(ctx context.Context, tenantID, job, source string, report *ImportReport, jobErr error) {
+	if uc.publisher == nil {
+		return
+	}
+
+	data := map[string]any{
+		"job":       job,
+		"source":    source,
+		"status":    "succeeded",
+		"total":     report.Total,
+		"created":   report.Created,
+		"updated":   report.Updated,
+		"unchanged": report.Unchanged,
+		"failed":    report.Failed,
+	}
+	if jobErr != nil {
+		data["status"] = "failed"
+		data["error"] = jobErr.Error()
+	}
+
+	if err := uc.publisher.Publish(context.WithoutCancel(ctx), tenantID, entity.EventJobFinished, data); err != nil {
+		uc.logger.WithError(err).WithField("tenant_id", tenantID).Warn("failed to publish job finished event")
+	}
+}
+
+// SyncStatus 查询租户的同步状态报告
+func (uc *OrderImportUseCase) SyncStatus(ctx context.Context, tenantID string) (*entity.OrderSyncState, error) {
+	tenantID = entity.NormalizeTenantID(tenantID)
+
+	source := uc.source(tenantID)
+	if source == nil {
+		return nil, ErrOrderSyncNotEnabled
+	}
+
+	name := source.Source.Name()
+	state, err := uc.syncRepos(tenantID).GetState(ctx, name)
+	if errors.Is(err, entity.ErrOrderSyncStateNotFound) {
+		return entity.NewOrderSyncState(tenantID, name), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load order sync state: %w", err)
+	}
+	return state, nil
+}
+
+// pull 逐页拉取并写入订单，返回报告和新的游标
+func (uc *OrderImportUseCase) pull(ctx context.Context, tenantID string, source *SyncSource, since time.Time) (*ImportReport, time.Time, error) {
+	mapping := source.Mapping.withDefaults()
+	repo := uc.repos(tenantID)
+	actor := "sync:" + source.Source.Name()
+
+	report := &ImportReport{Errors: []RowError{}}
+	cursor := since
+
+	for page := 1; ; page++ {
+		if page > maxSyncPages {
+			return report, since, ErrOrderSyncPageLimited
+		}
+
+		records, hasMore, err := source.Source.Fetch(ctx, since, page)
+		if err != nil {
+			return report, since, fmt.Errorf("failed to fetch page %d: %w", page, err)
+		}
+
+		for _, record := range records {
+			report.Total++
+
+			order, err := mapping.ToOrder(tenantID, record)
+			if err != nil {
+				report.addError(report.Total, stringValue(lookup(record, mapping.ID)), err)
+				continue
+			}
+			report.Valid++
+
+			upserted, err := repo.Upsert(ctx, order, actor)
+			if err != nil {
+				return report, since, fmt.Errorf("failed to upsert order %s: %w", order.ID, err)
+			}
+			report.count(upserted)
+
+			if order.UpdatedAt.After(cursor) {
+				cursor = order.UpdatedAt
+			}
+		}
+
+		if !hasMore || len(records) == 0 {
+			return report, cursor, nil
+		}
+	}
+}
+
+// source 获取租户的同步数据源
+func (uc *OrderImportUseCase) source(tenantID string) *SyncSource {
+	if uc.sources == nil {
+		return nil
+	}
+	source := uc.sources(tenantID)
+	if source == nil || source.Source == nil {
+		return nil
+	}
+	return source
+}
+
+// acquire 标记租户开始同步，已在同步时返回 false
+func (uc *OrderImportUseCase) acquire(tenantID string) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.running[tenantID] {
+		return false
+	}
+	uc.running[tenantID] = true
+	return true
+}
+
+// release 标记租户同步结束
+func (uc *OrderImportUseCase) release(tenantID string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	delete(uc.running, tenantID)
+}
+
+// forEachRecord 逐条解析文件中的记录
+// 单条记录解析失败时以 parseErr 回调，fn 返回错误时停止解析
+func forEachRecord(format string, r io.Reader, fn func(line int, record map[string]any, parseErr error) error) error {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return forEachCSVRecord(r, fn)
+	case FormatJSONL:
+		return forEachJSONLRecord(r, fn)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// forEachCSVRecord 解析 CSV，第一行为表头
+func forEachCSVRecord(r io.Reader, fn func(line int, record map[string]any, parseErr error) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read csv header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.TrimSpace(name)
+	}
+	// Excel 导出的 UTF-8 文件带有 BOM
+	header[0] = strings.TrimPrefix(header[0], "\uFEFF")
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return fmt.Errorf("failed to read csv: %w", err)
+			}
+			if err := fn(parseErr.StartLine, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(row) != len(header) {
+			if err := fn(line, nil, fmt.Errorf("expected %d columns, got %d", len(header), len(row))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record := make(map[string]any, len(header))
+		for i, name := range header {
+			record[name] = row[i]
+		}
+		if err := fn(line, record, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// forEachJSONLRecord 解析 JSONL，每行一个 JSON 对象，空行忽略
+func forEachJSONLRecord(r io.Reader, fn func(line int, record map[string]any, parseErr error) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxJSONLLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if line == 1 {
+			data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var record map[string]any
+		var parseErr error
+		if err := decoder.Decode(&record); err != nil {
+			parseErr = fmt.Errorf("invalid json: %w", err)
+		}
+		if err := fn(line, record, parseErr); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read jsonl: %w", err)
+	}
+	return nil
+}
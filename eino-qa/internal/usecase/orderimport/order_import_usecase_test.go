@@ -0,0 +1,439 @@
+package orderimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/infrastructure/ai/eino"
+	"eino-qa/internal/infrastructure/logger"
+	"eino-qa/internal/infrastructure/repository/sqlite"
+	"eino-qa/internal/infrastructure/webhook"
+	"eino-qa/internal/usecase/chat"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOrderRepository 内存订单仓储（仅实现导入用到的方法）
+type fakeOrderRepository struct {
+	repository.OrderRepository
+	orders map[string]*entity.Order
+	actors []string
+}
+
+func (r *fakeOrderRepository) Upsert(ctx context.Context, order *entity.Order, actor string) (repository.OrderUpsertResult, error) {
+	r.actors = append(r.actors, actor)
+
+	current, ok := r.orders[order.ID]
+	copied := *order
+	r.orders[order.ID] = &copied
+	if !ok {
+		return repository.OrderUpsertCreated, nil
+	}
+	if current.Status == order.Status && current.Amount == order.Amount {
+		return repository.OrderUpsertUnchanged, nil
+	}
+	return repository.OrderUpsertUpdated, nil
+}
+
+// fakeSyncRepository 内存同步状态仓储
+type fakeSyncRepository struct {
+	states map[string]*entity.OrderSyncState
+}
+
+func (r *fakeSyncRepository) GetState(ctx context.Context, source string) (*entity.OrderSyncState, error) {
+	if state, ok := r.states[source]; ok {
+		copied := *state
+		return &copied, nil
+	}
+	return nil, entity.ErrOrderSyncStateNotFound
+}
+
+func (r *fakeSyncRepository) SaveState(ctx context.Context, state *entity.OrderSyncState) error {
+	copied := *state
+	r.states[state.Source] = &copied
+	return nil
+}
+
+// fakeSource 按页返回固定记录的数据源
+type fakeSource struct {
+	pages  [][]map[string]any
+	failAt int // 第 n 页返回错误（0 表示不失败）
+	since  []time.Time
+}
+
+func (s *fakeSource) Name() string { return "shop" }
+
+func (s *fakeSource) Fetch(ctx context.Context, since time.Time, page int) ([]map[string]any, bool, error) {
+	s.since = append(s.since, since)
+	if page == s.failAt {
+		return nil, false, errors.New("connection reset")
+	}
+	if page > len(s.pages) {
+		return nil, false, nil
+	}
+	return s.pages[page-1], page < len(s.pages), nil
+}
+
+func newTestUseCase(source *fakeSource) (*OrderImportUseCase, *fakeOrderRepository, *fakeSyncRepository) {
+	orders := &fakeOrderRepository{orders: make(map[string]*entity.Order)}
+	states := &fakeSyncRepository{states: make(map[string]*entity.OrderSyncState)}
+
+	var sources SourceProvider
+	if source != nil {
+		sources = func(tenantID string) *SyncSource {
+			return &SyncSource{
+				Source: source,
+				Mapping: &FieldMapping{
+					ID:           "order_no",
+					UserID:       "buyer.id",
+					CourseName:   "title",
+					Amount:       "total",
+					Status:       "state",
+					UpdatedAt:    "modified",
+					StatusValues: map[string]string{"TRADE_SUCCESS": "paid", "TRADE_CLOSED": "cancelled"},
+				},
+			}
+		}
+	}
+
+	uc := NewOrderImportUseCase(
+		func(tenantID string) repository.OrderRepository { return orders },
+		func(tenantID string) repository.OrderSyncRepository { return states },
+		sources,
+		nil,
+	)
+	return uc, orders, states
+}
+
+func TestOrderImportUseCase_ImportCSV(t *testing.T) {
+	uc, orders, _ := newTestUseCase(nil)
+
+	csvData := "\uFEFF订单号,用户,课程,金额,状态,下单时间\n" +
+		"20251114001,alice,Go 语言进阶,199,已支付,2025-11-14 10:00:00\n" +
+		"#20251114002,bob,\"Python, 入门\",99.5,待支付,2025-11-14\n" +
+		"20251114003,carol,Rust 实战,-1,已支付,2025-11-14\n" +
+		"20251114004,dave,Java 基础,59,unknown,2025-11-14\n" +
+		"bad-id,erin,Vue 实战,59,已支付,2025-11-14\n" +
+		"20251114006,frank\n"
+
+	mapping := &FieldMapping{
+		ID:           "订单号",
+		UserID:       "用户",
+		CourseName:   "课程",
+		Amount:       "金额",
+		Status:       "状态",
+		CreatedAt:    "下单时间",
+		StatusValues: map[string]string{"已支付": "paid", "待支付": "pending"},
+	}
+
+	report, err := uc.Import(context.Background(), &ImportRequest{
+		TenantID: "tenant1",
+		Format:   FormatCSV,
+		Reader:   strings.NewReader(csvData),
+		Mapping:  mapping,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 4, report.Failed)
+	require.Len(t, report.Errors, 4)
+	assert.Equal(t, 4, report.Errors[0].Line)
+	assert.Contains(t, report.Errors[0].Error, entity.ErrInvalidAmount.Error())
+	assert.Contains(t, report.Errors[1].Error, entity.ErrInvalidOrderStatus.Error())
+	assert.Contains(t, report.Errors[2].Error, entity.ErrInvalidOrderID.Error())
+	assert.Equal(t, 7, report.Errors[3].Line)
+
+	// 订单号按租户方案规范化，时间缺失时互相补齐
+	order := orders.orders["#20251114001"]
+	require.NotNil(t, order)
+	assert.Equal(t, entity.OrderStatusPaid, order.Status)
+	assert.Equal(t, time.Date(2025, 11, 14, 10, 0, 0, 0, time.Local), order.CreatedAt)
+	assert.Equal(t, order.CreatedAt, order.UpdatedAt)
+	assert.Equal(t, "Python, 入门", orders.orders["#20251114002"].CourseName)
+	assert.Equal(t, []string{"import:csv", "import:csv"}, orders.actors)
+}
+
+func TestOrderImportUseCase_ImportJSONL(t *testing.T) {
+	uc, orders, _ := newTestUseCase(nil)
+
+	jsonl := `{"id": "#20251114001", "user_id": "alice", "course_name": "Go 语言进阶", "amount": 199, "status": "paid", "updated_at": "2025-11-14T10:00:00+08:00"}
+
+{"id": "#20251114002", "user_id": "bob", "course_name": "Python 入门", "amount": "99", "status": "pending", "updated_at": 1763085600}
+{"id": "#20251114003", "user_id": "carol"
+{"id": "#20251114004", "course_name": "Rust 实战", "amount": 59, "status": "paid"}
+`
+
+	// 只校验不写入
+	report, err := uc.Import(context.Background(), &ImportRequest{
+		Format: FormatJSONL,
+		Reader: strings.NewReader(jsonl),
+		DryRun: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.Valid)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, 4, report.Errors[0].Line)
+	assert.Contains(t, report.Errors[1].Error, ErrMissingField.Error())
+	assert.Empty(t, orders.orders)
+
+	report, err = uc.Import(context.Background(), &ImportRequest{
+		Format: FormatJSONL,
+		Reader: strings.NewReader(jsonl),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, time.Unix(1763085600, 0), orders.orders["#20251114002"].UpdatedAt)
+
+	_, err = uc.Import(context.Background(), &ImportRequest{Format: "xlsx", Reader: strings.NewReader("")})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestOrderImportUseCase_Sync(t *testing.T) {
+	record := func(id, state, modified string) map[string]any {
+		return map[string]any{
+			"order_no": id,
+			"buyer":    map[string]any{"id": "alice"},
+			"title":    "Go 语言进阶",
+			"total":    "199.00",
+			"state":    state,
+			"modified": modified,
+		}
+	}
+
+	source := &fakeSource{
+		pages: [][]map[string]any{
+			{record("20251114001", "TRADE_SUCCESS", "2025-11-14 10:00:00"), record("20251114002", "TRADE_SUCCESS", "2025-11-14 12:00:00")},
+			{record("20251114003", "TRADE_CLOSED", "2025-11-14 11:00:00"), record("20251114004", "UNKNOWN", "2025-11-15 09:00:00")},
+		},
+	}
+	uc, orders, states := newTestUseCase(source)
+	ctx := context.Background()
+
+	report, err := uc.Sync(ctx, "tenant1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 3, report.Created)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, entity.OrderStatusCancelled, orders.orders["#20251114003"].Status)
+	assert.Equal(t, "sync:shop", orders.actors[0])
+
+	// 游标推进到成功写入记录的最大更新时间
+	state, err := uc.SyncStatus(ctx, "tenant1")
+	require.NoError(t, err)
+	assert.Equal(t, entity.OrderSyncSucceeded, state.Status)
+	cursor := time.Date(2025, 11, 14, 12, 0, 0, 0, time.Local)
+	assert.True(t, state.Cursor.Equal(cursor))
+	assert.Equal(t, 3, state.LastStats.Created)
+
+	// 第二次同步从游标开始；中途失败时游标不变
+	source.failAt = 2
+	source.since = nil
+	_, err = uc.Sync(ctx, "tenant1")
+	require.Error(t, err)
+	assert.True(t, source.since[0].Equal(cursor))
+
+	saved := states.states["shop"]
+	assert.Equal(t, entity.OrderSyncFailed, saved.Status)
+	assert.True(t, saved.Cursor.Equal(cursor))
+	assert.Contains(t, saved.LastError, "connection reset")
+	assert.Equal(t, 2, saved.LastStats.Unchanged)
+}
+
+func TestOrderImportUseCase_SyncNotConfigured(t *testing.T) {
+	uc, _, _ := newTestUseCase(nil)
+
+	_, err := uc.Sync(context.Background(), "tenant1")
+	assert.ErrorIs(t, err, ErrOrderSyncNotEnabled)
+
+	_, err = uc.SyncStatus(context.Background(), "tenant1")
+	assert.ErrorIs(t, err, ErrOrderSyncNotEnabled)
+}
+
+func TestOrderImportUseCase_PublishesJobFinished(t *testing.T) {
+	// 接收端记录收到的 job.finished 事件
+	events := make(chan map[string]any, 8)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		var payload map[string]any
+		if json.Unmarshal(body, &payload) == nil && req.Header.Get(webhook.HeaderEvent) == string(entity.EventJobFinished) {
+			events <- payload
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+	webhookRepos := func(tenantID string) repository.WebhookRepository {
+		return sqlite.NewWebhookRepository(dbManager, tenantID)
+	}
+	endpoint := entity.NewWebhookEndpoint("tenant1", receiver.URL, []entity.WebhookEventType{entity.EventJobFinished})
+	require.NoError(t, webhookRepos("tenant1").CreateEndpoint(context.Background(), endpoint))
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	dispatcher := webhook.NewDispatcher(webhookRepos, webhook.Config{Timeout: 2 * time.Second}, log)
+
+	source := &fakeSource{
+		pages: [][]map[string]any{{{
+			"order_no": "20251114001",
+			"buyer":    map[string]any{"id": "alice"},
+			"title":    "Go 语言进阶",
+			"total":    "199.00",
+			"state":    "TRADE_SUCCESS",
+			"modified": "2025-11-14 10:00:00",
+		}}},
+	}
+	uc, _, _ := newTestUseCase(source)
+	uc.WithEventPublisher(dispatcher)
+	ctx := context.Background()
+
+	// 试运行不发布事件
+	_, err := uc.Import(ctx, &ImportRequest{
+		TenantID: "tenant1",
+		Format:   FormatJSONL,
+		Reader:   strings.NewReader(`{"id":"#20251114002","user_id":"bob","course_name":"Python","amount":99,"status":"paid"}`),
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+
+	_, err = uc.Import(ctx, &ImportRequest{
+		TenantID: "tenant1",
+		Format:   FormatJSONL,
+		Reader:   strings.NewReader(`{"id":"#20251114002","user_id":"bob","course_name":"Python","amount":99,"status":"paid"}`),
+	})
+	require.NoError(t, err)
+
+	_, err = uc.Sync(ctx, "tenant1")
+	require.NoError(t, err)
+
+	source.failAt = 1
+	_, err = uc.Sync(ctx, "tenant1")
+	require.Error(t, err)
+
+	require.NoError(t, dispatcher.Close(5*time.Second))
+	close(events)
+
+	received := make(map[string]map[string]any)
+	for payload := range events {
+		assert.Equal(t, "tenant1", payload["tenant_id"])
+		data := payload["data"].(map[string]any)
+		received[data["job"].(string)+"/"+data["status"].(string)] = data
+	}
+	require.Len(t, received, 3)
+
+	imported := received["order_import/succeeded"]
+	require.NotNil(t, imported)
+	assert.Equal(t, float64(1), imported["created"])
+
+	synced := received["order_sync/succeeded"]
+	require.NotNil(t, synced)
+	assert.Equal(t, "sync:shop", synced["source"])
+	assert.Equal(t, float64(1), synced["created"])
+
+	failed := received["order_sync/failed"]
+	require.NotNil(t, failed)
+	assert.Contains(t, failed["error"], "connection reset")
+}
+
+// orderChatModel 意图总是 order 的聊天模型，订单解析返回空条件，其余请求原样返回输入
+type orderChatModel struct{}
+
+func (orderChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	switch {
+	case strings.Contains(input[len(input)-1].Content, "请分析用户意图"):
+		return schema.AssistantMessage(`{"intent":"order","confidence":0.95,"reason":"订单查询"}`, nil), nil
+	case strings.Contains(input[0].Content, "订单查询解析助手"), strings.Contains(input[0].Content, "订单操作识别助手"):
+		return schema.AssistantMessage(`{}`, nil), nil
+	}
+	return schema.AssistantMessage(input[len(input)-1].Content, nil), nil
+}
+
+func (m orderChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (orderChatModel) BindTools(tools []*schema.ToolInfo) error {
+	return nil
+}
+
+func TestOrderImportUseCase_ImportedOrdersVisibleInChat(t *testing.T) {
+	scheme, err := entity.NewPatternOrderIDScheme(entity.OrderIDSchemeConfig{
+		Prefix:  "SO-",
+		Pattern: `[A-Z]{2}\d{6}`,
+	})
+	require.NoError(t, err)
+	entity.RegisterOrderIDScheme("shop", scheme)
+	defer entity.RegisterOrderIDScheme("shop", nil)
+
+	dbManager := sqlite.NewDBManager(filepath.Join(t.TempDir(), "db"))
+	t.Cleanup(func() { dbManager.Close() })
+	orderRepos := func(tenantID string) repository.OrderRepository {
+		return sqlite.NewOrderRepository(dbManager, tenantID)
+	}
+
+	// 导入 shop 租户的订单
+	importer := NewOrderImportUseCase(
+		orderRepos,
+		func(tenantID string) repository.OrderSyncRepository {
+			return sqlite.NewOrderSyncRepository(dbManager, tenantID)
+		},
+		func(string) *SyncSource { return nil },
+		nil,
+	)
+	report, err := importer.Import(context.Background(), &ImportRequest{
+		TenantID: "shop",
+		Format:   FormatJSONL,
+		Reader:   strings.NewReader(`{"id":"SO-AB123456","user_id":"alice","course_name":"店铺导入课程","amount":299,"status":"paid","created_at":"2025-11-14 10:00:00"}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Created)
+
+	// 通过对话的订单路由查询
+	client := eino.NewClientWithModels(orderChatModel{}, nil, eino.ClientConfig{})
+	log, _ := logger.New(logger.Config{Level: "info", Format: "text", Output: "stdout"})
+	uc := chat.NewChatUseCase(
+		eino.NewIntentRecognizer(client, nil),
+		nil,
+		eino.NewOrderQuerier(client, orderRepos),
+		eino.NewResponseGenerator(client),
+		sqlite.NewSessionRepository(dbManager, "shop"),
+		time.Hour,
+		log,
+	)
+
+	ask := func(tenantID, userID string) *chat.ChatResponse {
+		ctx := context.WithValue(context.Background(), "tenant_id", tenantID)
+		resp, err := uc.Execute(ctx, &chat.ChatRequest{Query: "订单 SO-AB123456 到哪了", TenantID: tenantID, UserID: userID})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := ask("shop", "alice")
+	assert.Equal(t, string(entity.IntentOrder), resp.Route)
+	assert.Contains(t, resp.Answer, "店铺导入课程")
+
+	// 其他租户的同名用户看不到
+	resp = ask("default", "alice")
+	assert.NotContains(t, resp.Answer, "店铺导入课程")
+}
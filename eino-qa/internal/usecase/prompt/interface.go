@@ -0,0 +1,23 @@
+package prompt
+
+import (
+	"context"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+)
+
+// PromptUseCaseInterface 提示词模板管理用例接口
+type PromptUseCaseInterface interface {
+	List(ctx context.Context, tenantID string) ([]*PromptSummary, error)
+	Get(ctx context.Context, tenantID string, name entity.PromptName) (*PromptDetail, error)
+	Create(ctx context.Context, req *CreateRequest) (*entity.PromptTemplate, error)
+	Activate(ctx context.Context, tenantID string, name entity.PromptName, version int) (*entity.PromptTemplate, error)
+	Rollback(ctx context.Context, tenantID string, name entity.PromptName) (*entity.PromptTemplate, error)
+}
+
+// RepositoryProvider 按租户获取提示词模板仓储
+type RepositoryProvider func(tenantID string) repository.PromptTemplateRepository
+
+// VariablesProvider 按租户获取提示词模板变量（品牌名称、业务范围等）
+type VariablesProvider func(tenantID string) entity.PromptData
@@ -0,0 +1,367 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+
+	"github.com/sirupsen/logrus"
+)
+
+// activeTemplateTTL 启用模板的缓存时间
+// 写操作会立即清除本实例的缓存，多实例部署时其他实例最多延迟一个 TTL 生效
+const activeTemplateTTL = 30 * time.Second
+
+// PromptSummary 提示词当前生效的模板概要
+type PromptSummary struct {
+	Name          entity.PromptName
+	ActiveVersion int // 0 表示使用内置默认模板
+	Content       string
+	ActivatedAt   *time.Time
+}
+
+// PromptDetail 提示词的版本历史
+type PromptDetail struct {
+	Name           entity.PromptName
+	ActiveVersion  int    // 0 表示使用内置默认模板
+	DefaultContent string // 内置默认模板
+	Rendered       string // 当前生效模板按租户变量渲染的结果
+	Versions       []*entity.PromptTemplate
+}
+
+// CreateRequest 创建模板版本请求
+type CreateRequest struct {
+	TenantID string
+	Name     entity.PromptName
+	Content  string
+	Comment  string
+	Activate bool // 创建后立即启用
+}
+
+// PromptUseCase 提示词模板用例
+// 管理租户提示词模板的版本、启用和回滚，并为 AI 组件按租户渲染系统提示词
+type PromptUseCase struct {
+	repos     RepositoryProvider
+	variables VariablesProvider
+	logger    *logrus.Logger
+
+	mu    sync.RWMutex
+	cache map[string]*cachedTemplate
+}
+
+// cachedTemplate 缓存的启用模板，template 为 nil 表示使用默认模板
+type cachedTemplate struct {
+	template  *entity.PromptTemplate
+	expiresAt time.Time
+}
+
+// NewPromptUseCase 创建提示词模板用例
+func NewPromptUseCase(repos RepositoryProvider, variables VariablesProvider, logger *logrus.Logger) *PromptUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &PromptUseCase{
+		repos:     repos,
+		variables: variables,
+		logger:    logger,
+		cache:     make(map[string]*cachedTemplate),
+	}
+}
+
+// RenderPrompt 渲染当前租户的系统提示词，实现 eino.PromptRenderer
+// A/B 实验分组指定了提示词版本时使用该版本，否则使用租户启用的模板；
+// 模板不存在或渲染失败时降级为默认模板，保证对话不中断
+func (uc *PromptUseCase) RenderPrompt(ctx context.Context, name entity.PromptName) (string, bool) {
+	tenantID, _ := ctx.Value("tenant_id").(string)
+	tenantID = entity.NormalizeTenantID(tenantID)
+	data := uc.tenantData(tenantID)
+
+	if template := uc.templateFor(ctx, tenantID, name); template != nil {
+		rendered, err := template.Render(data)
+		if err == nil {
+			return rendered, true
+		}
+		uc.logger.WithError(err).WithFields(logrus.Fields{
+			"tenant_id": tenantID,
+			"prompt":    name,
+			"version":   template.Version,
+		}).Warn("failed to render prompt template, falling back to default")
+	}
+
+	content, ok := eino.DefaultPromptTemplates[name]
+	if !ok {
+		return "", false
+	}
+	rendered, err := entity.RenderPrompt(content, data)
+	if err != nil {
+		uc.logger.WithError(err).WithField("prompt", name).Warn("failed to render default prompt with tenant variables")
+		return "", false
+	}
+	return rendered, true
+}
+
+// List 列出租户所有提示词当前生效的模板
+func (uc *PromptUseCase) List(ctx context.Context, tenantID string) ([]*PromptSummary, error) {
+	tenantID = entity.NormalizeTenantID(tenantID)
+
+	active, err := uc.repos(tenantID).ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	byName := make(map[entity.PromptName]*entity.PromptTemplate, len(active))
+	for _, t := range active {
+		byName[t.Name] = t
+	}
+
+	summaries := make([]*PromptSummary, 0, len(entity.PromptNames))
+	for _, name := range entity.PromptNames {
+		summary := &PromptSummary{Name: name, Content: eino.DefaultPromptTemplates[name]}
+		if t, ok := byName[name]; ok {
+			summary.ActiveVersion = t.Version
+			summary.Content = t.Content
+			summary.ActivatedAt = t.ActivatedAt
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// Get 获取提示词的版本历史和当前生效模板
+func (uc *PromptUseCase) Get(ctx context.Context, tenantID string, name entity.PromptName) (*PromptDetail, error) {
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidPromptName, name)
+	}
+	tenantID = entity.NormalizeTenantID(tenantID)
+
+	versions, err := uc.repos(tenantID).ListVersions(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt versions: %w", err)
+	}
+
+	detail := &PromptDetail{
+		Name:           name,
+		DefaultContent: eino.DefaultPromptTemplates[name],
+		Versions:       versions,
+	}
+	content := detail.DefaultContent
+	for _, v := range versions {
+		if v.Active {
+			detail.ActiveVersion = v.Version
+			content = v.Content
+		}
+	}
+	detail.Rendered, _ = entity.RenderPrompt(content, uc.tenantData(tenantID))
+
+	return detail, nil
+}
+
+// Create 创建新的模板版本
+// 模板必须能用示例数据和租户实际变量渲染成功才会保存
+func (uc *PromptUseCase) Create(ctx context.Context, req *CreateRequest) (*entity.PromptTemplate, error) {
+	tenantID := entity.NormalizeTenantID(req.TenantID)
+
+	template := entity.NewPromptTemplate(tenantID, req.Name, 0, req.Content, req.Comment)
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.validateRender(tenantID, template); err != nil {
+		return nil, err
+	}
+
+	template.Active = req.Activate
+	if err := uc.repos(tenantID).Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to save prompt template: %w", err)
+	}
+	uc.invalidate(tenantID, template.Name)
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"prompt":    template.Name,
+		"version":   template.Version,
+		"active":    template.Active,
+	}).Info("prompt template version created")
+
+	return template, nil
+}
+
+// Activate 启用指定版本，启用前重新校验渲染（租户变量可能已变化）
+func (uc *PromptUseCase) Activate(ctx context.Context, tenantID string, name entity.PromptName, version int) (*entity.PromptTemplate, error) {
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidPromptName, name)
+	}
+	tenantID = entity.NormalizeTenantID(tenantID)
+	repo := uc.repos(tenantID)
+
+	template, err := repo.FindVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.validateRender(tenantID, template); err != nil {
+		return nil, err
+	}
+
+	if err := repo.Activate(ctx, name, version); err != nil {
+		return nil, fmt.Errorf("failed to activate prompt template: %w", err)
+	}
+	uc.invalidate(tenantID, name)
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id": tenantID,
+		"prompt":    name,
+		"version":   version,
+	}).Info("prompt template activated")
+
+	return repo.FindVersion(ctx, name, version)
+}
+
+// Rollback 回滚到当前版本之前最近一个启用过的版本，重复调用会逐级回退
+// 没有更早启用过的版本时停用当前版本，回到内置默认模板，此时返回 nil
+func (uc *PromptUseCase) Rollback(ctx context.Context, tenantID string, name entity.PromptName) (*entity.PromptTemplate, error) {
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidPromptName, name)
+	}
+	tenantID = entity.NormalizeTenantID(tenantID)
+	repo := uc.repos(tenantID)
+
+	versions, err := repo.ListVersions(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt versions: %w", err)
+	}
+
+	var current, previous *entity.PromptTemplate
+	for _, v := range versions {
+		if v.Active {
+			current = v
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w: %s has no active version to roll back", entity.ErrPromptTemplateNotFound, name)
+	}
+	// 版本按倒序排列，取当前版本之前最近一个启用过的版本（跳过从未启用的草稿）
+	for _, v := range versions {
+		if v.Version < current.Version && v.ActivatedAt != nil {
+			previous = v
+			break
+		}
+	}
+
+	if previous == nil {
+		if err := repo.Deactivate(ctx, name); err != nil {
+			return nil, fmt.Errorf("failed to deactivate prompt template: %w", err)
+		}
+		uc.invalidate(tenantID, name)
+		uc.logger.WithFields(logrus.Fields{
+			"tenant_id": tenantID,
+			"prompt":    name,
+			"from":      current.Version,
+		}).Info("prompt template rolled back to default")
+		return nil, nil
+	}
+
+	return uc.Activate(ctx, tenantID, name, previous.Version)
+}
+
+// validateRender 用示例数据和租户实际变量渲染模板，任一失败即拒绝
+func (uc *PromptUseCase) validateRender(tenantID string, template *entity.PromptTemplate) error {
+	data := uc.tenantData(tenantID)
+	if _, err := template.Render(entity.SamplePromptData(data.Vars)); err != nil {
+		return err
+	}
+	if _, err := template.Render(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// tenantData 获取租户的模板变量
+func (uc *PromptUseCase) tenantData(tenantID string) entity.PromptData {
+	var data entity.PromptData
+	if uc.variables != nil {
+		data = uc.variables(tenantID)
+	}
+	data.TenantID = tenantID
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+	return data
+}
+
+// templateFor 获取本次调用使用的模板，实验分组指定的版本优先
+func (uc *PromptUseCase) templateFor(ctx context.Context, tenantID string, name entity.PromptName) *entity.PromptTemplate {
+	if assignment := eino.ExperimentVariantFromContext(ctx); assignment != nil &&
+		assignment.PromptVersion > 0 && assignment.Component.PromptName() == name {
+		if template := uc.cachedTemplate(ctx, tenantID, name, assignment.PromptVersion); template != nil {
+			return template
+		}
+		uc.logger.WithFields(logrus.Fields{
+			"tenant_id":  tenantID,
+			"prompt":     name,
+			"experiment": assignment.Experiment,
+			"variant":    assignment.Variant,
+			"version":    assignment.PromptVersion,
+		}).Warn("experiment prompt version not found, using active template")
+	}
+	return uc.cachedTemplate(ctx, tenantID, name, 0)
+}
+
+// cachedTemplate 获取指定版本（version 为 0 时为启用版本）的模板（带缓存），不存在或查询失败时返回 nil
+func (uc *PromptUseCase) cachedTemplate(ctx context.Context, tenantID string, name entity.PromptName, version int) *entity.PromptTemplate {
+	key := cacheKey(tenantID, name, version)
+	now := time.Now()
+
+	uc.mu.RLock()
+	cached, ok := uc.cache[key]
+	uc.mu.RUnlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.template
+	}
+
+	var (
+		template *entity.PromptTemplate
+		err      error
+	)
+	if version > 0 {
+		template, err = uc.repos(tenantID).FindVersion(ctx, name, version)
+	} else {
+		template, err = uc.repos(tenantID).FindActive(ctx, name)
+	}
+	if err != nil {
+		if !errors.Is(err, entity.ErrPromptTemplateNotFound) {
+			// 查询失败时不缓存，下次重试
+			uc.logger.WithError(err).WithField("prompt", name).Warn("failed to load prompt template")
+			return nil
+		}
+		template = nil
+	}
+
+	uc.mu.Lock()
+	uc.cache[key] = &cachedTemplate{template: template, expiresAt: now.Add(activeTemplateTTL)}
+	uc.mu.Unlock()
+
+	return template
+}
+
+// invalidate 清除租户某个提示词的模板缓存（包括实验分组指定的版本）
+func (uc *PromptUseCase) invalidate(tenantID string, name entity.PromptName) {
+	prefix := fmt.Sprintf("%s/%s/", tenantID, name)
+
+	uc.mu.Lock()
+	for key := range uc.cache {
+		if strings.HasPrefix(key, prefix) {
+			delete(uc.cache, key)
+		}
+	}
+	uc.mu.Unlock()
+}
+
+// cacheKey 模板缓存键，version 为 0 表示启用版本
+func cacheKey(tenantID string, name entity.PromptName, version int) string {
+	return fmt.Sprintf("%s/%s/%d", tenantID, name, version)
+}
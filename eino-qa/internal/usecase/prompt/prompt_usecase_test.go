@@ -0,0 +1,253 @@
+package prompt
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/infrastructure/ai/eino"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePromptRepository 内存提示词模板仓储
+type fakePromptRepository struct {
+	items []*entity.PromptTemplate
+	finds int
+}
+
+func (r *fakePromptRepository) Create(ctx context.Context, template *entity.PromptTemplate) error {
+	latest := 0
+	for _, t := range r.items {
+		if t.Name == template.Name && t.Version > latest {
+			latest = t.Version
+		}
+	}
+	template.Version = latest + 1
+	if template.Active {
+		r.deactivate(template.Name)
+		now := time.Now()
+		template.ActivatedAt = &now
+	}
+	copied := *template
+	r.items = append(r.items, &copied)
+	return nil
+}
+
+func (r *fakePromptRepository) FindActive(ctx context.Context, name entity.PromptName) (*entity.PromptTemplate, error) {
+	r.finds++
+	for _, t := range r.items {
+		if t.Name == name && t.Active {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, entity.ErrPromptTemplateNotFound
+}
+
+func (r *fakePromptRepository) FindVersion(ctx context.Context, name entity.PromptName, version int) (*entity.PromptTemplate, error) {
+	for _, t := range r.items {
+		if t.Name == name && t.Version == version {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, entity.ErrPromptTemplateNotFound
+}
+
+func (r *fakePromptRepository) ListVersions(ctx context.Context, name entity.PromptName) ([]*entity.PromptTemplate, error) {
+	var result []*entity.PromptTemplate
+	for _, t := range r.items {
+		if t.Name == name {
+			copied := *t
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version > result[j].Version })
+	return result, nil
+}
+
+func (r *fakePromptRepository) ListActive(ctx context.Context) ([]*entity.PromptTemplate, error) {
+	var result []*entity.PromptTemplate
+	for _, t := range r.items {
+		if t.Active {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakePromptRepository) Activate(ctx context.Context, name entity.PromptName, version int) error {
+	r.deactivate(name)
+	for _, t := range r.items {
+		if t.Name == name && t.Version == version {
+			now := time.Now()
+			t.Active = true
+			t.ActivatedAt = &now
+			return nil
+		}
+	}
+	return entity.ErrPromptTemplateNotFound
+}
+
+func (r *fakePromptRepository) Deactivate(ctx context.Context, name entity.PromptName) error {
+	r.deactivate(name)
+	return nil
+}
+
+func (r *fakePromptRepository) deactivate(name entity.PromptName) {
+	for _, t := range r.items {
+		if t.Name == name {
+			t.Active = false
+		}
+	}
+}
+
+func newTestUseCase(repo *fakePromptRepository, data entity.PromptData) *PromptUseCase {
+	return NewPromptUseCase(
+		func(string) repository.PromptTemplateRepository { return repo },
+		func(string) entity.PromptData { return data },
+		nil,
+	)
+}
+
+func tenantContext(tenantID string) context.Context {
+	return context.WithValue(context.Background(), "tenant_id", tenantID)
+}
+
+func TestPromptUseCase_RenderPrompt(t *testing.T) {
+	repo := &fakePromptRepository{}
+	uc := newTestUseCase(repo, entity.PromptData{BrandName: "极客学院", Vars: map[string]string{"hotline": "400-000"}})
+	ctx := tenantContext("tenant1")
+
+	// 没有自定义模板时使用默认模板并代入租户变量
+	prompt, ok := uc.RenderPrompt(ctx, entity.PromptRAG)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(prompt, "你是一个极客学院的专业的课程咨询助手"))
+
+	// 启用自定义模板后立即生效（写操作清除缓存）
+	_, err := uc.Create(ctx, &CreateRequest{
+		TenantID: "tenant1",
+		Name:     entity.PromptRAG,
+		Content:  "你是{{.BrandName}}的顾问，客服热线 {{.Vars.hotline}}。",
+		Activate: true,
+	})
+	require.NoError(t, err)
+
+	prompt, ok = uc.RenderPrompt(ctx, entity.PromptRAG)
+	require.True(t, ok)
+	assert.Equal(t, "你是极客学院的顾问，客服热线 400-000。", prompt)
+
+	// 缓存期内不重复查询仓储
+	finds := repo.finds
+	uc.RenderPrompt(ctx, entity.PromptRAG)
+	assert.Equal(t, finds, repo.finds)
+}
+
+func TestPromptUseCase_CreateValidatesRender(t *testing.T) {
+	repo := &fakePromptRepository{}
+	uc := newTestUseCase(repo, entity.PromptData{})
+	ctx := tenantContext("tenant1")
+
+	tests := []struct {
+		name    string
+		prompt  entity.PromptName
+		content string
+		wantErr error
+	}{
+		{"unknown name", "unknown", "你好", entity.ErrInvalidPromptName},
+		{"empty content", entity.PromptIntent, "  ", entity.ErrEmptyPromptContent},
+		{"syntax error", entity.PromptIntent, "你是{{.BrandName", entity.ErrPromptRenderFailed},
+		{"unknown field", entity.PromptIntent, "你是{{.Brand}}", entity.ErrPromptRenderFailed},
+		{"undefined variable", entity.PromptIntent, "热线 {{.Vars.hotline}}", entity.ErrPromptRenderFailed},
+		{"renders empty", entity.PromptIntent, "{{if .BrandName}}{{end}}", entity.ErrPromptRenderFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Create(ctx, &CreateRequest{TenantID: "tenant1", Name: tt.prompt, Content: tt.content})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+	assert.Empty(t, repo.items)
+}
+
+func TestPromptUseCase_Rollback(t *testing.T) {
+	repo := &fakePromptRepository{}
+	uc := newTestUseCase(repo, entity.PromptData{})
+	ctx := tenantContext("tenant1")
+
+	create := func(content string, activate bool) {
+		_, err := uc.Create(ctx, &CreateRequest{TenantID: "tenant1", Name: entity.PromptResponse, Content: content, Activate: activate})
+		require.NoError(t, err)
+	}
+	create("v1", true)
+	create("v2 草稿", false)
+	create("v3", true)
+
+	// v3 -> v1（跳过从未启用的 v2）
+	template, err := uc.Rollback(ctx, "tenant1", entity.PromptResponse)
+	require.NoError(t, err)
+	require.NotNil(t, template)
+	assert.Equal(t, 1, template.Version)
+
+	prompt, _ := uc.RenderPrompt(ctx, entity.PromptResponse)
+	assert.Equal(t, "v1", prompt)
+
+	// v1 -> 默认模板
+	template, err = uc.Rollback(ctx, "tenant1", entity.PromptResponse)
+	require.NoError(t, err)
+	assert.Nil(t, template)
+
+	prompt, _ = uc.RenderPrompt(ctx, entity.PromptResponse)
+	assert.Equal(t, eino.DefaultPrompt(entity.PromptResponse), prompt)
+
+	// 已经是默认模板时无法回滚
+	_, err = uc.Rollback(ctx, "tenant1", entity.PromptResponse)
+	assert.ErrorIs(t, err, entity.ErrPromptTemplateNotFound)
+
+	detail, err := uc.Get(ctx, "tenant1", entity.PromptResponse)
+	require.NoError(t, err)
+	assert.Equal(t, 0, detail.ActiveVersion)
+	assert.Len(t, detail.Versions, 3)
+}
+
+func TestPromptUseCase_RenderPromptForExperimentVariant(t *testing.T) {
+	repo := &fakePromptRepository{}
+	uc := newTestUseCase(repo, entity.PromptData{})
+	ctx := tenantContext("tenant1")
+
+	for _, content := range []string{"RAG v1", "RAG v2 实验"} {
+		_, err := uc.Create(ctx, &CreateRequest{TenantID: "tenant1", Name: entity.PromptRAG, Content: content})
+		require.NoError(t, err)
+	}
+	_, err := uc.Activate(ctx, "tenant1", entity.PromptRAG, 1)
+	require.NoError(t, err)
+
+	treatment := eino.WithExperimentVariant(ctx, &entity.ExperimentAssignment{
+		Experiment:    "rag_prompt",
+		Component:     entity.ExperimentRAG,
+		Variant:       "treatment",
+		PromptVersion: 2,
+	})
+
+	// 实验分组指定的版本只用于对应组件的提示词
+	prompt, _ := uc.RenderPrompt(treatment, entity.PromptRAG)
+	assert.Equal(t, "RAG v2 实验", prompt)
+	prompt, _ = uc.RenderPrompt(treatment, entity.PromptIntent)
+	assert.Equal(t, eino.DefaultPrompt(entity.PromptIntent), prompt)
+
+	// 对照组使用启用的版本
+	prompt, _ = uc.RenderPrompt(ctx, entity.PromptRAG)
+	assert.Equal(t, "RAG v1", prompt)
+
+	// 指定的版本不存在时使用启用的版本
+	missing := eino.WithExperimentVariant(ctx, &entity.ExperimentAssignment{Component: entity.ExperimentRAG, PromptVersion: 9})
+	prompt, _ = uc.RenderPrompt(missing, entity.PromptRAG)
+	assert.Equal(t, "RAG v1", prompt)
+}
@@ -0,0 +1,247 @@
+package vector
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/infrastructure/ai/eino"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DocumentAction 文档疑似提示词注入时的处理方式
+type DocumentAction string
+
+const (
+	// DocumentActionQuarantine 不写入知识库，保存到隔离区等待管理员放行或删除
+	DocumentActionQuarantine DocumentAction = "quarantine"
+	// DocumentActionBlock 拒绝整个写入请求
+	DocumentActionBlock DocumentAction = "block"
+	// DocumentActionSanitize 去掉命中的注入片段后写入，去掉后为空时隔离
+	DocumentActionSanitize DocumentAction = "sanitize"
+	// DocumentActionFlag 原样写入，在文档元数据中标记
+	DocumentActionFlag DocumentAction = "flag"
+)
+
+// defaultInjectionThreshold 默认的最低可疑分数
+const defaultInjectionThreshold = 0.5
+
+// InjectionPolicy 租户的文档提示词注入检测策略
+type InjectionPolicy struct {
+	Enabled   bool
+	Method    eino.InjectionMethod
+	Threshold float64 // 最低可疑分数，0 表示使用默认值
+	Action    DocumentAction
+}
+
+// pendingDocument 通过检测、待写入知识库的文本
+type pendingDocument struct {
+	text     string
+	metadata map[string]any
+}
+
+// WithInjectionGuard 设置写入文档的提示词注入检测（可选）
+// 被隔离的文档保存到 quarantine 提供的仓储，检索时不会出现在知识库上下文中
+func (uc *VectorManagementUseCase) WithInjectionGuard(guard *eino.InjectionGuard, policies InjectionPolicyProvider, quarantine QuarantineRepositoryProvider) *VectorManagementUseCase {
+	uc.injectionGuard = guard
+	uc.injectionPolicies = policies
+	uc.quarantine = quarantine
+	return uc
+}
+
+// screenDocuments 在生成向量之前检测每段文本是否包含提示词注入
+// 返回待写入的文本和被隔离的文档 ID；策略为 block 时任一文本可疑即返回 entity.ErrSuspiciousDocument
+func (uc *VectorManagementUseCase) screenDocuments(ctx context.Context, tenantID string, req *AddVectorRequest) ([]pendingDocument, []string, error) {
+	pending := make([]pendingDocument, len(req.Texts))
+	for i, text := range req.Texts {
+		pending[i] = pendingDocument{text: text, metadata: req.Metadata}
+	}
+	if uc.injectionGuard == nil || uc.injectionPolicies == nil {
+		return pending, nil, nil
+	}
+
+	policy := uc.injectionPolicies(tenantID)
+	if !policy.Enabled {
+		return pending, nil, nil
+	}
+	threshold := policy.Threshold
+	if threshold <= 0 {
+		threshold = defaultInjectionThreshold
+	}
+
+	// 先检测全部文本，block 时不写入任何内容
+	reports := make([]*eino.InjectionReport, len(req.Texts))
+	actions := make([]DocumentAction, len(req.Texts))
+	for i, text := range req.Texts {
+		report, err := uc.injectionGuard.Check(ctx, policy.Method, text)
+		if err != nil {
+			uc.logger.WithError(err).WithField("tenant_id", tenantID).Warn("injection classification failed, falling back to heuristic")
+			report, _ = uc.injectionGuard.Check(ctx, eino.InjectionMethodHeuristic, text)
+		}
+		if report.Score < threshold {
+			continue
+		}
+		action := documentAction(policy.Action, report)
+		reports[i], actions[i] = report, action
+
+		uc.logger.WithFields(logrus.Fields{
+			"tenant_id": tenantID,
+			"index":     i,
+			"score":     report.Score,
+			"rules":     report.Rules,
+			"method":    report.Method,
+			"reason":    report.Reason,
+			"action":    action,
+		}).Warn("prompt injection detected in document")
+
+		if action == DocumentActionBlock {
+			return nil, nil, fmt.Errorf("%w at index %d", entity.ErrSuspiciousDocument, i)
+		}
+	}
+
+	kept := make([]pendingDocument, 0, len(pending))
+	var quarantinedIDs []string
+	for i, doc := range pending {
+		report := reports[i]
+		if report == nil {
+			kept = append(kept, doc)
+			continue
+		}
+
+		switch actions[i] {
+		case DocumentActionSanitize:
+			doc.text = report.Sanitized
+			doc.metadata = withInjectionMetadata(doc.metadata, report, "injection_sanitized")
+			kept = append(kept, doc)
+		case DocumentActionFlag:
+			doc.metadata = withInjectionMetadata(doc.metadata, report, "injection_flagged")
+			kept = append(kept, doc)
+		default:
+			id, err := uc.quarantineDocument(ctx, tenantID, doc, report)
+			if err != nil {
+				return nil, nil, err
+			}
+			quarantinedIDs = append(quarantinedIDs, id)
+		}
+	}
+
+	return kept, quarantinedIDs, nil
+}
+
+// documentAction 规范化处理方式：未知值按 quarantine 处理，sanitize 后为空时隔离
+func documentAction(action DocumentAction, report *eino.InjectionReport) DocumentAction {
+	switch action {
+	case DocumentActionBlock, DocumentActionFlag:
+		return action
+	case DocumentActionSanitize:
+		if report.Sanitized != "" {
+			return action
+		}
+	}
+	return DocumentActionQuarantine
+}
+
+// withInjectionMetadata 复制元数据并记录检测结果，不修改请求携带的元数据
+func withInjectionMetadata(metadata map[string]any, report *eino.InjectionReport, key string) map[string]any {
+	result := make(map[string]any, len(metadata)+2)
+	maps.Copy(result, metadata)
+	result[key] = true
+	result["injection_score"] = report.Score
+	return result
+}
+
+// quarantineDocument 保存隔离文档
+func (uc *VectorManagementUseCase) quarantineDocument(ctx context.Context, tenantID string, doc pendingDocument, report *eino.InjectionReport) (string, error) {
+	if uc.quarantine == nil {
+		return "", fmt.Errorf("%w: quarantine is not configured", entity.ErrSuspiciousDocument)
+	}
+
+	metadata := make(map[string]any, len(doc.metadata))
+	maps.Copy(metadata, doc.metadata)
+	quarantined := entity.NewQuarantinedDocument(tenantID, doc.text, metadata)
+	quarantined.Score = report.Score
+	quarantined.Rules = report.Rules
+	quarantined.Method = string(report.Method)
+	quarantined.Reason = report.Reason
+
+	if err := uc.quarantine(tenantID).Create(ctx, quarantined); err != nil {
+		return "", fmt.Errorf("failed to quarantine document: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":     tenantID,
+		"quarantine_id": quarantined.ID,
+		"score":         report.Score,
+	}).Info("document quarantined")
+	return quarantined.ID, nil
+}
+
+// ListQuarantined 列出租户的隔离文档
+func (uc *VectorManagementUseCase) ListQuarantined(ctx context.Context, tenantID string) ([]*entity.QuarantinedDocument, error) {
+	if uc.quarantine == nil {
+		return nil, nil
+	}
+	if tenantID == "" {
+		tenantID = "default"
+	}
+
+	docs, err := uc.quarantine(tenantID).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined documents: %w", err)
+	}
+	return docs, nil
+}
+
+// ReleaseQuarantined 管理员确认无害后放行隔离文档：写入知识库（不再检测）并从隔离区删除
+func (uc *VectorManagementUseCase) ReleaseQuarantined(ctx context.Context, tenantID, id string) (*AddVectorResponse, error) {
+	if uc.quarantine == nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrQuarantinedDocumentNotFound, id)
+	}
+	if tenantID == "" {
+		tenantID = "default"
+	}
+	ctx = context.WithValue(ctx, "tenant_id", tenantID)
+
+	repo := uc.quarantine(tenantID)
+	quarantined, err := repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := uc.indexDocuments(ctx, tenantID, []pendingDocument{{text: quarantined.Content, metadata: quarantined.Metadata}})
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.Delete(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to delete quarantined document: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":     tenantID,
+		"quarantine_id": id,
+		"document_ids":  resp.DocumentIDs,
+	}).Info("quarantined document released")
+	return resp, nil
+}
+
+// DeleteQuarantined 删除隔离文档
+func (uc *VectorManagementUseCase) DeleteQuarantined(ctx context.Context, tenantID, id string) error {
+	if uc.quarantine == nil {
+		return fmt.Errorf("%w: %s", entity.ErrQuarantinedDocumentNotFound, id)
+	}
+	if tenantID == "" {
+		tenantID = "default"
+	}
+
+	if err := uc.quarantine(tenantID).Delete(ctx, id); err != nil {
+		return err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":     tenantID,
+		"quarantine_id": id,
+	}).Info("quarantined document deleted")
+	return nil
+}
@@ -3,6 +3,7 @@ package vector
 import (
 	"context"
 	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
 )
 
 // VectorUseCaseInterface 向量管理用例接口
@@ -12,3 +13,21 @@ type VectorUseCaseInterface interface {
 	GetVectorCount(ctx context.Context, tenantID string) (int64, error)
 	GetVectorByID(ctx context.Context, id string, tenantID string) (*entity.Document, error)
 }
+
+// QuarantineUseCaseInterface 隔离文档管理用例接口
+type QuarantineUseCaseInterface interface {
+	ListQuarantined(ctx context.Context, tenantID string) ([]*entity.QuarantinedDocument, error)
+	ReleaseQuarantined(ctx context.Context, tenantID, id string) (*AddVectorResponse, error)
+	DeleteQuarantined(ctx context.Context, tenantID, id string) error
+}
+
+// KnowledgeBaseListener 知识库变更监听接口（语义回答缓存按租户失效）
+type KnowledgeBaseListener interface {
+	InvalidateTenant(tenantID string)
+}
+
+// InjectionPolicyProvider 按租户获取文档提示词注入检测策略
+type InjectionPolicyProvider func(tenantID string) InjectionPolicy
+
+// QuarantineRepositoryProvider 按租户获取隔离文档仓储
+type QuarantineRepositoryProvider func(tenantID string) repository.QuarantineRepository
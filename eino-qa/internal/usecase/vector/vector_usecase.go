@@ -7,6 +7,7 @@ import (
 
 	"eino-qa/internal/domain/entity"
 	"eino-qa/internal/domain/repository"
+	"eino-qa/internal/infrastructure/ai/eino"
 
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/sirupsen/logrus"
@@ -17,6 +18,11 @@ type VectorManagementUseCase struct {
 	embedder   embedding.Embedder
 	vectorRepo repository.VectorRepository
 	logger     *logrus.Logger
+	listeners  []KnowledgeBaseListener
+
+	injectionGuard    *eino.InjectionGuard
+	injectionPolicies InjectionPolicyProvider
+	quarantine        QuarantineRepositoryProvider
 }
 
 // NewVectorManagementUseCase 创建向量管理用例
@@ -36,6 +42,12 @@ func NewVectorManagementUseCase(
 	}
 }
 
+// WithKnowledgeBaseListener 添加知识库变更监听（可选），文档写入或删除成功后按租户通知
+func (uc *VectorManagementUseCase) WithKnowledgeBaseListener(listener KnowledgeBaseListener) *VectorManagementUseCase {
+	uc.listeners = append(uc.listeners, listener)
+	return uc
+}
+
 // AddVectorRequest 添加向量请求
 type AddVectorRequest struct {
 	Texts    []string       `json:"texts" binding:"required"`
@@ -45,10 +57,11 @@ type AddVectorRequest struct {
 
 // AddVectorResponse 添加向量响应
 type AddVectorResponse struct {
-	Success     bool     `json:"success"`
-	DocumentIDs []string `json:"document_ids"`
-	Count       int      `json:"count"`
-	Message     string   `json:"message"`
+	Success        bool     `json:"success"`
+	DocumentIDs    []string `json:"document_ids"`
+	Count          int      `json:"count"`
+	QuarantinedIDs []string `json:"quarantined_ids,omitempty"` // 疑似提示词注入、未写入知识库的隔离文档 ID
+	Message        string   `json:"message"`
 }
 
 // DeleteVectorRequest 删除向量请求
@@ -83,26 +96,56 @@ func (uc *VectorManagementUseCase) AddVectors(ctx context.Context, req *AddVecto
 		"count":     len(req.Texts),
 	}).Info("adding vectors")
 
-	// 1. 使用嵌入模型生成向量
-	vectors, err := uc.generateVectors(ctx, req.Texts)
+	// 1. 检测提示词注入，可疑文本按租户策略隔离、清理或标记
+	pending, quarantinedIDs, err := uc.screenDocuments(ctx, tenantID, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return &AddVectorResponse{
+			Success:        true,
+			DocumentIDs:    []string{},
+			QuarantinedIDs: quarantinedIDs,
+			Message:        fmt.Sprintf("all %d texts quarantined", len(quarantinedIDs)),
+		}, nil
+	}
+
+	resp, err := uc.indexDocuments(ctx, tenantID, pending)
+	if err != nil {
+		return nil, err
+	}
+	if len(quarantinedIDs) > 0 {
+		resp.QuarantinedIDs = quarantinedIDs
+		resp.Message = fmt.Sprintf("%s, %d quarantined", resp.Message, len(quarantinedIDs))
+	}
+	return resp, nil
+}
+
+// indexDocuments 生成向量并写入知识库
+func (uc *VectorManagementUseCase) indexDocuments(ctx context.Context, tenantID string, pending []pendingDocument) (*AddVectorResponse, error) {
+	texts := make([]string, len(pending))
+	for i, p := range pending {
+		texts[i] = p.text
+	}
+
+	// 2. 使用嵌入模型生成向量
+	vectors, err := uc.generateVectors(ctx, texts)
 	if err != nil {
 		uc.logger.WithError(err).Error("failed to generate vectors")
 		return nil, fmt.Errorf("failed to generate vectors: %w", err)
 	}
 
-	// 2. 构建文档对象
-	docs := make([]*entity.Document, len(req.Texts))
-	documentIDs := make([]string, len(req.Texts))
+	// 3. 构建文档对象
+	docs := make([]*entity.Document, len(pending))
+	documentIDs := make([]string, len(pending))
 
-	for i, text := range req.Texts {
-		doc := entity.NewDocument(text, tenantID)
+	for i, p := range pending {
+		doc := entity.NewDocument(p.text, tenantID)
 		doc.SetVector(vectors[i])
 
 		// 添加元数据
-		if req.Metadata != nil {
-			for k, v := range req.Metadata {
-				doc.AddMetadata(k, v)
-			}
+		for k, v := range p.metadata {
+			doc.AddMetadata(k, v)
 		}
 
 		// 验证文档
@@ -115,7 +158,20 @@ func (uc *VectorManagementUseCase) AddVectors(ctx context.Context, req *AddVecto
 		documentIDs[i] = doc.ID
 	}
 
-	// 3. 插入向量库
+	// 同一请求中的多段文本视为同一来源的连续分块，记录相邻关系供检索时扩展上下文
+	if len(docs) > 1 {
+		for i, doc := range docs {
+			doc.AddMetadata(entity.DocumentKeyChunkIndex, i)
+			if i > 0 {
+				doc.AddMetadata(entity.DocumentKeyPrevChunk, docs[i-1].ID)
+			}
+			if i < len(docs)-1 {
+				doc.AddMetadata(entity.DocumentKeyNextChunk, docs[i+1].ID)
+			}
+		}
+	}
+
+	// 4. 插入向量库
 	err = uc.vectorRepo.Insert(ctx, docs)
 	if err != nil {
 		uc.logger.WithError(err).Error("failed to insert vectors")
@@ -126,6 +182,7 @@ func (uc *VectorManagementUseCase) AddVectors(ctx context.Context, req *AddVecto
 		"tenant_id": tenantID,
 		"count":     len(docs),
 	}).Info("vectors added successfully")
+	uc.notifyChanged(tenantID)
 
 	return &AddVectorResponse{
 		Success:     true,
@@ -165,6 +222,9 @@ func (uc *VectorManagementUseCase) DeleteVectors(ctx context.Context, req *Delet
 		"tenant_id":     tenantID,
 		"deleted_count": deletedCount,
 	}).Info("vectors deleted successfully")
+	if deletedCount > 0 {
+		uc.notifyChanged(tenantID)
+	}
 
 	return &DeleteVectorResponse{
 		Success:      true,
@@ -173,6 +233,13 @@ func (uc *VectorManagementUseCase) DeleteVectors(ctx context.Context, req *Delet
 	}, nil
 }
 
+// notifyChanged 通知租户知识库已变更
+func (uc *VectorManagementUseCase) notifyChanged(tenantID string) {
+	for _, listener := range uc.listeners {
+		listener.InvalidateTenant(tenantID)
+	}
+}
+
 // generateVectors 生成文本向量
 // 需求: 9.2
 func (uc *VectorManagementUseCase) generateVectors(ctx context.Context, texts []string) ([][]float32, error) {
@@ -176,6 +176,39 @@ func TestAddVectors_WithMetadata(t *testing.T) {
 	mockVectorRepo.AssertExpectations(t)
 }
 
+// TestAddVectors_ChunkNeighbours 测试同一请求的多段文本记录相邻分块
+func TestAddVectors_ChunkNeighbours(t *testing.T) {
+	mockEmbedder := new(MockEmbedder)
+	mockVectorRepo := new(MockVectorRepository)
+
+	uc := NewVectorManagementUseCase(mockEmbedder, mockVectorRepo, nil)
+
+	ctx := context.Background()
+	texts := []string{"第一章", "第二章", "第三章"}
+
+	mockEmbeddings := [][]float64{
+		{0.1, 0.2, 0.3},
+		{0.4, 0.5, 0.6},
+		{0.7, 0.8, 0.9},
+	}
+	mockEmbedder.On("EmbedStrings", mock.Anything, texts).Return(mockEmbeddings, nil)
+
+	var inserted []*entity.Document
+	mockVectorRepo.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		inserted = args.Get(1).([]*entity.Document)
+	}).Return(nil)
+
+	_, err := uc.AddVectors(ctx, &AddVectorRequest{Texts: texts, TenantID: "test"})
+	assert.NoError(t, err)
+
+	assert.Len(t, inserted, 3)
+	assert.Equal(t, []string{inserted[1].ID}, inserted[0].NeighbourChunkIDs())
+	assert.Equal(t, []string{inserted[0].ID, inserted[2].ID}, inserted[1].NeighbourChunkIDs())
+	assert.Equal(t, []string{inserted[1].ID}, inserted[2].NeighbourChunkIDs())
+	index, _ := inserted[2].GetMetadata(entity.DocumentKeyChunkIndex)
+	assert.Equal(t, 2, index)
+}
+
 // TestDeleteVectors 测试删除向量
 func TestDeleteVectors(t *testing.T) {
 	mockEmbedder := new(MockEmbedder)
@@ -357,3 +390,38 @@ func TestDeleteVectors_BatchOperation(t *testing.T) {
 
 	mockVectorRepo.AssertExpectations(t)
 }
+
+// recordingListener 记录知识库变更通知
+type recordingListener struct {
+	tenants []string
+}
+
+func (l *recordingListener) InvalidateTenant(tenantID string) {
+	l.tenants = append(l.tenants, tenantID)
+}
+
+// TestKnowledgeBaseListener 测试文档写入和删除后通知知识库变更
+func TestKnowledgeBaseListener(t *testing.T) {
+	mockEmbedder := new(MockEmbedder)
+	mockVectorRepo := new(MockVectorRepository)
+	listener := &recordingListener{}
+
+	uc := NewVectorManagementUseCase(mockEmbedder, mockVectorRepo, nil).WithKnowledgeBaseListener(listener)
+	ctx := context.Background()
+
+	mockEmbedder.On("EmbedStrings", mock.Anything, []string{"Go 语言基础"}).Return([][]float64{{0.1, 0.2}}, nil)
+	mockVectorRepo.On("Insert", mock.Anything, mock.Anything).Return(nil)
+	mockVectorRepo.On("Delete", mock.Anything, []string{"doc_001"}).Return(1, nil)
+	mockVectorRepo.On("Delete", mock.Anything, []string{"doc_missing"}).Return(0, nil)
+
+	_, err := uc.AddVectors(ctx, &AddVectorRequest{Texts: []string{"Go 语言基础"}, TenantID: "tenant1"})
+	assert.NoError(t, err)
+	_, err = uc.DeleteVectors(ctx, &DeleteVectorRequest{IDs: []string{"doc_001"}})
+	assert.NoError(t, err)
+
+	// 没有删除任何文档时不通知
+	_, err = uc.DeleteVectors(ctx, &DeleteVectorRequest{IDs: []string{"doc_missing"}, TenantID: "tenant1"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"tenant1", "default"}, listener.tenants)
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"context"
+	"eino-qa/internal/domain/entity"
+)
+
+// WebhookUseCaseInterface Webhook 管理用例接口
+type WebhookUseCaseInterface interface {
+	CreateEndpoint(ctx context.Context, req *CreateEndpointRequest) (*entity.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context, tenantID string) ([]*entity.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, tenantID, id string) error
+	ListDeadLetters(ctx context.Context, tenantID string, offset, limit int) ([]*entity.WebhookDelivery, error)
+	ReplayDeadLetter(ctx context.Context, tenantID, id string) (*entity.WebhookDelivery, error)
+}
+
+// Replayer 死信重放接口（由 Webhook 分发器实现）
+type Replayer interface {
+	Replay(ctx context.Context, tenantID, deadLetterID string) (*entity.WebhookDelivery, error)
+}
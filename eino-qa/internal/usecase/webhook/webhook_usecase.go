@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"eino-qa/internal/domain/entity"
+	"eino-qa/internal/domain/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RepositoryProvider 按租户获取 Webhook 仓储
+type RepositoryProvider func(tenantID string) repository.WebhookRepository
+
+// WebhookManagementUseCase Webhook 管理用例
+type WebhookManagementUseCase struct {
+	repos    RepositoryProvider
+	replayer Replayer
+	logger   *logrus.Logger
+}
+
+// NewWebhookManagementUseCase 创建 Webhook 管理用例
+func NewWebhookManagementUseCase(
+	repos RepositoryProvider,
+	replayer Replayer,
+	logger *logrus.Logger,
+) *WebhookManagementUseCase {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &WebhookManagementUseCase{
+		repos:    repos,
+		replayer: replayer,
+		logger:   logger,
+	}
+}
+
+// CreateEndpointRequest 创建端点请求
+type CreateEndpointRequest struct {
+	TenantID string
+	URL      string
+	Events   []string
+}
+
+// CreateEndpoint 创建 Webhook 端点
+// 返回的端点包含签名密钥，仅在创建时完整返回给调用方
+func (uc *WebhookManagementUseCase) CreateEndpoint(ctx context.Context, req *CreateEndpointRequest) (*entity.WebhookEndpoint, error) {
+	tenantID := entity.NormalizeTenantID(req.TenantID)
+
+	events := make([]entity.WebhookEventType, 0, len(req.Events))
+	for _, e := range req.Events {
+		events = append(events, entity.WebhookEventType(e))
+	}
+
+	endpoint := entity.NewWebhookEndpoint(tenantID, req.URL, events)
+	if err := endpoint.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repos(tenantID).CreateEndpoint(ctx, endpoint); err != nil {
+		uc.logger.WithError(err).Error("failed to create webhook endpoint")
+		return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":   tenantID,
+		"endpoint_id": endpoint.ID,
+		"events":      req.Events,
+	}).Info("webhook endpoint created")
+
+	return endpoint, nil
+}
+
+// ListEndpoints 列出租户的 Webhook 端点
+func (uc *WebhookManagementUseCase) ListEndpoints(ctx context.Context, tenantID string) ([]*entity.WebhookEndpoint, error) {
+	endpoints, err := uc.repos(entity.NormalizeTenantID(tenantID)).ListEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// DeleteEndpoint 删除 Webhook 端点
+func (uc *WebhookManagementUseCase) DeleteEndpoint(ctx context.Context, tenantID, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	tenantID = entity.NormalizeTenantID(tenantID)
+	if err := uc.repos(tenantID).DeleteEndpoint(ctx, id); err != nil {
+		return err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":   tenantID,
+		"endpoint_id": id,
+	}).Info("webhook endpoint deleted")
+
+	return nil
+}
+
+// ListDeadLetters 列出死信记录
+func (uc *WebhookManagementUseCase) ListDeadLetters(ctx context.Context, tenantID string, offset, limit int) ([]*entity.WebhookDelivery, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, err := uc.repos(entity.NormalizeTenantID(tenantID)).ListDeadLetters(ctx, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ReplayDeadLetter 重放死信记录
+func (uc *WebhookManagementUseCase) ReplayDeadLetter(ctx context.Context, tenantID, id string) (*entity.WebhookDelivery, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id cannot be empty")
+	}
+
+	tenantID = entity.NormalizeTenantID(tenantID)
+	delivery, err := uc.replayer.Replay(ctx, tenantID, id)
+	if err != nil {
+		uc.logger.WithError(err).WithFields(logrus.Fields{
+			"tenant_id":      tenantID,
+			"dead_letter_id": id,
+		}).Warn("webhook replay failed")
+		return delivery, err
+	}
+
+	uc.logger.WithFields(logrus.Fields{
+		"tenant_id":      tenantID,
+		"dead_letter_id": id,
+	}).Info("webhook dead letter replayed")
+
+	return delivery, nil
+}
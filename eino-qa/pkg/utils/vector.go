@@ -0,0 +1,28 @@
+package utils
+
+import "math"
+
+// Float 嵌入向量的元素类型（嵌入模型返回 float64，向量库和缓存保存 float32）
+type Float interface {
+	~float32 | ~float64
+}
+
+// CosineSimilarity 计算余弦相似度，维度不一致或为零向量时返回 0
+func CosineSimilarity[A, B Float](a []A, b []B) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, CosineSimilarity([]float64{1, 2}, []float32{2, 4}), 1e-6)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float64{1, 0}, []float64{0, 1}), 1e-9)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float64{1}, []float64{1, 0}), 1e-9)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float32{0, 0}, []float32{1, 0}), 1e-9)
+}